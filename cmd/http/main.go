@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"os/signal"
@@ -13,15 +14,30 @@ import (
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/http/handlers"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/http/middlewares"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/dummy"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/failover"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/httpsender"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/pgsql"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/redis"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/router"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/smpp"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/throttle"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/webhooksender"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/smsgateway"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/swagger"
 
 	_ "github.com/AshkanAbd/arvancloud_sms_gateway/docs"
+	apikeymodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/models"
+	apikeysrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/services"
+	idempotencysrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/idempotency/services"
+	phonesrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/phone/services"
+	pricingsrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/pricing/services"
+	ratelimitsrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/services"
+	smsmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	smsrepo "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/repositories"
 	smssrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/services"
 	usersrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/services"
+	webhooksrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/services"
 	pkgCfg "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/config"
 	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
 	pkgMetrics "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/metrics"
@@ -43,11 +59,50 @@ func closer(c io.Closer) {
 	}
 }
 
+func newSmsSender(cfg config.SmsSenderConfig, limiter smsrepo.IProviderLimiter) (*router.SmsSender, error) {
+	senders := make(map[string]smsrepo.ISmsSender, len(cfg.Providers))
+	for _, provider := range cfg.Providers {
+		if _, ok := senders[provider.Name]; ok {
+			return nil, fmt.Errorf("duplicate sms provider %s", provider.Name)
+		}
+		sender, err := newProviderSender(provider, senders, limiter)
+		if err != nil {
+			return nil, err
+		}
+		senders[provider.Name] = sender
+	}
+
+	return router.NewSmsSender(cfg.Routing, senders)
+}
+
+func newProviderSender(
+	cfg config.SmsProviderConfig,
+	senders map[string]smsrepo.ISmsSender,
+	limiter smsrepo.IProviderLimiter,
+) (smsrepo.ISmsSender, error) {
+	switch cfg.Driver {
+	case config.SmsSenderDummy, "":
+		return throttle.NewSmsSender(cfg.Name, cfg.Throughput, dummy.NewSmsSender(), limiter), nil
+	case config.SmsSenderHttp:
+		return throttle.NewSmsSender(cfg.Name, cfg.Throughput, httpsender.NewSmsSender(cfg.Http), limiter), nil
+	case config.SmsSenderSmpp:
+		return throttle.NewSmsSender(cfg.Name, cfg.Throughput, smpp.NewSmsSender(cfg.Smpp), limiter), nil
+	case config.SmsSenderFailover:
+		return failover.NewSmsSender(cfg.Name, cfg.Failover, senders)
+	default:
+		return nil, fmt.Errorf("unknown sms sender driver %s for provider %s", cfg.Driver, cfg.Name)
+	}
+}
+
 // @title			SMS Gateway API
 // @version		1.0
 // @description	SMS Gateway API
 // @host			localhost:8000
 // @BasePath		/
+//
+// @securityDefinitions.apikey	ApiKeyAuth
+// @in							header
+// @name						X-Api-Key
 func main() {
 	appCtx, cancel := context.WithCancel(context.Background())
 
@@ -74,14 +129,43 @@ func main() {
 
 	redisRepo := redis.NewRepository(Config.RedisRepoConfig, redisConn)
 
-	smsSender := dummy.NewSmsSender()
+	pkgMetrics.RegisterMetrics()
+
+	smsSender, err := newSmsSender(Config.SmsSenderConfig, redisRepo)
+	if err != nil {
+		pkgLog.Error(err, "failed to create sms sender")
+		return
+	}
+	defer closer(smsSender)
 
 	userService := usersrv.NewUserService(pgsqlRepo)
 	smsService := smssrv.NewSmsService(Config.SmsServiceConfig, pgsqlRepo, smsSender, redisRepo)
+	pricingService := pricingsrv.NewPricingService(pgsqlRepo)
+	idempotencyService := idempotencysrv.NewIdempotencyService(Config.IdempotencyServiceConfig, pgsqlRepo)
+	apiKeyService, err := apikeysrv.NewApiKeyService(Config.ApiKeyServiceConfig, pgsqlRepo, redisRepo)
+	if err != nil {
+		pkgLog.Error(err, "failed to create api key service")
+		return
+	}
+	rateLimitService := ratelimitsrv.NewRateLimitService(Config.RateLimitServiceConfig, pgsqlRepo, redisRepo)
+	phoneService, err := phonesrv.NewPhoneService(Config.PhoneServiceConfig)
+	if err != nil {
+		pkgLog.Error(err, "failed to create phone service")
+		return
+	}
+	webhookService := webhooksrv.NewWebhookService(
+		Config.WebhookServiceConfig,
+		pgsqlRepo,
+		webhooksender.NewWebhookSender(Config.WebhookSenderConfig),
+	)
 
-	gateway := smsgateway.NewSmsGateway(Config.SmsGatewayConfig, userService, smsService)
+	gateway := smsgateway.NewSmsGateway(Config.SmsGatewayConfig, userService, smsService, pricingService, webhookService, apiKeyService, rateLimitService, phoneService, idempotencyService, pgsqlRepo)
 
-	pkgMetrics.RegisterMetrics()
+	smsSender.OnDeliveryReport(func(report smsmodels.DeliveryReport) {
+		if _, err := gateway.ProcessDeliveryReport(appCtx, report); err != nil {
+			pkgLog.Error(err, "failed to process delivery report of message %s from provider %s", report.MessageId, report.Provider)
+		}
+	})
 
 	httpHandler := handlers.NewHttpHandler(gateway)
 
@@ -93,21 +177,59 @@ func main() {
 
 	app.Get("/swagger/*", swagger.HandlerDefault)
 
+	idempotency := middlewares.Idempotency(idempotencyService)
+	send := middlewares.Authorize(apiKeyService, apikeymodels.ScopeSend)
+	read := middlewares.Authorize(apiKeyService, apikeymodels.ScopeRead)
+	billing := middlewares.Authorize(apiKeyService, apikeymodels.ScopeBilling)
+	keys := middlewares.Authorize(apiKeyService, apikeymodels.ScopeKeys)
+	admin := middlewares.Authorize(apiKeyService, apikeymodels.ScopeAdmin)
+
 	api := app.Group("/api")
-	api.Post("/user", httpHandler.CreateUser)
-	api.Get("/user/:id", httpHandler.GetUser)
-	api.Get("/user/:id/sms", httpHandler.GetUserMessages)
-	api.Post("/user/:id/balance", httpHandler.IncreaseUserBalance)
-	api.Post("/user/:id/sms/single", httpHandler.SendSingleMessage)
-	api.Post("/user/:id/sms/bulk", httpHandler.SendBulkMessage)
+	api.Post("/user", admin, httpHandler.CreateUser)
+	api.Get("/user/:id", read, httpHandler.GetUser)
+	api.Get("/user/:id/sms", read, httpHandler.GetUserMessages)
+	api.Post("/user/:id/balance", billing, idempotency, httpHandler.IncreaseUserBalance)
+	api.Get("/user/:id/transactions", billing, httpHandler.GetUserTransactions)
+	api.Get("/user/:id/statement", billing, httpHandler.GetUserStatement)
+	api.Post("/user/:id/sms/single", send, idempotency, httpHandler.SendSingleMessage)
+	api.Post("/user/:id/sms/bulk", send, idempotency, httpHandler.SendBulkMessage)
+	api.Post("/user/:id/sms/quote", send, httpHandler.QuoteMessages)
+	api.Post("/user/:id/sms/:smsId/cancel", send, httpHandler.CancelMessage)
+	api.Post("/user/:id/sms/:smsId/reschedule", send, httpHandler.RescheduleMessage)
+	api.Post("/user/:id/webhooks", send, httpHandler.CreateWebhook)
+	api.Get("/user/:id/webhooks", read, httpHandler.GetWebhooks)
+	api.Delete("/user/:id/webhooks/:webhookId", send, httpHandler.DeleteWebhook)
+	api.Post("/user/:id/webhooks/:webhookId/test", send, httpHandler.TestWebhook)
+	api.Post("/user/:id/webhooks/:webhookId/rotate-secret", send, httpHandler.RotateWebhookSecret)
+	api.Get("/user/:id/webhooks/:webhookId/deliveries", read, httpHandler.GetWebhookDeliveries)
+	api.Post("/user/:id/api-keys", keys, httpHandler.CreateApiKey)
+	api.Get("/user/:id/api-keys", keys, httpHandler.GetApiKeys)
+	api.Delete("/user/:id/api-keys/:keyId", keys, httpHandler.RevokeApiKey)
+	api.Post("/dlr/:provider", middlewares.VerifyDeliveryReport(smsService), httpHandler.ReportDelivery)
+	api.Post("/admin/user/:id/weight", admin, httpHandler.SetUserEnqueueWeight)
+	api.Post("/admin/user/:id/account", admin, httpHandler.SetUserAccount)
+	api.Post("/admin/user/:id/limits", admin, httpHandler.SetUserLimits)
+	api.Post("/admin/user/:id/price-list", admin, httpHandler.AssignUserPriceList)
+	api.Post("/admin/user/:id/prices", admin, httpHandler.AddUserPrices)
+	api.Get("/admin/user/:id/prices", admin, httpHandler.GetUserPrices)
+	api.Post("/admin/price-lists", admin, httpHandler.CreatePriceList)
+	api.Get("/admin/price-lists", admin, httpHandler.GetPriceLists)
+	api.Post("/admin/price-lists/:id/prices", admin, httpHandler.AddPriceListPrices)
+	api.Get("/admin/price-lists/:id/prices", admin, httpHandler.GetPriceListPrices)
+	api.Delete("/admin/prices/:id", admin, httpHandler.DeletePrice)
+	api.Get("/admin/dead-letters", admin, httpHandler.GetDeadLetters)
+	api.Delete("/admin/dead-letters", admin, httpHandler.PurgeDeadLetters)
+	api.Get("/admin/dead-letters/:id", admin, httpHandler.GetDeadLetter)
+	api.Delete("/admin/dead-letters/:id", admin, httpHandler.DeleteDeadLetter)
+	api.Post("/admin/dead-letters/:id/requeue", admin, httpHandler.RequeueDeadLetter)
 	app.Get("/metrics", handlers.Metrics())
 
 	wg := sync.WaitGroup{}
 	defer wg.Wait()
 
 	enqueueWorkerErrCh := make(chan error, 1)
+	wg.Add(1)
 	go func() {
-		wg.Add(1)
 		if err := gateway.StartEnqueueWorker(appCtx); err != nil {
 			enqueueWorkerErrCh <- err
 		}
@@ -116,24 +238,66 @@ func main() {
 
 	sendWorkerErrCh := make(chan error, Config.SendWorkerCount)
 	for range Config.SendWorkerCount {
+		wg.Add(1)
 		go func() {
-			wg.Add(1)
 			if err := gateway.StartSendWorkers(appCtx); err != nil {
 				sendWorkerErrCh <- err
 			}
 			wg.Done()
 		}()
 	}
+
+	reconcileWorkerErrCh := make(chan error, 1)
+	wg.Add(1)
+	go func() {
+		if err := gateway.StartReconcileWorker(appCtx); err != nil {
+			reconcileWorkerErrCh <- err
+		}
+		wg.Done()
+	}()
+
+	webhookWorkerErrCh := make(chan error, 1)
+	wg.Add(1)
+	go func() {
+		if err := gateway.StartWebhookWorker(appCtx); err != nil {
+			webhookWorkerErrCh <- err
+		}
+		wg.Done()
+	}()
+
+	idempotencyPurgeWorkerErrCh := make(chan error, 1)
+	wg.Add(1)
+	go func() {
+		if err := gateway.StartIdempotencyPurgeWorker(appCtx); err != nil {
+			idempotencyPurgeWorkerErrCh <- err
+		}
+		wg.Done()
+	}()
+
+	recoveryWorkerErrCh := make(chan error, 1)
+	if Config.RedisRepoConfig.Reliable {
+		wg.Add(1)
+		go func() {
+			if err := gateway.StartRecoveryWorker(appCtx); err != nil {
+				recoveryWorkerErrCh <- err
+			}
+			wg.Done()
+		}()
+	}
 	httpErrCh := make(chan error, 1)
 
 	app.Get("/healthz", handlers.HealthCheck([]<-chan error{
 		enqueueWorkerErrCh,
 		sendWorkerErrCh,
+		reconcileWorkerErrCh,
+		webhookWorkerErrCh,
+		idempotencyPurgeWorkerErrCh,
+		recoveryWorkerErrCh,
 		httpErrCh,
 	}))
 
+	wg.Add(1)
 	go func() {
-		wg.Add(1)
 		pkgLog.Debug("Starting http server on %s", Config.HttpConfig.Address)
 		if err := app.Listen(Config.HttpConfig.Address); err != nil {
 			httpErrCh <- err
@@ -3,20 +3,63 @@ package config
 import (
 	"github.com/AshkanAbd/arvancloud_sms_gateway/cmd/http/config"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/services"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/failover"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/httpsender"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/redis"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/router"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/smpp"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/throttle"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/webhooksender"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/smsgateway"
 
+	apikeysrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/services"
+	idempotencysrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/idempotency/services"
+	phonesrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/phone/services"
+	ratelimitsrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/services"
+	webhooksrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/services"
 	pkgPgSql "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/pgsql"
 	pkgRedis "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/redis"
 )
 
+const (
+	SmsSenderDummy = "dummy"
+	SmsSenderHttp  = "http"
+	SmsSenderSmpp  = "smpp"
+	// SmsSenderFailover composes other providers into an ordered failover
+	// chain, so it must be declared after its members.
+	SmsSenderFailover = "failover"
+)
+
+type SmsProviderConfig struct {
+	Name     string            `mapstructure:"name"`
+	Driver   string            `mapstructure:"driver"`
+	Http     httpsender.Config `mapstructure:"http"`
+	Smpp     smpp.Config       `mapstructure:"smpp"`
+	Failover failover.Config   `mapstructure:"failover"`
+	// Throughput limits sends of the provider across all gateway instances.
+	// Failover chains are limited by their providers.
+	Throughput throttle.Config `mapstructure:"throughput"`
+}
+
+type SmsSenderConfig struct {
+	Providers []SmsProviderConfig `mapstructure:"providers"`
+	Routing   router.Config       `mapstructure:"routing"`
+}
+
 type AppConfig struct {
-	HttpConfig       config.HTTPConfig         `mapstructure:"http"`
-	SmsServiceConfig services.SmsServiceConfig `mapstructure:"sms_service"`
-	PgSQLConfig      pkgPgSql.Config           `mapstructure:"pgsql"`
-	RedisConfig      pkgRedis.Config           `mapstructure:"redis"`
-	RedisRepoConfig  redis.Config              `mapstructure:"redis_repo"`
-	SmsGatewayConfig smsgateway.Config         `mapstructure:"sms_gateway"`
-	LogLevel         string                    `mapstructure:"log_level"`
-	SendWorkerCount  int                       `mapstructure:"send_worker_count"`
+	HttpConfig               config.HTTPConfig                       `mapstructure:"http"`
+	SmsServiceConfig         services.SmsServiceConfig               `mapstructure:"sms_service"`
+	IdempotencyServiceConfig idempotencysrv.IdempotencyServiceConfig `mapstructure:"idempotency"`
+	ApiKeyServiceConfig      apikeysrv.ApiKeyServiceConfig           `mapstructure:"api_key"`
+	RateLimitServiceConfig   ratelimitsrv.RateLimitServiceConfig     `mapstructure:"rate_limit"`
+	PhoneServiceConfig       phonesrv.PhoneServiceConfig             `mapstructure:"phone"`
+	WebhookServiceConfig     webhooksrv.WebhookServiceConfig         `mapstructure:"webhook"`
+	WebhookSenderConfig      webhooksender.Config                    `mapstructure:"webhook_sender"`
+	PgSQLConfig              pkgPgSql.Config                         `mapstructure:"pgsql"`
+	RedisConfig              pkgRedis.Config                         `mapstructure:"redis"`
+	RedisRepoConfig          redis.Config                            `mapstructure:"redis_repo"`
+	SmsGatewayConfig         smsgateway.Config                       `mapstructure:"sms_gateway"`
+	SmsSenderConfig          SmsSenderConfig                         `mapstructure:"sms_sender"`
+	LogLevel                 string                                  `mapstructure:"log_level"`
+	SendWorkerCount          int                                     `mapstructure:"send_worker_count"`
 }
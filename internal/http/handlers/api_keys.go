@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/http/middlewares"
+	"github.com/gofiber/fiber/v2"
+
+	apikeymodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/models"
+	usermodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+)
+
+// CreateApiKey issues an api key for a user
+//
+//	@Summary		Create an api key for a user by ID
+//	@Description	Issues a key with the given scopes. The key and its signing secret are only returned here, and callers can only grant scopes they hold
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int				true	"User ID"
+//	@Param			apiKey	body		apiKeyRequest	true	"Api key payload"
+//	@Success		200		{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/user/{id}/api-keys [post]
+func (h *HttpHandler) CreateApiKey(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+
+	var req apiKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+	}
+	validationErrs := h.getValidationErrors(req)
+	if len(validationErrs) > 0 {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(validationErrs.Error()))
+	}
+
+	scopes := req.toScopes()
+	if caller, ok := middlewares.RequestApiKey(c); !ok || !caller.CanGrant(scopes) {
+		return buildResponse(c, http.StatusForbidden, newMessageResponse(apikeymodels.ScopeNotGrantedError.Error()))
+	}
+
+	key, rawKey, err := h.gateway.CreateApiKey(c.Context(), userId, req.Name, scopes, req.RequireSignature)
+	if err != nil {
+		if errors.Is(err, usermodels.UserNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, apikeymodels.EmptyScopesError) || errors.Is(err, apikeymodels.InvalidScopeError) {
+			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromApiKey(key, rawKey)))
+}
+
+// GetApiKeys returns api keys of a user
+//
+//	@Summary		List a user api keys by ID
+//	@Description	Returns the keys of the user, including revoked ones, without the keys themselves
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"User ID"
+//	@Success		200	{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/user/{id}/api-keys [get]
+func (h *HttpHandler) GetApiKeys(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+
+	keys, err := h.gateway.GetApiKeys(c.Context(), userId)
+	if err != nil {
+		if errors.Is(err, usermodels.UserNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	ke := make([]apiKeyResponse, len(keys))
+	for i := range keys {
+		ke[i] = fromApiKey(keys[i], "")
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(ke))
+}
+
+// RevokeApiKey revokes an api key of a user
+//
+//	@Summary		Revoke a user api key by ID
+//	@Description	Revokes the key right away. Callers can only revoke keys with scopes they hold, and revoked keys are kept in the listing
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int	true	"User ID"
+//	@Param			keyId	path		int	true	"Api key ID"
+//	@Success		200		{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/user/{id}/api-keys/{keyId} [delete]
+func (h *HttpHandler) RevokeApiKey(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+	keyId := c.Params("keyId")
+	if keyId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid api key id"))
+	}
+
+	caller, ok := middlewares.RequestApiKey(c)
+	if !ok {
+		return buildResponse(c, http.StatusForbidden, newMessageResponse(apikeymodels.ScopeNotGrantedError.Error()))
+	}
+
+	key, err := h.gateway.RevokeApiKey(c.Context(), caller, userId, keyId)
+	if err != nil {
+		if errors.Is(err, apikeymodels.ApiKeyNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, apikeymodels.ScopeNotGrantedError) {
+			return buildResponse(c, http.StatusForbidden, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromApiKey(key, "")))
+}
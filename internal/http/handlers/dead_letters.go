@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	smsmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	usermodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+)
+
+// GetDeadLetters returns dead letters
+//
+//	@Summary		List dead letters
+//	@Description	Returns dead-lettered messages, newest first
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			page		query		int	false	"Page number"				default(1)
+//	@Param			pageSize	query		int	false	"Number of items per page"	default(10)
+//	@Success		200			{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/admin/dead-letters [get]
+func (h *HttpHandler) GetDeadLetters(c *fiber.Ctx) error {
+	skip, limit := paginateFromQuery(c)
+
+	letters, err := h.gateway.GetDeadLetters(c.Context(), skip, limit)
+	if err != nil {
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	de := make([]deadLetterResponse, len(letters))
+	for i := range letters {
+		de[i] = fromDeadLetter(letters[i])
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(de))
+}
+
+// GetDeadLetter returns a dead letter
+//
+//	@Summary		Get dead letter by ID
+//	@Description	Returns a dead letter with its original payload and error
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Dead letter ID"
+//	@Success		200	{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/admin/dead-letters/{id} [get]
+func (h *HttpHandler) GetDeadLetter(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid dead letter id"))
+	}
+
+	letter, err := h.gateway.GetDeadLetter(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, smsmodels.DeadLetterNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromDeadLetter(letter)))
+}
+
+// RequeueDeadLetter requeues a dead letter
+//
+//	@Summary		Requeue dead letter by ID
+//	@Description	Schedules the failed message of a dead letter again and charges its cost
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Dead letter ID"
+//	@Success		200	{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/admin/dead-letters/{id}/requeue [post]
+func (h *HttpHandler) RequeueDeadLetter(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid dead letter id"))
+	}
+
+	msg, err := h.gateway.RequeueDeadLetter(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, smsmodels.DeadLetterNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, smsmodels.NotRequeueableError) {
+			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, usermodels.InsufficientBalanceError) {
+			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, smsmodels.MessageNotExistError) {
+			return buildResponse(c, http.StatusConflict, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromSms(msg)))
+}
+
+// DeleteDeadLetter deletes a dead letter
+//
+//	@Summary		Delete dead letter by ID
+//	@Description	Deletes a dead letter without requeuing it
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Dead letter ID"
+//	@Success		200	{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/admin/dead-letters/{id} [delete]
+func (h *HttpHandler) DeleteDeadLetter(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid dead letter id"))
+	}
+
+	if err := h.gateway.DeleteDeadLetter(c.Context(), id); err != nil {
+		if errors.Is(err, smsmodels.DeadLetterNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newMessageResponse("dead letter deleted successfully"))
+}
+
+// PurgeDeadLetters deletes all dead letters
+//
+//	@Summary		Purge dead letters
+//	@Description	Deletes all dead letters
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/admin/dead-letters [delete]
+func (h *HttpHandler) PurgeDeadLetters(c *fiber.Ctx) error {
+	purged, err := h.gateway.PurgeDeadLetters(c.Context())
+	if err != nil {
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fiber.Map{
+		"purged": purged,
+	}))
+}
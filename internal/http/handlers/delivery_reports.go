@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	smsmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+)
+
+// ReportDelivery accepts a delivery report
+//
+//	@Summary		Report delivery of a sent SMS
+//	@Description	Moves the message a provider accepted with the given message ID to its final delivery state. Reports must come from the provider allowed addresses and be signed with its secret when configured
+//	@Tags			providers
+//	@Accept			json
+//	@Produce		json
+//	@Param			provider		path		string					true	"Provider name"
+//	@Param			X-Dlr-Timestamp	header		string					false	"Unix seconds the report was signed at"
+//	@Param			X-Dlr-Signature	header		string					false	"Hex HMAC-SHA256 of {timestamp}.{body} with the provider secret"
+//	@Param			report			body		deliveryReportRequest	true	"Delivery report payload"
+//	@Success		200				{object}	stdResponse
+//	@Router			/api/dlr/{provider} [post]
+func (h *HttpHandler) ReportDelivery(c *fiber.Ctx) error {
+	provider := c.Params("provider")
+	if provider == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid provider"))
+	}
+
+	var req deliveryReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+	}
+	validationErrs := h.getValidationErrors(req)
+	if len(validationErrs) > 0 {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(validationErrs.Error()))
+	}
+
+	msg, err := h.gateway.ProcessDeliveryReport(c.Context(), req.toDeliveryReport(provider))
+	if err != nil {
+		if errors.Is(err, smsmodels.MessageNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, smsmodels.InvalidDeliveryStatusError) || errors.Is(err, smsmodels.EmptyProviderIdError) {
+			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromSms(msg)))
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 
+	phonemodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/phone/models"
+	ratelimitmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/models"
 	smsmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
 	usermodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
 )
@@ -61,6 +63,7 @@ func NewHttpHandler(gateway *smsgateway.SmsGateway) *HttpHandler {
 //	@Produce		json
 //	@Param			user	body		createUserRequest	true	"User payload"
 //	@Success		200		{object}	stdResponse
+//	@Security		ApiKeyAuth
 //	@Router			/api/user/ [post]
 func (h *HttpHandler) CreateUser(c *fiber.Ctx) error {
 	var req createUserRequest
@@ -87,12 +90,13 @@ func (h *HttpHandler) CreateUser(c *fiber.Ctx) error {
 // GetUser returns a user
 //
 //	@Summary		Get user by ID
-//	@Description	Returns a user with the given ID
+//	@Description	Returns a user with the given ID, its send limits and its usage of the daily and monthly quotas
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
 //	@Param			id	path		int	true	"User ID"
 //	@Success		200	{object}	stdResponse
+//	@Security		ApiKeyAuth
 //	@Router			/api/user/{id} [get]
 func (h *HttpHandler) GetUser(c *fiber.Ctx) error {
 	userId := c.Params("id")
@@ -109,7 +113,14 @@ func (h *HttpHandler) GetUser(c *fiber.Ctx) error {
 		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
 	}
 
-	return buildResponse(c, http.StatusOK, newObjectResponse(fromUser(user)))
+	usage, err := h.gateway.GetUserUsage(c.Context(), userId)
+	if err != nil {
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	resp := fromUser(user)
+	resp.Usage = fromUsage(usage)
+	return buildResponse(c, http.StatusOK, newObjectResponse(resp))
 }
 
 // GetUserMessages returns user messages
@@ -124,6 +135,7 @@ func (h *HttpHandler) GetUser(c *fiber.Ctx) error {
 //	@Param			page		query		int		false	"Page number"				default(1)
 //	@Param			pageSize	query		int		false	"Number of items per page"	default(10)
 //	@Success		200			{object}	stdResponse
+//	@Security		ApiKeyAuth
 //	@Router			/api/user/{id}/sms [get]
 func (h *HttpHandler) GetUserMessages(c *fiber.Ctx) error {
 	userId := c.Params("id")
@@ -152,13 +164,15 @@ func (h *HttpHandler) GetUserMessages(c *fiber.Ctx) error {
 // SendSingleMessage send a single SMS
 //
 //	@Summary		Send a single SMS
-//	@Description	Send a single SMS with given data
+//	@Description	Send a single SMS with given data. Sends without enough balance get 402 and sends over the user limits get 429 with a Retry-After header
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		int			true	"User ID"
-//	@Param			sms	body		smsRequest	true	"User payload"
-//	@Success		200	{object}	stdResponse
+//	@Param			id				path		int			true	"User ID"
+//	@Param			Idempotency-Key	header		string		false	"Key to safely retry the request"
+//	@Param			sms				body		smsRequest	true	"User payload"
+//	@Success		200				{object}	stdResponse
+//	@Security		ApiKeyAuth
 //	@Router			/api/user/{id}/sms/single [post]
 func (h *HttpHandler) SendSingleMessage(c *fiber.Ctx) error {
 	userId := c.Params("id")
@@ -183,6 +197,22 @@ func (h *HttpHandler) SendSingleMessage(c *fiber.Ctx) error {
 		if errors.Is(err, smsmodels.EmptyReceiverError) {
 			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
 		}
+		if errors.Is(err, phonemodels.InvalidPhoneNumberError) {
+			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, ratelimitmodels.BulkSizeExceededError) {
+			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, usermodels.InsufficientBalanceError) {
+			return buildResponse(c, http.StatusPaymentRequired, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, usermodels.UserNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+		var limitErr *ratelimitmodels.LimitError
+		if errors.As(err, &limitErr) {
+			return buildLimitResponse(c, limitErr)
+		}
 
 		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
 	}
@@ -193,13 +223,15 @@ func (h *HttpHandler) SendSingleMessage(c *fiber.Ctx) error {
 // SendBulkMessage send bulk SMS
 //
 //	@Summary		Send bulk SMS
-//	@Description	Send bulk SMS with given data
+//	@Description	Send bulk SMS with given data. Sends without enough balance get 402 and sends over the user limits get 429 with a Retry-After header
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		int				true	"User ID"
-//	@Param			sms	body		[]smsRequest	true	"User payload"
-//	@Success		200	{object}	stdResponse
+//	@Param			id				path		int				true	"User ID"
+//	@Param			Idempotency-Key	header		string			false	"Key to safely retry the request"
+//	@Param			sms				body		[]smsRequest	true	"User payload"
+//	@Success		200				{object}	stdResponse
+//	@Security		ApiKeyAuth
 //	@Router			/api/user/{id}/sms/bulk [post]
 func (h *HttpHandler) SendBulkMessage(c *fiber.Ctx) error {
 	userId := c.Params("id")
@@ -230,6 +262,22 @@ func (h *HttpHandler) SendBulkMessage(c *fiber.Ctx) error {
 		if errors.Is(err, smsmodels.EmptyReceiverError) {
 			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
 		}
+		if errors.Is(err, phonemodels.InvalidPhoneNumberError) {
+			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, ratelimitmodels.BulkSizeExceededError) {
+			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, usermodels.InsufficientBalanceError) {
+			return buildResponse(c, http.StatusPaymentRequired, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, usermodels.UserNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+		var limitErr *ratelimitmodels.LimitError
+		if errors.As(err, &limitErr) {
+			return buildLimitResponse(c, limitErr)
+		}
 
 		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
 	}
@@ -237,6 +285,87 @@ func (h *HttpHandler) SendBulkMessage(c *fiber.Ctx) error {
 	return buildResponse(c, http.StatusOK, newMessageResponse("messages scheduled successfully"))
 }
 
+// CancelMessage cancels a scheduled SMS
+//
+//	@Summary		Cancel a scheduled SMS
+//	@Description	Cancels a message that is not enqueued yet and releases its balance hold
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int	true	"User ID"
+//	@Param			smsId	path		int	true	"SMS ID"
+//	@Success		200		{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/user/{id}/sms/{smsId}/cancel [post]
+func (h *HttpHandler) CancelMessage(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+	smsId := c.Params("smsId")
+	if smsId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid sms id"))
+	}
+
+	msg, err := h.gateway.CancelMessage(c.Context(), userId, smsId)
+	if err != nil {
+		if errors.Is(err, smsmodels.MessageNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromSms(msg)))
+}
+
+// RescheduleMessage changes send time of a scheduled SMS
+//
+//	@Summary		Reschedule a scheduled SMS
+//	@Description	Changes the send time of a message that is not enqueued yet to a time in the future
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int						true	"User ID"
+//	@Param			smsId	path		int						true	"SMS ID"
+//	@Param			sms		body		rescheduleSmsRequest	true	"Reschedule payload"
+//	@Success		200		{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/user/{id}/sms/{smsId}/reschedule [post]
+func (h *HttpHandler) RescheduleMessage(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+	smsId := c.Params("smsId")
+	if smsId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid sms id"))
+	}
+
+	var req rescheduleSmsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+	}
+	validationErrs := h.getValidationErrors(req)
+	if len(validationErrs) > 0 {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(validationErrs.Error()))
+	}
+
+	msg, err := h.gateway.RescheduleMessage(c.Context(), userId, smsId, req.SendAt)
+	if err != nil {
+		if errors.Is(err, smsmodels.PastSendAtError) {
+			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, smsmodels.MessageNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromSms(msg)))
+}
+
 // IncreaseUserBalance increases user balance
 //
 //	@Summary		Increase user balance with given ID
@@ -244,9 +373,11 @@ func (h *HttpHandler) SendBulkMessage(c *fiber.Ctx) error {
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
-//	@Param			id		path		int						true	"User ID"
-//	@Param			balance	body		increaseBalanceRequest	true	"User payload"
-//	@Success		200		{object}	stdResponse
+//	@Param			id				path		int						true	"User ID"
+//	@Param			Idempotency-Key	header		string					false	"Key to safely retry the request"
+//	@Param			balance			body		increaseBalanceRequest	true	"User payload"
+//	@Success		200				{object}	stdResponse
+//	@Security		ApiKeyAuth
 //	@Router			/api/user/{id}/balance [post]
 func (h *HttpHandler) IncreaseUserBalance(c *fiber.Ctx) error {
 	userId := c.Params("id")
@@ -263,7 +394,7 @@ func (h *HttpHandler) IncreaseUserBalance(c *fiber.Ctx) error {
 		return buildResponse(c, http.StatusBadRequest, newMessageResponse(validationErrs.Error()))
 	}
 
-	newBalance, err := h.gateway.IncreaseUserBalance(c.Context(), userId, req.Amount)
+	newBalance, err := h.gateway.IncreaseUserBalance(c.Context(), userId, req.Amount, req.Reference)
 	if err != nil {
 		if errors.Is(err, usermodels.InvalidBalanceError) {
 			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
@@ -279,3 +410,93 @@ func (h *HttpHandler) IncreaseUserBalance(c *fiber.Ctx) error {
 		"balance": newBalance,
 	}))
 }
+
+// SetUserEnqueueWeight sets user enqueue weight
+//
+//	@Summary		Set user enqueue weight with given ID
+//	@Description	Sets the share of enqueue slots the user gets relative to other users
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int						true	"User ID"
+//	@Param			weight	body		enqueueWeightRequest	true	"Weight payload"
+//	@Success		200		{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/admin/user/{id}/weight [post]
+func (h *HttpHandler) SetUserEnqueueWeight(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+
+	var req enqueueWeightRequest
+	if err := c.BodyParser(&req); err != nil {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+	}
+	validationErrs := h.getValidationErrors(req)
+	if len(validationErrs) > 0 {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(validationErrs.Error()))
+	}
+
+	user, err := h.gateway.SetUserEnqueueWeight(c.Context(), userId, req.Weight)
+	if err != nil {
+		if errors.Is(err, usermodels.InvalidEnqueueWeightError) {
+			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, usermodels.UserNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromUser(user)))
+}
+
+// SetUserAccount sets user account type and credit limit
+//
+//	@Summary		Set user account with given ID
+//	@Description	Switches the user between prepaid and postpaid, postpaid balances may go negative down to the credit limit
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int				true	"User ID"
+//	@Param			account	body		accountRequest	true	"Account payload"
+//	@Success		200		{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/admin/user/{id}/account [post]
+func (h *HttpHandler) SetUserAccount(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+
+	var req accountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+	}
+	validationErrs := h.getValidationErrors(req)
+	if len(validationErrs) > 0 {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(validationErrs.Error()))
+	}
+
+	user, err := h.gateway.SetUserAccount(c.Context(), userId, usermodels.AccountType(req.AccountType), req.CreditLimit)
+	if err != nil {
+		if errors.Is(err, usermodels.InvalidAccountTypeError) {
+			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, usermodels.InvalidCreditLimitError) {
+			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, usermodels.InsufficientBalanceError) {
+			return buildResponse(c, http.StatusConflict, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, usermodels.UserNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromUser(user)))
+}
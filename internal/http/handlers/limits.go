@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	ratelimitmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/models"
+	usermodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+)
+
+// buildLimitResponse answers a send over the user limits with 429 and a
+// Retry-After of whole seconds.
+func buildLimitResponse(c *fiber.Ctx, limitErr *ratelimitmodels.LimitError) error {
+	retryAfter := int(math.Ceil(limitErr.RetryAfter.Seconds()))
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(retryAfter, 1)))
+
+	return buildResponse(c, http.StatusTooManyRequests, newMessageResponse(limitErr.Error()))
+}
+
+// SetUserLimits sets user send limits
+//
+//	@Summary		Set user send limits with given ID
+//	@Description	Replaces the send rate limits, daily and monthly quotas and max bulk size of the user, zero limits are unlimited
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int				true	"User ID"
+//	@Param			limits	body		limitsRequest	true	"Limits payload"
+//	@Success		200		{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/admin/user/{id}/limits [post]
+func (h *HttpHandler) SetUserLimits(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+
+	var req limitsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+	}
+	validationErrs := h.getValidationErrors(req)
+	if len(validationErrs) > 0 {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(validationErrs.Error()))
+	}
+
+	limits, err := h.gateway.SetUserLimits(c.Context(), userId, req.toLimits())
+	if err != nil {
+		if errors.Is(err, ratelimitmodels.InvalidLimitsError) {
+			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, usermodels.UserNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromLimits(limits)))
+}
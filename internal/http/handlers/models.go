@@ -3,8 +3,14 @@ package handlers
 import (
 	"time"
 
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/smsgateway"
+
+	apikeymodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/models"
+	pricingmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/pricing/models"
+	ratelimitmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/models"
 	smsmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
 	usermodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+	webhookmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/models"
 )
 
 type stdResponse struct {
@@ -37,17 +43,26 @@ func (c createUserRequest) toUser() usermodels.User {
 }
 
 type userResponse struct {
-	ID        string     `json:"id"`
-	Name      string     `json:"name"`
-	Balance   int64      `json:"balance"`
-	CreatedAt *time.Time `json:"createdAt"`
+	ID               string         `json:"id"`
+	Name             string         `json:"name"`
+	Balance          int64          `json:"balance"`
+	AvailableBalance int64          `json:"availableBalance"`
+	EnqueueWeight    int            `json:"enqueueWeight"`
+	AccountType      string         `json:"accountType"`
+	CreditLimit      int64          `json:"creditLimit"`
+	Usage            *usageResponse `json:"usage,omitempty"`
+	CreatedAt        *time.Time     `json:"createdAt"`
 }
 
 func fromUser(user usermodels.User) userResponse {
 	resp := userResponse{
-		ID:      user.ID,
-		Name:    user.Name,
-		Balance: user.Balance,
+		ID:               user.ID,
+		Name:             user.Name,
+		Balance:          user.Balance,
+		AvailableBalance: user.AvailableBalance(),
+		EnqueueWeight:    user.EnqueueWeight,
+		AccountType:      string(user.AccountType),
+		CreditLimit:      user.CreditLimit,
 	}
 	if user.Entity != nil {
 		resp.ID = user.ID
@@ -59,26 +74,114 @@ func fromUser(user usermodels.User) userResponse {
 	return resp
 }
 
+// limitsRequest sets the send limits of a user, zero limits are unlimited.
+type limitsRequest struct {
+	PerSecond   int `json:"perSecond" validate:"gte=0"`
+	PerMinute   int `json:"perMinute" validate:"gte=0"`
+	Daily       int `json:"daily" validate:"gte=0"`
+	Monthly     int `json:"monthly" validate:"gte=0"`
+	MaxBulkSize int `json:"maxBulkSize" validate:"gte=0"`
+}
+
+func (r limitsRequest) toLimits() ratelimitmodels.Limits {
+	return ratelimitmodels.Limits{
+		PerSecond:   r.PerSecond,
+		PerMinute:   r.PerMinute,
+		Daily:       r.Daily,
+		Monthly:     r.Monthly,
+		MaxBulkSize: r.MaxBulkSize,
+	}
+}
+
+type limitsResponse struct {
+	PerSecond   int `json:"perSecond"`
+	PerMinute   int `json:"perMinute"`
+	Daily       int `json:"daily"`
+	Monthly     int `json:"monthly"`
+	MaxBulkSize int `json:"maxBulkSize"`
+}
+
+func fromLimits(limits ratelimitmodels.Limits) limitsResponse {
+	return limitsResponse{
+		PerSecond:   limits.PerSecond,
+		PerMinute:   limits.PerMinute,
+		Daily:       limits.Daily,
+		Monthly:     limits.Monthly,
+		MaxBulkSize: limits.MaxBulkSize,
+	}
+}
+
+type usageResponse struct {
+	Limits         limitsResponse `json:"limits"`
+	Daily          int            `json:"daily"`
+	Monthly        int            `json:"monthly"`
+	DailyResetAt   time.Time      `json:"dailyResetAt"`
+	MonthlyResetAt time.Time      `json:"monthlyResetAt"`
+}
+
+func fromUsage(usage ratelimitmodels.Usage) *usageResponse {
+	return &usageResponse{
+		Limits:         fromLimits(usage.Limits),
+		Daily:          usage.Daily,
+		Monthly:        usage.Monthly,
+		DailyResetAt:   usage.DailyResetAt,
+		MonthlyResetAt: usage.MonthlyResetAt,
+	}
+}
+
 type smsResponse struct {
-	ID        string     `json:"id"`
-	Content   string     `json:"content"`
-	Receiver  string     `json:"receiver"`
-	Status    string     `json:"status"`
-	Cost      int        `json:"cost"`
-	CreatedAt *time.Time `json:"createdAt"`
-	UpdatedAt *time.Time `json:"updatedAt"`
+	ID                string     `json:"id"`
+	Content           string     `json:"content"`
+	Receiver          string     `json:"receiver"`
+	Country           string     `json:"country,omitempty"`
+	Operator          string     `json:"operator,omitempty"`
+	Status            string     `json:"status"`
+	Encoding          string     `json:"encoding"`
+	Segments          int        `json:"segments"`
+	Cost              int        `json:"cost"`
+	Tags              []string   `json:"tags"`
+	Provider          string     `json:"provider"`
+	ProviderMessageId string     `json:"providerMessageId"`
+	Attempts          int        `json:"attempts"`
+	Priority          string     `json:"priority"`
+	DeliveryError     string     `json:"deliveryError,omitempty"`
+	SendAt            *time.Time `json:"sendAt"`
+	SentAt            *time.Time `json:"sentAt"`
+	DoneAt            *time.Time `json:"doneAt"`
+	CreatedAt         *time.Time `json:"createdAt"`
+	UpdatedAt         *time.Time `json:"updatedAt"`
 }
 
 func fromSms(sms smsmodels.Sms) smsResponse {
 	resp := smsResponse{
 		Content:  sms.Content,
 		Receiver: sms.Receiver,
+		Country:  sms.Country,
+		Operator: sms.Operator,
 		Status:   fromSmsStatus(sms.Status),
+		Encoding: fromSmsEncoding(sms.Encoding),
+		Segments: sms.Segments,
 		Cost:     sms.Cost,
+		Tags:     sms.Tags,
+		Provider: sms.Provider,
+		Attempts: sms.Attempts,
+		Priority: fromSmsPriority(sms.Priority),
+
+		ProviderMessageId: sms.ProviderMessageId,
+		DeliveryError:     sms.DeliveryError,
 	}
 	if sms.Entity != nil {
 		resp.ID = sms.ID
 	}
+	if !sms.SendAt.IsZero() {
+		resp.SendAt = &sms.SendAt
+	}
+	if !sms.SentAt.IsZero() {
+		resp.SentAt = &sms.SentAt
+	}
+	if !sms.DoneAt.IsZero() {
+		resp.DoneAt = &sms.DoneAt
+	}
 	if sms.CreateDate != nil {
 		resp.CreatedAt = &sms.CreatedAt
 	}
@@ -90,32 +193,482 @@ func fromSms(sms smsmodels.Sms) smsResponse {
 }
 
 func fromSmsStatus(status smsmodels.SmsStatus) string {
-	switch status {
-	case smsmodels.StatusScheduled:
-		return "Scheduled"
-	case smsmodels.StatusEnqueued:
-		return "Enqueued"
-	case smsmodels.StatusSent:
-		return "Sent"
-	case smsmodels.StatusFailed:
-		return "Failed"
+	return status.String()
+}
+
+func fromSmsEncoding(encoding smsmodels.SmsEncoding) string {
+	switch encoding {
+	case smsmodels.EncodingUCS2:
+		return "ucs2"
 	default:
-		return "Unknown"
+		return "gsm7"
+	}
+}
+
+func fromSmsPriority(priority smsmodels.SmsPriority) string {
+	switch priority {
+	case smsmodels.PriorityHigh:
+		return "high"
+	case smsmodels.PriorityLow:
+		return "low"
+	default:
+		return "normal"
+	}
+}
+
+func toSmsPriority(priority string) smsmodels.SmsPriority {
+	switch priority {
+	case "high":
+		return smsmodels.PriorityHigh
+	case "low":
+		return smsmodels.PriorityLow
+	default:
+		return smsmodels.PriorityNormal
 	}
 }
 
 type smsRequest struct {
-	Content  string `json:"content" validate:"required,min=3,max=1000"`
-	Receiver string `json:"receiver" validate:"required,number,min=10,max=20"`
+	Content  string     `json:"content" validate:"required,min=3,max=1000"`
+	Receiver string     `json:"receiver" validate:"required,max=32"`
+	Tags     []string   `json:"tags" validate:"max=10,dive,min=1,max=32,excludesall=0x2C"`
+	SendAt   *time.Time `json:"sendAt"`
+	Priority string     `json:"priority" validate:"omitempty,oneof=high normal low" enums:"high,normal,low"`
 }
 
 func (r smsRequest) toSms() smsmodels.Sms {
-	return smsmodels.Sms{
+	s := smsmodels.Sms{
 		Content:  r.Content,
 		Receiver: r.Receiver,
+		Tags:     r.Tags,
+		Priority: toSmsPriority(r.Priority),
 	}
+	if r.SendAt != nil {
+		s.SendAt = *r.SendAt
+	}
+
+	return s
+}
+
+type rescheduleSmsRequest struct {
+	SendAt time.Time `json:"sendAt" validate:"required,gt"`
 }
 
 type increaseBalanceRequest struct {
-	Amount int64 `json:"balance" validate:"required,gt=0,lt=1000000"`
+	Amount    int64  `json:"balance" validate:"required,gt=0,lt=1000000"`
+	Reference string `json:"reference" validate:"omitempty,max=250"`
+}
+
+type deliveryReportRequest struct {
+	MessageId string     `json:"messageId" validate:"required,max=250"`
+	Status    string     `json:"status" validate:"required,oneof=delivered undelivered expired"`
+	DoneAt    *time.Time `json:"doneAt"`
+	Error     string     `json:"error" validate:"omitempty,max=250"`
+}
+
+func (r deliveryReportRequest) toDeliveryReport(provider string) smsmodels.DeliveryReport {
+	report := smsmodels.DeliveryReport{
+		Provider:  provider,
+		MessageId: r.MessageId,
+		Status:    toDeliveryStatus(r.Status),
+		Error:     r.Error,
+	}
+	if r.DoneAt != nil {
+		report.DoneAt = *r.DoneAt
+	}
+
+	return report
+}
+
+func toDeliveryStatus(status string) smsmodels.SmsStatus {
+	switch status {
+	case "delivered":
+		return smsmodels.StatusDelivered
+	case "undelivered":
+		return smsmodels.StatusUndelivered
+	default:
+		return smsmodels.StatusExpired
+	}
+}
+
+type deadLetterResponse struct {
+	ID        string     `json:"id"`
+	MessageId string     `json:"messageId"`
+	Payload   string     `json:"payload"`
+	Reason    string     `json:"reason"`
+	Error     string     `json:"error"`
+	CreatedAt *time.Time `json:"createdAt"`
+}
+
+func fromDeadLetter(letter smsmodels.DeadLetter) deadLetterResponse {
+	resp := deadLetterResponse{
+		MessageId: letter.MessageId,
+		Payload:   letter.Payload,
+		Reason:    string(letter.Reason),
+		Error:     letter.Error,
+	}
+	if letter.Entity != nil {
+		resp.ID = letter.ID
+	}
+	if letter.CreateDate != nil {
+		resp.CreatedAt = &letter.CreatedAt
+	}
+
+	return resp
+}
+
+type enqueueWeightRequest struct {
+	Weight int `json:"weight" validate:"required,gt=0,lte=1000"`
+}
+
+type accountRequest struct {
+	AccountType string `json:"accountType" validate:"required,oneof=prepaid postpaid" enums:"prepaid,postpaid"`
+	CreditLimit int64  `json:"creditLimit" validate:"gte=0,lt=100000000"`
+}
+
+type transactionFilterQuery struct {
+	Type      string `query:"type" validate:"omitempty,oneof=topup charge refund adjustment"`
+	MessageId string `query:"messageId" validate:"omitempty,numeric"`
+	From      string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To        string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+func (q transactionFilterQuery) toFilter() usermodels.TransactionFilter {
+	filter := usermodels.TransactionFilter{
+		Type:      usermodels.TransactionType(q.Type),
+		MessageId: q.MessageId,
+	}
+	if q.From != "" {
+		filter.From, _ = time.Parse(time.RFC3339, q.From)
+	}
+	if q.To != "" {
+		filter.To, _ = time.Parse(time.RFC3339, q.To)
+	}
+
+	return filter
+}
+
+type transactionResponse struct {
+	ID           string     `json:"id"`
+	Type         string     `json:"type"`
+	Amount       int64      `json:"amount"`
+	BalanceAfter int64      `json:"balanceAfter"`
+	MessageId    string     `json:"messageId,omitempty"`
+	Reference    string     `json:"reference,omitempty"`
+	CreatedAt    *time.Time `json:"createdAt"`
+}
+
+func fromBalanceTransaction(tx usermodels.BalanceTransaction) transactionResponse {
+	resp := transactionResponse{
+		Type:         string(tx.Type),
+		Amount:       tx.Amount,
+		BalanceAfter: tx.BalanceAfter,
+		MessageId:    tx.MessageId,
+		Reference:    tx.Reference,
+	}
+	if tx.Entity != nil {
+		resp.ID = tx.ID
+	}
+	if tx.CreateDate != nil {
+		resp.CreatedAt = &tx.CreatedAt
+	}
+
+	return resp
+}
+
+type createPriceListRequest struct {
+	Name      string `json:"name" validate:"required,min=3,max=250"`
+	IsDefault bool   `json:"isDefault"`
+}
+
+func (r createPriceListRequest) toPriceList() pricingmodels.PriceList {
+	return pricingmodels.PriceList{
+		Name:      r.Name,
+		IsDefault: r.IsDefault,
+	}
+}
+
+type priceListResponse struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	IsDefault bool       `json:"isDefault"`
+	CreatedAt *time.Time `json:"createdAt"`
+}
+
+func fromPriceList(list pricingmodels.PriceList) priceListResponse {
+	resp := priceListResponse{
+		Name:      list.Name,
+		IsDefault: list.IsDefault,
+	}
+	if list.Entity != nil {
+		resp.ID = list.ID
+	}
+	if list.CreateDate != nil {
+		resp.CreatedAt = &list.CreatedAt
+	}
+
+	return resp
+}
+
+type priceRequest struct {
+	Prefix        string     `json:"prefix" validate:"required,numeric,min=1,max=15"`
+	Price         int        `json:"price" validate:"gte=0,lt=1000000"`
+	EffectiveFrom *time.Time `json:"effectiveFrom"`
+}
+
+func (r priceRequest) toPrice() pricingmodels.Price {
+	p := pricingmodels.Price{
+		Prefix: r.Prefix,
+		Price:  r.Price,
+	}
+	if r.EffectiveFrom != nil {
+		p.EffectiveFrom = *r.EffectiveFrom
+	}
+
+	return p
+}
+
+type priceResponse struct {
+	ID            string     `json:"id"`
+	PriceListId   string     `json:"priceListId,omitempty"`
+	UserId        string     `json:"userId,omitempty"`
+	Prefix        string     `json:"prefix"`
+	Price         int        `json:"price"`
+	EffectiveFrom time.Time  `json:"effectiveFrom"`
+	CreatedAt     *time.Time `json:"createdAt"`
+}
+
+func fromPrice(price pricingmodels.Price) priceResponse {
+	resp := priceResponse{
+		PriceListId:   price.PriceListId,
+		UserId:        price.UserId,
+		Prefix:        price.Prefix,
+		Price:         price.Price,
+		EffectiveFrom: price.EffectiveFrom,
+	}
+	if price.Entity != nil {
+		resp.ID = price.ID
+	}
+	if price.CreateDate != nil {
+		resp.CreatedAt = &price.CreatedAt
+	}
+
+	return resp
+}
+
+type assignPriceListRequest struct {
+	PriceListId string `json:"priceListId" validate:"omitempty,numeric"`
+}
+
+type quoteMessageResponse struct {
+	Receiver    string `json:"receiver"`
+	Country     string `json:"country,omitempty"`
+	Operator    string `json:"operator,omitempty"`
+	Encoding    string `json:"encoding"`
+	Segments    int    `json:"segments"`
+	Prefix      string `json:"prefix,omitempty"`
+	PriceSource string `json:"priceSource"`
+	Cost        int    `json:"cost"`
+}
+
+type quoteResponse struct {
+	Messages         []quoteMessageResponse `json:"messages"`
+	TotalCost        int64                  `json:"totalCost"`
+	AvailableBalance int64                  `json:"availableBalance"`
+}
+
+func fromQuote(quote smsgateway.Quote) quoteResponse {
+	resp := quoteResponse{
+		Messages:         make([]quoteMessageResponse, len(quote.Messages)),
+		TotalCost:        quote.TotalCost,
+		AvailableBalance: quote.AvailableBalance,
+	}
+	for i := range quote.Messages {
+		resp.Messages[i] = quoteMessageResponse{
+			Receiver:    quote.Messages[i].Receiver,
+			Country:     quote.Messages[i].Country,
+			Operator:    quote.Messages[i].Operator,
+			Encoding:    fromSmsEncoding(quote.Messages[i].Encoding),
+			Segments:    quote.Messages[i].Segments,
+			Prefix:      quote.Prices[i].Prefix,
+			PriceSource: string(quote.Prices[i].Source),
+			Cost:        quote.Messages[i].Cost,
+		}
+	}
+
+	return resp
+}
+
+const statementMonthLayout = "2006-01"
+
+type statementQuery struct {
+	Month string `query:"month" validate:"omitempty,datetime=2006-01"`
+}
+
+type statementResponse struct {
+	From            time.Time `json:"from"`
+	To              time.Time `json:"to"`
+	OpeningBalance  int64     `json:"openingBalance"`
+	ClosingBalance  int64     `json:"closingBalance"`
+	TopUps          int64     `json:"topUps"`
+	Charges         int64     `json:"charges"`
+	Refunds         int64     `json:"refunds"`
+	Adjustments     int64     `json:"adjustments"`
+	ChargedMessages int       `json:"chargedMessages"`
+}
+
+func fromBalanceStatement(statement usermodels.BalanceStatement) statementResponse {
+	return statementResponse{
+		From:            statement.From,
+		To:              statement.To,
+		OpeningBalance:  statement.OpeningBalance,
+		ClosingBalance:  statement.ClosingBalance,
+		TopUps:          statement.TopUps,
+		Charges:         statement.Charges,
+		Refunds:         statement.Refunds,
+		Adjustments:     statement.Adjustments,
+		ChargedMessages: statement.ChargedMessages,
+	}
+}
+
+type webhookRequest struct {
+	Url string `json:"url" validate:"required,url,max=2048"`
+}
+
+type webhookResponse struct {
+	ID        string     `json:"id"`
+	Url       string     `json:"url"`
+	Secret    string     `json:"secret,omitempty"`
+	CreatedAt *time.Time `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updatedAt"`
+}
+
+// fromWebhook builds the response of a webhook. The secret is only shown
+// when it was just generated.
+func fromWebhook(webhook webhookmodels.Webhook, withSecret bool) webhookResponse {
+	resp := webhookResponse{
+		Url: webhook.Url,
+	}
+	if withSecret {
+		resp.Secret = webhook.Secret
+	}
+	if webhook.Entity != nil {
+		resp.ID = webhook.ID
+	}
+	if webhook.CreateDate != nil {
+		resp.CreatedAt = &webhook.CreatedAt
+	}
+	if webhook.UpdateDate != nil {
+		resp.UpdatedAt = &webhook.UpdatedAt
+	}
+
+	return resp
+}
+
+type webhookDeliveryResponse struct {
+	ID             string     `json:"id"`
+	Event          string     `json:"event"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status" enums:"Pending,Succeeded,Failed"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"nextAttemptAt,omitempty"`
+	ResponseStatus int        `json:"responseStatus"`
+	Error          string     `json:"error,omitempty"`
+	CreatedAt      *time.Time `json:"createdAt"`
+	UpdatedAt      *time.Time `json:"updatedAt"`
+}
+
+func fromWebhookDelivery(delivery webhookmodels.Delivery) webhookDeliveryResponse {
+	resp := webhookDeliveryResponse{
+		Event:          delivery.Event,
+		Payload:        delivery.Payload,
+		Status:         fromDeliveryStatus(delivery.Status),
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		Error:          delivery.Error,
+	}
+	if delivery.Entity != nil {
+		resp.ID = delivery.ID
+	}
+	if delivery.Status == webhookmodels.DeliveryPending && !delivery.NextAttemptAt.IsZero() {
+		resp.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	if delivery.CreateDate != nil {
+		resp.CreatedAt = &delivery.CreatedAt
+	}
+	if delivery.UpdateDate != nil {
+		resp.UpdatedAt = &delivery.UpdatedAt
+	}
+
+	return resp
+}
+
+func fromDeliveryStatus(status webhookmodels.DeliveryStatus) string {
+	switch status {
+	case webhookmodels.DeliveryPending:
+		return "Pending"
+	case webhookmodels.DeliverySucceeded:
+		return "Succeeded"
+	case webhookmodels.DeliveryFailed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+type apiKeyRequest struct {
+	Name   string   `json:"name" validate:"max=250"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,oneof=send read billing keys:manage admin" enums:"send,read,billing,keys:manage,admin"`
+	// RequireSignature makes every request with the key carry an HMAC
+	// signature made with the returned signing secret.
+	RequireSignature bool `json:"requireSignature"`
+}
+
+func (r apiKeyRequest) toScopes() []apikeymodels.Scope {
+	scopes := make([]apikeymodels.Scope, len(r.Scopes))
+	for i := range r.Scopes {
+		scopes[i] = apikeymodels.Scope(r.Scopes[i])
+	}
+
+	return scopes
+}
+
+type apiKeyResponse struct {
+	ID               string     `json:"id"`
+	Name             string     `json:"name"`
+	Prefix           string     `json:"prefix"`
+	Scopes           []string   `json:"scopes"`
+	Key              string     `json:"key,omitempty"`
+	RequireSignature bool       `json:"requireSignature"`
+	SigningSecret    string     `json:"signingSecret,omitempty"`
+	RevokedAt        *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt        *time.Time `json:"createdAt"`
+}
+
+// fromApiKey builds the response of an api key. The raw key and signing
+// secret are only set when the key was just created.
+func fromApiKey(key apikeymodels.ApiKey, rawKey string) apiKeyResponse {
+	resp := apiKeyResponse{
+		Name:             key.Name,
+		Prefix:           key.Prefix,
+		Scopes:           make([]string, len(key.Scopes)),
+		Key:              rawKey,
+		RequireSignature: key.RequireSignature,
+	}
+	if rawKey != "" {
+		resp.SigningSecret = key.SigningSecret
+	}
+	for i := range key.Scopes {
+		resp.Scopes[i] = string(key.Scopes[i])
+	}
+	if key.Entity != nil {
+		resp.ID = key.ID
+	}
+	if key.IsRevoked() {
+		resp.RevokedAt = &key.RevokedAt
+	}
+	if key.CreateDate != nil {
+		resp.CreatedAt = &key.CreatedAt
+	}
+
+	return resp
 }
@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	phonemodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/phone/models"
+	pricingmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/pricing/models"
+	smsmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	usermodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+)
+
+// QuoteMessages previews the cost of messages
+//
+//	@Summary		Quote SMS cost
+//	@Description	Returns the encoding, segments and cost of each message as they would be charged when sent
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int				true	"User ID"
+//	@Param			sms	body		[]smsRequest	true	"Messages payload"
+//	@Success		200	{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/user/{id}/sms/quote [post]
+func (h *HttpHandler) QuoteMessages(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+
+	var req []smsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+	}
+	for i := range req {
+		validationErrs := h.getValidationErrors(req[i])
+		if len(validationErrs) > 0 {
+			return buildResponse(c, http.StatusBadRequest, newMessageResponse(validationErrs.Error()))
+		}
+	}
+
+	ss := make([]smsmodels.Sms, len(req))
+	for i := range req {
+		ss[i] = req[i].toSms()
+	}
+	quote, err := h.gateway.QuoteMessages(c.Context(), userId, ss)
+	if err != nil {
+		if errors.Is(err, usermodels.UserNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, phonemodels.InvalidPhoneNumberError) {
+			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromQuote(quote)))
+}
+
+// CreatePriceList creates a price list
+//
+//	@Summary		Create price list
+//	@Description	Creates a price list, a default one replaces the current default
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			priceList	body		createPriceListRequest	true	"Price list payload"
+//	@Success		200			{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/admin/price-lists [post]
+func (h *HttpHandler) CreatePriceList(c *fiber.Ctx) error {
+	var req createPriceListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+	}
+	validationErrs := h.getValidationErrors(req)
+	if len(validationErrs) > 0 {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(validationErrs.Error()))
+	}
+
+	list, err := h.gateway.CreatePriceList(c.Context(), req.toPriceList())
+	if err != nil {
+		if errors.Is(err, pricingmodels.EmptyPriceListNameError) {
+			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromPriceList(list)))
+}
+
+// GetPriceLists returns price lists
+//
+//	@Summary		List price lists
+//	@Description	Returns price lists
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			page		query		int	false	"Page number"				default(1)
+//	@Param			pageSize	query		int	false	"Number of items per page"	default(10)
+//	@Success		200			{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/admin/price-lists [get]
+func (h *HttpHandler) GetPriceLists(c *fiber.Ctx) error {
+	skip, limit := paginateFromQuery(c)
+
+	lists, err := h.gateway.GetPriceLists(c.Context(), skip, limit)
+	if err != nil {
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	le := make([]priceListResponse, len(lists))
+	for i := range lists {
+		le[i] = fromPriceList(lists[i])
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(le))
+}
+
+// AddPriceListPrices adds prices to a price list
+//
+//	@Summary		Add prices to price list by ID
+//	@Description	Adds segment prices by destination prefix, each taking effect at its effective time or now
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int				true	"Price list ID"
+//	@Param			prices	body		[]priceRequest	true	"Prices payload"
+//	@Success		200		{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/admin/price-lists/{id}/prices [post]
+func (h *HttpHandler) AddPriceListPrices(c *fiber.Ctx) error {
+	priceListId := c.Params("id")
+	if priceListId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid price list id"))
+	}
+
+	prices, err := h.parsePrices(c)
+	if err != nil {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+	}
+
+	res, err := h.gateway.AddPriceListPrices(c.Context(), priceListId, prices)
+	if err != nil {
+		return buildPricesErrorResponse(c, err)
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromPrices(res)))
+}
+
+// GetPriceListPrices returns prices of a price list
+//
+//	@Summary		List prices of price list by ID
+//	@Description	Returns all prices of the price list, including past and future ones
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int	true	"Price list ID"
+//	@Param			page		query		int	false	"Page number"				default(1)
+//	@Param			pageSize	query		int	false	"Number of items per page"	default(10)
+//	@Success		200			{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/admin/price-lists/{id}/prices [get]
+func (h *HttpHandler) GetPriceListPrices(c *fiber.Ctx) error {
+	priceListId := c.Params("id")
+	if priceListId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid price list id"))
+	}
+	skip, limit := paginateFromQuery(c)
+
+	prices, err := h.gateway.GetPriceListPrices(c.Context(), priceListId, skip, limit)
+	if err != nil {
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromPrices(prices)))
+}
+
+// DeletePrice deletes a price
+//
+//	@Summary		Delete price by ID
+//	@Description	Deletes a price of a price list or a user override
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Price ID"
+//	@Success		200	{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/admin/prices/{id} [delete]
+func (h *HttpHandler) DeletePrice(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid price id"))
+	}
+
+	if err := h.gateway.DeletePrice(c.Context(), id); err != nil {
+		if errors.Is(err, pricingmodels.PriceNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newMessageResponse("price deleted successfully"))
+}
+
+// AssignUserPriceList assigns a price list to a user
+//
+//	@Summary		Assign price list to user with given ID
+//	@Description	Assigns the price list the user is billed with, an empty price list ID falls back to the default one
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int						true	"User ID"
+//	@Param			priceList	body		assignPriceListRequest	true	"Price list payload"
+//	@Success		200			{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/admin/user/{id}/price-list [post]
+func (h *HttpHandler) AssignUserPriceList(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+
+	var req assignPriceListRequest
+	if err := c.BodyParser(&req); err != nil {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+	}
+	validationErrs := h.getValidationErrors(req)
+	if len(validationErrs) > 0 {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(validationErrs.Error()))
+	}
+
+	if err := h.gateway.AssignUserPriceList(c.Context(), userId, req.PriceListId); err != nil {
+		if errors.Is(err, usermodels.UserNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, pricingmodels.PriceListNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newMessageResponse("price list assigned successfully"))
+}
+
+// AddUserPrices adds price overrides to a user
+//
+//	@Summary		Add price overrides to user with given ID
+//	@Description	Adds segment prices of the user that take precedence over its price list
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int				true	"User ID"
+//	@Param			prices	body		[]priceRequest	true	"Prices payload"
+//	@Success		200		{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/admin/user/{id}/prices [post]
+func (h *HttpHandler) AddUserPrices(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+
+	prices, err := h.parsePrices(c)
+	if err != nil {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+	}
+
+	res, err := h.gateway.AddUserPrices(c.Context(), userId, prices)
+	if err != nil {
+		if errors.Is(err, usermodels.UserNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+
+		return buildPricesErrorResponse(c, err)
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromPrices(res)))
+}
+
+// GetUserPrices returns price overrides of a user
+//
+//	@Summary		List price overrides of user with given ID
+//	@Description	Returns all price overrides of the user, including past and future ones
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int	true	"User ID"
+//	@Param			page		query		int	false	"Page number"				default(1)
+//	@Param			pageSize	query		int	false	"Number of items per page"	default(10)
+//	@Success		200			{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/admin/user/{id}/prices [get]
+func (h *HttpHandler) GetUserPrices(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+	skip, limit := paginateFromQuery(c)
+
+	prices, err := h.gateway.GetUserPrices(c.Context(), userId, skip, limit)
+	if err != nil {
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromPrices(prices)))
+}
+
+func (h *HttpHandler) parsePrices(c *fiber.Ctx) ([]pricingmodels.Price, error) {
+	var req []priceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return nil, err
+	}
+	for i := range req {
+		validationErrs := h.getValidationErrors(req[i])
+		if len(validationErrs) > 0 {
+			return nil, validationErrs
+		}
+	}
+
+	prices := make([]pricingmodels.Price, len(req))
+	for i := range req {
+		prices[i] = req[i].toPrice()
+	}
+
+	return prices, nil
+}
+
+func buildPricesErrorResponse(c *fiber.Ctx, err error) error {
+	if errors.Is(err, pricingmodels.PriceListNotExistError) {
+		return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+	}
+	if errors.Is(err, pricingmodels.InvalidPrefixError) ||
+		errors.Is(err, pricingmodels.InvalidPriceError) ||
+		errors.Is(err, pricingmodels.InvalidPriceScopeError) {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+}
+
+func fromPrices(prices []pricingmodels.Price) []priceResponse {
+	pe := make([]priceResponse, len(prices))
+	for i := range prices {
+		pe[i] = fromPrice(prices[i])
+	}
+
+	return pe
+}
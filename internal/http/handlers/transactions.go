@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	usermodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+)
+
+// GetUserTransactions returns user balance transactions
+//
+//	@Summary		List a user balance transactions by ID
+//	@Description	Returns the balance ledger of the user, newest first
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int		true	"User ID"
+//	@Param			type		query		string	false	"Transaction type"				Enums(topup, charge, refund, adjustment)
+//	@Param			messageId	query		int		false	"Message ID"
+//	@Param			from		query		string	false	"Created at or after (RFC3339)"
+//	@Param			to			query		string	false	"Created before (RFC3339)"
+//	@Param			page		query		int		false	"Page number"					default(1)
+//	@Param			pageSize	query		int		false	"Number of items per page"		default(10)
+//	@Success		200			{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/user/{id}/transactions [get]
+func (h *HttpHandler) GetUserTransactions(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+	skip, limit := paginateFromQuery(c)
+
+	var query transactionFilterQuery
+	if err := c.QueryParser(&query); err != nil {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+	}
+	validationErrs := h.getValidationErrors(query)
+	if len(validationErrs) > 0 {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(validationErrs.Error()))
+	}
+
+	txs, err := h.gateway.GetUserTransactions(c.Context(), userId, query.toFilter(), skip, limit)
+	if err != nil {
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	te := make([]transactionResponse, len(txs))
+	for i := range txs {
+		te[i] = fromBalanceTransaction(txs[i])
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(te))
+}
+
+// GetUserStatement returns a user monthly usage statement
+//
+//	@Summary		Get a user monthly statement by ID
+//	@Description	Returns the opening and closing balance and the totals of the ledger of the user for a calendar month in UTC
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int		true	"User ID"
+//	@Param			month	query		string	false	"Month (YYYY-MM), defaults to the current month"
+//	@Success		200		{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/user/{id}/statement [get]
+func (h *HttpHandler) GetUserStatement(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+
+	var query statementQuery
+	if err := c.QueryParser(&query); err != nil {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+	}
+	validationErrs := h.getValidationErrors(query)
+	if len(validationErrs) > 0 {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(validationErrs.Error()))
+	}
+
+	month := time.Now().UTC()
+	if query.Month != "" {
+		month, _ = time.Parse(statementMonthLayout, query.Month)
+	}
+
+	statement, err := h.gateway.GetUserStatement(c.Context(), userId, month)
+	if err != nil {
+		if errors.Is(err, usermodels.UserNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromBalanceStatement(statement)))
+}
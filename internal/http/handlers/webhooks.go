@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	usermodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+	webhookmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/models"
+)
+
+// CreateWebhook registers a webhook of a user
+//
+//	@Summary		Register a webhook for a user by ID
+//	@Description	Registers a URL that receives signed message.status callbacks. The generated secret is only returned here and on rotation
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int				true	"User ID"
+//	@Param			webhook	body		webhookRequest	true	"Webhook payload"
+//	@Success		200		{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/user/{id}/webhooks [post]
+func (h *HttpHandler) CreateWebhook(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+
+	var req webhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+	}
+	validationErrs := h.getValidationErrors(req)
+	if len(validationErrs) > 0 {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse(validationErrs.Error()))
+	}
+
+	webhook, err := h.gateway.CreateWebhook(c.Context(), userId, req.Url)
+	if err != nil {
+		if errors.Is(err, usermodels.UserNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+		if errors.Is(err, webhookmodels.InvalidUrlError) {
+			return buildResponse(c, http.StatusBadRequest, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromWebhook(webhook, true)))
+}
+
+// GetWebhooks returns webhooks of a user
+//
+//	@Summary		List a user webhooks by ID
+//	@Description	Returns the webhooks of the user without their secrets
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"User ID"
+//	@Success		200	{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/user/{id}/webhooks [get]
+func (h *HttpHandler) GetWebhooks(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+
+	webhooks, err := h.gateway.GetWebhooks(c.Context(), userId)
+	if err != nil {
+		if errors.Is(err, usermodels.UserNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	we := make([]webhookResponse, len(webhooks))
+	for i := range webhooks {
+		we[i] = fromWebhook(webhooks[i], false)
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(we))
+}
+
+// DeleteWebhook removes a webhook of a user
+//
+//	@Summary		Delete a user webhook by ID
+//	@Description	Removes the webhook and its pending deliveries
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int	true	"User ID"
+//	@Param			webhookId	path		int	true	"Webhook ID"
+//	@Success		200			{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/user/{id}/webhooks/{webhookId} [delete]
+func (h *HttpHandler) DeleteWebhook(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+	webhookId := c.Params("webhookId")
+	if webhookId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid webhook id"))
+	}
+
+	if err := h.gateway.DeleteWebhook(c.Context(), userId, webhookId); err != nil {
+		if errors.Is(err, webhookmodels.WebhookNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newMessageResponse("Webhook deleted"))
+}
+
+// TestWebhook sends a test event to a webhook of a user
+//
+//	@Summary		Test a user webhook by ID
+//	@Description	Sends a signed webhook.test event right away and returns the logged delivery. Test events are not retried
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int	true	"User ID"
+//	@Param			webhookId	path		int	true	"Webhook ID"
+//	@Success		200			{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/user/{id}/webhooks/{webhookId}/test [post]
+func (h *HttpHandler) TestWebhook(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+	webhookId := c.Params("webhookId")
+	if webhookId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid webhook id"))
+	}
+
+	delivery, err := h.gateway.TestWebhook(c.Context(), userId, webhookId)
+	if err != nil {
+		if errors.Is(err, webhookmodels.WebhookNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromWebhookDelivery(delivery)))
+}
+
+// RotateWebhookSecret replaces the secret of a webhook of a user
+//
+//	@Summary		Rotate a user webhook secret by ID
+//	@Description	Generates a new signing secret. Callbacks sent afterwards, including retries, are signed with it
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int	true	"User ID"
+//	@Param			webhookId	path		int	true	"Webhook ID"
+//	@Success		200			{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/user/{id}/webhooks/{webhookId}/rotate-secret [post]
+func (h *HttpHandler) RotateWebhookSecret(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+	webhookId := c.Params("webhookId")
+	if webhookId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid webhook id"))
+	}
+
+	webhook, err := h.gateway.RotateWebhookSecret(c.Context(), userId, webhookId)
+	if err != nil {
+		if errors.Is(err, webhookmodels.WebhookNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(fromWebhook(webhook, true)))
+}
+
+// GetWebhookDeliveries returns the delivery log of a webhook of a user
+//
+//	@Summary		List a user webhook deliveries by ID
+//	@Description	Returns the callbacks queued for the webhook and the outcome of their last attempt, newest first
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int	true	"User ID"
+//	@Param			webhookId	path		int	true	"Webhook ID"
+//	@Param			page		query		int	false	"Page number"				default(1)
+//	@Param			pageSize	query		int	false	"Number of items per page"	default(10)
+//	@Success		200			{object}	stdResponse
+//	@Security		ApiKeyAuth
+//	@Router			/api/user/{id}/webhooks/{webhookId}/deliveries [get]
+func (h *HttpHandler) GetWebhookDeliveries(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid user id"))
+	}
+	webhookId := c.Params("webhookId")
+	if webhookId == "" {
+		return buildResponse(c, http.StatusBadRequest, newMessageResponse("Invalid webhook id"))
+	}
+	skip, limit := paginateFromQuery(c)
+
+	deliveries, err := h.gateway.GetWebhookDeliveries(c.Context(), userId, webhookId, skip, limit)
+	if err != nil {
+		if errors.Is(err, webhookmodels.WebhookNotExistError) {
+			return buildResponse(c, http.StatusNotFound, newMessageResponse(err.Error()))
+		}
+
+		return buildResponse(c, http.StatusInternalServerError, newMessageResponse(err.Error()))
+	}
+
+	de := make([]webhookDeliveryResponse, len(deliveries))
+	for i := range deliveries {
+		de[i] = fromWebhookDelivery(deliveries[i])
+	}
+
+	return buildResponse(c, http.StatusOK, newObjectResponse(de))
+}
@@ -0,0 +1,108 @@
+package middlewares
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	apikeymodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/models"
+	apikeysrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/services"
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+)
+
+const (
+	ApiKeyHeader = "X-Api-Key"
+
+	apiKeyLocal = "apiKey"
+)
+
+var (
+	missingApiKeyError = errors.New("api key is missing")
+	forbiddenError     = errors.New("api key is not allowed to access this resource")
+)
+
+// Authorize resolves the api key of a request, sent as a bearer token or in
+// the X-Api-Key header, and rejects it unless it holds scope. On routes with
+// an :id param the key must also belong to that user, unless it is an admin
+// key. Keys that require signing must also carry a valid request signature.
+// The resolved key is available to handlers through RequestApiKey.
+func Authorize(service apikeysrv.IApiKeyService, scope apikeymodels.Scope) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		rawKey := requestRawKey(c)
+		if rawKey == "" {
+			return unauthorized(c, missingApiKeyError)
+		}
+
+		key, err := service.Authenticate(c.Context(), rawKey)
+		if err != nil {
+			if errors.Is(err, apikeymodels.InvalidApiKeyError) {
+				return unauthorized(c, err)
+			}
+
+			pkgLog.Error(err, "failed to authenticate api key")
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"data":    nil,
+				"message": err.Error(),
+			})
+		}
+
+		if key.RequireSignature {
+			if err = service.VerifySignature(c.Context(), key, signedRequest(c)); err != nil {
+				if isSignatureError(err) {
+					return unauthorized(c, err)
+				}
+
+				pkgLog.Error(err, "failed to verify request signature")
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"data":    nil,
+					"message": err.Error(),
+				})
+			}
+		}
+
+		if !key.HasScope(scope) {
+			return forbidden(c)
+		}
+		if userId := c.Params("id"); userId != "" && !key.CanActOn(userId) {
+			return forbidden(c)
+		}
+
+		c.Locals(apiKeyLocal, key)
+		return c.Next()
+	}
+}
+
+// RequestApiKey returns the api key Authorize resolved for the request.
+func RequestApiKey(c *fiber.Ctx) (apikeymodels.ApiKey, bool) {
+	key, ok := c.Locals(apiKeyLocal).(apikeymodels.ApiKey)
+	return key, ok
+}
+
+func requestRawKey(c *fiber.Ctx) string {
+	if key := c.Get(ApiKeyHeader); key != "" {
+		return key
+	}
+
+	auth := c.Get(fiber.HeaderAuthorization)
+	if len(auth) > len("Bearer ") && strings.EqualFold(auth[:len("Bearer ")], "Bearer ") {
+		return strings.TrimSpace(auth[len("Bearer "):])
+	}
+
+	return ""
+}
+
+func unauthorized(c *fiber.Ctx, err error) error {
+	c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+		"data":    nil,
+		"message": err.Error(),
+	})
+}
+
+func forbidden(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"data":    nil,
+		"message": forbiddenError.Error(),
+	})
+}
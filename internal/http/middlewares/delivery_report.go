@@ -0,0 +1,46 @@
+package middlewares
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	smsmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	smssrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/services"
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+)
+
+const (
+	ReportSignatureHeader = "X-Dlr-Signature"
+	ReportTimestampHeader = "X-Dlr-Timestamp"
+)
+
+// VerifyDeliveryReport rejects reports pushed to routes with a :provider
+// param unless they come from that provider.
+func VerifyDeliveryReport(service smssrv.ISmsService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := service.VerifyDeliveryReport(smsmodels.PushedReport{
+			Provider:  c.Params("provider"),
+			RemoteIP:  c.IP(),
+			Timestamp: c.Get(ReportTimestampHeader),
+			Signature: c.Get(ReportSignatureHeader),
+			Body:      c.Body(),
+		})
+		if err != nil {
+			if errors.Is(err, smsmodels.UnauthorizedReportError) {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"data":    nil,
+					"message": err.Error(),
+				})
+			}
+
+			pkgLog.Error(err, "failed to verify delivery report")
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"data":    nil,
+				"message": err.Error(),
+			})
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,86 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	idempotencymodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/idempotency/models"
+	idempotencysrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/idempotency/services"
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+)
+
+// Idempotency makes a route safe to retry. The first request with an
+// Idempotency-Key header is processed and its response is stored, retries
+// with the same key and body get the stored response, and retries with the
+// same key but another body are rejected. Keys are scoped to the method and
+// path, and server errors and rate limited requests release the key so the
+// request can be retried.
+func Idempotency(service idempotencysrv.IIdempotencyService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" {
+			return c.Next()
+		}
+
+		scope := c.Method() + " " + c.Path()
+		sum := sha256.Sum256(c.Body())
+		hash := hex.EncodeToString(sum[:])
+
+		stored, err := service.Begin(c.Context(), scope, key, hash)
+		if err != nil {
+			return c.Status(idempotencyErrorStatus(err)).JSON(fiber.Map{
+				"data":    nil,
+				"message": err.Error(),
+			})
+		}
+		if stored.Status == idempotencymodels.KeyCompleted {
+			c.Set(IdempotencyReplayedHeader, "true")
+			c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			return c.Status(stored.StatusCode).Send(stored.Response)
+		}
+
+		if routeErr := c.Next(); routeErr != nil {
+			if err := service.Release(c.Context(), scope, key); err != nil {
+				pkgLog.Error(err, "failed to release idempotency key %s", key)
+			}
+			return routeErr
+		}
+
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError || status == fiber.StatusTooManyRequests {
+			if err := service.Release(c.Context(), scope, key); err != nil {
+				pkgLog.Error(err, "failed to release idempotency key %s", key)
+			}
+			return nil
+		}
+
+		if err := service.Complete(c.Context(), scope, key, status, bytes.Clone(c.Response().Body())); err != nil {
+			pkgLog.Error(err, "failed to complete idempotency key %s", key)
+		}
+
+		return nil
+	}
+}
+
+func idempotencyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, idempotencymodels.EmptyKeyError),
+		errors.Is(err, idempotencymodels.InvalidKeyError):
+		return fiber.StatusBadRequest
+	case errors.Is(err, idempotencymodels.KeyMismatchError):
+		return fiber.StatusUnprocessableEntity
+	case errors.Is(err, idempotencymodels.KeyInProgressError):
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
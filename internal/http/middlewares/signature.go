@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	apikeymodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/models"
+)
+
+const (
+	SignatureHeader          = "X-Signature"
+	SignatureTimestampHeader = "X-Signature-Timestamp"
+	SignatureNonceHeader     = "X-Signature-Nonce"
+)
+
+var signatureErrors = []error{
+	apikeymodels.MissingSignatureError,
+	apikeymodels.InvalidTimestampError,
+	apikeymodels.ExpiredSignatureError,
+	apikeymodels.InvalidNonceError,
+	apikeymodels.InvalidSignatureError,
+	apikeymodels.ReplayedRequestError,
+}
+
+// signedRequest reads the signature headers of a request. The signed path is
+// the original url with its query.
+func signedRequest(c *fiber.Ctx) apikeymodels.SignedRequest {
+	return apikeymodels.SignedRequest{
+		Method:    c.Method(),
+		Path:      c.OriginalURL(),
+		Timestamp: c.Get(SignatureTimestampHeader),
+		Nonce:     c.Get(SignatureNonceHeader),
+		Signature: c.Get(SignatureHeader),
+		Body:      c.Body(),
+	}
+}
+
+func isSignatureError(err error) bool {
+	for _, signatureErr := range signatureErrors {
+		if errors.Is(err, signatureErr) {
+			return true
+		}
+	}
+
+	return false
+}
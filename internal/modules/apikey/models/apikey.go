@@ -0,0 +1,73 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+)
+
+type Scope string
+
+const (
+	// ScopeSend allows sending, quoting, canceling and rescheduling messages
+	// and managing webhooks.
+	ScopeSend Scope = "send"
+	// ScopeRead allows reading the user, its messages and webhooks.
+	ScopeRead Scope = "read"
+	// ScopeBilling allows topping up the balance and reading transactions
+	// and statements.
+	ScopeBilling Scope = "billing"
+	// ScopeKeys allows creating, listing and revoking api keys of the user,
+	// limited to the scopes the key holds itself.
+	ScopeKeys Scope = "keys:manage"
+	// ScopeAdmin allows everything on every user and the admin endpoints.
+	ScopeAdmin Scope = "admin"
+)
+
+var Scopes = []Scope{ScopeSend, ScopeRead, ScopeBilling, ScopeKeys, ScopeAdmin}
+
+// ApiKey is a credential of a user. Only the hash of the key is stored, the
+// prefix identifies it in listings.
+type ApiKey struct {
+	*shared.Entity
+	*shared.CreateDate
+
+	UserId    string
+	Name      string
+	Prefix    string
+	Hash      string
+	Scopes    []Scope
+	RevokedAt time.Time
+
+	// RequireSignature makes requests with the key valid only when they are
+	// signed with SigningSecret.
+	RequireSignature bool
+	SigningSecret    string
+}
+
+// HasScope reports whether the key grants scope. Admin keys grant every scope.
+func (k ApiKey) HasScope(scope Scope) bool {
+	return slices.Contains(k.Scopes, ScopeAdmin) || slices.Contains(k.Scopes, scope)
+}
+
+// CanActOn reports whether the key may act on the resources of a user.
+func (k ApiKey) CanActOn(userId string) bool {
+	return slices.Contains(k.Scopes, ScopeAdmin) || (k.UserId != "" && k.UserId == userId)
+}
+
+func (k ApiKey) IsRevoked() bool {
+	return !k.RevokedAt.IsZero()
+}
+
+// CanGrant reports whether the key may issue or revoke a key with scopes.
+// Keys can only manage keys with scopes they hold themselves.
+func (k ApiKey) CanGrant(scopes []Scope) bool {
+	for _, scope := range scopes {
+		if !k.HasScope(scope) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,19 @@
+package models
+
+import "errors"
+
+var (
+	InvalidApiKeyError   = errors.New("api key is invalid or revoked")
+	ApiKeyNotExistError  = errors.New("api key does not exist")
+	EmptyScopesError     = errors.New("api key needs at least one scope")
+	InvalidScopeError    = errors.New("api key scope is invalid")
+	ScopeNotGrantedError = errors.New("api key can not grant scopes it does not hold")
+	WeakAdminKeyError    = errors.New("admin key is weak")
+
+	MissingSignatureError = errors.New("request signature, timestamp or nonce header is missing")
+	InvalidTimestampError = errors.New("request timestamp is invalid")
+	ExpiredSignatureError = errors.New("request timestamp is outside the allowed clock skew")
+	InvalidNonceError     = errors.New("request nonce is too long")
+	InvalidSignatureError = errors.New("request signature is invalid")
+	ReplayedRequestError  = errors.New("request nonce was already used")
+)
@@ -0,0 +1,12 @@
+package models
+
+// SignedRequest is the part of an http request covered by its signature.
+type SignedRequest struct {
+	Method string
+	// Path is the request path with its query string.
+	Path      string
+	Timestamp string
+	Nonce     string
+	Signature string
+	Body      []byte
+}
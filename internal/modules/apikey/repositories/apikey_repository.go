@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/models"
+)
+
+type IApiKeyRepository interface {
+	CreateApiKey(ctx context.Context, key models.ApiKey) (models.ApiKey, error)
+	GetApiKeyByHash(ctx context.Context, hash string) (models.ApiKey, error)
+	GetApiKeys(ctx context.Context, userId string) ([]models.ApiKey, error)
+	RevokeApiKey(ctx context.Context, userId string, id string) (models.ApiKey, error)
+}
@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+type INonceRepository interface {
+	// ReserveNonce stores nonce for ttl and reports whether it was not stored
+	// already.
+	ReserveNonce(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
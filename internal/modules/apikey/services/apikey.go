@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/repositories"
+
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+)
+
+const (
+	keyPrefix           = "sgw_"
+	prefixLength        = len(keyPrefix) + 8
+	signingSecretPrefix = "sgs_"
+	maxNonceLength      = 128
+	minAdminKeyLength   = 32
+)
+
+// placeholderAdminKeys are sample values that must never guard the admin scope.
+var placeholderAdminKeys = []string{"change-me", "changeme", "change_me", "admin", "secret", "password"}
+
+type ApiKeyServiceConfig struct {
+	// AdminKey is a static key with admin scope that belongs to no user, used
+	// to create the first users and their keys. Leave it empty to disable it.
+	AdminKey string `mapstructure:"admin_key"`
+	// MaxClockSkew is how far the timestamp of a signed request may be from
+	// the server clock. Nonces are remembered for twice as long.
+	MaxClockSkew time.Duration `mapstructure:"max_clock_skew"`
+}
+
+type IApiKeyService interface {
+	// CreateApiKey issues a key and returns it with the raw key, which is not
+	// stored and can not be shown again.
+	CreateApiKey(ctx context.Context, key models.ApiKey) (models.ApiKey, string, error)
+	GetApiKeys(ctx context.Context, userId string) ([]models.ApiKey, error)
+	RevokeApiKey(ctx context.Context, userId string, id string) (models.ApiKey, error)
+	Authenticate(ctx context.Context, rawKey string) (models.ApiKey, error)
+	VerifySignature(ctx context.Context, key models.ApiKey, req models.SignedRequest) error
+}
+
+type ApiKeyService struct {
+	apiKeyRepo repositories.IApiKeyRepository
+	nonceRepo  repositories.INonceRepository
+	cfg        ApiKeyServiceConfig
+}
+
+func NewApiKeyService(
+	cfg ApiKeyServiceConfig,
+	apiKeyRepo repositories.IApiKeyRepository,
+	nonceRepo repositories.INonceRepository,
+) (*ApiKeyService, error) {
+	if cfg.AdminKey != "" {
+		if slices.Contains(placeholderAdminKeys, strings.ToLower(cfg.AdminKey)) {
+			return nil, fmt.Errorf("%w: admin key is a placeholder", models.WeakAdminKeyError)
+		}
+		if len(cfg.AdminKey) < minAdminKeyLength {
+			return nil, fmt.Errorf("%w: admin key needs at least %d characters", models.WeakAdminKeyError, minAdminKeyLength)
+		}
+	}
+	if cfg.MaxClockSkew <= 0 {
+		cfg.MaxClockSkew = 5 * time.Minute
+	}
+
+	return &ApiKeyService{
+		cfg:        cfg,
+		apiKeyRepo: apiKeyRepo,
+		nonceRepo:  nonceRepo,
+	}, nil
+}
+
+// SignRequest returns the hex HMAC-SHA256 with secret of the method, path,
+// timestamp, nonce and hex SHA-256 of the body, each on its own line.
+func SignRequest(secret string, req models.SignedRequest) string {
+	bodySum := sha256.Sum256(req.Body)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(req.Method + "\n" + req.Path + "\n" + req.Timestamp + "\n" + req.Nonce + "\n"))
+	_, _ = mac.Write([]byte(hex.EncodeToString(bodySum[:])))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (a *ApiKeyService) CreateApiKey(ctx context.Context, key models.ApiKey) (models.ApiKey, string, error) {
+	pkgLog.Debug("creating api key for user %s", key.UserId)
+	if len(key.Scopes) == 0 {
+		pkgLog.Error(models.EmptyScopesError, "api key without scopes")
+		return models.ApiKey{}, "", models.EmptyScopesError
+	}
+	for _, scope := range key.Scopes {
+		if !slices.Contains(models.Scopes, scope) {
+			pkgLog.Error(models.InvalidScopeError, "invalid api key scope %s", scope)
+			return models.ApiKey{}, "", models.InvalidScopeError
+		}
+	}
+
+	rawKey, err := newRandom(keyPrefix)
+	if err != nil {
+		pkgLog.Error(err, "failed to generate api key")
+		return models.ApiKey{}, "", err
+	}
+
+	key.Prefix = rawKey[:prefixLength]
+	key.Hash = hashKey(rawKey)
+	key.SigningSecret = ""
+	if key.RequireSignature {
+		secret, err := newRandom(signingSecretPrefix)
+		if err != nil {
+			pkgLog.Error(err, "failed to generate api key signing secret")
+			return models.ApiKey{}, "", err
+		}
+		key.SigningSecret = secret
+	}
+	slices.Sort(key.Scopes)
+	key.Scopes = slices.Compact(key.Scopes)
+
+	res, err := a.apiKeyRepo.CreateApiKey(ctx, key)
+	if err != nil {
+		pkgLog.Error(err, "failed to create api key for user %s", key.UserId)
+		return models.ApiKey{}, "", err
+	}
+
+	pkgLog.Debug("created api key %s for user %s", res.Prefix, key.UserId)
+	return res, rawKey, nil
+}
+
+func (a *ApiKeyService) GetApiKeys(ctx context.Context, userId string) ([]models.ApiKey, error) {
+	pkgLog.Debug("getting api keys of user %s", userId)
+	res, err := a.apiKeyRepo.GetApiKeys(ctx, userId)
+	if err != nil {
+		pkgLog.Error(err, "failed to get api keys of user %s", userId)
+		return nil, err
+	}
+
+	pkgLog.Debug("got %d api keys of user %s", len(res), userId)
+	return res, nil
+}
+
+func (a *ApiKeyService) RevokeApiKey(ctx context.Context, userId string, id string) (models.ApiKey, error) {
+	pkgLog.Debug("revoking api key %s of user %s", id, userId)
+	res, err := a.apiKeyRepo.RevokeApiKey(ctx, userId, id)
+	if err != nil {
+		pkgLog.Error(err, "failed to revoke api key %s of user %s", id, userId)
+		return models.ApiKey{}, err
+	}
+
+	pkgLog.Debug("revoked api key %s of user %s", id, userId)
+	return res, nil
+}
+
+// Authenticate resolves a raw key to its api key. Unknown and revoked keys
+// are rejected with the same error.
+func (a *ApiKeyService) Authenticate(ctx context.Context, rawKey string) (models.ApiKey, error) {
+	if rawKey == "" {
+		return models.ApiKey{}, models.InvalidApiKeyError
+	}
+	if a.cfg.AdminKey != "" && subtle.ConstantTimeCompare([]byte(rawKey), []byte(a.cfg.AdminKey)) == 1 {
+		return models.ApiKey{
+			Name:   "admin",
+			Scopes: []models.Scope{models.ScopeAdmin},
+		}, nil
+	}
+
+	key, err := a.apiKeyRepo.GetApiKeyByHash(ctx, hashKey(rawKey))
+	if err != nil {
+		if errors.Is(err, models.ApiKeyNotExistError) {
+			return models.ApiKey{}, models.InvalidApiKeyError
+		}
+
+		pkgLog.Error(err, "failed to get api key")
+		return models.ApiKey{}, err
+	}
+	if key.IsRevoked() {
+		return models.ApiKey{}, models.InvalidApiKeyError
+	}
+
+	return key, nil
+}
+
+// VerifySignature checks a request made with a key that requires signing.
+// The signature is checked before the nonce is stored, so unsigned requests
+// can not use up nonces of the client.
+func (a *ApiKeyService) VerifySignature(ctx context.Context, key models.ApiKey, req models.SignedRequest) error {
+	if req.Signature == "" || req.Timestamp == "" || req.Nonce == "" {
+		return models.MissingSignatureError
+	}
+	if len(req.Nonce) > maxNonceLength {
+		return models.InvalidNonceError
+	}
+
+	timestamp, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return models.InvalidTimestampError
+	}
+	skew := time.Since(time.Unix(timestamp, 0))
+	if skew > a.cfg.MaxClockSkew || skew < -a.cfg.MaxClockSkew {
+		return models.ExpiredSignatureError
+	}
+
+	expected := SignRequest(key.SigningSecret, req)
+	if !hmac.Equal([]byte(expected), []byte(req.Signature)) {
+		return models.InvalidSignatureError
+	}
+
+	fresh, err := a.nonceRepo.ReserveNonce(ctx, key.Prefix+":"+req.Nonce, 2*a.cfg.MaxClockSkew)
+	if err != nil {
+		pkgLog.Error(err, "failed to store nonce of api key %s", key.Prefix)
+		return err
+	}
+	if !fresh {
+		return models.ReplayedRequestError
+	}
+
+	return nil
+}
+
+// hashKey hashes a raw key for storage. Keys are random, so a fast hash is
+// enough to make a leaked table useless.
+func hashKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func newRandom(prefix string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return prefix + hex.EncodeToString(b), nil
+}
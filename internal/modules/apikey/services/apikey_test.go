@@ -0,0 +1,439 @@
+package services_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/mocks"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/services"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func hash(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestNewApiKeyService(t *testing.T) {
+	t.Run("should return WeakAdminKeyError when admin key is a placeholder", func(t *testing.T) {
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		_, actualErr := services.NewApiKeyService(services.ApiKeyServiceConfig{AdminKey: "change-me"}, mockRepo, mockNonce)
+
+		assert.ErrorIs(t, actualErr, models.WeakAdminKeyError)
+	})
+
+	t.Run("should return WeakAdminKeyError when admin key is too short", func(t *testing.T) {
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		_, actualErr := services.NewApiKeyService(services.ApiKeyServiceConfig{AdminKey: "bootstrap"}, mockRepo, mockNonce)
+
+		assert.ErrorIs(t, actualErr, models.WeakAdminKeyError)
+	})
+
+	t.Run("should accept empty admin key", func(t *testing.T) {
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		_, actualErr := services.NewApiKeyService(services.ApiKeyServiceConfig{}, mockRepo, mockNonce)
+
+		assert.NoError(t, actualErr)
+	})
+}
+
+func TestApiKeyService_CreateApiKey(t *testing.T) {
+	t.Run("should store hash of generated key and return raw key once", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		var storedKey models.ApiKey
+		mockRepo.EXPECT().
+			CreateApiKey(ctx, mock.Anything).
+			RunAndReturn(func(_ context.Context, key models.ApiKey) (models.ApiKey, error) {
+				storedKey = key
+				key.Entity = &shared.Entity{ID: "3"}
+				return key, nil
+			}).
+			Once()
+
+		service, _ := services.NewApiKeyService(services.ApiKeyServiceConfig{}, mockRepo, mockNonce)
+		actualKey, actualRawKey, actualErr := service.CreateApiKey(ctx, models.ApiKey{
+			UserId: "1",
+			Name:   "backend",
+			Scopes: []models.Scope{models.ScopeSend, models.ScopeRead, models.ScopeSend},
+		})
+
+		assert.NoError(t, actualErr)
+		assert.True(t, strings.HasPrefix(actualRawKey, "sgw_"))
+		assert.Equal(t, hash(actualRawKey), storedKey.Hash)
+		assert.Equal(t, actualRawKey[:12], storedKey.Prefix)
+		assert.Equal(t, []models.Scope{models.ScopeRead, models.ScopeSend}, storedKey.Scopes)
+		assert.Equal(t, "3", actualKey.ID)
+	})
+
+	t.Run("should generate signing secret when key requires signature", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		mockRepo.EXPECT().
+			CreateApiKey(ctx, mock.MatchedBy(func(key models.ApiKey) bool {
+				return key.RequireSignature && strings.HasPrefix(key.SigningSecret, "sgs_")
+			})).
+			RunAndReturn(func(_ context.Context, key models.ApiKey) (models.ApiKey, error) {
+				return key, nil
+			}).
+			Once()
+
+		service, _ := services.NewApiKeyService(services.ApiKeyServiceConfig{}, mockRepo, mockNonce)
+		actualKey, _, actualErr := service.CreateApiKey(ctx, models.ApiKey{
+			UserId:           "1",
+			Scopes:           []models.Scope{models.ScopeSend},
+			RequireSignature: true,
+		})
+
+		assert.NoError(t, actualErr)
+		assert.Len(t, actualKey.SigningSecret, 68)
+	})
+
+	t.Run("should return EmptyScopesError when no scope is given", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		service, _ := services.NewApiKeyService(services.ApiKeyServiceConfig{}, mockRepo, mockNonce)
+		_, _, actualErr := service.CreateApiKey(ctx, models.ApiKey{UserId: "1"})
+
+		assert.Equal(t, models.EmptyScopesError, actualErr)
+	})
+
+	t.Run("should return InvalidScopeError when scope is unknown", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		service, _ := services.NewApiKeyService(services.ApiKeyServiceConfig{}, mockRepo, mockNonce)
+		_, _, actualErr := service.CreateApiKey(ctx, models.ApiKey{
+			UserId: "1",
+			Scopes: []models.Scope{"root"},
+		})
+
+		assert.Equal(t, models.InvalidScopeError, actualErr)
+	})
+}
+
+func TestApiKeyService_Authenticate(t *testing.T) {
+	t.Run("should resolve raw key by its hash", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		expectedKey := models.ApiKey{
+			Entity: &shared.Entity{ID: "3"},
+			UserId: "1",
+			Scopes: []models.Scope{models.ScopeRead},
+		}
+		mockRepo.EXPECT().
+			GetApiKeyByHash(ctx, hash("sgw_test")).
+			Return(expectedKey, nil).
+			Once()
+
+		service, _ := services.NewApiKeyService(services.ApiKeyServiceConfig{}, mockRepo, mockNonce)
+		actualKey, actualErr := service.Authenticate(ctx, "sgw_test")
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedKey, actualKey)
+	})
+
+	t.Run("should return InvalidApiKeyError when key is unknown", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		mockRepo.EXPECT().
+			GetApiKeyByHash(ctx, hash("sgw_test")).
+			Return(models.ApiKey{}, models.ApiKeyNotExistError).
+			Once()
+
+		service, _ := services.NewApiKeyService(services.ApiKeyServiceConfig{}, mockRepo, mockNonce)
+		_, actualErr := service.Authenticate(ctx, "sgw_test")
+
+		assert.Equal(t, models.InvalidApiKeyError, actualErr)
+	})
+
+	t.Run("should return InvalidApiKeyError when key is revoked", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		mockRepo.EXPECT().
+			GetApiKeyByHash(ctx, hash("sgw_test")).
+			Return(models.ApiKey{UserId: "1", RevokedAt: time.Now()}, nil).
+			Once()
+
+		service, _ := services.NewApiKeyService(services.ApiKeyServiceConfig{}, mockRepo, mockNonce)
+		_, actualErr := service.Authenticate(ctx, "sgw_test")
+
+		assert.Equal(t, models.InvalidApiKeyError, actualErr)
+	})
+
+	t.Run("should return error when repository fails", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		expectedErr := errors.New("test error")
+		mockRepo.EXPECT().
+			GetApiKeyByHash(ctx, mock.Anything).
+			Return(models.ApiKey{}, expectedErr).
+			Once()
+
+		service, _ := services.NewApiKeyService(services.ApiKeyServiceConfig{}, mockRepo, mockNonce)
+		_, actualErr := service.Authenticate(ctx, "sgw_test")
+
+		assert.Equal(t, expectedErr, actualErr)
+	})
+
+	t.Run("should resolve configured admin key without repository", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		service, _ := services.NewApiKeyService(services.ApiKeyServiceConfig{AdminKey: "4f1c9e2b7a6d83c05e19b2f4a7c6d8e1"}, mockRepo, mockNonce)
+		actualKey, actualErr := service.Authenticate(ctx, "4f1c9e2b7a6d83c05e19b2f4a7c6d8e1")
+
+		assert.NoError(t, actualErr)
+		assert.True(t, actualKey.HasScope(models.ScopeBilling))
+		assert.True(t, actualKey.CanActOn("42"))
+	})
+
+	t.Run("should return InvalidApiKeyError when key is empty", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		service, _ := services.NewApiKeyService(services.ApiKeyServiceConfig{}, mockRepo, mockNonce)
+		_, actualErr := service.Authenticate(ctx, "")
+
+		assert.Equal(t, models.InvalidApiKeyError, actualErr)
+	})
+}
+
+func TestSignRequest(t *testing.T) {
+	t.Run("should sign method, path, timestamp, nonce and body hash", func(t *testing.T) {
+		actualSignature := services.SignRequest("sgs_secret", models.SignedRequest{
+			Method:    "POST",
+			Path:      "/api/user/1/sms/single",
+			Timestamp: "1700000000",
+			Nonce:     "nonce-1",
+			Body:      []byte(`{"receiver":"09123456789","content":"hi"}`),
+		})
+
+		assert.Equal(t, "9fca4ab246594aa80571ac94d9ee74721e85602e9160eff845731422d61cdeaf", actualSignature)
+	})
+}
+
+func TestApiKeyService_VerifySignature(t *testing.T) {
+	key := models.ApiKey{
+		UserId:           "1",
+		Prefix:           "sgw_01234567",
+		RequireSignature: true,
+		SigningSecret:    "sgs_secret",
+	}
+
+	t.Run("should accept signed request and reserve its nonce", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		mockNonce.EXPECT().
+			ReserveNonce(ctx, "sgw_01234567:nonce-1", 10*time.Minute).
+			Return(true, nil).
+			Once()
+
+		req := models.SignedRequest{
+			Method:    "POST",
+			Path:      "/api/user/1/sms/single",
+			Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+			Nonce:     "nonce-1",
+			Body:      []byte(`{"receiver":"09123456789","content":"hi"}`),
+		}
+		req.Signature = services.SignRequest(key.SigningSecret, req)
+
+		service, _ := services.NewApiKeyService(services.ApiKeyServiceConfig{}, mockRepo, mockNonce)
+		actualErr := service.VerifySignature(ctx, key, req)
+
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("should return MissingSignatureError when headers are missing", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		req := models.SignedRequest{
+			Method:    "POST",
+			Path:      "/api/user/1/sms/single",
+			Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+			Nonce:     "nonce-1",
+			Body:      []byte(`{"receiver":"09123456789","content":"hi"}`),
+		}
+		req.Signature = services.SignRequest(key.SigningSecret, req)
+		req.Nonce = ""
+
+		service, _ := services.NewApiKeyService(services.ApiKeyServiceConfig{}, mockRepo, mockNonce)
+		actualErr := service.VerifySignature(ctx, key, req)
+
+		assert.Equal(t, models.MissingSignatureError, actualErr)
+	})
+
+	t.Run("should return InvalidTimestampError when timestamp is not unix seconds", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		req := models.SignedRequest{
+			Method:    "POST",
+			Path:      "/api/user/1/sms/single",
+			Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+			Nonce:     "nonce-1",
+			Body:      []byte(`{"receiver":"09123456789","content":"hi"}`),
+		}
+		req.Signature = services.SignRequest(key.SigningSecret, req)
+		req.Timestamp = "yesterday"
+
+		service, _ := services.NewApiKeyService(services.ApiKeyServiceConfig{}, mockRepo, mockNonce)
+		actualErr := service.VerifySignature(ctx, key, req)
+
+		assert.Equal(t, models.InvalidTimestampError, actualErr)
+	})
+
+	t.Run("should return ExpiredSignatureError when timestamp is outside clock skew", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		pastReq := models.SignedRequest{
+			Method:    "POST",
+			Path:      "/api/user/1/sms/single",
+			Timestamp: strconv.FormatInt(time.Now().Add(-2*time.Minute).Unix(), 10),
+			Nonce:     "nonce-1",
+			Body:      []byte(`{"receiver":"09123456789","content":"hi"}`),
+		}
+		pastReq.Signature = services.SignRequest(key.SigningSecret, pastReq)
+
+		futureReq := models.SignedRequest{
+			Method:    "POST",
+			Path:      "/api/user/1/sms/single",
+			Timestamp: strconv.FormatInt(time.Now().Add(2*time.Minute).Unix(), 10),
+			Nonce:     "nonce-1",
+			Body:      []byte(`{"receiver":"09123456789","content":"hi"}`),
+		}
+		futureReq.Signature = services.SignRequest(key.SigningSecret, futureReq)
+
+		service, _ := services.NewApiKeyService(services.ApiKeyServiceConfig{MaxClockSkew: time.Minute}, mockRepo, mockNonce)
+
+		actualErr := service.VerifySignature(ctx, key, pastReq)
+		assert.Equal(t, models.ExpiredSignatureError, actualErr)
+
+		actualErr = service.VerifySignature(ctx, key, futureReq)
+		assert.Equal(t, models.ExpiredSignatureError, actualErr)
+	})
+
+	t.Run("should return InvalidSignatureError when body was changed", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		req := models.SignedRequest{
+			Method:    "POST",
+			Path:      "/api/user/1/sms/single",
+			Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+			Nonce:     "nonce-1",
+			Body:      []byte(`{"receiver":"09123456789","content":"hi"}`),
+		}
+		req.Signature = services.SignRequest(key.SigningSecret, req)
+		req.Body = []byte(`{"receiver":"09123456789","content":"bye"}`)
+
+		service, _ := services.NewApiKeyService(services.ApiKeyServiceConfig{}, mockRepo, mockNonce)
+		actualErr := service.VerifySignature(ctx, key, req)
+
+		assert.Equal(t, models.InvalidSignatureError, actualErr)
+	})
+
+	t.Run("should return ReplayedRequestError when nonce was used", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		mockNonce.EXPECT().
+			ReserveNonce(ctx, "sgw_01234567:nonce-1", mock.Anything).
+			Return(false, nil).
+			Once()
+
+		req := models.SignedRequest{
+			Method:    "POST",
+			Path:      "/api/user/1/sms/single",
+			Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+			Nonce:     "nonce-1",
+			Body:      []byte(`{"receiver":"09123456789","content":"hi"}`),
+		}
+		req.Signature = services.SignRequest(key.SigningSecret, req)
+
+		service, _ := services.NewApiKeyService(services.ApiKeyServiceConfig{}, mockRepo, mockNonce)
+		actualErr := service.VerifySignature(ctx, key, req)
+
+		assert.Equal(t, models.ReplayedRequestError, actualErr)
+	})
+
+	t.Run("should return error when nonce repository fails", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIApiKeyRepository(t)
+		mockNonce := mocks.NewMockINonceRepository(t)
+
+		expectedErr := errors.New("test error")
+		mockNonce.EXPECT().
+			ReserveNonce(ctx, mock.Anything, mock.Anything).
+			Return(false, expectedErr).
+			Once()
+
+		req := models.SignedRequest{
+			Method:    "POST",
+			Path:      "/api/user/1/sms/single",
+			Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+			Nonce:     "nonce-1",
+			Body:      []byte(`{"receiver":"09123456789","content":"hi"}`),
+		}
+		req.Signature = services.SignRequest(key.SigningSecret, req)
+
+		service, _ := services.NewApiKeyService(services.ApiKeyServiceConfig{}, mockRepo, mockNonce)
+		actualErr := service.VerifySignature(ctx, key, req)
+
+		assert.Equal(t, expectedErr, actualErr)
+	})
+}
+
+func TestApiKey_CanActOn(t *testing.T) {
+	t.Run("should only allow own user unless key is admin", func(t *testing.T) {
+		key := models.ApiKey{UserId: "1", Scopes: []models.Scope{models.ScopeSend, models.ScopeRead}}
+
+		assert.True(t, key.CanActOn("1"))
+		assert.False(t, key.CanActOn("2"))
+		assert.True(t, key.HasScope(models.ScopeSend))
+		assert.False(t, key.HasScope(models.ScopeBilling))
+		assert.True(t, key.CanGrant([]models.Scope{models.ScopeRead}))
+		assert.False(t, key.CanGrant([]models.Scope{models.ScopeRead, models.ScopeAdmin}))
+	})
+}
@@ -0,0 +1,10 @@
+package models
+
+import "errors"
+
+var (
+	EmptyKeyError      = errors.New("idempotency key is empty")
+	InvalidKeyError    = errors.New("idempotency key is too long")
+	KeyInProgressError = errors.New("a request with this idempotency key is in progress")
+	KeyMismatchError   = errors.New("idempotency key was used with a different request")
+)
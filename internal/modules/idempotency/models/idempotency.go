@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+)
+
+type KeyStatus int
+
+const (
+	KeyInProgress KeyStatus = iota
+	KeyCompleted
+)
+
+// IdempotencyKey is a client supplied key of a request. Keys are unique
+// within their scope, and the response of a completed request is kept until
+// the key expires so retries of the same request can replay it.
+type IdempotencyKey struct {
+	*shared.CreateDate
+
+	Scope       string
+	Key         string
+	RequestHash string
+	Status      KeyStatus
+	StatusCode  int
+	Response    []byte
+	ExpiresAt   time.Time
+}
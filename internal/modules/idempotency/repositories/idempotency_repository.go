@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/idempotency/models"
+)
+
+type IIdempotencyRepository interface {
+	// ReserveKey stores key unless its scope already holds an unexpired key
+	// with the same value, in which case the stored key is returned with false.
+	ReserveKey(ctx context.Context, key models.IdempotencyKey) (models.IdempotencyKey, bool, error)
+	CompleteKey(ctx context.Context, key models.IdempotencyKey) error
+	ReleaseKey(ctx context.Context, scope string, key string) error
+	DeleteExpiredKeys(ctx context.Context, at time.Time) (int, error)
+}
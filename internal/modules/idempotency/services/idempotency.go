@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/idempotency/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/idempotency/repositories"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+)
+
+const maxKeyLength = 255
+
+type IdempotencyServiceConfig struct {
+	// TTL is how long the response of a completed request is replayed.
+	TTL time.Duration `mapstructure:"ttl"`
+	// LockTimeout bounds how long a request in progress holds its key, so a
+	// key of a crashed request can be reused.
+	LockTimeout time.Duration `mapstructure:"lock_timeout"`
+}
+
+type IIdempotencyService interface {
+	Begin(ctx context.Context, scope string, key string, requestHash string) (models.IdempotencyKey, error)
+	Complete(ctx context.Context, scope string, key string, statusCode int, response []byte) error
+	Release(ctx context.Context, scope string, key string) error
+	PurgeExpired(ctx context.Context) (int, error)
+}
+
+type IdempotencyService struct {
+	idempotencyRepo repositories.IIdempotencyRepository
+	cfg             IdempotencyServiceConfig
+}
+
+func NewIdempotencyService(
+	cfg IdempotencyServiceConfig,
+	idempotencyRepo repositories.IIdempotencyRepository,
+) *IdempotencyService {
+	if cfg.TTL <= 0 {
+		cfg.TTL = 24 * time.Hour
+	}
+	if cfg.LockTimeout <= 0 {
+		cfg.LockTimeout = time.Minute
+	}
+
+	return &IdempotencyService{
+		cfg:             cfg,
+		idempotencyRepo: idempotencyRepo,
+	}
+}
+
+// Begin reserves key for a request. A newly reserved key is returned in
+// progress and the request should be processed, while a completed key holds
+// the response to replay.
+func (i *IdempotencyService) Begin(
+	ctx context.Context,
+	scope string,
+	key string,
+	requestHash string,
+) (models.IdempotencyKey, error) {
+	pkgLog.Debug("beginning request with idempotency key %s on %s", key, scope)
+	if key == "" {
+		pkgLog.Error(models.EmptyKeyError, "empty idempotency key")
+		return models.IdempotencyKey{}, models.EmptyKeyError
+	}
+	if len(key) > maxKeyLength {
+		pkgLog.Error(models.InvalidKeyError, "idempotency key longer than %d", maxKeyLength)
+		return models.IdempotencyKey{}, models.InvalidKeyError
+	}
+
+	now := time.Now()
+	res, reserved, err := i.idempotencyRepo.ReserveKey(ctx, models.IdempotencyKey{
+		CreateDate: &shared.CreateDate{
+			CreatedAt: now,
+		},
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		Status:      models.KeyInProgress,
+		ExpiresAt:   now.Add(i.cfg.LockTimeout),
+	})
+	if err != nil {
+		pkgLog.Error(err, "failed to reserve idempotency key %s on %s", key, scope)
+		return models.IdempotencyKey{}, err
+	}
+	if reserved {
+		pkgLog.Debug("reserved idempotency key %s on %s", key, scope)
+		return res, nil
+	}
+
+	if res.RequestHash != requestHash {
+		pkgLog.Error(models.KeyMismatchError, "idempotency key %s on %s reused with another request", key, scope)
+		return models.IdempotencyKey{}, models.KeyMismatchError
+	}
+	if res.Status != models.KeyCompleted {
+		pkgLog.Error(models.KeyInProgressError, "idempotency key %s on %s is in progress", key, scope)
+		return models.IdempotencyKey{}, models.KeyInProgressError
+	}
+
+	pkgLog.Debug("replaying idempotency key %s on %s", key, scope)
+	return res, nil
+}
+
+// Complete stores the response of a request and keeps it for the configured TTL.
+func (i *IdempotencyService) Complete(
+	ctx context.Context,
+	scope string,
+	key string,
+	statusCode int,
+	response []byte,
+) error {
+	pkgLog.Debug("completing idempotency key %s on %s", key, scope)
+	err := i.idempotencyRepo.CompleteKey(ctx, models.IdempotencyKey{
+		Scope:      scope,
+		Key:        key,
+		Status:     models.KeyCompleted,
+		StatusCode: statusCode,
+		Response:   response,
+		ExpiresAt:  time.Now().Add(i.cfg.TTL),
+	})
+	if err != nil {
+		pkgLog.Error(err, "failed to complete idempotency key %s on %s", key, scope)
+		return err
+	}
+
+	pkgLog.Debug("completed idempotency key %s on %s", key, scope)
+	return nil
+}
+
+// Release frees a key in progress so the request can be retried with it.
+func (i *IdempotencyService) Release(ctx context.Context, scope string, key string) error {
+	pkgLog.Debug("releasing idempotency key %s on %s", key, scope)
+	if err := i.idempotencyRepo.ReleaseKey(ctx, scope, key); err != nil {
+		pkgLog.Error(err, "failed to release idempotency key %s on %s", key, scope)
+		return err
+	}
+
+	pkgLog.Debug("released idempotency key %s on %s", key, scope)
+	return nil
+}
+
+func (i *IdempotencyService) PurgeExpired(ctx context.Context) (int, error) {
+	pkgLog.Debug("purging expired idempotency keys")
+	purged, err := i.idempotencyRepo.DeleteExpiredKeys(ctx, time.Now())
+	if err != nil {
+		pkgLog.Error(err, "failed to purge expired idempotency keys")
+		return 0, err
+	}
+
+	pkgLog.Debug("purged %d expired idempotency keys", purged)
+	return purged, nil
+}
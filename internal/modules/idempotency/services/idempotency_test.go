@@ -0,0 +1,217 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/idempotency/mocks"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/idempotency/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/idempotency/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	scope = "POST /api/user/1/sms/single"
+	key   = "b1f6c1d2"
+	hash  = "hash"
+)
+
+var cfg = services.IdempotencyServiceConfig{
+	TTL:         time.Hour,
+	LockTimeout: time.Minute,
+}
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	t.Run("should reserve new key in progress until lock timeout", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIIdempotencyRepository(t)
+
+		var reservedKey models.IdempotencyKey
+		mockRepo.EXPECT().
+			ReserveKey(ctx, mock.Anything).
+			RunAndReturn(func(_ context.Context, k models.IdempotencyKey) (models.IdempotencyKey, bool, error) {
+				reservedKey = k
+				return k, true, nil
+			}).
+			Once()
+
+		service := services.NewIdempotencyService(cfg, mockRepo)
+		actualKey, actualErr := service.Begin(ctx, scope, key, hash)
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.KeyInProgress, actualKey.Status)
+		assert.Equal(t, scope, reservedKey.Scope)
+		assert.Equal(t, key, reservedKey.Key)
+		assert.Equal(t, hash, reservedKey.RequestHash)
+		assert.Equal(t, time.Minute, reservedKey.ExpiresAt.Sub(reservedKey.CreatedAt))
+	})
+
+	t.Run("should return completed key to replay", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIIdempotencyRepository(t)
+
+		storedKey := models.IdempotencyKey{
+			Scope:       scope,
+			Key:         key,
+			RequestHash: hash,
+			Status:      models.KeyCompleted,
+			StatusCode:  200,
+			Response:    []byte(`{"data":null,"message":"ok"}`),
+		}
+		mockRepo.EXPECT().
+			ReserveKey(ctx, mock.Anything).
+			Return(storedKey, false, nil).
+			Once()
+
+		service := services.NewIdempotencyService(cfg, mockRepo)
+		actualKey, actualErr := service.Begin(ctx, scope, key, hash)
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, storedKey, actualKey)
+	})
+
+	t.Run("should return KeyMismatchError when request hash differs", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIIdempotencyRepository(t)
+
+		mockRepo.EXPECT().
+			ReserveKey(ctx, mock.Anything).
+			Return(models.IdempotencyKey{
+				Scope:       scope,
+				Key:         key,
+				RequestHash: "other",
+				Status:      models.KeyCompleted,
+			}, false, nil).
+			Once()
+
+		service := services.NewIdempotencyService(cfg, mockRepo)
+		_, actualErr := service.Begin(ctx, scope, key, hash)
+
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.KeyMismatchError, actualErr)
+	})
+
+	t.Run("should return KeyInProgressError when key is in progress", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIIdempotencyRepository(t)
+
+		mockRepo.EXPECT().
+			ReserveKey(ctx, mock.Anything).
+			Return(models.IdempotencyKey{
+				Scope:       scope,
+				Key:         key,
+				RequestHash: hash,
+				Status:      models.KeyInProgress,
+			}, false, nil).
+			Once()
+
+		service := services.NewIdempotencyService(cfg, mockRepo)
+		_, actualErr := service.Begin(ctx, scope, key, hash)
+
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.KeyInProgressError, actualErr)
+	})
+
+	t.Run("should return EmptyKeyError when key is empty", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIIdempotencyRepository(t)
+
+		service := services.NewIdempotencyService(cfg, mockRepo)
+		_, actualErr := service.Begin(ctx, scope, "", hash)
+
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.EmptyKeyError, actualErr)
+	})
+
+	t.Run("should return InvalidKeyError when key is too long", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIIdempotencyRepository(t)
+
+		service := services.NewIdempotencyService(cfg, mockRepo)
+		_, actualErr := service.Begin(ctx, scope, strings.Repeat("k", 256), hash)
+
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.InvalidKeyError, actualErr)
+	})
+
+	t.Run("should return error when repository fails", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIIdempotencyRepository(t)
+
+		expectedErr := errors.New("test error")
+		mockRepo.EXPECT().
+			ReserveKey(ctx, mock.Anything).
+			Return(models.IdempotencyKey{}, false, expectedErr).
+			Once()
+
+		service := services.NewIdempotencyService(cfg, mockRepo)
+		_, actualErr := service.Begin(ctx, scope, key, hash)
+
+		assert.Error(t, actualErr)
+		assert.Equal(t, expectedErr, actualErr)
+	})
+}
+
+func TestIdempotencyService_Complete(t *testing.T) {
+	t.Run("should store response until ttl", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIIdempotencyRepository(t)
+
+		response := []byte(`{"data":null,"message":"ok"}`)
+		mockRepo.EXPECT().
+			CompleteKey(ctx, mock.MatchedBy(func(k models.IdempotencyKey) bool {
+				return k.Scope == scope &&
+					k.Key == key &&
+					k.Status == models.KeyCompleted &&
+					k.StatusCode == 200 &&
+					string(k.Response) == string(response) &&
+					time.Until(k.ExpiresAt) > 59*time.Minute
+			})).
+			Return(nil).
+			Once()
+
+		service := services.NewIdempotencyService(cfg, mockRepo)
+		actualErr := service.Complete(ctx, scope, key, 200, response)
+
+		assert.NoError(t, actualErr)
+	})
+}
+
+func TestIdempotencyService_Release(t *testing.T) {
+	t.Run("should release key", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIIdempotencyRepository(t)
+
+		mockRepo.EXPECT().
+			ReleaseKey(ctx, scope, key).
+			Return(nil).
+			Once()
+
+		service := services.NewIdempotencyService(cfg, mockRepo)
+		actualErr := service.Release(ctx, scope, key)
+
+		assert.NoError(t, actualErr)
+	})
+}
+
+func TestIdempotencyService_PurgeExpired(t *testing.T) {
+	t.Run("should delete expired keys", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIIdempotencyRepository(t)
+
+		mockRepo.EXPECT().
+			DeleteExpiredKeys(ctx, mock.Anything).
+			Return(3, nil).
+			Once()
+
+		service := services.NewIdempotencyService(cfg, mockRepo)
+		actualPurged, actualErr := service.PurgeExpired(ctx)
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 3, actualPurged)
+	})
+}
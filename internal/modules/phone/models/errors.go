@@ -0,0 +1,12 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	InvalidPhoneNumberError = errors.New("invalid phone number")
+	UnsupportedCountryError = fmt.Errorf("%w: country is not supported", InvalidPhoneNumberError)
+	UnallocatedRangeError   = fmt.Errorf("%w: range is not allocated", InvalidPhoneNumberError)
+)
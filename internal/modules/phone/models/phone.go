@@ -0,0 +1,12 @@
+package models
+
+// PhoneNumber is a receiver normalized to E.164 with the country and mobile
+// operator its range is allocated to.
+type PhoneNumber struct {
+	// Number is the E.164 form of the receiver, such as +989121234567.
+	Number string
+	// Country is the ISO 3166-1 alpha-2 code of the country.
+	Country string
+	// Operator is empty when the numbering plan of the country has no ranges.
+	Operator string
+}
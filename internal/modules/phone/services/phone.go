@@ -0,0 +1,188 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/phone/models"
+
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+)
+
+// maxE164Digits is the longest number E.164 allows, country code included.
+const maxE164Digits = 15
+
+type RangeConfig struct {
+	Operator string `mapstructure:"operator"`
+	// Prefixes are the national number prefixes allocated to the operator.
+	Prefixes []string `mapstructure:"prefixes"`
+}
+
+// CountryConfig is the numbering plan of a country. Numbers of a country
+// without ranges are only checked by their length.
+type CountryConfig struct {
+	Code        string `mapstructure:"code"`
+	CallingCode string `mapstructure:"calling_code"`
+	// TrunkPrefix is dialed before national numbers, such as the 0 of
+	// 09121234567.
+	TrunkPrefix string `mapstructure:"trunk_prefix"`
+	// Lengths are the allowed lengths of national numbers, trunk prefix
+	// excluded.
+	Lengths []int         `mapstructure:"lengths"`
+	Ranges  []RangeConfig `mapstructure:"ranges"`
+}
+
+type PhoneServiceConfig struct {
+	// DefaultCountry is the country of numbers without a country code. Such
+	// numbers are rejected when it is empty.
+	DefaultCountry string          `mapstructure:"default_country"`
+	Countries      []CountryConfig `mapstructure:"countries"`
+}
+
+type IPhoneService interface {
+	// Normalize returns the E.164 form of receiver, or InvalidPhoneNumberError
+	// when it is not a number in an allocated range of a supported country.
+	Normalize(receiver string) (models.PhoneNumber, error)
+}
+
+type PhoneService struct {
+	countries      []CountryConfig
+	defaultCountry *CountryConfig
+}
+
+func NewPhoneService(cfg PhoneServiceConfig) (*PhoneService, error) {
+	s := &PhoneService{
+		countries: slices.Clone(cfg.Countries),
+	}
+	// longer calling codes first, so +1 does not shadow +1268
+	slices.SortStableFunc(s.countries, func(a, b CountryConfig) int {
+		return len(b.CallingCode) - len(a.CallingCode)
+	})
+
+	if cfg.DefaultCountry != "" {
+		i := slices.IndexFunc(s.countries, func(c CountryConfig) bool {
+			return strings.EqualFold(c.Code, cfg.DefaultCountry)
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("%w: default country %s has no numbering plan", models.UnsupportedCountryError, cfg.DefaultCountry)
+		}
+		s.defaultCountry = &s.countries[i]
+	}
+
+	return s, nil
+}
+
+func (s *PhoneService) Normalize(receiver string) (models.PhoneNumber, error) {
+	number := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '(', ')', '.':
+			return -1
+		default:
+			return r
+		}
+	}, receiver)
+
+	international := false
+	switch {
+	case strings.HasPrefix(number, "+"):
+		number = number[1:]
+		international = true
+	case strings.HasPrefix(number, "00"):
+		number = number[2:]
+		international = true
+	}
+	if number == "" || strings.Trim(number, "0123456789") != "" {
+		pkgLog.Debug("receiver %s is not a phone number", receiver)
+		return models.PhoneNumber{}, models.InvalidPhoneNumberError
+	}
+
+	var res models.PhoneNumber
+	var err error
+	if international {
+		res, err = s.normalizeInternational(number)
+	} else {
+		res, err = s.normalizeNational(number)
+	}
+	if err != nil {
+		pkgLog.Debug("receiver %s is not a valid phone number: %s", receiver, err.Error())
+		return models.PhoneNumber{}, err
+	}
+
+	return res, nil
+}
+
+func (s *PhoneService) normalizeInternational(number string) (models.PhoneNumber, error) {
+	err := models.UnsupportedCountryError
+	for i := range s.countries {
+		country := &s.countries[i]
+		if !strings.HasPrefix(number, country.CallingCode) {
+			continue
+		}
+
+		// countries sharing a calling code are told apart by their ranges
+		res, matchErr := matchNumber(country, number[len(country.CallingCode):])
+		if matchErr == nil {
+			return res, nil
+		}
+		err = matchErr
+	}
+
+	return models.PhoneNumber{}, err
+}
+
+func (s *PhoneService) normalizeNational(number string) (models.PhoneNumber, error) {
+	country := s.defaultCountry
+	if country == nil {
+		return models.PhoneNumber{}, fmt.Errorf("%w: number has no country code", models.UnsupportedCountryError)
+	}
+
+	if country.TrunkPrefix != "" && strings.HasPrefix(number, country.TrunkPrefix) {
+		return matchNumber(country, number[len(country.TrunkPrefix):])
+	}
+	// numbers of the default country written without the plus
+	if !validLength(country, number) && strings.HasPrefix(number, country.CallingCode) {
+		return matchNumber(country, number[len(country.CallingCode):])
+	}
+
+	return matchNumber(country, number)
+}
+
+// matchNumber checks the national number against the numbering plan of the
+// country, and finds the operator of its range.
+func matchNumber(country *CountryConfig, national string) (models.PhoneNumber, error) {
+	if national == "" || !validLength(country, national) {
+		return models.PhoneNumber{}, fmt.Errorf("%w: wrong length for %s", models.InvalidPhoneNumberError, country.Code)
+	}
+
+	res := models.PhoneNumber{
+		Number:  "+" + country.CallingCode + national,
+		Country: strings.ToUpper(country.Code),
+	}
+	if len(country.Ranges) == 0 {
+		return res, nil
+	}
+
+	longest := 0
+	for _, r := range country.Ranges {
+		for _, prefix := range r.Prefixes {
+			if len(prefix) > longest && strings.HasPrefix(national, prefix) {
+				res.Operator = r.Operator
+				longest = len(prefix)
+			}
+		}
+	}
+	if longest == 0 {
+		return models.PhoneNumber{}, fmt.Errorf("%w in %s", models.UnallocatedRangeError, country.Code)
+	}
+
+	return res, nil
+}
+
+func validLength(country *CountryConfig, national string) bool {
+	if len(country.CallingCode)+len(national) > maxE164Digits {
+		return false
+	}
+
+	return len(country.Lengths) == 0 || slices.Contains(country.Lengths, len(national))
+}
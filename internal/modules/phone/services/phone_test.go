@@ -0,0 +1,106 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/phone/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/phone/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhoneService_Normalize(t *testing.T) {
+	cfg := services.PhoneServiceConfig{
+		DefaultCountry: "IR",
+		Countries: []services.CountryConfig{
+			{
+				Code:        "IR",
+				CallingCode: "98",
+				TrunkPrefix: "0",
+				Lengths:     []int{10},
+				Ranges: []services.RangeConfig{
+					{Operator: "MCI", Prefixes: []string{"91", "990"}},
+					{Operator: "Irancell", Prefixes: []string{"90", "93"}},
+				},
+			},
+			{
+				Code:        "GB",
+				CallingCode: "44",
+				TrunkPrefix: "0",
+				Lengths:     []int{10},
+			},
+		},
+	}
+
+	service, err := services.NewPhoneService(cfg)
+	assert.NoError(t, err)
+
+	t.Run("should normalize national and international forms to same number", func(t *testing.T) {
+		expectedNumber := models.PhoneNumber{
+			Number:   "+989121234567",
+			Country:  "IR",
+			Operator: "MCI",
+		}
+
+		for _, receiver := range []string{"09121234567", "9121234567", "989121234567", "+989121234567", "00989121234567", "+98 912 123-4567"} {
+			actualNumber, actualErr := service.Normalize(receiver)
+			assert.NoError(t, actualErr, receiver)
+			assert.Equal(t, expectedNumber, actualNumber, receiver)
+		}
+	})
+
+	t.Run("should detect operator by longest range prefix", func(t *testing.T) {
+		actualNumber, actualErr := service.Normalize("09901234567")
+		assert.NoError(t, actualErr)
+		assert.Equal(t, "MCI", actualNumber.Operator)
+
+		actualNumber, actualErr = service.Normalize("09351234567")
+		assert.NoError(t, actualErr)
+		assert.Equal(t, "Irancell", actualNumber.Operator)
+	})
+
+	t.Run("should normalize number of country without ranges", func(t *testing.T) {
+		actualNumber, actualErr := service.Normalize("+447911123456")
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.PhoneNumber{Number: "+447911123456", Country: "GB"}, actualNumber)
+	})
+
+	t.Run("should return UnallocatedRangeError when range is not allocated", func(t *testing.T) {
+		_, actualErr := service.Normalize("09801234567")
+		assert.ErrorIs(t, actualErr, models.UnallocatedRangeError)
+		assert.ErrorIs(t, actualErr, models.InvalidPhoneNumberError)
+	})
+
+	t.Run("should return InvalidPhoneNumberError when length is wrong", func(t *testing.T) {
+		_, actualErr := service.Normalize("0912123456")
+		assert.ErrorIs(t, actualErr, models.InvalidPhoneNumberError)
+	})
+
+	t.Run("should return InvalidPhoneNumberError when receiver is not a number", func(t *testing.T) {
+		_, actualErr := service.Normalize("+98912abc4567")
+		assert.ErrorIs(t, actualErr, models.InvalidPhoneNumberError)
+	})
+
+	t.Run("should return UnsupportedCountryError when country has no numbering plan", func(t *testing.T) {
+		_, actualErr := service.Normalize("+4915112345678")
+		assert.ErrorIs(t, actualErr, models.UnsupportedCountryError)
+	})
+
+	t.Run("should return UnsupportedCountryError for national number without default country", func(t *testing.T) {
+		service, err := services.NewPhoneService(services.PhoneServiceConfig{Countries: cfg.Countries})
+		assert.NoError(t, err)
+
+		_, actualErr := service.Normalize("09121234567")
+		assert.ErrorIs(t, actualErr, models.UnsupportedCountryError)
+
+		actualNumber, actualErr := service.Normalize("+989121234567")
+		assert.NoError(t, actualErr)
+		assert.Equal(t, "+989121234567", actualNumber.Number)
+	})
+}
+
+func TestNewPhoneService(t *testing.T) {
+	t.Run("should return UnsupportedCountryError when default country has no numbering plan", func(t *testing.T) {
+		_, actualErr := services.NewPhoneService(services.PhoneServiceConfig{DefaultCountry: "IR"})
+		assert.ErrorIs(t, actualErr, models.UnsupportedCountryError)
+	})
+}
@@ -0,0 +1,12 @@
+package models
+
+import "errors"
+
+var (
+	EmptyPriceListNameError = errors.New("price list name is empty")
+	PriceListNotExistError  = errors.New("price list does not exist")
+	PriceNotExistError      = errors.New("price does not exist")
+	InvalidPrefixError      = errors.New("invalid price prefix")
+	InvalidPriceError       = errors.New("invalid price")
+	InvalidPriceScopeError  = errors.New("price must belong to either a price list or a user")
+)
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+)
+
+// PriceList is a customer plan, a set of segment prices by destination prefix.
+type PriceList struct {
+	*shared.Entity
+	*shared.CreateDate
+	*shared.UpdateDate
+
+	Name string
+	// IsDefault marks the price list used for users without an assigned one.
+	IsDefault bool
+}
+
+// Price is the price of one segment sent to receivers starting with Prefix.
+// It belongs either to a price list or, as an override, to a single user, and
+// applies from EffectiveFrom until a newer price of the same prefix does.
+type Price struct {
+	*shared.Entity
+	*shared.CreateDate
+
+	PriceListId   string
+	UserId        string
+	Prefix        string
+	Price         int
+	EffectiveFrom time.Time
+}
+
+type PriceFilter struct {
+	PriceListId string
+	UserId      string
+}
+
+// PriceBook holds the prices in effect for a user, by where they come from.
+type PriceBook struct {
+	Overrides []Price
+	Plan      []Price
+	Default   []Price
+}
+
+type PriceSource string
+
+const (
+	SourceOverride PriceSource = "override"
+	SourcePlan     PriceSource = "plan"
+	SourceDefault  PriceSource = "default"
+	SourceNone     PriceSource = "none"
+)
+
+// UnitPrice is the segment price resolved for a receiver. Source is
+// SourceNone when no price matches the receiver.
+type UnitPrice struct {
+	Receiver string
+	Prefix   string
+	Price    int
+	Source   PriceSource
+}
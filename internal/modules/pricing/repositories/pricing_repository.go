@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/pricing/models"
+)
+
+type IPricingRepository interface {
+	CreatePriceList(ctx context.Context, list models.PriceList) (models.PriceList, error)
+	GetPriceLists(ctx context.Context, skip int, limit int) ([]models.PriceList, error)
+	CreatePrices(ctx context.Context, prices []models.Price) ([]models.Price, error)
+	GetPrices(ctx context.Context, filter models.PriceFilter, skip int, limit int) ([]models.Price, error)
+	DeletePrice(ctx context.Context, id string) error
+	AssignUserPriceList(ctx context.Context, userId string, priceListId string) error
+	GetPriceBook(ctx context.Context, userId string, at time.Time) (models.PriceBook, error)
+}
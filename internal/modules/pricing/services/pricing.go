@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/pricing/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/pricing/repositories"
+
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+)
+
+const maxPrefixLength = 15
+
+type IPricingService interface {
+	CreatePriceList(ctx context.Context, list models.PriceList) (models.PriceList, error)
+	GetPriceLists(ctx context.Context, skip int, limit int) ([]models.PriceList, error)
+	AddPrices(ctx context.Context, prices []models.Price) ([]models.Price, error)
+	GetPrices(ctx context.Context, filter models.PriceFilter, skip int, limit int) ([]models.Price, error)
+	DeletePrice(ctx context.Context, id string) error
+	AssignUserPriceList(ctx context.Context, userId string, priceListId string) error
+	ResolvePrices(ctx context.Context, userId string, receivers []string, at time.Time) ([]models.UnitPrice, error)
+}
+
+type PricingService struct {
+	pricingRepo repositories.IPricingRepository
+}
+
+func NewPricingService(
+	pricingRepo repositories.IPricingRepository,
+) *PricingService {
+	return &PricingService{
+		pricingRepo: pricingRepo,
+	}
+}
+
+func (p *PricingService) CreatePriceList(ctx context.Context, list models.PriceList) (models.PriceList, error) {
+	pkgLog.Debug("creating price list with name %s", list.Name)
+	if list.Name == "" {
+		pkgLog.Error(models.EmptyPriceListNameError, "empty price list name")
+		return models.PriceList{}, models.EmptyPriceListNameError
+	}
+
+	res, err := p.pricingRepo.CreatePriceList(ctx, list)
+	if err != nil {
+		pkgLog.Error(err, "failed to create price list with name %s", list.Name)
+		return models.PriceList{}, err
+	}
+
+	pkgLog.Debug("created price list with name %s id %s", list.Name, res.ID)
+	return res, nil
+}
+
+func (p *PricingService) GetPriceLists(ctx context.Context, skip int, limit int) ([]models.PriceList, error) {
+	pkgLog.Debug("getting price lists")
+	res, err := p.pricingRepo.GetPriceLists(ctx, skip, limit)
+	if err != nil {
+		pkgLog.Error(err, "failed to get price lists")
+		return nil, err
+	}
+
+	pkgLog.Debug("got %d price lists", len(res))
+	return res, nil
+}
+
+// AddPrices validates and stores prices. Prices without an effective time
+// take effect immediately.
+func (p *PricingService) AddPrices(ctx context.Context, prices []models.Price) ([]models.Price, error) {
+	pkgLog.Debug("adding %d prices", len(prices))
+	now := time.Now()
+	for i := range prices {
+		if (prices[i].PriceListId == "") == (prices[i].UserId == "") {
+			pkgLog.Error(models.InvalidPriceScopeError, "invalid scope for price of prefix %s", prices[i].Prefix)
+			return nil, models.InvalidPriceScopeError
+		}
+		if !validPrefix(prices[i].Prefix) {
+			pkgLog.Error(models.InvalidPrefixError, "invalid price prefix %s", prices[i].Prefix)
+			return nil, models.InvalidPrefixError
+		}
+		if prices[i].Price < 0 {
+			pkgLog.Error(models.InvalidPriceError, "negative price for prefix %s", prices[i].Prefix)
+			return nil, models.InvalidPriceError
+		}
+		if prices[i].EffectiveFrom.IsZero() {
+			prices[i].EffectiveFrom = now
+		}
+	}
+
+	res, err := p.pricingRepo.CreatePrices(ctx, prices)
+	if err != nil {
+		pkgLog.Error(err, "failed to add %d prices", len(prices))
+		return nil, err
+	}
+
+	pkgLog.Debug("added %d prices", len(res))
+	return res, nil
+}
+
+func (p *PricingService) GetPrices(ctx context.Context, filter models.PriceFilter, skip int, limit int) ([]models.Price, error) {
+	pkgLog.Debug("getting prices of price list %s user %s", filter.PriceListId, filter.UserId)
+	res, err := p.pricingRepo.GetPrices(ctx, filter, skip, limit)
+	if err != nil {
+		pkgLog.Error(err, "failed to get prices of price list %s user %s", filter.PriceListId, filter.UserId)
+		return nil, err
+	}
+
+	pkgLog.Debug("got %d prices of price list %s user %s", len(res), filter.PriceListId, filter.UserId)
+	return res, nil
+}
+
+func (p *PricingService) DeletePrice(ctx context.Context, id string) error {
+	pkgLog.Debug("deleting price with id %s", id)
+	if err := p.pricingRepo.DeletePrice(ctx, id); err != nil {
+		pkgLog.Error(err, "failed to delete price with id %s", id)
+		return err
+	}
+
+	pkgLog.Debug("deleted price with id %s", id)
+	return nil
+}
+
+// AssignUserPriceList assigns a price list to the user, or unassigns the
+// current one when priceListId is empty.
+func (p *PricingService) AssignUserPriceList(ctx context.Context, userId string, priceListId string) error {
+	pkgLog.Debug("assigning price list %s to user id %s", priceListId, userId)
+	if err := p.pricingRepo.AssignUserPriceList(ctx, userId, priceListId); err != nil {
+		pkgLog.Error(err, "failed to assign price list %s to user id %s", priceListId, userId)
+		return err
+	}
+
+	pkgLog.Debug("assigned price list %s to user id %s", priceListId, userId)
+	return nil
+}
+
+// ResolvePrices returns the segment price in effect at the given time for
+// each receiver. User overrides come first, then the user price list, then
+// the default price list, and the longest matching prefix wins within each.
+func (p *PricingService) ResolvePrices(
+	ctx context.Context,
+	userId string,
+	receivers []string,
+	at time.Time,
+) ([]models.UnitPrice, error) {
+	pkgLog.Debug("resolving prices of %d receivers for user id %s", len(receivers), userId)
+	book, err := p.pricingRepo.GetPriceBook(ctx, userId, at)
+	if err != nil {
+		pkgLog.Error(err, "failed to get price book for user id %s", userId)
+		return nil, err
+	}
+
+	scopes := []struct {
+		source models.PriceSource
+		prices []models.Price
+	}{
+		{models.SourceOverride, book.Overrides},
+		{models.SourcePlan, book.Plan},
+		{models.SourceDefault, book.Default},
+	}
+
+	res := make([]models.UnitPrice, len(receivers))
+	for i := range receivers {
+		res[i] = models.UnitPrice{
+			Receiver: receivers[i],
+			Source:   models.SourceNone,
+		}
+
+		number := strings.TrimPrefix(receivers[i], "+")
+		for _, scope := range scopes {
+			price, ok := longestPrefixMatch(scope.prices, number)
+			if !ok {
+				continue
+			}
+
+			res[i].Prefix = price.Prefix
+			res[i].Price = price.Price
+			res[i].Source = scope.source
+			break
+		}
+	}
+
+	pkgLog.Debug("resolved prices of %d receivers for user id %s", len(receivers), userId)
+	return res, nil
+}
+
+func longestPrefixMatch(prices []models.Price, number string) (models.Price, bool) {
+	var match models.Price
+	found := false
+	for i := range prices {
+		if !strings.HasPrefix(number, prices[i].Prefix) {
+			continue
+		}
+		if !found || len(prices[i].Prefix) > len(match.Prefix) {
+			match = prices[i]
+			found = true
+		}
+	}
+
+	return match, found
+}
+
+func validPrefix(prefix string) bool {
+	if prefix == "" || len(prefix) > maxPrefixLength {
+		return false
+	}
+	for _, r := range prefix {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
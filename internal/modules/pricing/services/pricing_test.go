@@ -0,0 +1,264 @@
+package services_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/pricing/mocks"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/pricing/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/pricing/services"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPricingService_CreatePriceList(t *testing.T) {
+	t.Run("should create price list", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIPricingRepository(t)
+
+		inputList := models.PriceList{
+			Name:      "Standard",
+			IsDefault: true,
+		}
+		expectedList := models.PriceList{
+			Entity:    &shared.Entity{ID: "1"},
+			Name:      "Standard",
+			IsDefault: true,
+		}
+
+		mockRepo.EXPECT().
+			CreatePriceList(ctx, inputList).
+			Return(expectedList, nil).
+			Once()
+
+		service := services.NewPricingService(mockRepo)
+		actualList, actualErr := service.CreatePriceList(ctx, inputList)
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedList, actualList)
+	})
+
+	t.Run("should return EmptyPriceListNameError when name is empty", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIPricingRepository(t)
+
+		service := services.NewPricingService(mockRepo)
+		_, actualErr := service.CreatePriceList(ctx, models.PriceList{})
+
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.EmptyPriceListNameError, actualErr)
+	})
+}
+
+func TestPricingService_AddPrices(t *testing.T) {
+	t.Run("should add prices and make them effective now when no time is given", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIPricingRepository(t)
+
+		effectiveFrom := time.Now().Add(24 * time.Hour)
+		inputPrices := []models.Price{
+			{PriceListId: "1", Prefix: "98912", Price: 100},
+			{UserId: "2", Prefix: "1", Price: 500, EffectiveFrom: effectiveFrom},
+		}
+
+		mockRepo.EXPECT().
+			CreatePrices(ctx, mock.Anything).
+			RunAndReturn(func(ctx context.Context, prices []models.Price) ([]models.Price, error) {
+				assert.False(t, prices[0].EffectiveFrom.IsZero())
+				assert.Equal(t, effectiveFrom, prices[1].EffectiveFrom)
+				return prices, nil
+			}).
+			Once()
+
+		service := services.NewPricingService(mockRepo)
+		actualPrices, actualErr := service.AddPrices(ctx, inputPrices)
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 2, len(actualPrices))
+	})
+
+	t.Run("should return InvalidPriceScopeError when price has no or both scopes", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIPricingRepository(t)
+
+		service := services.NewPricingService(mockRepo)
+
+		_, actualErr := service.AddPrices(ctx, []models.Price{{Prefix: "98", Price: 100}})
+		assert.Equal(t, models.InvalidPriceScopeError, actualErr)
+
+		_, actualErr = service.AddPrices(ctx, []models.Price{{PriceListId: "1", UserId: "2", Prefix: "98", Price: 100}})
+		assert.Equal(t, models.InvalidPriceScopeError, actualErr)
+	})
+
+	t.Run("should return InvalidPrefixError when prefix is not digits", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIPricingRepository(t)
+
+		service := services.NewPricingService(mockRepo)
+
+		for _, prefix := range []string{"", "+98", "98a", "1234567890123456"} {
+			_, actualErr := service.AddPrices(ctx, []models.Price{{PriceListId: "1", Prefix: prefix, Price: 100}})
+			assert.Equal(t, models.InvalidPrefixError, actualErr)
+		}
+	})
+
+	t.Run("should return InvalidPriceError when price is negative", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIPricingRepository(t)
+
+		service := services.NewPricingService(mockRepo)
+		_, actualErr := service.AddPrices(ctx, []models.Price{{PriceListId: "1", Prefix: "98", Price: -1}})
+
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.InvalidPriceError, actualErr)
+	})
+
+	t.Run("should return error when can not create prices", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIPricingRepository(t)
+
+		mockRepo.EXPECT().
+			CreatePrices(ctx, mock.Anything).
+			Return(nil, models.PriceListNotExistError).
+			Once()
+
+		service := services.NewPricingService(mockRepo)
+		_, actualErr := service.AddPrices(ctx, []models.Price{{PriceListId: "1", Prefix: "98", Price: 100}})
+
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.PriceListNotExistError, actualErr)
+	})
+}
+
+func TestPricingService_DeletePrice(t *testing.T) {
+	t.Run("should delete price", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIPricingRepository(t)
+
+		mockRepo.EXPECT().
+			DeletePrice(ctx, "1").
+			Return(nil).
+			Once()
+
+		service := services.NewPricingService(mockRepo)
+		actualErr := service.DeletePrice(ctx, "1")
+
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("should return PriceNotExistError when price does not exist", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIPricingRepository(t)
+
+		mockRepo.EXPECT().
+			DeletePrice(ctx, "1").
+			Return(models.PriceNotExistError).
+			Once()
+
+		service := services.NewPricingService(mockRepo)
+		actualErr := service.DeletePrice(ctx, "1")
+
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.PriceNotExistError, actualErr)
+	})
+}
+
+func TestPricingService_AssignUserPriceList(t *testing.T) {
+	t.Run("should assign price list to user", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIPricingRepository(t)
+
+		mockRepo.EXPECT().
+			AssignUserPriceList(ctx, "1", "2").
+			Return(nil).
+			Once()
+
+		service := services.NewPricingService(mockRepo)
+		actualErr := service.AssignUserPriceList(ctx, "1", "2")
+
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("should return PriceListNotExistError when price list does not exist", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIPricingRepository(t)
+
+		mockRepo.EXPECT().
+			AssignUserPriceList(ctx, "1", "2").
+			Return(models.PriceListNotExistError).
+			Once()
+
+		service := services.NewPricingService(mockRepo)
+		actualErr := service.AssignUserPriceList(ctx, "1", "2")
+
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.PriceListNotExistError, actualErr)
+	})
+}
+
+func TestPricingService_ResolvePrices(t *testing.T) {
+	at := time.Now()
+
+	book := models.PriceBook{
+		Overrides: []models.Price{
+			{Prefix: "98935", Price: 80},
+		},
+		Plan: []models.Price{
+			{Prefix: "98", Price: 120},
+			{Prefix: "98912", Price: 110},
+		},
+		Default: []models.Price{
+			{Prefix: "98", Price: 150},
+			{Prefix: "1", Price: 600},
+		},
+	}
+
+	t.Run("should resolve prices by source and longest prefix", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIPricingRepository(t)
+
+		mockRepo.EXPECT().
+			GetPriceBook(ctx, "1", at).
+			Return(book, nil).
+			Once()
+
+		service := services.NewPricingService(mockRepo)
+		actualPrices, actualErr := service.ResolvePrices(ctx, "1", []string{
+			"989351234567",
+			"+989121234567",
+			"989301234567",
+			"12025550100",
+			"447700900000",
+		}, at)
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, []models.UnitPrice{
+			{Receiver: "989351234567", Prefix: "98935", Price: 80, Source: models.SourceOverride},
+			{Receiver: "+989121234567", Prefix: "98912", Price: 110, Source: models.SourcePlan},
+			{Receiver: "989301234567", Prefix: "98", Price: 120, Source: models.SourcePlan},
+			{Receiver: "12025550100", Prefix: "1", Price: 600, Source: models.SourceDefault},
+			{Receiver: "447700900000", Source: models.SourceNone},
+		}, actualPrices)
+	})
+
+	t.Run("should return error when can not get price book", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIPricingRepository(t)
+
+		expectedErr := fmt.Errorf("some error")
+
+		mockRepo.EXPECT().
+			GetPriceBook(ctx, "1", at).
+			Return(models.PriceBook{}, expectedErr).
+			Once()
+
+		service := services.NewPricingService(mockRepo)
+		_, actualErr := service.ResolvePrices(ctx, "1", []string{"989121234567"}, at)
+
+		assert.Error(t, actualErr)
+		assert.Equal(t, expectedErr, actualErr)
+	})
+}
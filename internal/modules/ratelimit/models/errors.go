@@ -0,0 +1,30 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	RateLimitExceededError = errors.New("send rate limit exceeded")
+	QuotaExceededError     = errors.New("send quota exceeded")
+	BulkSizeExceededError  = errors.New("too many messages in one request")
+	InvalidLimitsError     = errors.New("invalid send limits")
+	LimitsNotExistError    = errors.New("send limits do not exist")
+)
+
+// LimitError is returned when a send is over a limit. RetryAfter is how long
+// the user has to wait before the same send can pass.
+type LimitError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", e.Err, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LimitError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Limits caps how fast and how much a user can send. Zero fields are
+// unlimited.
+type Limits struct {
+	PerSecond   int
+	PerMinute   int
+	Daily       int
+	Monthly     int
+	MaxBulkSize int
+}
+
+// Bucket is a token bucket that refills at Rate tokens per second up to
+// Burst tokens.
+type Bucket struct {
+	Key   string
+	Rate  float64
+	Burst int
+}
+
+// Counter counts messages of a quota period, which ends at ResetAt.
+type Counter struct {
+	Key     string
+	Limit   int
+	ResetAt time.Time
+}
+
+// Reservation is what a send took from the limits of a user, so it can be
+// given back when the send fails.
+type Reservation struct {
+	UserId   string
+	Count    int
+	Counters []Counter
+}
+
+// Usage is the quota usage of a user in the current periods.
+type Usage struct {
+	Limits         Limits
+	Daily          int
+	Monthly        int
+	DailyResetAt   time.Time
+	MonthlyResetAt time.Time
+}
@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/models"
+)
+
+type ILimitRepository interface {
+	GetUserLimits(ctx context.Context, userId string) (models.Limits, error)
+	SetUserLimits(ctx context.Context, userId string, limits models.Limits) (models.Limits, error)
+}
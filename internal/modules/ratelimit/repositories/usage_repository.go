@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/models"
+)
+
+type IUsageRepository interface {
+	// TakeUsage takes n tokens from every bucket and adds n to every counter
+	// in one step. When any of them has no room nothing is taken, and the
+	// waits until the buckets and the counters have room are returned.
+	// Counters without a limit are only counted.
+	TakeUsage(ctx context.Context, buckets []models.Bucket, counters []models.Counter, n int) (time.Duration, time.Duration, error)
+	ReturnUsage(ctx context.Context, counters []models.Counter, n int) error
+	GetUsage(ctx context.Context, counters []models.Counter) ([]int, error)
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/repositories"
+
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+)
+
+type LimitsConfig struct {
+	PerSecond   int `mapstructure:"per_second"`
+	PerMinute   int `mapstructure:"per_minute"`
+	Daily       int `mapstructure:"daily"`
+	Monthly     int `mapstructure:"monthly"`
+	MaxBulkSize int `mapstructure:"max_bulk_size"`
+}
+
+type RateLimitServiceConfig struct {
+	// DefaultLimits apply to users without limits of their own.
+	DefaultLimits LimitsConfig `mapstructure:"default_limits"`
+}
+
+type IRateLimitService interface {
+	GetLimits(ctx context.Context, userId string) (models.Limits, error)
+	SetLimits(ctx context.Context, userId string, limits models.Limits) (models.Limits, error)
+	// Reserve takes count messages from the limits of a user, or returns a
+	// LimitError when they do not have room for them.
+	Reserve(ctx context.Context, userId string, count int) (models.Reservation, error)
+	// Release gives back the quota of a reservation whose send failed.
+	Release(ctx context.Context, reservation models.Reservation) error
+	GetUsage(ctx context.Context, userId string) (models.Usage, error)
+}
+
+type RateLimitService struct {
+	limitRepo repositories.ILimitRepository
+	usageRepo repositories.IUsageRepository
+	cfg       RateLimitServiceConfig
+}
+
+func NewRateLimitService(
+	cfg RateLimitServiceConfig,
+	limitRepo repositories.ILimitRepository,
+	usageRepo repositories.IUsageRepository,
+) *RateLimitService {
+	return &RateLimitService{
+		cfg:       cfg,
+		limitRepo: limitRepo,
+		usageRepo: usageRepo,
+	}
+}
+
+func (r *RateLimitService) GetLimits(ctx context.Context, userId string) (models.Limits, error) {
+	res, err := r.limitRepo.GetUserLimits(ctx, userId)
+	if err != nil {
+		if errors.Is(err, models.LimitsNotExistError) {
+			return r.defaultLimits(), nil
+		}
+
+		pkgLog.Error(err, "failed to get send limits of user %s", userId)
+		return models.Limits{}, err
+	}
+
+	return res, nil
+}
+
+func (r *RateLimitService) SetLimits(ctx context.Context, userId string, limits models.Limits) (models.Limits, error) {
+	pkgLog.Debug("setting send limits of user %s", userId)
+	if limits.PerSecond < 0 || limits.PerMinute < 0 || limits.Daily < 0 || limits.Monthly < 0 || limits.MaxBulkSize < 0 {
+		pkgLog.Error(models.InvalidLimitsError, "negative send limits for user %s", userId)
+		return models.Limits{}, models.InvalidLimitsError
+	}
+
+	res, err := r.limitRepo.SetUserLimits(ctx, userId, limits)
+	if err != nil {
+		pkgLog.Error(err, "failed to set send limits of user %s", userId)
+		return models.Limits{}, err
+	}
+
+	pkgLog.Debug("set send limits of user %s", userId)
+	return res, nil
+}
+
+// Reserve checks the per second and per minute token buckets and the daily
+// and monthly quotas of a user at once. A bucket lets a send through when it
+// holds count tokens, or is full for sends larger than the bucket, and may go
+// into debt so later sends wait until it refills.
+func (r *RateLimitService) Reserve(ctx context.Context, userId string, count int) (models.Reservation, error) {
+	limits, err := r.GetLimits(ctx, userId)
+	if err != nil {
+		return models.Reservation{}, err
+	}
+
+	if limits.MaxBulkSize > 0 && count > limits.MaxBulkSize {
+		return models.Reservation{}, models.BulkSizeExceededError
+	}
+	if (limits.Daily > 0 && count > limits.Daily) || (limits.Monthly > 0 && count > limits.Monthly) {
+		return models.Reservation{}, models.BulkSizeExceededError
+	}
+
+	buckets := make([]models.Bucket, 0, 2)
+	if limits.PerSecond > 0 {
+		buckets = append(buckets, models.Bucket{
+			Key:   fmt.Sprintf("%s:second", userId),
+			Rate:  float64(limits.PerSecond),
+			Burst: limits.PerSecond,
+		})
+	}
+	if limits.PerMinute > 0 {
+		buckets = append(buckets, models.Bucket{
+			Key:   fmt.Sprintf("%s:minute", userId),
+			Rate:  float64(limits.PerMinute) / 60,
+			Burst: limits.PerMinute,
+		})
+	}
+	counters := quotaCounters(userId, limits, time.Now())
+
+	bucketWait, counterWait, err := r.usageRepo.TakeUsage(ctx, buckets, counters, count)
+	if err != nil {
+		pkgLog.Error(err, "failed to take send limits of user %s", userId)
+		return models.Reservation{}, err
+	}
+	if counterWait > 0 {
+		return models.Reservation{}, &models.LimitError{
+			Err:        models.QuotaExceededError,
+			RetryAfter: max(counterWait, bucketWait),
+		}
+	}
+	if bucketWait > 0 {
+		return models.Reservation{}, &models.LimitError{
+			Err:        models.RateLimitExceededError,
+			RetryAfter: bucketWait,
+		}
+	}
+
+	return models.Reservation{
+		UserId:   userId,
+		Count:    count,
+		Counters: counters,
+	}, nil
+}
+
+func (r *RateLimitService) Release(ctx context.Context, reservation models.Reservation) error {
+	if reservation.Count == 0 || len(reservation.Counters) == 0 {
+		return nil
+	}
+
+	if err := r.usageRepo.ReturnUsage(ctx, reservation.Counters, reservation.Count); err != nil {
+		pkgLog.Error(err, "failed to release send limits of user %s", reservation.UserId)
+		return err
+	}
+
+	return nil
+}
+
+func (r *RateLimitService) GetUsage(ctx context.Context, userId string) (models.Usage, error) {
+	limits, err := r.GetLimits(ctx, userId)
+	if err != nil {
+		return models.Usage{}, err
+	}
+
+	counters := quotaCounters(userId, limits, time.Now())
+	used, err := r.usageRepo.GetUsage(ctx, counters)
+	if err != nil {
+		pkgLog.Error(err, "failed to get send usage of user %s", userId)
+		return models.Usage{}, err
+	}
+
+	return models.Usage{
+		Limits:         limits,
+		Daily:          used[0],
+		Monthly:        used[1],
+		DailyResetAt:   counters[0].ResetAt,
+		MonthlyResetAt: counters[1].ResetAt,
+	}, nil
+}
+
+func (r *RateLimitService) defaultLimits() models.Limits {
+	return models.Limits{
+		PerSecond:   r.cfg.DefaultLimits.PerSecond,
+		PerMinute:   r.cfg.DefaultLimits.PerMinute,
+		Daily:       r.cfg.DefaultLimits.Daily,
+		Monthly:     r.cfg.DefaultLimits.Monthly,
+		MaxBulkSize: r.cfg.DefaultLimits.MaxBulkSize,
+	}
+}
+
+// quotaCounters returns the daily and monthly counters of a user at now.
+// Periods follow the UTC calendar, and both are counted even without a limit
+// so usage can be shown.
+func quotaCounters(userId string, limits models.Limits, now time.Time) []models.Counter {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	return []models.Counter{
+		{
+			Key:     fmt.Sprintf("%s:daily:%s", userId, day.Format("20060102")),
+			Limit:   limits.Daily,
+			ResetAt: day.AddDate(0, 0, 1),
+		},
+		{
+			Key:     fmt.Sprintf("%s:monthly:%s", userId, month.Format("200601")),
+			Limit:   limits.Monthly,
+			ResetAt: month.AddDate(0, 1, 0),
+		},
+	}
+}
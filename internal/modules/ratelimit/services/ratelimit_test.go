@@ -0,0 +1,279 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/mocks"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRateLimitService_GetLimits(t *testing.T) {
+	t.Run("should return user limits", func(t *testing.T) {
+		ctx := context.Background()
+		mockLimit := mocks.NewMockILimitRepository(t)
+		mockUsage := mocks.NewMockIUsageRepository(t)
+
+		expectedLimits := models.Limits{PerSecond: 5, Daily: 100}
+		mockLimit.EXPECT().
+			GetUserLimits(ctx, "1").
+			Return(expectedLimits, nil).
+			Once()
+
+		service := services.NewRateLimitService(services.RateLimitServiceConfig{
+			DefaultLimits: services.LimitsConfig{PerSecond: 50},
+		}, mockLimit, mockUsage)
+		actualLimits, actualErr := service.GetLimits(ctx, "1")
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedLimits, actualLimits)
+	})
+
+	t.Run("should return default limits when user has none", func(t *testing.T) {
+		ctx := context.Background()
+		mockLimit := mocks.NewMockILimitRepository(t)
+		mockUsage := mocks.NewMockIUsageRepository(t)
+
+		mockLimit.EXPECT().
+			GetUserLimits(ctx, "1").
+			Return(models.Limits{}, models.LimitsNotExistError).
+			Once()
+
+		service := services.NewRateLimitService(services.RateLimitServiceConfig{
+			DefaultLimits: services.LimitsConfig{PerSecond: 50, MaxBulkSize: 1000},
+		}, mockLimit, mockUsage)
+		actualLimits, actualErr := service.GetLimits(ctx, "1")
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.Limits{PerSecond: 50, MaxBulkSize: 1000}, actualLimits)
+	})
+}
+
+func TestRateLimitService_SetLimits(t *testing.T) {
+	t.Run("should store limits", func(t *testing.T) {
+		ctx := context.Background()
+		mockLimit := mocks.NewMockILimitRepository(t)
+		mockUsage := mocks.NewMockIUsageRepository(t)
+
+		expectedLimits := models.Limits{PerMinute: 60, Monthly: 1000}
+		mockLimit.EXPECT().
+			SetUserLimits(ctx, "1", expectedLimits).
+			Return(expectedLimits, nil).
+			Once()
+
+		service := services.NewRateLimitService(services.RateLimitServiceConfig{}, mockLimit, mockUsage)
+		actualLimits, actualErr := service.SetLimits(ctx, "1", expectedLimits)
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedLimits, actualLimits)
+	})
+
+	t.Run("should return InvalidLimitsError when a limit is negative", func(t *testing.T) {
+		ctx := context.Background()
+		mockLimit := mocks.NewMockILimitRepository(t)
+		mockUsage := mocks.NewMockIUsageRepository(t)
+
+		service := services.NewRateLimitService(services.RateLimitServiceConfig{}, mockLimit, mockUsage)
+		_, actualErr := service.SetLimits(ctx, "1", models.Limits{PerSecond: -1})
+
+		assert.Equal(t, models.InvalidLimitsError, actualErr)
+	})
+}
+
+func TestRateLimitService_Reserve(t *testing.T) {
+	t.Run("should take buckets and counters of user", func(t *testing.T) {
+		ctx := context.Background()
+		mockLimit := mocks.NewMockILimitRepository(t)
+		mockUsage := mocks.NewMockIUsageRepository(t)
+
+		mockLimit.EXPECT().
+			GetUserLimits(ctx, "1").
+			Return(models.Limits{PerSecond: 10, PerMinute: 120, Daily: 100}, nil).
+			Once()
+
+		expectedBuckets := []models.Bucket{
+			{Key: "1:second", Rate: 10, Burst: 10},
+			{Key: "1:minute", Rate: 2, Burst: 120},
+		}
+		mockUsage.EXPECT().
+			TakeUsage(ctx, expectedBuckets, mock.MatchedBy(func(counters []models.Counter) bool {
+				return len(counters) == 2 &&
+					strings.HasPrefix(counters[0].Key, "1:daily:") && counters[0].Limit == 100 &&
+					strings.HasPrefix(counters[1].Key, "1:monthly:") && counters[1].Limit == 0
+			}), 3).
+			Return(0, 0, nil).
+			Once()
+
+		service := services.NewRateLimitService(services.RateLimitServiceConfig{}, mockLimit, mockUsage)
+		actualReservation, actualErr := service.Reserve(ctx, "1", 3)
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, "1", actualReservation.UserId)
+		assert.Equal(t, 3, actualReservation.Count)
+		assert.Len(t, actualReservation.Counters, 2)
+	})
+
+	t.Run("should return BulkSizeExceededError when send is larger than max bulk size", func(t *testing.T) {
+		ctx := context.Background()
+		mockLimit := mocks.NewMockILimitRepository(t)
+		mockUsage := mocks.NewMockIUsageRepository(t)
+
+		mockLimit.EXPECT().
+			GetUserLimits(ctx, "1").
+			Return(models.Limits{MaxBulkSize: 2}, nil).
+			Once()
+
+		service := services.NewRateLimitService(services.RateLimitServiceConfig{}, mockLimit, mockUsage)
+		_, actualErr := service.Reserve(ctx, "1", 3)
+
+		assert.Equal(t, models.BulkSizeExceededError, actualErr)
+	})
+
+	t.Run("should return BulkSizeExceededError when send is larger than a quota", func(t *testing.T) {
+		ctx := context.Background()
+		mockLimit := mocks.NewMockILimitRepository(t)
+		mockUsage := mocks.NewMockIUsageRepository(t)
+
+		mockLimit.EXPECT().
+			GetUserLimits(ctx, "1").
+			Return(models.Limits{Monthly: 2}, nil).
+			Once()
+
+		service := services.NewRateLimitService(services.RateLimitServiceConfig{}, mockLimit, mockUsage)
+		_, actualErr := service.Reserve(ctx, "1", 3)
+
+		assert.Equal(t, models.BulkSizeExceededError, actualErr)
+	})
+
+	t.Run("should return RateLimitExceededError with wait of buckets", func(t *testing.T) {
+		ctx := context.Background()
+		mockLimit := mocks.NewMockILimitRepository(t)
+		mockUsage := mocks.NewMockIUsageRepository(t)
+
+		mockLimit.EXPECT().
+			GetUserLimits(ctx, "1").
+			Return(models.Limits{PerSecond: 1}, nil).
+			Once()
+		mockUsage.EXPECT().
+			TakeUsage(ctx, mock.Anything, mock.Anything, 1).
+			Return(500*time.Millisecond, 0, nil).
+			Once()
+
+		service := services.NewRateLimitService(services.RateLimitServiceConfig{}, mockLimit, mockUsage)
+		_, actualErr := service.Reserve(ctx, "1", 1)
+
+		var limitErr *models.LimitError
+		assert.ErrorIs(t, actualErr, models.RateLimitExceededError)
+		assert.True(t, errors.As(actualErr, &limitErr))
+		assert.Equal(t, 500*time.Millisecond, limitErr.RetryAfter)
+	})
+
+	t.Run("should return QuotaExceededError with longest wait", func(t *testing.T) {
+		ctx := context.Background()
+		mockLimit := mocks.NewMockILimitRepository(t)
+		mockUsage := mocks.NewMockIUsageRepository(t)
+
+		mockLimit.EXPECT().
+			GetUserLimits(ctx, "1").
+			Return(models.Limits{PerSecond: 1, Daily: 10}, nil).
+			Once()
+		mockUsage.EXPECT().
+			TakeUsage(ctx, mock.Anything, mock.Anything, 1).
+			Return(time.Second, time.Hour, nil).
+			Once()
+
+		service := services.NewRateLimitService(services.RateLimitServiceConfig{}, mockLimit, mockUsage)
+		_, actualErr := service.Reserve(ctx, "1", 1)
+
+		var limitErr *models.LimitError
+		assert.ErrorIs(t, actualErr, models.QuotaExceededError)
+		assert.True(t, errors.As(actualErr, &limitErr))
+		assert.Equal(t, time.Hour, limitErr.RetryAfter)
+	})
+
+	t.Run("should return error when usage repository fails", func(t *testing.T) {
+		ctx := context.Background()
+		mockLimit := mocks.NewMockILimitRepository(t)
+		mockUsage := mocks.NewMockIUsageRepository(t)
+
+		expectedErr := errors.New("test error")
+		mockLimit.EXPECT().
+			GetUserLimits(ctx, "1").
+			Return(models.Limits{}, models.LimitsNotExistError).
+			Once()
+		mockUsage.EXPECT().
+			TakeUsage(ctx, mock.Anything, mock.Anything, 1).
+			Return(0, 0, expectedErr).
+			Once()
+
+		service := services.NewRateLimitService(services.RateLimitServiceConfig{}, mockLimit, mockUsage)
+		_, actualErr := service.Reserve(ctx, "1", 1)
+
+		assert.Equal(t, expectedErr, actualErr)
+	})
+}
+
+func TestRateLimitService_Release(t *testing.T) {
+	t.Run("should return counters of reservation", func(t *testing.T) {
+		ctx := context.Background()
+		mockLimit := mocks.NewMockILimitRepository(t)
+		mockUsage := mocks.NewMockIUsageRepository(t)
+
+		counters := []models.Counter{{Key: "1:daily:20261017"}, {Key: "1:monthly:202610"}}
+		mockUsage.EXPECT().
+			ReturnUsage(ctx, counters, 3).
+			Return(nil).
+			Once()
+
+		service := services.NewRateLimitService(services.RateLimitServiceConfig{}, mockLimit, mockUsage)
+		actualErr := service.Release(ctx, models.Reservation{UserId: "1", Count: 3, Counters: counters})
+
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("should do nothing for empty reservation", func(t *testing.T) {
+		ctx := context.Background()
+		mockLimit := mocks.NewMockILimitRepository(t)
+		mockUsage := mocks.NewMockIUsageRepository(t)
+
+		service := services.NewRateLimitService(services.RateLimitServiceConfig{}, mockLimit, mockUsage)
+		actualErr := service.Release(ctx, models.Reservation{})
+
+		assert.NoError(t, actualErr)
+	})
+}
+
+func TestRateLimitService_GetUsage(t *testing.T) {
+	t.Run("should return usage of current periods", func(t *testing.T) {
+		ctx := context.Background()
+		mockLimit := mocks.NewMockILimitRepository(t)
+		mockUsage := mocks.NewMockIUsageRepository(t)
+
+		limits := models.Limits{Daily: 100, Monthly: 1000}
+		mockLimit.EXPECT().
+			GetUserLimits(ctx, "1").
+			Return(limits, nil).
+			Once()
+		mockUsage.EXPECT().
+			GetUsage(ctx, mock.Anything).
+			Return([]int{7, 42}, nil).
+			Once()
+
+		service := services.NewRateLimitService(services.RateLimitServiceConfig{}, mockLimit, mockUsage)
+		actualUsage, actualErr := service.GetUsage(ctx, "1")
+
+		now := time.Now().UTC()
+		assert.NoError(t, actualErr)
+		assert.Equal(t, limits, actualUsage.Limits)
+		assert.Equal(t, 7, actualUsage.Daily)
+		assert.Equal(t, 42, actualUsage.Monthly)
+		assert.Equal(t, time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC), actualUsage.DailyResetAt)
+		assert.Equal(t, time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC), actualUsage.MonthlyResetAt)
+	})
+}
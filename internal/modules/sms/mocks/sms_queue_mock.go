@@ -38,6 +38,63 @@ func (_m *MockISmsQueue) EXPECT() *MockISmsQueue_Expecter {
 	return &MockISmsQueue_Expecter{mock: &_m.Mock}
 }
 
+// Ack provides a mock function for the type MockISmsQueue
+func (_mock *MockISmsQueue) Ack(ctx context.Context, msg models.Sms) error {
+	ret := _mock.Called(ctx, msg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ack")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Sms) error); ok {
+		r0 = returnFunc(ctx, msg)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockISmsQueue_Ack_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ack'
+type MockISmsQueue_Ack_Call struct {
+	*mock.Call
+}
+
+// Ack is a helper method to define mock.On call
+//   - ctx context.Context
+//   - msg models.Sms
+func (_e *MockISmsQueue_Expecter) Ack(ctx interface{}, msg interface{}) *MockISmsQueue_Ack_Call {
+	return &MockISmsQueue_Ack_Call{Call: _e.mock.On("Ack", ctx, msg)}
+}
+
+func (_c *MockISmsQueue_Ack_Call) Run(run func(ctx context.Context, msg models.Sms)) *MockISmsQueue_Ack_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.Sms
+		if args[1] != nil {
+			arg1 = args[1].(models.Sms)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsQueue_Ack_Call) Return(err error) *MockISmsQueue_Ack_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockISmsQueue_Ack_Call) RunAndReturn(run func(ctx context.Context, msg models.Sms) error) *MockISmsQueue_Ack_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Enqueue provides a mock function for the type MockISmsQueue
 func (_mock *MockISmsQueue) Enqueue(ctx context.Context, msg []models.Sms) error {
 	ret := _mock.Called(ctx, msg)
@@ -95,6 +152,74 @@ func (_c *MockISmsQueue_Enqueue_Call) RunAndReturn(run func(ctx context.Context,
 	return _c
 }
 
+// GetAccepted provides a mock function for the type MockISmsQueue
+func (_mock *MockISmsQueue) GetAccepted(ctx context.Context, ids []string) (map[string]models.SendResult, error) {
+	ret := _mock.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccepted")
+	}
+
+	var r0 map[string]models.SendResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) (map[string]models.SendResult, error)); ok {
+		return returnFunc(ctx, ids)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) map[string]models.SendResult); ok {
+		r0 = returnFunc(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]models.SendResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = returnFunc(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsQueue_GetAccepted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAccepted'
+type MockISmsQueue_GetAccepted_Call struct {
+	*mock.Call
+}
+
+// GetAccepted is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []string
+func (_e *MockISmsQueue_Expecter) GetAccepted(ctx interface{}, ids interface{}) *MockISmsQueue_GetAccepted_Call {
+	return &MockISmsQueue_GetAccepted_Call{Call: _e.mock.On("GetAccepted", ctx, ids)}
+}
+
+func (_c *MockISmsQueue_GetAccepted_Call) Run(run func(ctx context.Context, ids []string)) *MockISmsQueue_GetAccepted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsQueue_GetAccepted_Call) Return(stringToSendResult map[string]models.SendResult, err error) *MockISmsQueue_GetAccepted_Call {
+	_c.Call.Return(stringToSendResult, err)
+	return _c
+}
+
+func (_c *MockISmsQueue_GetAccepted_Call) RunAndReturn(run func(ctx context.Context, ids []string) (map[string]models.SendResult, error)) *MockISmsQueue_GetAccepted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetLength provides a mock function for the type MockISmsQueue
 func (_mock *MockISmsQueue) GetLength(ctx context.Context) (int, error) {
 	ret := _mock.Called(ctx)
@@ -155,6 +280,131 @@ func (_c *MockISmsQueue_GetLength_Call) RunAndReturn(run func(ctx context.Contex
 	return _c
 }
 
+// GetQueuedIds provides a mock function for the type MockISmsQueue
+func (_mock *MockISmsQueue) GetQueuedIds(ctx context.Context, ids []string) ([]string, error) {
+	ret := _mock.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetQueuedIds")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) ([]string, error)); ok {
+		return returnFunc(ctx, ids)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) []string); ok {
+		r0 = returnFunc(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = returnFunc(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsQueue_GetQueuedIds_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetQueuedIds'
+type MockISmsQueue_GetQueuedIds_Call struct {
+	*mock.Call
+}
+
+// GetQueuedIds is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []string
+func (_e *MockISmsQueue_Expecter) GetQueuedIds(ctx interface{}, ids interface{}) *MockISmsQueue_GetQueuedIds_Call {
+	return &MockISmsQueue_GetQueuedIds_Call{Call: _e.mock.On("GetQueuedIds", ctx, ids)}
+}
+
+func (_c *MockISmsQueue_GetQueuedIds_Call) Run(run func(ctx context.Context, ids []string)) *MockISmsQueue_GetQueuedIds_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsQueue_GetQueuedIds_Call) Return(strings []string, err error) *MockISmsQueue_GetQueuedIds_Call {
+	_c.Call.Return(strings, err)
+	return _c
+}
+
+func (_c *MockISmsQueue_GetQueuedIds_Call) RunAndReturn(run func(ctx context.Context, ids []string) ([]string, error)) *MockISmsQueue_GetQueuedIds_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Nack provides a mock function for the type MockISmsQueue
+func (_mock *MockISmsQueue) Nack(ctx context.Context, msg models.Sms) error {
+	ret := _mock.Called(ctx, msg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Nack")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Sms) error); ok {
+		r0 = returnFunc(ctx, msg)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockISmsQueue_Nack_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Nack'
+type MockISmsQueue_Nack_Call struct {
+	*mock.Call
+}
+
+// Nack is a helper method to define mock.On call
+//   - ctx context.Context
+//   - msg models.Sms
+func (_e *MockISmsQueue_Expecter) Nack(ctx interface{}, msg interface{}) *MockISmsQueue_Nack_Call {
+	return &MockISmsQueue_Nack_Call{Call: _e.mock.On("Nack", ctx, msg)}
+}
+
+func (_c *MockISmsQueue_Nack_Call) Run(run func(ctx context.Context, msg models.Sms)) *MockISmsQueue_Nack_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.Sms
+		if args[1] != nil {
+			arg1 = args[1].(models.Sms)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsQueue_Nack_Call) Return(err error) *MockISmsQueue_Nack_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockISmsQueue_Nack_Call) RunAndReturn(run func(ctx context.Context, msg models.Sms) error) *MockISmsQueue_Nack_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Pop provides a mock function for the type MockISmsQueue
 func (_mock *MockISmsQueue) Pop(ctx context.Context) (models.Sms, error) {
 	ret := _mock.Called(ctx)
@@ -214,3 +464,183 @@ func (_c *MockISmsQueue_Pop_Call) RunAndReturn(run func(ctx context.Context) (mo
 	_c.Call.Return(run)
 	return _c
 }
+
+// RecoverUnacked provides a mock function for the type MockISmsQueue
+func (_mock *MockISmsQueue) RecoverUnacked(ctx context.Context) (int, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecoverUnacked")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsQueue_RecoverUnacked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecoverUnacked'
+type MockISmsQueue_RecoverUnacked_Call struct {
+	*mock.Call
+}
+
+// RecoverUnacked is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockISmsQueue_Expecter) RecoverUnacked(ctx interface{}) *MockISmsQueue_RecoverUnacked_Call {
+	return &MockISmsQueue_RecoverUnacked_Call{Call: _e.mock.On("RecoverUnacked", ctx)}
+}
+
+func (_c *MockISmsQueue_RecoverUnacked_Call) Run(run func(ctx context.Context)) *MockISmsQueue_RecoverUnacked_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsQueue_RecoverUnacked_Call) Return(n int, err error) *MockISmsQueue_RecoverUnacked_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockISmsQueue_RecoverUnacked_Call) RunAndReturn(run func(ctx context.Context) (int, error)) *MockISmsQueue_RecoverUnacked_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveAccepted provides a mock function for the type MockISmsQueue
+func (_mock *MockISmsQueue) RemoveAccepted(ctx context.Context, ids []string) error {
+	ret := _mock.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveAccepted")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = returnFunc(ctx, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockISmsQueue_RemoveAccepted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveAccepted'
+type MockISmsQueue_RemoveAccepted_Call struct {
+	*mock.Call
+}
+
+// RemoveAccepted is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []string
+func (_e *MockISmsQueue_Expecter) RemoveAccepted(ctx interface{}, ids interface{}) *MockISmsQueue_RemoveAccepted_Call {
+	return &MockISmsQueue_RemoveAccepted_Call{Call: _e.mock.On("RemoveAccepted", ctx, ids)}
+}
+
+func (_c *MockISmsQueue_RemoveAccepted_Call) Run(run func(ctx context.Context, ids []string)) *MockISmsQueue_RemoveAccepted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsQueue_RemoveAccepted_Call) Return(err error) *MockISmsQueue_RemoveAccepted_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockISmsQueue_RemoveAccepted_Call) RunAndReturn(run func(ctx context.Context, ids []string) error) *MockISmsQueue_RemoveAccepted_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetAccepted provides a mock function for the type MockISmsQueue
+func (_mock *MockISmsQueue) SetAccepted(ctx context.Context, id string, res models.SendResult) error {
+	ret := _mock.Called(ctx, id, res)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetAccepted")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.SendResult) error); ok {
+		r0 = returnFunc(ctx, id, res)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockISmsQueue_SetAccepted_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetAccepted'
+type MockISmsQueue_SetAccepted_Call struct {
+	*mock.Call
+}
+
+// SetAccepted is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - res models.SendResult
+func (_e *MockISmsQueue_Expecter) SetAccepted(ctx interface{}, id interface{}, res interface{}) *MockISmsQueue_SetAccepted_Call {
+	return &MockISmsQueue_SetAccepted_Call{Call: _e.mock.On("SetAccepted", ctx, id, res)}
+}
+
+func (_c *MockISmsQueue_SetAccepted_Call) Run(run func(ctx context.Context, id string, res models.SendResult)) *MockISmsQueue_SetAccepted_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 models.SendResult
+		if args[2] != nil {
+			arg2 = args[2].(models.SendResult)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsQueue_SetAccepted_Call) Return(err error) *MockISmsQueue_SetAccepted_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockISmsQueue_SetAccepted_Call) RunAndReturn(run func(ctx context.Context, id string, res models.SendResult) error) *MockISmsQueue_SetAccepted_Call {
+	_c.Call.Return(run)
+	return _c
+}
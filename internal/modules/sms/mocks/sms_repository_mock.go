@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
 	mock "github.com/stretchr/testify/mock"
@@ -38,112 +39,813 @@ func (_m *MockISmsRepository) EXPECT() *MockISmsRepository_Expecter {
 	return &MockISmsRepository_Expecter{mock: &_m.Mock}
 }
 
+// CancelScheduledMessage provides a mock function for the type MockISmsRepository
+func (_mock *MockISmsRepository) CancelScheduledMessage(ctx context.Context, userId string, id string) (models.Sms, error) {
+	ret := _mock.Called(ctx, userId, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelScheduledMessage")
+	}
+
+	var r0 models.Sms
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (models.Sms, error)); ok {
+		return returnFunc(ctx, userId, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) models.Sms); ok {
+		r0 = returnFunc(ctx, userId, id)
+	} else {
+		r0 = ret.Get(0).(models.Sms)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, userId, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsRepository_CancelScheduledMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelScheduledMessage'
+type MockISmsRepository_CancelScheduledMessage_Call struct {
+	*mock.Call
+}
+
+// CancelScheduledMessage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId string
+//   - id string
+func (_e *MockISmsRepository_Expecter) CancelScheduledMessage(ctx interface{}, userId interface{}, id interface{}) *MockISmsRepository_CancelScheduledMessage_Call {
+	return &MockISmsRepository_CancelScheduledMessage_Call{Call: _e.mock.On("CancelScheduledMessage", ctx, userId, id)}
+}
+
+func (_c *MockISmsRepository_CancelScheduledMessage_Call) Run(run func(ctx context.Context, userId string, id string)) *MockISmsRepository_CancelScheduledMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsRepository_CancelScheduledMessage_Call) Return(sms models.Sms, err error) *MockISmsRepository_CancelScheduledMessage_Call {
+	_c.Call.Return(sms, err)
+	return _c
+}
+
+func (_c *MockISmsRepository_CancelScheduledMessage_Call) RunAndReturn(run func(ctx context.Context, userId string, id string) (models.Sms, error)) *MockISmsRepository_CancelScheduledMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ChangeMessageSendAt provides a mock function for the type MockISmsRepository
+func (_mock *MockISmsRepository) ChangeMessageSendAt(ctx context.Context, userId string, id string, sendAt time.Time) (models.Sms, error) {
+	ret := _mock.Called(ctx, userId, id, sendAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeMessageSendAt")
+	}
+
+	var r0 models.Sms
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (models.Sms, error)); ok {
+		return returnFunc(ctx, userId, id, sendAt)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Time) models.Sms); ok {
+		r0 = returnFunc(ctx, userId, id, sendAt)
+	} else {
+		r0 = ret.Get(0).(models.Sms)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = returnFunc(ctx, userId, id, sendAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsRepository_ChangeMessageSendAt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangeMessageSendAt'
+type MockISmsRepository_ChangeMessageSendAt_Call struct {
+	*mock.Call
+}
+
+// ChangeMessageSendAt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId string
+//   - id string
+//   - sendAt time.Time
+func (_e *MockISmsRepository_Expecter) ChangeMessageSendAt(ctx interface{}, userId interface{}, id interface{}, sendAt interface{}) *MockISmsRepository_ChangeMessageSendAt_Call {
+	return &MockISmsRepository_ChangeMessageSendAt_Call{Call: _e.mock.On("ChangeMessageSendAt", ctx, userId, id, sendAt)}
+}
+
+func (_c *MockISmsRepository_ChangeMessageSendAt_Call) Run(run func(ctx context.Context, userId string, id string, sendAt time.Time)) *MockISmsRepository_ChangeMessageSendAt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsRepository_ChangeMessageSendAt_Call) Return(sms models.Sms, err error) *MockISmsRepository_ChangeMessageSendAt_Call {
+	_c.Call.Return(sms, err)
+	return _c
+}
+
+func (_c *MockISmsRepository_ChangeMessageSendAt_Call) RunAndReturn(run func(ctx context.Context, userId string, id string, sendAt time.Time) (models.Sms, error)) *MockISmsRepository_ChangeMessageSendAt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateDeadLetter provides a mock function for the type MockISmsRepository
+func (_mock *MockISmsRepository) CreateDeadLetter(ctx context.Context, letter models.DeadLetter) error {
+	ret := _mock.Called(ctx, letter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDeadLetter")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.DeadLetter) error); ok {
+		r0 = returnFunc(ctx, letter)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockISmsRepository_CreateDeadLetter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDeadLetter'
+type MockISmsRepository_CreateDeadLetter_Call struct {
+	*mock.Call
+}
+
+// CreateDeadLetter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - letter models.DeadLetter
+func (_e *MockISmsRepository_Expecter) CreateDeadLetter(ctx interface{}, letter interface{}) *MockISmsRepository_CreateDeadLetter_Call {
+	return &MockISmsRepository_CreateDeadLetter_Call{Call: _e.mock.On("CreateDeadLetter", ctx, letter)}
+}
+
+func (_c *MockISmsRepository_CreateDeadLetter_Call) Run(run func(ctx context.Context, letter models.DeadLetter)) *MockISmsRepository_CreateDeadLetter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.DeadLetter
+		if args[1] != nil {
+			arg1 = args[1].(models.DeadLetter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsRepository_CreateDeadLetter_Call) Return(err error) *MockISmsRepository_CreateDeadLetter_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockISmsRepository_CreateDeadLetter_Call) RunAndReturn(run func(ctx context.Context, letter models.DeadLetter) error) *MockISmsRepository_CreateDeadLetter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateScheduleMessages provides a mock function for the type MockISmsRepository
-func (_mock *MockISmsRepository) CreateScheduleMessages(ctx context.Context, msgs []models.Sms) error {
+func (_mock *MockISmsRepository) CreateScheduleMessages(ctx context.Context, msgs []models.Sms) ([]models.Sms, error) {
 	ret := _mock.Called(ctx, msgs)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateScheduleMessages")
 	}
-
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.Sms) error); ok {
-		r0 = returnFunc(ctx, msgs)
+
+	var r0 []models.Sms
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.Sms) ([]models.Sms, error)); ok {
+		return returnFunc(ctx, msgs)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []models.Sms) []models.Sms); ok {
+		r0 = returnFunc(ctx, msgs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Sms)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []models.Sms) error); ok {
+		r1 = returnFunc(ctx, msgs)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsRepository_CreateScheduleMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateScheduleMessages'
+type MockISmsRepository_CreateScheduleMessages_Call struct {
+	*mock.Call
+}
+
+// CreateScheduleMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - msgs []models.Sms
+func (_e *MockISmsRepository_Expecter) CreateScheduleMessages(ctx interface{}, msgs interface{}) *MockISmsRepository_CreateScheduleMessages_Call {
+	return &MockISmsRepository_CreateScheduleMessages_Call{Call: _e.mock.On("CreateScheduleMessages", ctx, msgs)}
+}
+
+func (_c *MockISmsRepository_CreateScheduleMessages_Call) Run(run func(ctx context.Context, msgs []models.Sms)) *MockISmsRepository_CreateScheduleMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []models.Sms
+		if args[1] != nil {
+			arg1 = args[1].([]models.Sms)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsRepository_CreateScheduleMessages_Call) Return(smss []models.Sms, err error) *MockISmsRepository_CreateScheduleMessages_Call {
+	_c.Call.Return(smss, err)
+	return _c
+}
+
+func (_c *MockISmsRepository_CreateScheduleMessages_Call) RunAndReturn(run func(ctx context.Context, msgs []models.Sms) ([]models.Sms, error)) *MockISmsRepository_CreateScheduleMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteDeadLetter provides a mock function for the type MockISmsRepository
+func (_mock *MockISmsRepository) DeleteDeadLetter(ctx context.Context, id string) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDeadLetter")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockISmsRepository_DeleteDeadLetter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteDeadLetter'
+type MockISmsRepository_DeleteDeadLetter_Call struct {
+	*mock.Call
+}
+
+// DeleteDeadLetter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockISmsRepository_Expecter) DeleteDeadLetter(ctx interface{}, id interface{}) *MockISmsRepository_DeleteDeadLetter_Call {
+	return &MockISmsRepository_DeleteDeadLetter_Call{Call: _e.mock.On("DeleteDeadLetter", ctx, id)}
+}
+
+func (_c *MockISmsRepository_DeleteDeadLetter_Call) Run(run func(ctx context.Context, id string)) *MockISmsRepository_DeleteDeadLetter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsRepository_DeleteDeadLetter_Call) Return(err error) *MockISmsRepository_DeleteDeadLetter_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockISmsRepository_DeleteDeadLetter_Call) RunAndReturn(run func(ctx context.Context, id string) error) *MockISmsRepository_DeleteDeadLetter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EnqueueMessages provides a mock function for the type MockISmsRepository
+func (_mock *MockISmsRepository) EnqueueMessages(ctx context.Context, count int) ([]models.Sms, error) {
+	ret := _mock.Called(ctx, count)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnqueueMessages")
+	}
+
+	var r0 []models.Sms
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]models.Sms, error)); ok {
+		return returnFunc(ctx, count)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []models.Sms); ok {
+		r0 = returnFunc(ctx, count)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Sms)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, count)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsRepository_EnqueueMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnqueueMessages'
+type MockISmsRepository_EnqueueMessages_Call struct {
+	*mock.Call
+}
+
+// EnqueueMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - count int
+func (_e *MockISmsRepository_Expecter) EnqueueMessages(ctx interface{}, count interface{}) *MockISmsRepository_EnqueueMessages_Call {
+	return &MockISmsRepository_EnqueueMessages_Call{Call: _e.mock.On("EnqueueMessages", ctx, count)}
+}
+
+func (_c *MockISmsRepository_EnqueueMessages_Call) Run(run func(ctx context.Context, count int)) *MockISmsRepository_EnqueueMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsRepository_EnqueueMessages_Call) Return(smss []models.Sms, err error) *MockISmsRepository_EnqueueMessages_Call {
+	_c.Call.Return(smss, err)
+	return _c
+}
+
+func (_c *MockISmsRepository_EnqueueMessages_Call) RunAndReturn(run func(ctx context.Context, count int) ([]models.Sms, error)) *MockISmsRepository_EnqueueMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDeadLetter provides a mock function for the type MockISmsRepository
+func (_mock *MockISmsRepository) GetDeadLetter(ctx context.Context, id string) (models.DeadLetter, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeadLetter")
+	}
+
+	var r0 models.DeadLetter
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (models.DeadLetter, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) models.DeadLetter); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.DeadLetter)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsRepository_GetDeadLetter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDeadLetter'
+type MockISmsRepository_GetDeadLetter_Call struct {
+	*mock.Call
+}
+
+// GetDeadLetter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockISmsRepository_Expecter) GetDeadLetter(ctx interface{}, id interface{}) *MockISmsRepository_GetDeadLetter_Call {
+	return &MockISmsRepository_GetDeadLetter_Call{Call: _e.mock.On("GetDeadLetter", ctx, id)}
+}
+
+func (_c *MockISmsRepository_GetDeadLetter_Call) Run(run func(ctx context.Context, id string)) *MockISmsRepository_GetDeadLetter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsRepository_GetDeadLetter_Call) Return(deadLetter models.DeadLetter, err error) *MockISmsRepository_GetDeadLetter_Call {
+	_c.Call.Return(deadLetter, err)
+	return _c
+}
+
+func (_c *MockISmsRepository_GetDeadLetter_Call) RunAndReturn(run func(ctx context.Context, id string) (models.DeadLetter, error)) *MockISmsRepository_GetDeadLetter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDeadLetters provides a mock function for the type MockISmsRepository
+func (_mock *MockISmsRepository) GetDeadLetters(ctx context.Context, skip int, limit int) ([]models.DeadLetter, error) {
+	ret := _mock.Called(ctx, skip, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeadLetters")
+	}
+
+	var r0 []models.DeadLetter
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]models.DeadLetter, error)); ok {
+		return returnFunc(ctx, skip, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []models.DeadLetter); ok {
+		r0 = returnFunc(ctx, skip, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.DeadLetter)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = returnFunc(ctx, skip, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsRepository_GetDeadLetters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDeadLetters'
+type MockISmsRepository_GetDeadLetters_Call struct {
+	*mock.Call
+}
+
+// GetDeadLetters is a helper method to define mock.On call
+//   - ctx context.Context
+//   - skip int
+//   - limit int
+func (_e *MockISmsRepository_Expecter) GetDeadLetters(ctx interface{}, skip interface{}, limit interface{}) *MockISmsRepository_GetDeadLetters_Call {
+	return &MockISmsRepository_GetDeadLetters_Call{Call: _e.mock.On("GetDeadLetters", ctx, skip, limit)}
+}
+
+func (_c *MockISmsRepository_GetDeadLetters_Call) Run(run func(ctx context.Context, skip int, limit int)) *MockISmsRepository_GetDeadLetters_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsRepository_GetDeadLetters_Call) Return(deadLetters []models.DeadLetter, err error) *MockISmsRepository_GetDeadLetters_Call {
+	_c.Call.Return(deadLetters, err)
+	return _c
+}
+
+func (_c *MockISmsRepository_GetDeadLetters_Call) RunAndReturn(run func(ctx context.Context, skip int, limit int) ([]models.DeadLetter, error)) *MockISmsRepository_GetDeadLetters_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMessagesByUserId provides a mock function for the type MockISmsRepository
+func (_mock *MockISmsRepository) GetMessagesByUserId(ctx context.Context, userId string, skip int, limit int, desc bool) ([]models.Sms, error) {
+	ret := _mock.Called(ctx, userId, skip, limit, desc)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessagesByUserId")
+	}
+
+	var r0 []models.Sms
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int, bool) ([]models.Sms, error)); ok {
+		return returnFunc(ctx, userId, skip, limit, desc)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int, bool) []models.Sms); ok {
+		r0 = returnFunc(ctx, userId, skip, limit, desc)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Sms)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, int, bool) error); ok {
+		r1 = returnFunc(ctx, userId, skip, limit, desc)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsRepository_GetMessagesByUserId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessagesByUserId'
+type MockISmsRepository_GetMessagesByUserId_Call struct {
+	*mock.Call
+}
+
+// GetMessagesByUserId is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId string
+//   - skip int
+//   - limit int
+//   - desc bool
+func (_e *MockISmsRepository_Expecter) GetMessagesByUserId(ctx interface{}, userId interface{}, skip interface{}, limit interface{}, desc interface{}) *MockISmsRepository_GetMessagesByUserId_Call {
+	return &MockISmsRepository_GetMessagesByUserId_Call{Call: _e.mock.On("GetMessagesByUserId", ctx, userId, skip, limit, desc)}
+}
+
+func (_c *MockISmsRepository_GetMessagesByUserId_Call) Run(run func(ctx context.Context, userId string, skip int, limit int, desc bool)) *MockISmsRepository_GetMessagesByUserId_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		var arg4 bool
+		if args[4] != nil {
+			arg4 = args[4].(bool)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsRepository_GetMessagesByUserId_Call) Return(smss []models.Sms, err error) *MockISmsRepository_GetMessagesByUserId_Call {
+	_c.Call.Return(smss, err)
+	return _c
+}
+
+func (_c *MockISmsRepository_GetMessagesByUserId_Call) RunAndReturn(run func(ctx context.Context, userId string, skip int, limit int, desc bool) ([]models.Sms, error)) *MockISmsRepository_GetMessagesByUserId_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetStaleEnqueuedMessages provides a mock function for the type MockISmsRepository
+func (_mock *MockISmsRepository) GetStaleEnqueuedMessages(ctx context.Context, enqueuedBefore time.Time, limit int) ([]models.Sms, error) {
+	ret := _mock.Called(ctx, enqueuedBefore, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStaleEnqueuedMessages")
+	}
+
+	var r0 []models.Sms
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]models.Sms, error)); ok {
+		return returnFunc(ctx, enqueuedBefore, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) []models.Sms); ok {
+		r0 = returnFunc(ctx, enqueuedBefore, limit)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Sms)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = returnFunc(ctx, enqueuedBefore, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockISmsRepository_CreateScheduleMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateScheduleMessages'
-type MockISmsRepository_CreateScheduleMessages_Call struct {
+// MockISmsRepository_GetStaleEnqueuedMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetStaleEnqueuedMessages'
+type MockISmsRepository_GetStaleEnqueuedMessages_Call struct {
 	*mock.Call
 }
 
-// CreateScheduleMessages is a helper method to define mock.On call
+// GetStaleEnqueuedMessages is a helper method to define mock.On call
 //   - ctx context.Context
-//   - msgs []models.Sms
-func (_e *MockISmsRepository_Expecter) CreateScheduleMessages(ctx interface{}, msgs interface{}) *MockISmsRepository_CreateScheduleMessages_Call {
-	return &MockISmsRepository_CreateScheduleMessages_Call{Call: _e.mock.On("CreateScheduleMessages", ctx, msgs)}
+//   - enqueuedBefore time.Time
+//   - limit int
+func (_e *MockISmsRepository_Expecter) GetStaleEnqueuedMessages(ctx interface{}, enqueuedBefore interface{}, limit interface{}) *MockISmsRepository_GetStaleEnqueuedMessages_Call {
+	return &MockISmsRepository_GetStaleEnqueuedMessages_Call{Call: _e.mock.On("GetStaleEnqueuedMessages", ctx, enqueuedBefore, limit)}
 }
 
-func (_c *MockISmsRepository_CreateScheduleMessages_Call) Run(run func(ctx context.Context, msgs []models.Sms)) *MockISmsRepository_CreateScheduleMessages_Call {
+func (_c *MockISmsRepository_GetStaleEnqueuedMessages_Call) Run(run func(ctx context.Context, enqueuedBefore time.Time, limit int)) *MockISmsRepository_GetStaleEnqueuedMessages_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 []models.Sms
+		var arg1 time.Time
 		if args[1] != nil {
-			arg1 = args[1].([]models.Sms)
+			arg1 = args[1].(time.Time)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockISmsRepository_CreateScheduleMessages_Call) Return(err error) *MockISmsRepository_CreateScheduleMessages_Call {
-	_c.Call.Return(err)
+func (_c *MockISmsRepository_GetStaleEnqueuedMessages_Call) Return(smss []models.Sms, err error) *MockISmsRepository_GetStaleEnqueuedMessages_Call {
+	_c.Call.Return(smss, err)
 	return _c
 }
 
-func (_c *MockISmsRepository_CreateScheduleMessages_Call) RunAndReturn(run func(ctx context.Context, msgs []models.Sms) error) *MockISmsRepository_CreateScheduleMessages_Call {
+func (_c *MockISmsRepository_GetStaleEnqueuedMessages_Call) RunAndReturn(run func(ctx context.Context, enqueuedBefore time.Time, limit int) ([]models.Sms, error)) *MockISmsRepository_GetStaleEnqueuedMessages_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// EnqueueMessages provides a mock function for the type MockISmsRepository
-func (_mock *MockISmsRepository) EnqueueMessages(ctx context.Context, count int) ([]models.Sms, error) {
-	ret := _mock.Called(ctx, count)
+// PurgeDeadLetters provides a mock function for the type MockISmsRepository
+func (_mock *MockISmsRepository) PurgeDeadLetters(ctx context.Context) (int, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for EnqueueMessages")
+		panic("no return value specified for PurgeDeadLetters")
 	}
 
-	var r0 []models.Sms
+	var r0 int
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]models.Sms, error)); ok {
-		return returnFunc(ctx, count)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []models.Sms); ok {
-		r0 = returnFunc(ctx, count)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = returnFunc(ctx)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Sms)
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsRepository_PurgeDeadLetters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeDeadLetters'
+type MockISmsRepository_PurgeDeadLetters_Call struct {
+	*mock.Call
+}
+
+// PurgeDeadLetters is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockISmsRepository_Expecter) PurgeDeadLetters(ctx interface{}) *MockISmsRepository_PurgeDeadLetters_Call {
+	return &MockISmsRepository_PurgeDeadLetters_Call{Call: _e.mock.On("PurgeDeadLetters", ctx)}
+}
+
+func (_c *MockISmsRepository_PurgeDeadLetters_Call) Run(run func(ctx context.Context)) *MockISmsRepository_PurgeDeadLetters_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
 		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsRepository_PurgeDeadLetters_Call) Return(n int, err error) *MockISmsRepository_PurgeDeadLetters_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockISmsRepository_PurgeDeadLetters_Call) RunAndReturn(run func(ctx context.Context) (int, error)) *MockISmsRepository_PurgeDeadLetters_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RequeueDeadLetter provides a mock function for the type MockISmsRepository
+func (_mock *MockISmsRepository) RequeueDeadLetter(ctx context.Context, id string) (models.Sms, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequeueDeadLetter")
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = returnFunc(ctx, count)
+
+	var r0 models.Sms
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (models.Sms, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) models.Sms); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Sms)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockISmsRepository_EnqueueMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnqueueMessages'
-type MockISmsRepository_EnqueueMessages_Call struct {
+// MockISmsRepository_RequeueDeadLetter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequeueDeadLetter'
+type MockISmsRepository_RequeueDeadLetter_Call struct {
 	*mock.Call
 }
 
-// EnqueueMessages is a helper method to define mock.On call
+// RequeueDeadLetter is a helper method to define mock.On call
 //   - ctx context.Context
-//   - count int
-func (_e *MockISmsRepository_Expecter) EnqueueMessages(ctx interface{}, count interface{}) *MockISmsRepository_EnqueueMessages_Call {
-	return &MockISmsRepository_EnqueueMessages_Call{Call: _e.mock.On("EnqueueMessages", ctx, count)}
+//   - id string
+func (_e *MockISmsRepository_Expecter) RequeueDeadLetter(ctx interface{}, id interface{}) *MockISmsRepository_RequeueDeadLetter_Call {
+	return &MockISmsRepository_RequeueDeadLetter_Call{Call: _e.mock.On("RequeueDeadLetter", ctx, id)}
 }
 
-func (_c *MockISmsRepository_EnqueueMessages_Call) Run(run func(ctx context.Context, count int)) *MockISmsRepository_EnqueueMessages_Call {
+func (_c *MockISmsRepository_RequeueDeadLetter_Call) Run(run func(ctx context.Context, id string)) *MockISmsRepository_RequeueDeadLetter_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 int
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(int)
+			arg1 = args[1].(string)
 		}
 		run(
 			arg0,
@@ -153,98 +855,84 @@ func (_c *MockISmsRepository_EnqueueMessages_Call) Run(run func(ctx context.Cont
 	return _c
 }
 
-func (_c *MockISmsRepository_EnqueueMessages_Call) Return(smss []models.Sms, err error) *MockISmsRepository_EnqueueMessages_Call {
-	_c.Call.Return(smss, err)
+func (_c *MockISmsRepository_RequeueDeadLetter_Call) Return(sms models.Sms, err error) *MockISmsRepository_RequeueDeadLetter_Call {
+	_c.Call.Return(sms, err)
 	return _c
 }
 
-func (_c *MockISmsRepository_EnqueueMessages_Call) RunAndReturn(run func(ctx context.Context, count int) ([]models.Sms, error)) *MockISmsRepository_EnqueueMessages_Call {
+func (_c *MockISmsRepository_RequeueDeadLetter_Call) RunAndReturn(run func(ctx context.Context, id string) (models.Sms, error)) *MockISmsRepository_RequeueDeadLetter_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetMessagesByUserId provides a mock function for the type MockISmsRepository
-func (_mock *MockISmsRepository) GetMessagesByUserId(ctx context.Context, userId string, skip int, limit int, desc bool) ([]models.Sms, error) {
-	ret := _mock.Called(ctx, userId, skip, limit, desc)
+// RescheduleStaleMessages provides a mock function for the type MockISmsRepository
+func (_mock *MockISmsRepository) RescheduleStaleMessages(ctx context.Context, ids []string, enqueuedBefore time.Time) (int, error) {
+	ret := _mock.Called(ctx, ids, enqueuedBefore)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetMessagesByUserId")
+		panic("no return value specified for RescheduleStaleMessages")
 	}
 
-	var r0 []models.Sms
+	var r0 int
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int, bool) ([]models.Sms, error)); ok {
-		return returnFunc(ctx, userId, skip, limit, desc)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, time.Time) (int, error)); ok {
+		return returnFunc(ctx, ids, enqueuedBefore)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int, bool) []models.Sms); ok {
-		r0 = returnFunc(ctx, userId, skip, limit, desc)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, time.Time) int); ok {
+		r0 = returnFunc(ctx, ids, enqueuedBefore)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Sms)
-		}
+		r0 = ret.Get(0).(int)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, int, bool) error); ok {
-		r1 = returnFunc(ctx, userId, skip, limit, desc)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string, time.Time) error); ok {
+		r1 = returnFunc(ctx, ids, enqueuedBefore)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockISmsRepository_GetMessagesByUserId_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessagesByUserId'
-type MockISmsRepository_GetMessagesByUserId_Call struct {
+// MockISmsRepository_RescheduleStaleMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RescheduleStaleMessages'
+type MockISmsRepository_RescheduleStaleMessages_Call struct {
 	*mock.Call
 }
 
-// GetMessagesByUserId is a helper method to define mock.On call
+// RescheduleStaleMessages is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userId string
-//   - skip int
-//   - limit int
-//   - desc bool
-func (_e *MockISmsRepository_Expecter) GetMessagesByUserId(ctx interface{}, userId interface{}, skip interface{}, limit interface{}, desc interface{}) *MockISmsRepository_GetMessagesByUserId_Call {
-	return &MockISmsRepository_GetMessagesByUserId_Call{Call: _e.mock.On("GetMessagesByUserId", ctx, userId, skip, limit, desc)}
+//   - ids []string
+//   - enqueuedBefore time.Time
+func (_e *MockISmsRepository_Expecter) RescheduleStaleMessages(ctx interface{}, ids interface{}, enqueuedBefore interface{}) *MockISmsRepository_RescheduleStaleMessages_Call {
+	return &MockISmsRepository_RescheduleStaleMessages_Call{Call: _e.mock.On("RescheduleStaleMessages", ctx, ids, enqueuedBefore)}
 }
 
-func (_c *MockISmsRepository_GetMessagesByUserId_Call) Run(run func(ctx context.Context, userId string, skip int, limit int, desc bool)) *MockISmsRepository_GetMessagesByUserId_Call {
+func (_c *MockISmsRepository_RescheduleStaleMessages_Call) Run(run func(ctx context.Context, ids []string, enqueuedBefore time.Time)) *MockISmsRepository_RescheduleStaleMessages_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 []string
 		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg1 = args[1].([]string)
 		}
-		var arg2 int
+		var arg2 time.Time
 		if args[2] != nil {
-			arg2 = args[2].(int)
-		}
-		var arg3 int
-		if args[3] != nil {
-			arg3 = args[3].(int)
-		}
-		var arg4 bool
-		if args[4] != nil {
-			arg4 = args[4].(bool)
+			arg2 = args[2].(time.Time)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
-			arg3,
-			arg4,
 		)
 	})
 	return _c
 }
 
-func (_c *MockISmsRepository_GetMessagesByUserId_Call) Return(smss []models.Sms, err error) *MockISmsRepository_GetMessagesByUserId_Call {
-	_c.Call.Return(smss, err)
+func (_c *MockISmsRepository_RescheduleStaleMessages_Call) Return(n int, err error) *MockISmsRepository_RescheduleStaleMessages_Call {
+	_c.Call.Return(n, err)
 	return _c
 }
 
-func (_c *MockISmsRepository_GetMessagesByUserId_Call) RunAndReturn(run func(ctx context.Context, userId string, skip int, limit int, desc bool) ([]models.Sms, error)) *MockISmsRepository_GetMessagesByUserId_Call {
+func (_c *MockISmsRepository_RescheduleStaleMessages_Call) RunAndReturn(run func(ctx context.Context, ids []string, enqueuedBefore time.Time) (int, error)) *MockISmsRepository_RescheduleStaleMessages_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -372,9 +1060,81 @@ func (_c *MockISmsRepository_SetMessageAsFailed_Call) RunAndReturn(run func(ctx
 	return _c
 }
 
+// SetMessageAsRetrying provides a mock function for the type MockISmsRepository
+func (_mock *MockISmsRepository) SetMessageAsRetrying(ctx context.Context, id string, nextAttemptAt time.Time) (models.Sms, error) {
+	ret := _mock.Called(ctx, id, nextAttemptAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMessageAsRetrying")
+	}
+
+	var r0 models.Sms
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time) (models.Sms, error)); ok {
+		return returnFunc(ctx, id, nextAttemptAt)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time) models.Sms); ok {
+		r0 = returnFunc(ctx, id, nextAttemptAt)
+	} else {
+		r0 = ret.Get(0).(models.Sms)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = returnFunc(ctx, id, nextAttemptAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsRepository_SetMessageAsRetrying_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetMessageAsRetrying'
+type MockISmsRepository_SetMessageAsRetrying_Call struct {
+	*mock.Call
+}
+
+// SetMessageAsRetrying is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - nextAttemptAt time.Time
+func (_e *MockISmsRepository_Expecter) SetMessageAsRetrying(ctx interface{}, id interface{}, nextAttemptAt interface{}) *MockISmsRepository_SetMessageAsRetrying_Call {
+	return &MockISmsRepository_SetMessageAsRetrying_Call{Call: _e.mock.On("SetMessageAsRetrying", ctx, id, nextAttemptAt)}
+}
+
+func (_c *MockISmsRepository_SetMessageAsRetrying_Call) Run(run func(ctx context.Context, id string, nextAttemptAt time.Time)) *MockISmsRepository_SetMessageAsRetrying_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsRepository_SetMessageAsRetrying_Call) Return(sms models.Sms, err error) *MockISmsRepository_SetMessageAsRetrying_Call {
+	_c.Call.Return(sms, err)
+	return _c
+}
+
+func (_c *MockISmsRepository_SetMessageAsRetrying_Call) RunAndReturn(run func(ctx context.Context, id string, nextAttemptAt time.Time) (models.Sms, error)) *MockISmsRepository_SetMessageAsRetrying_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetMessageAsSent provides a mock function for the type MockISmsRepository
-func (_mock *MockISmsRepository) SetMessageAsSent(ctx context.Context, id string) (models.Sms, error) {
-	ret := _mock.Called(ctx, id)
+func (_mock *MockISmsRepository) SetMessageAsSent(ctx context.Context, id string, res models.SendResult) (models.Sms, error) {
+	ret := _mock.Called(ctx, id, res)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SetMessageAsSent")
@@ -382,16 +1142,16 @@ func (_mock *MockISmsRepository) SetMessageAsSent(ctx context.Context, id string
 
 	var r0 models.Sms
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (models.Sms, error)); ok {
-		return returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.SendResult) (models.Sms, error)); ok {
+		return returnFunc(ctx, id, res)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) models.Sms); ok {
-		r0 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.SendResult) models.Sms); ok {
+		r0 = returnFunc(ctx, id, res)
 	} else {
 		r0 = ret.Get(0).(models.Sms)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, models.SendResult) error); ok {
+		r1 = returnFunc(ctx, id, res)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -406,11 +1166,12 @@ type MockISmsRepository_SetMessageAsSent_Call struct {
 // SetMessageAsSent is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id string
-func (_e *MockISmsRepository_Expecter) SetMessageAsSent(ctx interface{}, id interface{}) *MockISmsRepository_SetMessageAsSent_Call {
-	return &MockISmsRepository_SetMessageAsSent_Call{Call: _e.mock.On("SetMessageAsSent", ctx, id)}
+//   - res models.SendResult
+func (_e *MockISmsRepository_Expecter) SetMessageAsSent(ctx interface{}, id interface{}, res interface{}) *MockISmsRepository_SetMessageAsSent_Call {
+	return &MockISmsRepository_SetMessageAsSent_Call{Call: _e.mock.On("SetMessageAsSent", ctx, id, res)}
 }
 
-func (_c *MockISmsRepository_SetMessageAsSent_Call) Run(run func(ctx context.Context, id string)) *MockISmsRepository_SetMessageAsSent_Call {
+func (_c *MockISmsRepository_SetMessageAsSent_Call) Run(run func(ctx context.Context, id string, res models.SendResult)) *MockISmsRepository_SetMessageAsSent_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -420,9 +1181,14 @@ func (_c *MockISmsRepository_SetMessageAsSent_Call) Run(run func(ctx context.Con
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
+		var arg2 models.SendResult
+		if args[2] != nil {
+			arg2 = args[2].(models.SendResult)
+		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -433,7 +1199,73 @@ func (_c *MockISmsRepository_SetMessageAsSent_Call) Return(sms models.Sms, err e
 	return _c
 }
 
-func (_c *MockISmsRepository_SetMessageAsSent_Call) RunAndReturn(run func(ctx context.Context, id string) (models.Sms, error)) *MockISmsRepository_SetMessageAsSent_Call {
+func (_c *MockISmsRepository_SetMessageAsSent_Call) RunAndReturn(run func(ctx context.Context, id string, res models.SendResult) (models.Sms, error)) *MockISmsRepository_SetMessageAsSent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetMessageDeliveryState provides a mock function for the type MockISmsRepository
+func (_mock *MockISmsRepository) SetMessageDeliveryState(ctx context.Context, report models.DeliveryReport) (models.Sms, error) {
+	ret := _mock.Called(ctx, report)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMessageDeliveryState")
+	}
+
+	var r0 models.Sms
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.DeliveryReport) (models.Sms, error)); ok {
+		return returnFunc(ctx, report)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.DeliveryReport) models.Sms); ok {
+		r0 = returnFunc(ctx, report)
+	} else {
+		r0 = ret.Get(0).(models.Sms)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.DeliveryReport) error); ok {
+		r1 = returnFunc(ctx, report)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsRepository_SetMessageDeliveryState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetMessageDeliveryState'
+type MockISmsRepository_SetMessageDeliveryState_Call struct {
+	*mock.Call
+}
+
+// SetMessageDeliveryState is a helper method to define mock.On call
+//   - ctx context.Context
+//   - report models.DeliveryReport
+func (_e *MockISmsRepository_Expecter) SetMessageDeliveryState(ctx interface{}, report interface{}) *MockISmsRepository_SetMessageDeliveryState_Call {
+	return &MockISmsRepository_SetMessageDeliveryState_Call{Call: _e.mock.On("SetMessageDeliveryState", ctx, report)}
+}
+
+func (_c *MockISmsRepository_SetMessageDeliveryState_Call) Run(run func(ctx context.Context, report models.DeliveryReport)) *MockISmsRepository_SetMessageDeliveryState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.DeliveryReport
+		if args[1] != nil {
+			arg1 = args[1].(models.DeliveryReport)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsRepository_SetMessageDeliveryState_Call) Return(sms models.Sms, err error) *MockISmsRepository_SetMessageDeliveryState_Call {
+	_c.Call.Return(sms, err)
+	return _c
+}
+
+func (_c *MockISmsRepository_SetMessageDeliveryState_Call) RunAndReturn(run func(ctx context.Context, report models.DeliveryReport) (models.Sms, error)) *MockISmsRepository_SetMessageDeliveryState_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -39,20 +39,29 @@ func (_m *MockISmsSender) EXPECT() *MockISmsSender_Expecter {
 }
 
 // Send provides a mock function for the type MockISmsSender
-func (_mock *MockISmsSender) Send(ctx context.Context, msg models.Sms) error {
+func (_mock *MockISmsSender) Send(ctx context.Context, msg models.Sms) (models.SendResult, error) {
 	ret := _mock.Called(ctx, msg)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Send")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Sms) error); ok {
+	var r0 models.SendResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Sms) (models.SendResult, error)); ok {
+		return returnFunc(ctx, msg)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.Sms) models.SendResult); ok {
 		r0 = returnFunc(ctx, msg)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(models.SendResult)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.Sms) error); ok {
+		r1 = returnFunc(ctx, msg)
+	} else {
+		r1 = ret.Error(1)
 	}
-	return r0
+	return r0, r1
 }
 
 // MockISmsSender_Send_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Send'
@@ -85,12 +94,12 @@ func (_c *MockISmsSender_Send_Call) Run(run func(ctx context.Context, msg models
 	return _c
 }
 
-func (_c *MockISmsSender_Send_Call) Return(err error) *MockISmsSender_Send_Call {
-	_c.Call.Return(err)
+func (_c *MockISmsSender_Send_Call) Return(sendResult models.SendResult, err error) *MockISmsSender_Send_Call {
+	_c.Call.Return(sendResult, err)
 	return _c
 }
 
-func (_c *MockISmsSender_Send_Call) RunAndReturn(run func(ctx context.Context, msg models.Sms) error) *MockISmsSender_Send_Call {
+func (_c *MockISmsSender_Send_Call) RunAndReturn(run func(ctx context.Context, msg models.Sms) (models.SendResult, error)) *MockISmsSender_Send_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
 	mock "github.com/stretchr/testify/mock"
@@ -38,53 +39,720 @@ func (_m *MockISmsService) EXPECT() *MockISmsService_Expecter {
 	return &MockISmsService_Expecter{mock: &_m.Mock}
 }
 
+// CancelSms provides a mock function for the type MockISmsService
+func (_mock *MockISmsService) CancelSms(ctx context.Context, userId string, id string) (models.Sms, error) {
+	ret := _mock.Called(ctx, userId, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelSms")
+	}
+
+	var r0 models.Sms
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (models.Sms, error)); ok {
+		return returnFunc(ctx, userId, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) models.Sms); ok {
+		r0 = returnFunc(ctx, userId, id)
+	} else {
+		r0 = ret.Get(0).(models.Sms)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, userId, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsService_CancelSms_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelSms'
+type MockISmsService_CancelSms_Call struct {
+	*mock.Call
+}
+
+// CancelSms is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId string
+//   - id string
+func (_e *MockISmsService_Expecter) CancelSms(ctx interface{}, userId interface{}, id interface{}) *MockISmsService_CancelSms_Call {
+	return &MockISmsService_CancelSms_Call{Call: _e.mock.On("CancelSms", ctx, userId, id)}
+}
+
+func (_c *MockISmsService_CancelSms_Call) Run(run func(ctx context.Context, userId string, id string)) *MockISmsService_CancelSms_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsService_CancelSms_Call) Return(sms models.Sms, err error) *MockISmsService_CancelSms_Call {
+	_c.Call.Return(sms, err)
+	return _c
+}
+
+func (_c *MockISmsService_CancelSms_Call) RunAndReturn(run func(ctx context.Context, userId string, id string) (models.Sms, error)) *MockISmsService_CancelSms_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteDeadLetter provides a mock function for the type MockISmsService
+func (_mock *MockISmsService) DeleteDeadLetter(ctx context.Context, id string) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDeadLetter")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockISmsService_DeleteDeadLetter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteDeadLetter'
+type MockISmsService_DeleteDeadLetter_Call struct {
+	*mock.Call
+}
+
+// DeleteDeadLetter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockISmsService_Expecter) DeleteDeadLetter(ctx interface{}, id interface{}) *MockISmsService_DeleteDeadLetter_Call {
+	return &MockISmsService_DeleteDeadLetter_Call{Call: _e.mock.On("DeleteDeadLetter", ctx, id)}
+}
+
+func (_c *MockISmsService_DeleteDeadLetter_Call) Run(run func(ctx context.Context, id string)) *MockISmsService_DeleteDeadLetter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsService_DeleteDeadLetter_Call) Return(err error) *MockISmsService_DeleteDeadLetter_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockISmsService_DeleteDeadLetter_Call) RunAndReturn(run func(ctx context.Context, id string) error) *MockISmsService_DeleteDeadLetter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // EnqueueEarliest provides a mock function for the type MockISmsService
 func (_mock *MockISmsService) EnqueueEarliest(ctx context.Context, count int) (int, error) {
 	ret := _mock.Called(ctx, count)
 
 	if len(ret) == 0 {
-		panic("no return value specified for EnqueueEarliest")
+		panic("no return value specified for EnqueueEarliest")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return returnFunc(ctx, count)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = returnFunc(ctx, count)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, count)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsService_EnqueueEarliest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnqueueEarliest'
+type MockISmsService_EnqueueEarliest_Call struct {
+	*mock.Call
+}
+
+// EnqueueEarliest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - count int
+func (_e *MockISmsService_Expecter) EnqueueEarliest(ctx interface{}, count interface{}) *MockISmsService_EnqueueEarliest_Call {
+	return &MockISmsService_EnqueueEarliest_Call{Call: _e.mock.On("EnqueueEarliest", ctx, count)}
+}
+
+func (_c *MockISmsService_EnqueueEarliest_Call) Run(run func(ctx context.Context, count int)) *MockISmsService_EnqueueEarliest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsService_EnqueueEarliest_Call) Return(n int, err error) *MockISmsService_EnqueueEarliest_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockISmsService_EnqueueEarliest_Call) RunAndReturn(run func(ctx context.Context, count int) (int, error)) *MockISmsService_EnqueueEarliest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDeadLetter provides a mock function for the type MockISmsService
+func (_mock *MockISmsService) GetDeadLetter(ctx context.Context, id string) (models.DeadLetter, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeadLetter")
+	}
+
+	var r0 models.DeadLetter
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (models.DeadLetter, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) models.DeadLetter); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.DeadLetter)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsService_GetDeadLetter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDeadLetter'
+type MockISmsService_GetDeadLetter_Call struct {
+	*mock.Call
+}
+
+// GetDeadLetter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockISmsService_Expecter) GetDeadLetter(ctx interface{}, id interface{}) *MockISmsService_GetDeadLetter_Call {
+	return &MockISmsService_GetDeadLetter_Call{Call: _e.mock.On("GetDeadLetter", ctx, id)}
+}
+
+func (_c *MockISmsService_GetDeadLetter_Call) Run(run func(ctx context.Context, id string)) *MockISmsService_GetDeadLetter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsService_GetDeadLetter_Call) Return(deadLetter models.DeadLetter, err error) *MockISmsService_GetDeadLetter_Call {
+	_c.Call.Return(deadLetter, err)
+	return _c
+}
+
+func (_c *MockISmsService_GetDeadLetter_Call) RunAndReturn(run func(ctx context.Context, id string) (models.DeadLetter, error)) *MockISmsService_GetDeadLetter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDeadLetters provides a mock function for the type MockISmsService
+func (_mock *MockISmsService) GetDeadLetters(ctx context.Context, skip int, limit int) ([]models.DeadLetter, error) {
+	ret := _mock.Called(ctx, skip, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeadLetters")
+	}
+
+	var r0 []models.DeadLetter
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) ([]models.DeadLetter, error)); ok {
+		return returnFunc(ctx, skip, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, int) []models.DeadLetter); ok {
+		r0 = returnFunc(ctx, skip, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.DeadLetter)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = returnFunc(ctx, skip, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsService_GetDeadLetters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDeadLetters'
+type MockISmsService_GetDeadLetters_Call struct {
+	*mock.Call
+}
+
+// GetDeadLetters is a helper method to define mock.On call
+//   - ctx context.Context
+//   - skip int
+//   - limit int
+func (_e *MockISmsService_Expecter) GetDeadLetters(ctx interface{}, skip interface{}, limit interface{}) *MockISmsService_GetDeadLetters_Call {
+	return &MockISmsService_GetDeadLetters_Call{Call: _e.mock.On("GetDeadLetters", ctx, skip, limit)}
+}
+
+func (_c *MockISmsService_GetDeadLetters_Call) Run(run func(ctx context.Context, skip int, limit int)) *MockISmsService_GetDeadLetters_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsService_GetDeadLetters_Call) Return(deadLetters []models.DeadLetter, err error) *MockISmsService_GetDeadLetters_Call {
+	_c.Call.Return(deadLetters, err)
+	return _c
+}
+
+func (_c *MockISmsService_GetDeadLetters_Call) RunAndReturn(run func(ctx context.Context, skip int, limit int) ([]models.DeadLetter, error)) *MockISmsService_GetDeadLetters_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserSms provides a mock function for the type MockISmsService
+func (_mock *MockISmsService) GetUserSms(ctx context.Context, userId string, skip int, limit int, desc bool) ([]models.Sms, error) {
+	ret := _mock.Called(ctx, userId, skip, limit, desc)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserSms")
+	}
+
+	var r0 []models.Sms
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int, bool) ([]models.Sms, error)); ok {
+		return returnFunc(ctx, userId, skip, limit, desc)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int, bool) []models.Sms); ok {
+		r0 = returnFunc(ctx, userId, skip, limit, desc)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Sms)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, int, bool) error); ok {
+		r1 = returnFunc(ctx, userId, skip, limit, desc)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsService_GetUserSms_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserSms'
+type MockISmsService_GetUserSms_Call struct {
+	*mock.Call
+}
+
+// GetUserSms is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId string
+//   - skip int
+//   - limit int
+//   - desc bool
+func (_e *MockISmsService_Expecter) GetUserSms(ctx interface{}, userId interface{}, skip interface{}, limit interface{}, desc interface{}) *MockISmsService_GetUserSms_Call {
+	return &MockISmsService_GetUserSms_Call{Call: _e.mock.On("GetUserSms", ctx, userId, skip, limit, desc)}
+}
+
+func (_c *MockISmsService_GetUserSms_Call) Run(run func(ctx context.Context, userId string, skip int, limit int, desc bool)) *MockISmsService_GetUserSms_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		var arg4 bool
+		if args[4] != nil {
+			arg4 = args[4].(bool)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsService_GetUserSms_Call) Return(smss []models.Sms, err error) *MockISmsService_GetUserSms_Call {
+	_c.Call.Return(smss, err)
+	return _c
+}
+
+func (_c *MockISmsService_GetUserSms_Call) RunAndReturn(run func(ctx context.Context, userId string, skip int, limit int, desc bool) ([]models.Sms, error)) *MockISmsService_GetUserSms_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ProcessDeliveryReport provides a mock function for the type MockISmsService
+func (_mock *MockISmsService) ProcessDeliveryReport(ctx context.Context, report models.DeliveryReport) (models.Sms, error) {
+	ret := _mock.Called(ctx, report)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProcessDeliveryReport")
+	}
+
+	var r0 models.Sms
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.DeliveryReport) (models.Sms, error)); ok {
+		return returnFunc(ctx, report)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.DeliveryReport) models.Sms); ok {
+		r0 = returnFunc(ctx, report)
+	} else {
+		r0 = ret.Get(0).(models.Sms)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.DeliveryReport) error); ok {
+		r1 = returnFunc(ctx, report)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsService_ProcessDeliveryReport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ProcessDeliveryReport'
+type MockISmsService_ProcessDeliveryReport_Call struct {
+	*mock.Call
+}
+
+// ProcessDeliveryReport is a helper method to define mock.On call
+//   - ctx context.Context
+//   - report models.DeliveryReport
+func (_e *MockISmsService_Expecter) ProcessDeliveryReport(ctx interface{}, report interface{}) *MockISmsService_ProcessDeliveryReport_Call {
+	return &MockISmsService_ProcessDeliveryReport_Call{Call: _e.mock.On("ProcessDeliveryReport", ctx, report)}
+}
+
+func (_c *MockISmsService_ProcessDeliveryReport_Call) Run(run func(ctx context.Context, report models.DeliveryReport)) *MockISmsService_ProcessDeliveryReport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.DeliveryReport
+		if args[1] != nil {
+			arg1 = args[1].(models.DeliveryReport)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsService_ProcessDeliveryReport_Call) Return(sms models.Sms, err error) *MockISmsService_ProcessDeliveryReport_Call {
+	_c.Call.Return(sms, err)
+	return _c
+}
+
+func (_c *MockISmsService_ProcessDeliveryReport_Call) RunAndReturn(run func(ctx context.Context, report models.DeliveryReport) (models.Sms, error)) *MockISmsService_ProcessDeliveryReport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PurgeDeadLetters provides a mock function for the type MockISmsService
+func (_mock *MockISmsService) PurgeDeadLetters(ctx context.Context) (int, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeadLetters")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsService_PurgeDeadLetters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeDeadLetters'
+type MockISmsService_PurgeDeadLetters_Call struct {
+	*mock.Call
+}
+
+// PurgeDeadLetters is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockISmsService_Expecter) PurgeDeadLetters(ctx interface{}) *MockISmsService_PurgeDeadLetters_Call {
+	return &MockISmsService_PurgeDeadLetters_Call{Call: _e.mock.On("PurgeDeadLetters", ctx)}
+}
+
+func (_c *MockISmsService_PurgeDeadLetters_Call) Run(run func(ctx context.Context)) *MockISmsService_PurgeDeadLetters_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsService_PurgeDeadLetters_Call) Return(n int, err error) *MockISmsService_PurgeDeadLetters_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockISmsService_PurgeDeadLetters_Call) RunAndReturn(run func(ctx context.Context) (int, error)) *MockISmsService_PurgeDeadLetters_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReconcileStuckMessages provides a mock function for the type MockISmsService
+func (_mock *MockISmsService) ReconcileStuckMessages(ctx context.Context) (models.ReconcileResult, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReconcileStuckMessages")
+	}
+
+	var r0 models.ReconcileResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (models.ReconcileResult, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) models.ReconcileResult); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(models.ReconcileResult)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsService_ReconcileStuckMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReconcileStuckMessages'
+type MockISmsService_ReconcileStuckMessages_Call struct {
+	*mock.Call
+}
+
+// ReconcileStuckMessages is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockISmsService_Expecter) ReconcileStuckMessages(ctx interface{}) *MockISmsService_ReconcileStuckMessages_Call {
+	return &MockISmsService_ReconcileStuckMessages_Call{Call: _e.mock.On("ReconcileStuckMessages", ctx)}
+}
+
+func (_c *MockISmsService_ReconcileStuckMessages_Call) Run(run func(ctx context.Context)) *MockISmsService_ReconcileStuckMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsService_ReconcileStuckMessages_Call) Return(reconcileResult models.ReconcileResult, err error) *MockISmsService_ReconcileStuckMessages_Call {
+	_c.Call.Return(reconcileResult, err)
+	return _c
+}
+
+func (_c *MockISmsService_ReconcileStuckMessages_Call) RunAndReturn(run func(ctx context.Context) (models.ReconcileResult, error)) *MockISmsService_ReconcileStuckMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecoverUnacked provides a mock function for the type MockISmsService
+func (_mock *MockISmsService) RecoverUnacked(ctx context.Context) (int, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecoverUnacked")
 	}
 
 	var r0 int
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
-		return returnFunc(ctx, count)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return returnFunc(ctx)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) int); ok {
-		r0 = returnFunc(ctx, count)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = returnFunc(ctx, count)
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockISmsService_EnqueueEarliest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnqueueEarliest'
-type MockISmsService_EnqueueEarliest_Call struct {
+// MockISmsService_RecoverUnacked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecoverUnacked'
+type MockISmsService_RecoverUnacked_Call struct {
 	*mock.Call
 }
 
-// EnqueueEarliest is a helper method to define mock.On call
+// RecoverUnacked is a helper method to define mock.On call
 //   - ctx context.Context
-//   - count int
-func (_e *MockISmsService_Expecter) EnqueueEarliest(ctx interface{}, count interface{}) *MockISmsService_EnqueueEarliest_Call {
-	return &MockISmsService_EnqueueEarliest_Call{Call: _e.mock.On("EnqueueEarliest", ctx, count)}
+func (_e *MockISmsService_Expecter) RecoverUnacked(ctx interface{}) *MockISmsService_RecoverUnacked_Call {
+	return &MockISmsService_RecoverUnacked_Call{Call: _e.mock.On("RecoverUnacked", ctx)}
 }
 
-func (_c *MockISmsService_EnqueueEarliest_Call) Run(run func(ctx context.Context, count int)) *MockISmsService_EnqueueEarliest_Call {
+func (_c *MockISmsService_RecoverUnacked_Call) Run(run func(ctx context.Context)) *MockISmsService_RecoverUnacked_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 int
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsService_RecoverUnacked_Call) Return(n int, err error) *MockISmsService_RecoverUnacked_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockISmsService_RecoverUnacked_Call) RunAndReturn(run func(ctx context.Context) (int, error)) *MockISmsService_RecoverUnacked_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RequeueDeadLetter provides a mock function for the type MockISmsService
+func (_mock *MockISmsService) RequeueDeadLetter(ctx context.Context, id string) (models.Sms, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequeueDeadLetter")
+	}
+
+	var r0 models.Sms
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (models.Sms, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) models.Sms); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Sms)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsService_RequeueDeadLetter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RequeueDeadLetter'
+type MockISmsService_RequeueDeadLetter_Call struct {
+	*mock.Call
+}
+
+// RequeueDeadLetter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockISmsService_Expecter) RequeueDeadLetter(ctx interface{}, id interface{}) *MockISmsService_RequeueDeadLetter_Call {
+	return &MockISmsService_RequeueDeadLetter_Call{Call: _e.mock.On("RequeueDeadLetter", ctx, id)}
+}
+
+func (_c *MockISmsService_RequeueDeadLetter_Call) Run(run func(ctx context.Context, id string)) *MockISmsService_RequeueDeadLetter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(int)
+			arg1 = args[1].(string)
 		}
 		run(
 			arg0,
@@ -94,60 +762,57 @@ func (_c *MockISmsService_EnqueueEarliest_Call) Run(run func(ctx context.Context
 	return _c
 }
 
-func (_c *MockISmsService_EnqueueEarliest_Call) Return(n int, err error) *MockISmsService_EnqueueEarliest_Call {
-	_c.Call.Return(n, err)
+func (_c *MockISmsService_RequeueDeadLetter_Call) Return(sms models.Sms, err error) *MockISmsService_RequeueDeadLetter_Call {
+	_c.Call.Return(sms, err)
 	return _c
 }
 
-func (_c *MockISmsService_EnqueueEarliest_Call) RunAndReturn(run func(ctx context.Context, count int) (int, error)) *MockISmsService_EnqueueEarliest_Call {
+func (_c *MockISmsService_RequeueDeadLetter_Call) RunAndReturn(run func(ctx context.Context, id string) (models.Sms, error)) *MockISmsService_RequeueDeadLetter_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserSms provides a mock function for the type MockISmsService
-func (_mock *MockISmsService) GetUserSms(ctx context.Context, userId string, skip int, limit int, desc bool) ([]models.Sms, error) {
-	ret := _mock.Called(ctx, userId, skip, limit, desc)
+// RescheduleSms provides a mock function for the type MockISmsService
+func (_mock *MockISmsService) RescheduleSms(ctx context.Context, userId string, id string, sendAt time.Time) (models.Sms, error) {
+	ret := _mock.Called(ctx, userId, id, sendAt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserSms")
+		panic("no return value specified for RescheduleSms")
 	}
 
-	var r0 []models.Sms
+	var r0 models.Sms
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int, bool) ([]models.Sms, error)); ok {
-		return returnFunc(ctx, userId, skip, limit, desc)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (models.Sms, error)); ok {
+		return returnFunc(ctx, userId, id, sendAt)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, int, bool) []models.Sms); ok {
-		r0 = returnFunc(ctx, userId, skip, limit, desc)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Time) models.Sms); ok {
+		r0 = returnFunc(ctx, userId, id, sendAt)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Sms)
-		}
+		r0 = ret.Get(0).(models.Sms)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, int, bool) error); ok {
-		r1 = returnFunc(ctx, userId, skip, limit, desc)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = returnFunc(ctx, userId, id, sendAt)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockISmsService_GetUserSms_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserSms'
-type MockISmsService_GetUserSms_Call struct {
+// MockISmsService_RescheduleSms_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RescheduleSms'
+type MockISmsService_RescheduleSms_Call struct {
 	*mock.Call
 }
 
-// GetUserSms is a helper method to define mock.On call
+// RescheduleSms is a helper method to define mock.On call
 //   - ctx context.Context
 //   - userId string
-//   - skip int
-//   - limit int
-//   - desc bool
-func (_e *MockISmsService_Expecter) GetUserSms(ctx interface{}, userId interface{}, skip interface{}, limit interface{}, desc interface{}) *MockISmsService_GetUserSms_Call {
-	return &MockISmsService_GetUserSms_Call{Call: _e.mock.On("GetUserSms", ctx, userId, skip, limit, desc)}
+//   - id string
+//   - sendAt time.Time
+func (_e *MockISmsService_Expecter) RescheduleSms(ctx interface{}, userId interface{}, id interface{}, sendAt interface{}) *MockISmsService_RescheduleSms_Call {
+	return &MockISmsService_RescheduleSms_Call{Call: _e.mock.On("RescheduleSms", ctx, userId, id, sendAt)}
 }
 
-func (_c *MockISmsService_GetUserSms_Call) Run(run func(ctx context.Context, userId string, skip int, limit int, desc bool)) *MockISmsService_GetUserSms_Call {
+func (_c *MockISmsService_RescheduleSms_Call) Run(run func(ctx context.Context, userId string, id string, sendAt time.Time)) *MockISmsService_RescheduleSms_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -157,54 +822,60 @@ func (_c *MockISmsService_GetUserSms_Call) Run(run func(ctx context.Context, use
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
-		var arg2 int
+		var arg2 string
 		if args[2] != nil {
-			arg2 = args[2].(int)
+			arg2 = args[2].(string)
 		}
-		var arg3 int
+		var arg3 time.Time
 		if args[3] != nil {
-			arg3 = args[3].(int)
-		}
-		var arg4 bool
-		if args[4] != nil {
-			arg4 = args[4].(bool)
+			arg3 = args[3].(time.Time)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
 			arg3,
-			arg4,
 		)
 	})
 	return _c
 }
 
-func (_c *MockISmsService_GetUserSms_Call) Return(smss []models.Sms, err error) *MockISmsService_GetUserSms_Call {
-	_c.Call.Return(smss, err)
+func (_c *MockISmsService_RescheduleSms_Call) Return(sms models.Sms, err error) *MockISmsService_RescheduleSms_Call {
+	_c.Call.Return(sms, err)
 	return _c
 }
 
-func (_c *MockISmsService_GetUserSms_Call) RunAndReturn(run func(ctx context.Context, userId string, skip int, limit int, desc bool) ([]models.Sms, error)) *MockISmsService_GetUserSms_Call {
+func (_c *MockISmsService_RescheduleSms_Call) RunAndReturn(run func(ctx context.Context, userId string, id string, sendAt time.Time) (models.Sms, error)) *MockISmsService_RescheduleSms_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // ScheduleSms provides a mock function for the type MockISmsService
-func (_mock *MockISmsService) ScheduleSms(ctx context.Context, userId string, msgs []models.Sms) error {
+func (_mock *MockISmsService) ScheduleSms(ctx context.Context, userId string, msgs []models.Sms) ([]models.Sms, error) {
 	ret := _mock.Called(ctx, userId, msgs)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ScheduleSms")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []models.Sms) error); ok {
+	var r0 []models.Sms
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []models.Sms) ([]models.Sms, error)); ok {
+		return returnFunc(ctx, userId, msgs)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []models.Sms) []models.Sms); ok {
 		r0 = returnFunc(ctx, userId, msgs)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Sms)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, []models.Sms) error); ok {
+		r1 = returnFunc(ctx, userId, msgs)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
 // MockISmsService_ScheduleSms_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ScheduleSms'
@@ -243,12 +914,12 @@ func (_c *MockISmsService_ScheduleSms_Call) Run(run func(ctx context.Context, us
 	return _c
 }
 
-func (_c *MockISmsService_ScheduleSms_Call) Return(err error) *MockISmsService_ScheduleSms_Call {
-	_c.Call.Return(err)
+func (_c *MockISmsService_ScheduleSms_Call) Return(smss []models.Sms, err error) *MockISmsService_ScheduleSms_Call {
+	_c.Call.Return(smss, err)
 	return _c
 }
 
-func (_c *MockISmsService_ScheduleSms_Call) RunAndReturn(run func(ctx context.Context, userId string, msgs []models.Sms) error) *MockISmsService_ScheduleSms_Call {
+func (_c *MockISmsService_ScheduleSms_Call) RunAndReturn(run func(ctx context.Context, userId string, msgs []models.Sms) ([]models.Sms, error)) *MockISmsService_ScheduleSms_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -379,9 +1050,81 @@ func (_c *MockISmsService_SetMessageAsFailed_Call) RunAndReturn(run func(ctx con
 	return _c
 }
 
+// SetMessageAsRetrying provides a mock function for the type MockISmsService
+func (_mock *MockISmsService) SetMessageAsRetrying(ctx context.Context, id string, nextAttemptAt time.Time) (models.Sms, error) {
+	ret := _mock.Called(ctx, id, nextAttemptAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMessageAsRetrying")
+	}
+
+	var r0 models.Sms
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time) (models.Sms, error)); ok {
+		return returnFunc(ctx, id, nextAttemptAt)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time) models.Sms); ok {
+		r0 = returnFunc(ctx, id, nextAttemptAt)
+	} else {
+		r0 = ret.Get(0).(models.Sms)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = returnFunc(ctx, id, nextAttemptAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockISmsService_SetMessageAsRetrying_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetMessageAsRetrying'
+type MockISmsService_SetMessageAsRetrying_Call struct {
+	*mock.Call
+}
+
+// SetMessageAsRetrying is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - nextAttemptAt time.Time
+func (_e *MockISmsService_Expecter) SetMessageAsRetrying(ctx interface{}, id interface{}, nextAttemptAt interface{}) *MockISmsService_SetMessageAsRetrying_Call {
+	return &MockISmsService_SetMessageAsRetrying_Call{Call: _e.mock.On("SetMessageAsRetrying", ctx, id, nextAttemptAt)}
+}
+
+func (_c *MockISmsService_SetMessageAsRetrying_Call) Run(run func(ctx context.Context, id string, nextAttemptAt time.Time)) *MockISmsService_SetMessageAsRetrying_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsService_SetMessageAsRetrying_Call) Return(sms models.Sms, err error) *MockISmsService_SetMessageAsRetrying_Call {
+	_c.Call.Return(sms, err)
+	return _c
+}
+
+func (_c *MockISmsService_SetMessageAsRetrying_Call) RunAndReturn(run func(ctx context.Context, id string, nextAttemptAt time.Time) (models.Sms, error)) *MockISmsService_SetMessageAsRetrying_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetMessageAsSent provides a mock function for the type MockISmsService
-func (_mock *MockISmsService) SetMessageAsSent(ctx context.Context, id string) (models.Sms, error) {
-	ret := _mock.Called(ctx, id)
+func (_mock *MockISmsService) SetMessageAsSent(ctx context.Context, id string, sendRes models.SendResult) (models.Sms, error) {
+	ret := _mock.Called(ctx, id, sendRes)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SetMessageAsSent")
@@ -389,16 +1132,16 @@ func (_mock *MockISmsService) SetMessageAsSent(ctx context.Context, id string) (
 
 	var r0 models.Sms
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (models.Sms, error)); ok {
-		return returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.SendResult) (models.Sms, error)); ok {
+		return returnFunc(ctx, id, sendRes)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) models.Sms); ok {
-		r0 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.SendResult) models.Sms); ok {
+		r0 = returnFunc(ctx, id, sendRes)
 	} else {
 		r0 = ret.Get(0).(models.Sms)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, models.SendResult) error); ok {
+		r1 = returnFunc(ctx, id, sendRes)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -413,11 +1156,12 @@ type MockISmsService_SetMessageAsSent_Call struct {
 // SetMessageAsSent is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id string
-func (_e *MockISmsService_Expecter) SetMessageAsSent(ctx interface{}, id interface{}) *MockISmsService_SetMessageAsSent_Call {
-	return &MockISmsService_SetMessageAsSent_Call{Call: _e.mock.On("SetMessageAsSent", ctx, id)}
+//   - sendRes models.SendResult
+func (_e *MockISmsService_Expecter) SetMessageAsSent(ctx interface{}, id interface{}, sendRes interface{}) *MockISmsService_SetMessageAsSent_Call {
+	return &MockISmsService_SetMessageAsSent_Call{Call: _e.mock.On("SetMessageAsSent", ctx, id, sendRes)}
 }
 
-func (_c *MockISmsService_SetMessageAsSent_Call) Run(run func(ctx context.Context, id string)) *MockISmsService_SetMessageAsSent_Call {
+func (_c *MockISmsService_SetMessageAsSent_Call) Run(run func(ctx context.Context, id string, sendRes models.SendResult)) *MockISmsService_SetMessageAsSent_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -427,9 +1171,14 @@ func (_c *MockISmsService_SetMessageAsSent_Call) Run(run func(ctx context.Contex
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
+		var arg2 models.SendResult
+		if args[2] != nil {
+			arg2 = args[2].(models.SendResult)
+		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -440,7 +1189,58 @@ func (_c *MockISmsService_SetMessageAsSent_Call) Return(sms models.Sms, err erro
 	return _c
 }
 
-func (_c *MockISmsService_SetMessageAsSent_Call) RunAndReturn(run func(ctx context.Context, id string) (models.Sms, error)) *MockISmsService_SetMessageAsSent_Call {
+func (_c *MockISmsService_SetMessageAsSent_Call) RunAndReturn(run func(ctx context.Context, id string, sendRes models.SendResult) (models.Sms, error)) *MockISmsService_SetMessageAsSent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyDeliveryReport provides a mock function for the type MockISmsService
+func (_mock *MockISmsService) VerifyDeliveryReport(report models.PushedReport) error {
+	ret := _mock.Called(report)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyDeliveryReport")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(models.PushedReport) error); ok {
+		r0 = returnFunc(report)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockISmsService_VerifyDeliveryReport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyDeliveryReport'
+type MockISmsService_VerifyDeliveryReport_Call struct {
+	*mock.Call
+}
+
+// VerifyDeliveryReport is a helper method to define mock.On call
+//   - report models.PushedReport
+func (_e *MockISmsService_Expecter) VerifyDeliveryReport(report interface{}) *MockISmsService_VerifyDeliveryReport_Call {
+	return &MockISmsService_VerifyDeliveryReport_Call{Call: _e.mock.On("VerifyDeliveryReport", report)}
+}
+
+func (_c *MockISmsService_VerifyDeliveryReport_Call) Run(run func(report models.PushedReport)) *MockISmsService_VerifyDeliveryReport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 models.PushedReport
+		if args[0] != nil {
+			arg0 = args[0].(models.PushedReport)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISmsService_VerifyDeliveryReport_Call) Return(err error) *MockISmsService_VerifyDeliveryReport_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockISmsService_VerifyDeliveryReport_Call) RunAndReturn(run func(report models.PushedReport) error) *MockISmsService_VerifyDeliveryReport_Call {
 	_c.Call.Return(run)
 	return _c
 }
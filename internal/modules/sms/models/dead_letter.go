@@ -0,0 +1,43 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+)
+
+type DeadLetterReason string
+
+const (
+	ReasonUndecodable      DeadLetterReason = "undecodable"
+	ReasonInvalidQueue     DeadLetterReason = "invalid_queue"
+	ReasonRetriesExhausted DeadLetterReason = "retries_exhausted"
+)
+
+// DeadLetter is a message, or a raw queue payload, that could not be
+// delivered and is kept for inspection.
+type DeadLetter struct {
+	*shared.Entity
+	*shared.CreateDate
+
+	// MessageId is empty when the payload could not be decoded.
+	MessageId string
+	Payload   string
+	Reason    DeadLetterReason
+	Error     string
+}
+
+// PayloadError is returned by a queue when a popped payload can not be
+// decoded. It carries the raw payload so it can be dead-lettered.
+type PayloadError struct {
+	Payload string
+	Err     error
+}
+
+func (e *PayloadError) Error() string {
+	return fmt.Sprintf("%s: %s", UndecodableMessageError, e.Err)
+}
+
+func (e *PayloadError) Unwrap() []error {
+	return []error{UndecodableMessageError, e.Err}
+}
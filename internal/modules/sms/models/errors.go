@@ -3,11 +3,21 @@ package models
 import "errors"
 
 var (
-	EmptyContentError      = errors.New("content is empty")
-	EmptyReceiverError     = errors.New("receiver is empty")
-	InvalidQueueError      = errors.New("queue is invalid")
-	NoCapacityInQueueError = errors.New("queue capacity is zero")
-	SendError              = errors.New("failed to send message")
-	MessageNotExistError   = errors.New("message does not exist")
-	EmptyQueueError        = errors.New("queue is empty")
+	EmptyContentError          = errors.New("content is empty")
+	EmptyReceiverError         = errors.New("receiver is empty")
+	InvalidQueueError          = errors.New("queue is invalid")
+	NoCapacityInQueueError     = errors.New("queue capacity is zero")
+	SendError                  = errors.New("failed to send message")
+	TemporarySendError         = errors.New("temporary failure on sending message")
+	PermanentSendError         = errors.New("permanent failure on sending message")
+	ProviderThrottledError     = errors.New("sms provider is at its throughput limit")
+	MessageNotExistError       = errors.New("message does not exist")
+	EmptyQueueError            = errors.New("queue is empty")
+	UndecodableMessageError    = errors.New("message payload is undecodable")
+	DeadLetterNotExistError    = errors.New("dead letter does not exist")
+	NotRequeueableError        = errors.New("dead letter has no message to requeue")
+	InvalidDeliveryStatusError = errors.New("invalid delivery status")
+	EmptyProviderIdError       = errors.New("provider message id is empty")
+	UnauthorizedReportError    = errors.New("delivery report is not authenticated")
+	PastSendAtError            = errors.New("send time is not in the future")
 )
@@ -1,6 +1,10 @@
 package models
 
-import "github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+import (
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+)
 
 type SmsStatus int
 
@@ -9,6 +13,59 @@ const (
 	StatusEnqueued
 	StatusSent
 	StatusFailed
+	StatusRetrying
+	StatusCanceled
+	StatusDelivered
+	StatusUndelivered
+	StatusExpired
+)
+
+func (s SmsStatus) String() string {
+	switch s {
+	case StatusScheduled:
+		return "Scheduled"
+	case StatusEnqueued:
+		return "Enqueued"
+	case StatusSent:
+		return "Sent"
+	case StatusFailed:
+		return "Failed"
+	case StatusRetrying:
+		return "Retrying"
+	case StatusCanceled:
+		return "Canceled"
+	case StatusDelivered:
+		return "Delivered"
+	case StatusUndelivered:
+		return "Undelivered"
+	case StatusExpired:
+		return "Expired"
+	default:
+		return "Unknown"
+	}
+}
+
+// IsFinal reports whether status is a final delivery state.
+func (s SmsStatus) IsFinal() bool {
+	return s == StatusDelivered || s == StatusUndelivered || s == StatusExpired
+}
+
+type SmsPriority int
+
+const (
+	PriorityLow SmsPriority = iota - 1
+	PriorityNormal
+	PriorityHigh
+)
+
+// Priorities lists message priorities from the most to the least urgent.
+var Priorities = []SmsPriority{PriorityHigh, PriorityNormal, PriorityLow}
+
+type SmsEncoding int
+
+const (
+	EncodingGSM7 SmsEncoding = iota
+	EncodingUCS2
 )
 
 type Sms struct {
@@ -19,6 +76,61 @@ type Sms struct {
 	UserId   string
 	Content  string
 	Receiver string
+	// Country and Operator are detected from the receiver when it is
+	// normalized.
+	Country  string
+	Operator string
+	Encoding SmsEncoding
+	Segments int
 	Cost     int
 	Status   SmsStatus
+	Tags     []string
+	Provider string
+	SendAt   time.Time
+	Priority SmsPriority
+
+	Attempts      int
+	NextAttemptAt time.Time
+
+	// ProviderMessageId is the id the provider accepted the message with,
+	// which its delivery reports refer to.
+	ProviderMessageId string
+	SentAt            time.Time
+	// DoneAt is when the provider reached the final delivery state.
+	DoneAt        time.Time
+	DeliveryError string
+}
+
+type SendResult struct {
+	Provider  string
+	MessageId string
+}
+
+// DeliveryReport is the final delivery state of a sent message reported by
+// its provider.
+type DeliveryReport struct {
+	Provider  string
+	MessageId string
+	Status    SmsStatus
+	DoneAt    time.Time
+	Error     string
+}
+
+// PushedReport is a delivery report pushed over http with what is needed to
+// tell it comes from its provider.
+type PushedReport struct {
+	Provider  string
+	RemoteIP  string
+	Timestamp string
+	Signature string
+	Body      []byte
+}
+
+// ReconcileResult is the outcome of reconciling stranded enqueued messages.
+type ReconcileResult struct {
+	Rescheduled int
+	Failed      []Sms
+	// Sent are messages the provider had accepted whose sent status was
+	// recorded late.
+	Sent []Sms
 }
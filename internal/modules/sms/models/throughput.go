@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type ThrottleReason string
+
+const (
+	ThrottleRate     ThrottleReason = "rate"
+	ThrottleInFlight ThrottleReason = "in_flight"
+)
+
+// ProviderLimit is the contracted throughput of a provider. Zero TPS or
+// MaxInFlight leave that side unlimited.
+type ProviderLimit struct {
+	TPS         float64
+	Burst       int
+	MaxInFlight int
+	// Lease bounds how long a send holds its in-flight slot, so slots of
+	// crashed workers are freed.
+	Lease time.Duration
+}
+
+// Throttle tells why a send to a provider has to wait and for how long. It
+// is empty when the send may go ahead.
+type Throttle struct {
+	Reason ThrottleReason
+	Wait   time.Duration
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+)
+
+// IProviderLimiter shares the throughput limits of providers between all
+// gateway instances.
+type IProviderLimiter interface {
+	// AcquireSend takes a send of provider and returns the token of its
+	// in-flight slot. When the provider is at its limit nothing is taken and
+	// the throttle is returned instead.
+	AcquireSend(ctx context.Context, provider string, limit models.ProviderLimit) (string, models.Throttle, error)
+	ReleaseSend(ctx context.Context, provider string, token string) error
+}
@@ -10,4 +10,15 @@ type ISmsQueue interface {
 	Enqueue(ctx context.Context, msg []models.Sms) error
 	GetLength(ctx context.Context) (int, error)
 	Pop(ctx context.Context) (models.Sms, error)
+	Ack(ctx context.Context, msg models.Sms) error
+	Nack(ctx context.Context, msg models.Sms) error
+	RecoverUnacked(ctx context.Context) (int, error)
+	// GetQueuedIds returns those of ids that are still queued or in flight.
+	GetQueuedIds(ctx context.Context, ids []string) ([]string, error)
+	// SetAccepted keeps the send result of a message the provider accepted
+	// but whose sent status could not be recorded.
+	SetAccepted(ctx context.Context, id string, res models.SendResult) error
+	// GetAccepted returns the kept send results of those of ids that have one.
+	GetAccepted(ctx context.Context, ids []string) (map[string]models.SendResult, error)
+	RemoveAccepted(ctx context.Context, ids []string) error
 }
@@ -2,15 +2,30 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
 )
 
 type ISmsRepository interface {
-	CreateScheduleMessages(ctx context.Context, msgs []models.Sms) error
+	CreateScheduleMessages(ctx context.Context, msgs []models.Sms) ([]models.Sms, error)
 	GetMessagesByUserId(ctx context.Context, userId string, skip int, limit int, desc bool) ([]models.Sms, error)
 	EnqueueMessages(ctx context.Context, count int) ([]models.Sms, error)
 	RescheduledMessages(ctx context.Context, ids []string) error
+	// RescheduleStaleMessages reschedules those of ids still enqueued since
+	// before enqueuedBefore, and returns their count.
+	RescheduleStaleMessages(ctx context.Context, ids []string, enqueuedBefore time.Time) (int, error)
+	GetStaleEnqueuedMessages(ctx context.Context, enqueuedBefore time.Time, limit int) ([]models.Sms, error)
 	SetMessageAsFailed(ctx context.Context, id string) (models.Sms, error)
-	SetMessageAsSent(ctx context.Context, id string) (models.Sms, error)
+	SetMessageAsRetrying(ctx context.Context, id string, nextAttemptAt time.Time) (models.Sms, error)
+	SetMessageAsSent(ctx context.Context, id string, res models.SendResult) (models.Sms, error)
+	SetMessageDeliveryState(ctx context.Context, report models.DeliveryReport) (models.Sms, error)
+	CancelScheduledMessage(ctx context.Context, userId string, id string) (models.Sms, error)
+	ChangeMessageSendAt(ctx context.Context, userId string, id string, sendAt time.Time) (models.Sms, error)
+	CreateDeadLetter(ctx context.Context, letter models.DeadLetter) error
+	GetDeadLetters(ctx context.Context, skip int, limit int) ([]models.DeadLetter, error)
+	GetDeadLetter(ctx context.Context, id string) (models.DeadLetter, error)
+	RequeueDeadLetter(ctx context.Context, id string) (models.Sms, error)
+	DeleteDeadLetter(ctx context.Context, id string) error
+	PurgeDeadLetters(ctx context.Context) (int, error)
 }
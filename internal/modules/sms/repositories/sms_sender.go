@@ -7,5 +7,11 @@ import (
 )
 
 type ISmsSender interface {
-	Send(ctx context.Context, msg models.Sms) error
+	Send(ctx context.Context, msg models.Sms) (models.SendResult, error)
+}
+
+// IDeliveryReporter is implemented by senders whose providers push delivery
+// reports over the connection messages are sent on.
+type IDeliveryReporter interface {
+	OnDeliveryReport(handler func(models.DeliveryReport))
 }
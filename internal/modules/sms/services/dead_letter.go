@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+	pkgMetrics "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/metrics"
+)
+
+// deadLetter stores a message that can not be delivered. A failure to store
+// it is only logged, together with the payload, so the caller flow is kept.
+func (s *SmsService) deadLetter(ctx context.Context, letter models.DeadLetter) {
+	pkgLog.Warn("dead-lettering message %s for %s", letter.MessageId, letter.Reason)
+	if err := s.smsRepo.CreateDeadLetter(ctx, letter); err != nil {
+		pkgLog.Error(err, "failed to dead-letter message %s with payload %s", letter.MessageId, letter.Payload)
+		return
+	}
+
+	pkgMetrics.DeadLetterMetric.WithLabelValues(string(letter.Reason)).Inc()
+}
+
+func (s *SmsService) GetDeadLetters(ctx context.Context, skip int, limit int) ([]models.DeadLetter, error) {
+	pkgLog.Debug("getting dead letters")
+	letters, err := s.smsRepo.GetDeadLetters(ctx, skip, limit)
+	if err != nil {
+		pkgLog.Error(err, "error getting dead letters")
+		return nil, err
+	}
+
+	pkgLog.Debug("%d dead letters retrieved", len(letters))
+	return letters, nil
+}
+
+func (s *SmsService) GetDeadLetter(ctx context.Context, id string) (models.DeadLetter, error) {
+	pkgLog.Debug("getting dead letter %s", id)
+	letter, err := s.smsRepo.GetDeadLetter(ctx, id)
+	if err != nil {
+		pkgLog.Error(err, "error getting dead letter %s", id)
+		return models.DeadLetter{}, err
+	}
+
+	return letter, nil
+}
+
+func (s *SmsService) RequeueDeadLetter(ctx context.Context, id string) (models.Sms, error) {
+	pkgLog.Debug("requeuing dead letter %s", id)
+	msg, err := s.smsRepo.RequeueDeadLetter(ctx, id)
+	if err != nil {
+		pkgLog.Error(err, "failed to requeue dead letter %s", id)
+		return models.Sms{}, err
+	}
+	pkgMetrics.SmsStatusMetric.WithLabelValues("scheduled").Inc()
+
+	pkgLog.Debug("dead letter %s requeued as message %s", id, msg.ID)
+	return msg, nil
+}
+
+func (s *SmsService) DeleteDeadLetter(ctx context.Context, id string) error {
+	pkgLog.Debug("deleting dead letter %s", id)
+	if err := s.smsRepo.DeleteDeadLetter(ctx, id); err != nil {
+		pkgLog.Error(err, "failed to delete dead letter %s", id)
+		return err
+	}
+
+	return nil
+}
+
+func (s *SmsService) PurgeDeadLetters(ctx context.Context) (int, error) {
+	pkgLog.Debug("purging dead letters")
+	purged, err := s.smsRepo.PurgeDeadLetters(ctx)
+	if err != nil {
+		pkgLog.Error(err, "failed to purge dead letters")
+		return 0, err
+	}
+
+	pkgLog.Info("%d dead letters purged", purged)
+	return purged, nil
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+)
+
+const defaultReportClockSkew = 5 * time.Minute
+
+// DeliveryReportAuth is how reports of a provider are authenticated. When
+// both are set, reports must be signed and come from an allowed address.
+type DeliveryReportAuth struct {
+	Provider string `mapstructure:"provider"`
+	// Secret signs reports, see SignDeliveryReport.
+	Secret string `mapstructure:"secret"`
+	// AllowedIps are addresses or CIDR ranges the provider calls from.
+	AllowedIps []string `mapstructure:"allowed_ips"`
+	// MaxClockSkew is how far the report timestamp may be from the server
+	// clock. It defaults to 5 minutes.
+	MaxClockSkew time.Duration `mapstructure:"max_clock_skew"`
+}
+
+// SignDeliveryReport returns the hex HMAC-SHA256 of "{timestamp}.{body}" with
+// the provider secret, which providers send with their reports.
+func SignDeliveryReport(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(timestamp + "."))
+	_, _ = mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *SmsService) VerifyDeliveryReport(report models.PushedReport) error {
+	i := slices.IndexFunc(s.cfg.DeliveryReports, func(auth DeliveryReportAuth) bool {
+		return auth.Provider == report.Provider
+	})
+	if i < 0 || (s.cfg.DeliveryReports[i].Secret == "" && len(s.cfg.DeliveryReports[i].AllowedIps) == 0) {
+		pkgLog.Debug("rejecting delivery report of provider %s without authentication", report.Provider)
+		return fmt.Errorf("%w: provider %s does not push reports", models.UnauthorizedReportError, report.Provider)
+	}
+	auth := s.cfg.DeliveryReports[i]
+
+	if len(auth.AllowedIps) > 0 && !ipAllowed(auth.AllowedIps, report.RemoteIP) {
+		pkgLog.Debug("rejecting delivery report of provider %s from %s", report.Provider, report.RemoteIP)
+		return fmt.Errorf("%w: address %s is not allowed", models.UnauthorizedReportError, report.RemoteIP)
+	}
+
+	if auth.Secret != "" {
+		if err := verifyReportSignature(auth, report); err != nil {
+			pkgLog.Debug("rejecting delivery report of provider %s: %s", report.Provider, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+func verifyReportSignature(auth DeliveryReportAuth, report models.PushedReport) error {
+	if report.Timestamp == "" || report.Signature == "" {
+		return fmt.Errorf("%w: signature is missing", models.UnauthorizedReportError)
+	}
+
+	timestamp, err := strconv.ParseInt(report.Timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: timestamp is invalid", models.UnauthorizedReportError)
+	}
+	maxSkew := auth.MaxClockSkew
+	if maxSkew <= 0 {
+		maxSkew = defaultReportClockSkew
+	}
+	if skew := time.Since(time.Unix(timestamp, 0)); skew > maxSkew || skew < -maxSkew {
+		return fmt.Errorf("%w: timestamp is outside the allowed clock skew", models.UnauthorizedReportError)
+	}
+
+	expected := SignDeliveryReport(auth.Secret, report.Timestamp, report.Body)
+	if !hmac.Equal([]byte(expected), []byte(report.Signature)) {
+		return fmt.Errorf("%w: signature is invalid", models.UnauthorizedReportError)
+	}
+
+	return nil
+}
+
+func ipAllowed(allowed []string, remoteIp string) bool {
+	addr, err := netip.ParseAddr(remoteIp)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, entry := range allowed {
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			if prefix.Contains(addr) {
+				return true
+			}
+			continue
+		}
+		if allowedAddr, err := netip.ParseAddr(entry); err == nil && allowedAddr.Unmap() == addr {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,91 @@
+package services_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/mocks"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSmsService_VerifyDeliveryReport(t *testing.T) {
+	cfg := services.SmsServiceConfig{
+		DeliveryReports: []services.DeliveryReportAuth{
+			{Provider: "rest", Secret: "dlr_secret"},
+			{Provider: "carrier", AllowedIps: []string{"10.0.0.0/24", "192.168.1.7"}},
+		},
+	}
+	body := []byte(`{"messageId":"abc","status":"delivered"}`)
+
+	t.Run("should accept report signed with provider secret", func(t *testing.T) {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+		service := services.NewSmsService(cfg, mocks.NewMockISmsRepository(t), mocks.NewMockISmsSender(t), mocks.NewMockISmsQueue(t))
+
+		actualErr := service.VerifyDeliveryReport(models.PushedReport{
+			Provider:  "rest",
+			RemoteIP:  "203.0.113.9",
+			Timestamp: timestamp,
+			Signature: services.SignDeliveryReport("dlr_secret", timestamp, body),
+			Body:      body,
+		})
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("should reject unsigned report", func(t *testing.T) {
+		service := services.NewSmsService(cfg, mocks.NewMockISmsRepository(t), mocks.NewMockISmsSender(t), mocks.NewMockISmsQueue(t))
+
+		actualErr := service.VerifyDeliveryReport(models.PushedReport{
+			Provider: "rest",
+			RemoteIP: "203.0.113.9",
+			Body:     body,
+		})
+		assert.ErrorIs(t, actualErr, models.UnauthorizedReportError)
+	})
+
+	t.Run("should reject report signed with another secret", func(t *testing.T) {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+		service := services.NewSmsService(cfg, mocks.NewMockISmsRepository(t), mocks.NewMockISmsSender(t), mocks.NewMockISmsQueue(t))
+
+		actualErr := service.VerifyDeliveryReport(models.PushedReport{
+			Provider:  "rest",
+			Timestamp: timestamp,
+			Signature: services.SignDeliveryReport("other", timestamp, body),
+			Body:      body,
+		})
+		assert.ErrorIs(t, actualErr, models.UnauthorizedReportError)
+	})
+
+	t.Run("should reject report with stale timestamp", func(t *testing.T) {
+		timestamp := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+		service := services.NewSmsService(cfg, mocks.NewMockISmsRepository(t), mocks.NewMockISmsSender(t), mocks.NewMockISmsQueue(t))
+
+		actualErr := service.VerifyDeliveryReport(models.PushedReport{
+			Provider:  "rest",
+			Timestamp: timestamp,
+			Signature: services.SignDeliveryReport("dlr_secret", timestamp, body),
+			Body:      body,
+		})
+		assert.ErrorIs(t, actualErr, models.UnauthorizedReportError)
+	})
+
+	t.Run("should accept report only from allowed addresses", func(t *testing.T) {
+		service := services.NewSmsService(cfg, mocks.NewMockISmsRepository(t), mocks.NewMockISmsSender(t), mocks.NewMockISmsQueue(t))
+
+		assert.NoError(t, service.VerifyDeliveryReport(models.PushedReport{Provider: "carrier", RemoteIP: "10.0.0.42"}))
+		assert.NoError(t, service.VerifyDeliveryReport(models.PushedReport{Provider: "carrier", RemoteIP: "192.168.1.7"}))
+		assert.ErrorIs(t, service.VerifyDeliveryReport(models.PushedReport{Provider: "carrier", RemoteIP: "10.0.1.1"}), models.UnauthorizedReportError)
+	})
+
+	t.Run("should reject report of provider without authentication", func(t *testing.T) {
+		service := services.NewSmsService(cfg, mocks.NewMockISmsRepository(t), mocks.NewMockISmsSender(t), mocks.NewMockISmsQueue(t))
+
+		actualErr := service.VerifyDeliveryReport(models.PushedReport{Provider: "default", RemoteIP: "10.0.0.42"})
+		assert.ErrorIs(t, actualErr, models.UnauthorizedReportError)
+	})
+}
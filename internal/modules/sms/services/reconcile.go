@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/common"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+	pkgMetrics "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/metrics"
+)
+
+type ReconcileConfig struct {
+	// StuckThreshold is how long a message may stay enqueued before it is
+	// considered stranded.
+	StuckThreshold time.Duration `mapstructure:"stuck_threshold"`
+	BatchSize      int           `mapstructure:"batch_size"`
+	// MaxAttempts is the attempt count below which stranded messages are
+	// re-queued. Messages that reached it are marked as failed.
+	MaxAttempts int `mapstructure:"max_attempts"`
+}
+
+// ReconcileStuckMessages finds messages enqueued for longer than the stuck
+// threshold that are no longer in the queue, then re-queues or fails them.
+// Failed messages are returned so the caller can refund their cost.
+func (s *SmsService) ReconcileStuckMessages(ctx context.Context) (models.ReconcileResult, error) {
+	pkgLog.Debug("reconciling enqueued messages older than %s", s.cfg.Reconcile.StuckThreshold)
+	enqueuedBefore := time.Now().Add(-s.cfg.Reconcile.StuckThreshold)
+	stale, err := s.smsRepo.GetStaleEnqueuedMessages(ctx, enqueuedBefore, s.cfg.Reconcile.BatchSize)
+	if err != nil {
+		pkgLog.Error(err, "failed to get stale enqueued messages")
+		return models.ReconcileResult{}, err
+	}
+	if len(stale) == 0 {
+		pkgLog.Debug("no stale enqueued message")
+		return models.ReconcileResult{}, nil
+	}
+
+	staleIds := make([]string, len(stale))
+	for i := range stale {
+		staleIds[i] = stale[i].ID
+	}
+	queuedIds, err := s.smsQueue.GetQueuedIds(ctx, staleIds)
+	if err != nil {
+		pkgLog.Error(err, "failed to get queued message ids")
+		return models.ReconcileResult{}, err
+	}
+	queued := make(map[string]struct{}, len(queuedIds))
+	for _, id := range queuedIds {
+		queued[id] = struct{}{}
+	}
+
+	var unqueuedIds []string
+	for _, id := range staleIds {
+		if _, ok := queued[id]; !ok {
+			unqueuedIds = append(unqueuedIds, id)
+		}
+	}
+	var accepted map[string]models.SendResult
+	if len(unqueuedIds) > 0 {
+		accepted, err = s.smsQueue.GetAccepted(ctx, unqueuedIds)
+		if err != nil {
+			pkgLog.Error(err, "failed to get accepted message results")
+			return models.ReconcileResult{}, err
+		}
+	}
+	sent := s.recordAccepted(ctx, accepted)
+
+	var rescheduleIds []string
+	var failed []models.Sms
+	for _, msg := range stale {
+		if _, ok := queued[msg.ID]; ok {
+			continue
+		}
+		// the provider already accepted the message, so it is never sent again
+		if _, ok := accepted[msg.ID]; ok {
+			continue
+		}
+
+		if msg.Attempts < s.cfg.Reconcile.MaxAttempts {
+			rescheduleIds = append(rescheduleIds, msg.ID)
+			continue
+		}
+
+		failedMsg, err := s.smsRepo.SetMessageAsFailed(ctx, msg.ID)
+		if err != nil {
+			if errors.Is(err, models.MessageNotExistError) {
+				continue
+			}
+			pkgLog.Error(err, "failed to set stranded message %s as failed", msg.ID)
+			return models.ReconcileResult{Failed: failed, Sent: sent}, err
+		}
+		failed = append(failed, failedMsg)
+
+		s.deadLetter(ctx, models.DeadLetter{
+			MessageId: msg.ID,
+			Payload:   common.ValueToJSON(msg),
+			Reason:    models.ReasonRetriesExhausted,
+			Error:     "message stranded in enqueued status",
+		})
+	}
+	if len(failed) > 0 {
+		pkgMetrics.SmsStatusMetric.WithLabelValues("failed").Add(float64(len(failed)))
+		pkgMetrics.MessageReconciledMetric.WithLabelValues("failed").Add(float64(len(failed)))
+	}
+
+	rescheduled := 0
+	if len(rescheduleIds) > 0 {
+		pkgLog.Debug("rescheduling %d stranded sms...", len(rescheduleIds))
+		// messages sent or enqueued again since they were read are skipped
+		rescheduled, err = s.smsRepo.RescheduleStaleMessages(ctx, rescheduleIds, enqueuedBefore)
+		if err != nil {
+			pkgLog.Error(err, "failed to reschedule stranded sms")
+			return models.ReconcileResult{Failed: failed, Sent: sent}, err
+		}
+		pkgMetrics.MessageReconciledMetric.WithLabelValues("rescheduled").Add(float64(rescheduled))
+	}
+
+	pkgLog.Info("%d stranded sms rescheduled, %d failed and %d recorded as sent", rescheduled, len(failed), len(sent))
+	return models.ReconcileResult{
+		Rescheduled: rescheduled,
+		Failed:      failed,
+		Sent:        sent,
+	}, nil
+}
+
+// recordAccepted sets messages the provider accepted as sent with their kept
+// send results. Results that could not be recorded are kept for the next run.
+func (s *SmsService) recordAccepted(ctx context.Context, accepted map[string]models.SendResult) []models.Sms {
+	var sent []models.Sms
+	var recordedIds []string
+	for id, res := range accepted {
+		sentMsg, err := s.SetMessageAsSent(ctx, id, res)
+		if err != nil && !errors.Is(err, models.MessageNotExistError) {
+			pkgLog.Error(err, "failed to set accepted message %s as sent", id)
+			continue
+		}
+		if err == nil {
+			sent = append(sent, sentMsg)
+		}
+		recordedIds = append(recordedIds, id)
+	}
+	if len(recordedIds) == 0 {
+		return sent
+	}
+
+	if err := s.smsQueue.RemoveAccepted(ctx, recordedIds); err != nil {
+		pkgLog.Error(err, "failed to remove recorded accepted message results")
+	}
+	if len(sent) > 0 {
+		pkgMetrics.MessageReconciledMetric.WithLabelValues("sent").Add(float64(len(sent)))
+	}
+
+	return sent
+}
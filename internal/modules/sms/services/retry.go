@@ -0,0 +1,60 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+)
+
+type RetryPolicy struct {
+	// MaxAttempts is the total number of send attempts, including the first
+	// one. Zero or one disables retries.
+	MaxAttempts    int           `mapstructure:"max_attempts"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+	Multiplier     float64       `mapstructure:"multiplier"`
+	// Jitter randomizes each backoff by up to this fraction in both directions.
+	Jitter float64 `mapstructure:"jitter"`
+}
+
+// RetryConfig holds a retry policy per class of send error.
+type RetryConfig struct {
+	Temporary RetryPolicy `mapstructure:"temporary"`
+	Permanent RetryPolicy `mapstructure:"permanent"`
+	Unknown   RetryPolicy `mapstructure:"unknown"`
+	// Record retries recording the sent status of a message the provider
+	// accepted, since the message must not be sent again.
+	Record RetryPolicy `mapstructure:"record"`
+}
+
+func (c RetryConfig) policyFor(err error) RetryPolicy {
+	switch {
+	case errors.Is(err, models.TemporarySendError):
+		return c.Temporary
+	case errors.Is(err, models.PermanentSendError):
+		return c.Permanent
+	default:
+		return c.Unknown
+	}
+}
+
+// Backoff returns the delay before the attempt following the given one.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(max(attempt-1, 0)))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		backoff += backoff * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(max(backoff, 0))
+}
@@ -0,0 +1,37 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	t.Run("should grow exponentially and cap at max backoff", func(t *testing.T) {
+		policy := services.RetryPolicy{
+			InitialBackoff: time.Second,
+			MaxBackoff:     10 * time.Second,
+			Multiplier:     3,
+		}
+
+		assert.Equal(t, time.Second, policy.Backoff(1))
+		assert.Equal(t, 3*time.Second, policy.Backoff(2))
+		assert.Equal(t, 9*time.Second, policy.Backoff(3))
+		assert.Equal(t, 10*time.Second, policy.Backoff(4))
+	})
+
+	t.Run("should keep jittered backoff within jitter bounds", func(t *testing.T) {
+		policy := services.RetryPolicy{
+			InitialBackoff: 10 * time.Second,
+			Jitter:         0.2,
+		}
+
+		for range 100 {
+			backoff := policy.Backoff(1)
+			assert.GreaterOrEqual(t, backoff, 8*time.Second)
+			assert.LessOrEqual(t, backoff, 12*time.Second)
+		}
+	})
+}
@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"errors"
+	"time"
 
+	"github.com/AshkanAbd/arvancloud_sms_gateway/common"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/repositories"
 
@@ -12,16 +14,35 @@ import (
 )
 
 type SmsServiceConfig struct {
-	QueueCapacity int `mapstructure:"queue_capacity"`
+	QueueCapacity int             `mapstructure:"queue_capacity"`
+	Retry         RetryConfig     `mapstructure:"retry"`
+	Reconcile     ReconcileConfig `mapstructure:"reconcile"`
+	// DeliveryReports authenticates reports providers push over http. Reports
+	// of providers without an entry are rejected.
+	DeliveryReports []DeliveryReportAuth `mapstructure:"delivery_reports"`
 }
 
 type ISmsService interface {
-	ScheduleSms(ctx context.Context, userId string, msgs []models.Sms) error
+	ScheduleSms(ctx context.Context, userId string, msgs []models.Sms) ([]models.Sms, error)
 	GetUserSms(ctx context.Context, userId string, skip int, limit int, desc bool) ([]models.Sms, error)
+	CancelSms(ctx context.Context, userId string, id string) (models.Sms, error)
+	RescheduleSms(ctx context.Context, userId string, id string, sendAt time.Time) (models.Sms, error)
 	EnqueueEarliest(ctx context.Context, count int) (int, error)
 	SetMessageAsFailed(ctx context.Context, id string) (models.Sms, error)
-	SetMessageAsSent(ctx context.Context, id string) (models.Sms, error)
+	SetMessageAsRetrying(ctx context.Context, id string, nextAttemptAt time.Time) (models.Sms, error)
+	SetMessageAsSent(ctx context.Context, id string, sendRes models.SendResult) (models.Sms, error)
+	ProcessDeliveryReport(ctx context.Context, report models.DeliveryReport) (models.Sms, error)
+	// VerifyDeliveryReport returns UnauthorizedReportError unless the pushed
+	// report is signed with the provider secret and comes from its addresses.
+	VerifyDeliveryReport(report models.PushedReport) error
 	SendFromQueue(ctx context.Context) (models.Sms, error)
+	RecoverUnacked(ctx context.Context) (int, error)
+	ReconcileStuckMessages(ctx context.Context) (models.ReconcileResult, error)
+	GetDeadLetters(ctx context.Context, skip int, limit int) ([]models.DeadLetter, error)
+	GetDeadLetter(ctx context.Context, id string) (models.DeadLetter, error)
+	RequeueDeadLetter(ctx context.Context, id string) (models.Sms, error)
+	DeleteDeadLetter(ctx context.Context, id string) error
+	PurgeDeadLetters(ctx context.Context) (int, error)
 }
 
 type SmsService struct {
@@ -45,21 +66,22 @@ func NewSmsService(
 	}
 }
 
-func (s *SmsService) ScheduleSms(ctx context.Context, userId string, msgs []models.Sms) error {
+func (s *SmsService) ScheduleSms(ctx context.Context, userId string, msgs []models.Sms) ([]models.Sms, error) {
 	pkgLog.Debug("scheduling %d sms for user %s", len(msgs), userId)
 	for i := range msgs {
 		msgs[i].UserId = userId
 		msgs[i].Status = models.StatusScheduled
 	}
 
-	if err := s.smsRepo.CreateScheduleMessages(ctx, msgs); err != nil {
+	created, err := s.smsRepo.CreateScheduleMessages(ctx, msgs)
+	if err != nil {
 		pkgLog.Error(err, "error creating scheduled sms for user %s", userId)
-		return err
+		return nil, err
 	}
-	pkgMetrics.SmsStatusMetric.WithLabelValues("scheduled").Add(float64(len(msgs)))
+	pkgMetrics.SmsStatusMetric.WithLabelValues("scheduled").Add(float64(len(created)))
 
-	pkgLog.Debug("%d sms scheduled for user %s", len(msgs), userId)
-	return nil
+	pkgLog.Debug("%d sms scheduled for user %s", len(created), userId)
+	return created, nil
 }
 
 func (s *SmsService) GetUserSms(ctx context.Context, userId string, skip int, limit int, desc bool) ([]models.Sms, error) {
@@ -74,6 +96,37 @@ func (s *SmsService) GetUserSms(ctx context.Context, userId string, skip int, li
 	return msgs, nil
 }
 
+func (s *SmsService) CancelSms(ctx context.Context, userId string, id string) (models.Sms, error) {
+	pkgLog.Debug("canceling sms %s of user %s", id, userId)
+	res, err := s.smsRepo.CancelScheduledMessage(ctx, userId, id)
+	if err != nil {
+		pkgLog.Error(err, "failed to cancel sms %s of user %s", id, userId)
+		return models.Sms{}, err
+	}
+
+	pkgMetrics.SmsStatusMetric.WithLabelValues("canceled").Inc()
+
+	pkgLog.Debug("sms %s of user %s canceled", id, userId)
+	return res, nil
+}
+
+func (s *SmsService) RescheduleSms(ctx context.Context, userId string, id string, sendAt time.Time) (models.Sms, error) {
+	pkgLog.Debug("rescheduling sms %s of user %s to %s", id, userId, sendAt)
+	if !sendAt.After(time.Now()) {
+		pkgLog.Error(models.PastSendAtError, "failed to reschedule sms %s of user %s", id, userId)
+		return models.Sms{}, models.PastSendAtError
+	}
+
+	res, err := s.smsRepo.ChangeMessageSendAt(ctx, userId, id, sendAt)
+	if err != nil {
+		pkgLog.Error(err, "failed to reschedule sms %s of user %s", id, userId)
+		return models.Sms{}, err
+	}
+
+	pkgLog.Debug("sms %s of user %s rescheduled", id, userId)
+	return res, nil
+}
+
 func (s *SmsService) EnqueueEarliest(ctx context.Context, count int) (int, error) {
 	pkgLog.Debug("enqueuing %d sms...", count)
 	pkgLog.Debug("getting sms queue length")
@@ -106,7 +159,7 @@ func (s *SmsService) EnqueueEarliest(ctx context.Context, count int) (int, error
 		pkgLog.Error(err, "failed to add sms to queue")
 		ids := make([]string, len(enqueuedMsgs))
 		for i := range enqueuedMsgs {
-			ids[i] = enqueuedMsgs[i].UserId
+			ids[i] = enqueuedMsgs[i].ID
 		}
 
 		pkgLog.Debug("rescheduling %d sms...", len(enqueuedMsgs))
@@ -135,9 +188,23 @@ func (s *SmsService) SetMessageAsFailed(ctx context.Context, id string) (models.
 	return res, nil
 }
 
-func (s *SmsService) SetMessageAsSent(ctx context.Context, id string) (models.Sms, error) {
-	pkgLog.Debug("setting message %s as sent", id)
-	res, err := s.smsRepo.SetMessageAsSent(ctx, id)
+func (s *SmsService) SetMessageAsRetrying(ctx context.Context, id string, nextAttemptAt time.Time) (models.Sms, error) {
+	pkgLog.Debug("setting message %s as retrying at %s", id, nextAttemptAt)
+	res, err := s.smsRepo.SetMessageAsRetrying(ctx, id, nextAttemptAt)
+	if err != nil {
+		pkgLog.Error(err, "failed to set message as retrying")
+		return models.Sms{}, err
+	}
+
+	pkgMetrics.SmsStatusMetric.WithLabelValues("retrying").Inc()
+
+	pkgLog.Debug("message %s set as retrying", id)
+	return res, nil
+}
+
+func (s *SmsService) SetMessageAsSent(ctx context.Context, id string, sendRes models.SendResult) (models.Sms, error) {
+	pkgLog.Debug("setting message %s as sent by provider %s", id, sendRes.Provider)
+	res, err := s.smsRepo.SetMessageAsSent(ctx, id, sendRes)
 	if err != nil {
 		pkgLog.Error(err, "failed to set message as sent")
 		return models.Sms{}, err
@@ -149,6 +216,45 @@ func (s *SmsService) SetMessageAsSent(ctx context.Context, id string) (models.Sm
 	return res, nil
 }
 
+// ProcessDeliveryReport moves a sent message to the final delivery state its
+// provider reported. Reports without a time are stamped with the current time.
+func (s *SmsService) ProcessDeliveryReport(ctx context.Context, report models.DeliveryReport) (models.Sms, error) {
+	pkgLog.Debug("processing delivery report of message %s from provider %s", report.MessageId, report.Provider)
+	if report.MessageId == "" {
+		pkgLog.Error(models.EmptyProviderIdError, "empty provider message id in delivery report")
+		return models.Sms{}, models.EmptyProviderIdError
+	}
+	if !report.Status.IsFinal() {
+		pkgLog.Error(models.InvalidDeliveryStatusError, "invalid delivery status %d", report.Status)
+		return models.Sms{}, models.InvalidDeliveryStatusError
+	}
+	if report.DoneAt.IsZero() {
+		report.DoneAt = time.Now()
+	}
+
+	res, err := s.smsRepo.SetMessageDeliveryState(ctx, report)
+	if err != nil {
+		pkgLog.Error(err, "failed to set delivery state of message %s from provider %s", report.MessageId, report.Provider)
+		return models.Sms{}, err
+	}
+
+	pkgMetrics.SmsStatusMetric.WithLabelValues(deliveryStatusLabel(report.Status)).Inc()
+
+	pkgLog.Debug("message %s set as %s", res.ID, deliveryStatusLabel(report.Status))
+	return res, nil
+}
+
+func deliveryStatusLabel(status models.SmsStatus) string {
+	switch status {
+	case models.StatusDelivered:
+		return "delivered"
+	case models.StatusUndelivered:
+		return "undelivered"
+	default:
+		return "expired"
+	}
+}
+
 func (s *SmsService) SendFromQueue(ctx context.Context) (models.Sms, error) {
 	pkgLog.Debug("poping message from queue")
 	msg, err := s.smsQueue.Pop(ctx)
@@ -157,14 +263,121 @@ func (s *SmsService) SendFromQueue(ctx context.Context) (models.Sms, error) {
 			return models.Sms{}, err
 		}
 		pkgLog.Error(err, "failed to pop message from queue")
+
+		var payloadErr *models.PayloadError
+		if errors.As(err, &payloadErr) {
+			s.deadLetter(ctx, models.DeadLetter{
+				Payload: payloadErr.Payload,
+				Reason:  models.ReasonUndecodable,
+				Error:   payloadErr.Err.Error(),
+			})
+		}
+		return models.Sms{}, err
+	}
+
+	updatedMsg, accepted, err := s.sendMessage(ctx, msg)
+	// once the provider accepted the message, returning it to the queue would
+	// send it again, so its status is left to the reconciler
+	if err != nil && !accepted && !errors.Is(err, models.MessageNotExistError) {
+		pkgLog.Debug("returning message %s to queue", msg.ID)
+		if nackErr := s.smsQueue.Nack(ctx, msg); nackErr != nil {
+			pkgLog.Error(nackErr, "failed to return message %s to queue", msg.ID)
+
+			if errors.Is(nackErr, models.InvalidQueueError) {
+				s.deadLetter(ctx, models.DeadLetter{
+					MessageId: msg.ID,
+					Payload:   common.ValueToJSON(msg),
+					Reason:    models.ReasonInvalidQueue,
+					Error:     nackErr.Error(),
+				})
+			}
+		}
 		return models.Sms{}, err
 	}
 
+	if ackErr := s.smsQueue.Ack(ctx, msg); ackErr != nil {
+		pkgLog.Error(ackErr, "failed to acknowledge message %s", msg.ID)
+	}
+
+	return updatedMsg, err
+}
+
+func (s *SmsService) RecoverUnacked(ctx context.Context) (int, error) {
+	pkgLog.Debug("recovering unacknowledged messages")
+	recovered, err := s.smsQueue.RecoverUnacked(ctx)
+	if err != nil {
+		pkgLog.Error(err, "failed to recover unacknowledged messages")
+		return 0, err
+	}
+	if recovered == 0 {
+		return 0, nil
+	}
+
+	pkgMetrics.SmsStatusMetric.WithLabelValues("redelivered").Add(float64(recovered))
+
+	pkgLog.Info("%d unacknowledged messages re-delivered", recovered)
+	return recovered, nil
+}
+
+// sendMessage sends the message and records the outcome. accepted reports
+// whether the provider took the message, even when recording it failed.
+func (s *SmsService) sendMessage(ctx context.Context, msg models.Sms) (models.Sms, bool, error) {
 	pkgLog.Debug("trying to send message %s to sms provider", msg.ID)
-	if err := s.smsSender.Send(ctx, msg); err != nil {
-		pkgLog.Error(err, "failed to send message %s to sms provider", msg.ID)
-		return s.SetMessageAsFailed(ctx, msg.ID)
+	res, err := s.smsSender.Send(ctx, msg)
+	if err != nil {
+		if errors.Is(err, models.ProviderThrottledError) {
+			// the provider never saw the message, so the attempt is not counted
+			pkgLog.Debug("message %s throttled by sms provider: %s", msg.ID, err.Error())
+			return models.Sms{}, false, err
+		}
+		pkgLog.Error(err, "failed to send message %s to sms provider on attempt %d", msg.ID, msg.Attempts)
+
+		policy := s.cfg.Retry.policyFor(err)
+		if msg.Attempts < policy.MaxAttempts {
+			retryingMsg, retryErr := s.SetMessageAsRetrying(ctx, msg.ID, time.Now().Add(policy.Backoff(msg.Attempts)))
+			return retryingMsg, false, retryErr
+		}
+
+		failedMsg, failErr := s.SetMessageAsFailed(ctx, msg.ID)
+		if failErr != nil {
+			return models.Sms{}, false, failErr
+		}
+
+		s.deadLetter(ctx, models.DeadLetter{
+			MessageId: msg.ID,
+			Payload:   common.ValueToJSON(msg),
+			Reason:    models.ReasonRetriesExhausted,
+			Error:     err.Error(),
+		})
+		return failedMsg, false, nil
 	}
+	pkgLog.Debug("message %s accepted by sms provider %s with id %s", msg.ID, res.Provider, res.MessageId)
 
-	return s.SetMessageAsSent(ctx, msg.ID)
+	sentMsg, err := s.recordSent(ctx, msg.ID, res)
+	if err != nil && !errors.Is(err, models.MessageNotExistError) {
+		// the reconciler records the kept result later instead of sending the
+		// message again
+		if acceptErr := s.smsQueue.SetAccepted(ctx, msg.ID, res); acceptErr != nil {
+			pkgLog.Error(acceptErr, "failed to keep send result of message %s", msg.ID)
+		}
+	}
+	return sentMsg, true, err
+}
+
+// recordSent sets the accepted message as sent, retrying with the record
+// policy while it fails.
+func (s *SmsService) recordSent(ctx context.Context, id string, res models.SendResult) (models.Sms, error) {
+	policy := s.cfg.Retry.Record
+	for attempt := 1; ; attempt++ {
+		sentMsg, err := s.SetMessageAsSent(ctx, id, res)
+		if err == nil || errors.Is(err, models.MessageNotExistError) || attempt >= policy.MaxAttempts {
+			return sentMsg, err
+		}
+
+		select {
+		case <-time.After(policy.Backoff(attempt)):
+		case <-ctx.Done():
+			return models.Sms{}, err
+		}
+	}
 }
@@ -6,12 +6,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/AshkanAbd/arvancloud_sms_gateway/common"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/mocks"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/services"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/pkg/metrics"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestSmsService_ScheduleMessage(t *testing.T) {
@@ -36,19 +38,26 @@ func TestSmsService_ScheduleMessage(t *testing.T) {
 			sendingMsgs[i] = msg
 		}
 
+		createdMsgs := make([]models.Sms, len(sendingMsgs))
+		for i, msg := range sendingMsgs {
+			msg.Entity = &shared.Entity{ID: fmt.Sprintf("%d", i+1)}
+			createdMsgs[i] = msg
+		}
+
 		mockQueue := mocks.NewMockISmsQueue(t)
 		mockSender := mocks.NewMockISmsSender(t)
 		mockRepo := mocks.NewMockISmsRepository(t)
 
 		mockRepo.EXPECT().
 			CreateScheduleMessages(ctx, sendingMsgs).
-			Return(nil).
+			Return(createdMsgs, nil).
 			Once()
 
 		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
 
-		actualErr := service.ScheduleSms(ctx, inputUserId, inputMsgs)
+		actualMsgs, actualErr := service.ScheduleSms(ctx, inputUserId, inputMsgs)
 		assert.NoError(t, actualErr)
+		assert.Equal(t, createdMsgs, actualMsgs)
 	})
 
 	t.Run("should return error when can not schedule", func(t *testing.T) {
@@ -75,13 +84,14 @@ func TestSmsService_ScheduleMessage(t *testing.T) {
 
 		mockRepo.EXPECT().
 			CreateScheduleMessages(ctx, sendingMsgs).
-			Return(fmt.Errorf("error")).
+			Return(nil, fmt.Errorf("error")).
 			Once()
 
 		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
 
-		actualErr := service.ScheduleSms(ctx, inputUserId, inputMsgs)
+		actualMsgs, actualErr := service.ScheduleSms(ctx, inputUserId, inputMsgs)
 		assert.Error(t, actualErr)
+		assert.Nil(t, actualMsgs)
 	})
 
 	t.Run("should return EmptyContentError when can not schedule", func(t *testing.T) {
@@ -108,13 +118,14 @@ func TestSmsService_ScheduleMessage(t *testing.T) {
 
 		mockRepo.EXPECT().
 			CreateScheduleMessages(ctx, sendingMsgs).
-			Return(models.EmptyReceiverError).
+			Return(nil, models.EmptyReceiverError).
 			Once()
 
 		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
 
-		actualErr := service.ScheduleSms(ctx, inputUserId, inputMsgs)
+		actualMsgs, actualErr := service.ScheduleSms(ctx, inputUserId, inputMsgs)
 		assert.Error(t, actualErr)
+		assert.Nil(t, actualMsgs)
 		assert.Equal(t, models.EmptyReceiverError, actualErr)
 	})
 
@@ -142,13 +153,14 @@ func TestSmsService_ScheduleMessage(t *testing.T) {
 
 		mockRepo.EXPECT().
 			CreateScheduleMessages(ctx, sendingMsgs).
-			Return(models.EmptyReceiverError).
+			Return(nil, models.EmptyReceiverError).
 			Once()
 
 		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
 
-		actualErr := service.ScheduleSms(ctx, inputUserId, inputMsgs)
+		actualMsgs, actualErr := service.ScheduleSms(ctx, inputUserId, inputMsgs)
 		assert.Error(t, actualErr)
+		assert.Nil(t, actualMsgs)
 		assert.Equal(t, models.EmptyReceiverError, actualErr)
 	})
 }
@@ -233,6 +245,129 @@ func TestSmsService_GetUserMessages(t *testing.T) {
 	})
 }
 
+func TestSmsService_CancelSms(t *testing.T) {
+	cfg := services.SmsServiceConfig{}
+
+	t.Run("should cancel scheduled sms", func(t *testing.T) {
+		ctx := context.Background()
+		expected := models.Sms{
+			Entity:   &shared.Entity{ID: "2"},
+			UserId:   "1",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusCanceled,
+			SendAt:   time.Now().Add(time.Hour),
+		}
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockRepo.EXPECT().
+			CancelScheduledMessage(ctx, expected.UserId, expected.ID).
+			Return(expected, nil).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.CancelSms(ctx, expected.UserId, expected.ID)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expected, actualMsg)
+	})
+
+	t.Run("should return MessageNotExistError when sms is not scheduled", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockRepo.EXPECT().
+			CancelScheduledMessage(ctx, "1", "2").
+			Return(models.Sms{}, models.MessageNotExistError).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.CancelSms(ctx, "1", "2")
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.MessageNotExistError, actualErr)
+		assert.Equal(t, models.Sms{}, actualMsg)
+	})
+}
+
+func TestSmsService_RescheduleSms(t *testing.T) {
+	cfg := services.SmsServiceConfig{}
+
+	t.Run("should change send time of scheduled sms", func(t *testing.T) {
+		ctx := context.Background()
+		sendAt := time.Now().Add(time.Hour)
+		expected := models.Sms{
+			Entity:        &shared.Entity{ID: "2"},
+			UserId:        "1",
+			Content:       "Test Content",
+			Receiver:      "09123456789",
+			Cost:          100,
+			Status:        models.StatusScheduled,
+			SendAt:        sendAt,
+			NextAttemptAt: sendAt,
+		}
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockRepo.EXPECT().
+			ChangeMessageSendAt(ctx, expected.UserId, expected.ID, sendAt).
+			Return(expected, nil).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.RescheduleSms(ctx, expected.UserId, expected.ID, sendAt)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expected, actualMsg)
+	})
+
+	t.Run("should return PastSendAtError when send time is not in the future", func(t *testing.T) {
+		ctx := context.Background()
+		sendAt := time.Now().Add(-time.Minute)
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.RescheduleSms(ctx, "1", "2", sendAt)
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.PastSendAtError, actualErr)
+		assert.Equal(t, models.Sms{}, actualMsg)
+	})
+
+	t.Run("should return MessageNotExistError when sms is not scheduled", func(t *testing.T) {
+		ctx := context.Background()
+		sendAt := time.Now().Add(time.Hour)
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockRepo.EXPECT().
+			ChangeMessageSendAt(ctx, "1", "2", sendAt).
+			Return(models.Sms{}, models.MessageNotExistError).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.RescheduleSms(ctx, "1", "2", sendAt)
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.MessageNotExistError, actualErr)
+		assert.Equal(t, models.Sms{}, actualMsg)
+	})
+}
+
 func TestSmsService_EnqueueEarliest(t *testing.T) {
 	cfg := services.SmsServiceConfig{
 		QueueCapacity: 100,
@@ -403,7 +538,7 @@ func TestSmsService_EnqueueEarliest(t *testing.T) {
 				Entity:     &shared.Entity{ID: "1"},
 				CreateDate: &shared.CreateDate{CreatedAt: time.Now()},
 				UpdateDate: &shared.UpdateDate{UpdatedAt: time.Now()},
-				UserId:     "1",
+				UserId:     "10",
 				Content:    "Test Content 1",
 				Receiver:   "09123456789",
 				Cost:       100,
@@ -413,7 +548,7 @@ func TestSmsService_EnqueueEarliest(t *testing.T) {
 				Entity:     &shared.Entity{ID: "2"},
 				CreateDate: &shared.CreateDate{CreatedAt: time.Now()},
 				UpdateDate: &shared.UpdateDate{UpdatedAt: time.Now()},
-				UserId:     "2",
+				UserId:     "20",
 				Content:    "Test Content 2",
 				Receiver:   "09123456788",
 				Cost:       200,
@@ -467,7 +602,7 @@ func TestSmsService_EnqueueEarliest(t *testing.T) {
 				Entity:     &shared.Entity{ID: "1"},
 				CreateDate: &shared.CreateDate{CreatedAt: time.Now()},
 				UpdateDate: &shared.UpdateDate{UpdatedAt: time.Now()},
-				UserId:     "1",
+				UserId:     "10",
 				Content:    "Test Content 1",
 				Receiver:   "09123456789",
 				Cost:       100,
@@ -477,7 +612,7 @@ func TestSmsService_EnqueueEarliest(t *testing.T) {
 				Entity:     &shared.Entity{ID: "2"},
 				CreateDate: &shared.CreateDate{CreatedAt: time.Now()},
 				UpdateDate: &shared.UpdateDate{UpdatedAt: time.Now()},
-				UserId:     "2",
+				UserId:     "20",
 				Content:    "Test Content 2",
 				Receiver:   "09123456788",
 				Cost:       200,
@@ -649,6 +784,63 @@ func TestSmsService_SetMessageAsFailed(t *testing.T) {
 	})
 }
 
+func TestSmsService_SetMessageAsRetrying(t *testing.T) {
+	cfg := services.SmsServiceConfig{}
+
+	t.Run("should set message as retrying", func(t *testing.T) {
+		ctx := context.Background()
+		nextAttemptAt := time.Now().Add(time.Minute)
+		expectedMsg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:        "1",
+			Content:       "Test Content",
+			Receiver:      "09123456789",
+			Cost:          100,
+			Status:        models.StatusRetrying,
+			Attempts:      1,
+			NextAttemptAt: nextAttemptAt,
+		}
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockRepo.EXPECT().
+			SetMessageAsRetrying(ctx, expectedMsg.ID, nextAttemptAt).
+			Return(expectedMsg, nil).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.SetMessageAsRetrying(ctx, expectedMsg.ID, nextAttemptAt)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedMsg, actualMsg)
+	})
+
+	t.Run("should return MessageNotExistError when message not exists", func(t *testing.T) {
+		ctx := context.Background()
+		nextAttemptAt := time.Now().Add(time.Minute)
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockRepo.EXPECT().
+			SetMessageAsRetrying(ctx, "1", nextAttemptAt).
+			Return(models.Sms{}, models.MessageNotExistError).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.SetMessageAsRetrying(ctx, "1", nextAttemptAt)
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.MessageNotExistError, actualErr)
+		assert.Equal(t, models.Sms{}, actualMsg)
+	})
+}
+
 func TestSmsService_SetMessageAsSent(t *testing.T) {
 	cfg := services.SmsServiceConfig{}
 
@@ -676,13 +868,13 @@ func TestSmsService_SetMessageAsSent(t *testing.T) {
 		mockRepo := mocks.NewMockISmsRepository(t)
 
 		mockRepo.EXPECT().
-			SetMessageAsSent(ctx, expectedMsg.ID).
+			SetMessageAsSent(ctx, expectedMsg.ID, models.SendResult{Provider: "primary"}).
 			Return(expectedMsg, nil).
 			Once()
 
 		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
 
-		actualMsg, actualErr := service.SetMessageAsSent(ctx, expectedMsg.ID)
+		actualMsg, actualErr := service.SetMessageAsSent(ctx, expectedMsg.ID, models.SendResult{Provider: "primary"})
 		assert.NoError(t, actualErr)
 		assert.Equal(t, expectedMsg.ID, actualMsg.ID)
 		assert.Equal(t, expectedMsg.CreatedAt, actualMsg.CreatedAt)
@@ -701,19 +893,143 @@ func TestSmsService_SetMessageAsSent(t *testing.T) {
 		mockRepo := mocks.NewMockISmsRepository(t)
 
 		mockRepo.EXPECT().
-			SetMessageAsSent(ctx, "1").
+			SetMessageAsSent(ctx, "1", models.SendResult{}).
 			Return(models.Sms{}, models.MessageNotExistError).
 			Once()
 
 		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
 
-		actualMsg, actualErr := service.SetMessageAsSent(ctx, "1")
+		actualMsg, actualErr := service.SetMessageAsSent(ctx, "1", models.SendResult{})
 		assert.Error(t, actualErr)
 		assert.Equal(t, models.MessageNotExistError, actualErr)
 		assert.Equal(t, models.Sms{}, actualMsg)
 	})
 }
 
+func TestSmsService_ProcessDeliveryReport(t *testing.T) {
+	cfg := services.SmsServiceConfig{}
+
+	t.Run("should set message delivery state", func(t *testing.T) {
+		ctx := context.Background()
+		report := models.DeliveryReport{
+			Provider:  "primary",
+			MessageId: "p-1",
+			Status:    models.StatusDelivered,
+			DoneAt:    time.Now(),
+		}
+		expectedMsg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:            "1",
+			Content:           "Test Content",
+			Receiver:          "09123456789",
+			Status:            models.StatusDelivered,
+			Provider:          "primary",
+			ProviderMessageId: "p-1",
+			DoneAt:            report.DoneAt,
+		}
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockRepo.EXPECT().
+			SetMessageDeliveryState(ctx, report).
+			Return(expectedMsg, nil).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.ProcessDeliveryReport(ctx, report)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedMsg, actualMsg)
+	})
+
+	t.Run("should stamp report without time with current time", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockRepo.EXPECT().
+			SetMessageDeliveryState(ctx, mock.MatchedBy(func(r models.DeliveryReport) bool {
+				return r.MessageId == "p-1" && !r.DoneAt.IsZero()
+			})).
+			Return(models.Sms{Entity: &shared.Entity{ID: "1"}, Status: models.StatusExpired}, nil).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		_, actualErr := service.ProcessDeliveryReport(ctx, models.DeliveryReport{
+			Provider:  "primary",
+			MessageId: "p-1",
+			Status:    models.StatusExpired,
+		})
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("should return InvalidDeliveryStatusError when status is not final", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		_, actualErr := service.ProcessDeliveryReport(ctx, models.DeliveryReport{
+			Provider:  "primary",
+			MessageId: "p-1",
+			Status:    models.StatusSent,
+		})
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.InvalidDeliveryStatusError, actualErr)
+	})
+
+	t.Run("should return EmptyProviderIdError when message id is empty", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		_, actualErr := service.ProcessDeliveryReport(ctx, models.DeliveryReport{
+			Provider: "primary",
+			Status:   models.StatusDelivered,
+		})
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.EmptyProviderIdError, actualErr)
+	})
+
+	t.Run("should return MessageNotExistError when no sent message matches", func(t *testing.T) {
+		ctx := context.Background()
+		report := models.DeliveryReport{
+			Provider:  "primary",
+			MessageId: "p-1",
+			Status:    models.StatusDelivered,
+			DoneAt:    time.Now(),
+		}
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockRepo.EXPECT().
+			SetMessageDeliveryState(ctx, report).
+			Return(models.Sms{}, models.MessageNotExistError).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		_, actualErr := service.ProcessDeliveryReport(ctx, report)
+		assert.ErrorIs(t, actualErr, models.MessageNotExistError)
+	})
+}
+
 func TestSmsService_SendFromQueue(t *testing.T) {
 	cfg := services.SmsServiceConfig{}
 
@@ -761,13 +1077,18 @@ func TestSmsService_SendFromQueue(t *testing.T) {
 			Return(msg, nil).
 			Once()
 
+		mockQueue.EXPECT().
+			Ack(ctx, msg).
+			Return(nil).
+			Once()
+
 		mockSender.EXPECT().
 			Send(ctx, msg).
-			Return(nil).
+			Return(models.SendResult{Provider: "primary", MessageId: "provider-1"}, nil).
 			Once()
 
 		mockRepo.EXPECT().
-			SetMessageAsSent(ctx, msg.ID).
+			SetMessageAsSent(ctx, msg.ID, models.SendResult{Provider: "primary", MessageId: "provider-1"}).
 			Return(expectedMsg, nil).
 			Once()
 
@@ -828,9 +1149,14 @@ func TestSmsService_SendFromQueue(t *testing.T) {
 			Return(msg, nil).
 			Once()
 
+		mockQueue.EXPECT().
+			Ack(ctx, msg).
+			Return(nil).
+			Once()
+
 		mockSender.EXPECT().
 			Send(ctx, msg).
-			Return(models.SendError).
+			Return(models.SendResult{}, models.SendError).
 			Once()
 
 		mockRepo.EXPECT().
@@ -838,6 +1164,16 @@ func TestSmsService_SendFromQueue(t *testing.T) {
 			Return(expectedMsg, nil).
 			Once()
 
+		mockRepo.EXPECT().
+			CreateDeadLetter(ctx, models.DeadLetter{
+				MessageId: msg.ID,
+				Payload:   common.ValueToJSON(msg),
+				Reason:    models.ReasonRetriesExhausted,
+				Error:     models.SendError.Error(),
+			}).
+			Return(nil).
+			Once()
+
 		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
 
 		actualMsg, actualErr := service.SendFromQueue(ctx)
@@ -851,8 +1187,29 @@ func TestSmsService_SendFromQueue(t *testing.T) {
 		assert.Equal(t, expectedMsg.Status, actualMsg.Status)
 	})
 
-	t.Run("should return InvalidQueueError when can not read from queue", func(t *testing.T) {
+	t.Run("should set message as retrying when attempts are left for the error class", func(t *testing.T) {
 		ctx := context.Background()
+		retryCfg := services.SmsServiceConfig{
+			Retry: services.RetryConfig{
+				Temporary: services.RetryPolicy{
+					MaxAttempts:    3,
+					InitialBackoff: time.Minute,
+				},
+			},
+		}
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "1",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+			Attempts: 2,
+		}
+		expectedMsg := msg
+		expectedMsg.Status = models.StatusRetrying
 
 		mockQueue := mocks.NewMockISmsQueue(t)
 		mockSender := mocks.NewMockISmsSender(t)
@@ -860,35 +1217,99 @@ func TestSmsService_SendFromQueue(t *testing.T) {
 
 		mockQueue.EXPECT().
 			Pop(ctx).
-			Return(models.Sms{}, models.InvalidQueueError).
+			Return(msg, nil).
 			Once()
 
-		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
-
-		actualMsg, actualErr := service.SendFromQueue(ctx)
-		assert.Error(t, actualErr)
-		assert.Equal(t, models.InvalidQueueError, actualErr)
-		assert.Equal(t, models.Sms{}, actualMsg)
-	})
+		mockQueue.EXPECT().
+			Ack(ctx, msg).
+			Return(nil).
+			Once()
 
-	t.Run("should return MessageNotExistError when message not exist", func(t *testing.T) {
+		mockSender.EXPECT().
+			Send(ctx, msg).
+			Return(models.SendResult{}, models.TemporarySendError).
+			Once()
+
+		mockRepo.EXPECT().
+			SetMessageAsRetrying(ctx, msg.ID, mock.MatchedBy(func(nextAttemptAt time.Time) bool {
+				delay := time.Until(nextAttemptAt)
+				return delay > time.Minute && delay <= 2*time.Minute
+			})).
+			Return(expectedMsg, nil).
+			Once()
+
+		service := services.NewSmsService(retryCfg, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.SendFromQueue(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.StatusRetrying, actualMsg.Status)
+	})
+
+	t.Run("should return message to queue without retrying when provider is throttled", func(t *testing.T) {
 		ctx := context.Background()
 		msg := models.Sms{
 			Entity: &shared.Entity{
 				ID: "1",
 			},
-			CreateDate: &shared.CreateDate{
-				CreatedAt: time.Now(),
+			UserId:   "1",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+			Attempts: 1,
+		}
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockQueue.EXPECT().
+			Pop(ctx).
+			Return(msg, nil).
+			Once()
+
+		mockSender.EXPECT().
+			Send(ctx, msg).
+			Return(models.SendResult{}, models.ProviderThrottledError).
+			Once()
+
+		mockQueue.EXPECT().
+			Nack(ctx, msg).
+			Return(nil).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.SendFromQueue(ctx)
+		assert.ErrorIs(t, actualErr, models.ProviderThrottledError)
+		assert.Equal(t, models.Sms{}, actualMsg)
+	})
+
+	t.Run("should set message as failed when retries of the error class are exhausted", func(t *testing.T) {
+		ctx := context.Background()
+		retryCfg := services.SmsServiceConfig{
+			Retry: services.RetryConfig{
+				Temporary: services.RetryPolicy{
+					MaxAttempts: 3,
+				},
+				Permanent: services.RetryPolicy{
+					MaxAttempts: 1,
+				},
 			},
-			UpdateDate: &shared.UpdateDate{
-				UpdatedAt: time.Now(),
+		}
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
 			},
 			UserId:   "1",
 			Content:  "Test Content",
 			Receiver: "09123456789",
 			Cost:     100,
 			Status:   models.StatusEnqueued,
+			Attempts: 1,
 		}
+		expectedMsg := msg
+		expectedMsg.Status = models.StatusFailed
 
 		mockQueue := mocks.NewMockISmsQueue(t)
 		mockSender := mocks.NewMockISmsSender(t)
@@ -899,21 +1320,902 @@ func TestSmsService_SendFromQueue(t *testing.T) {
 			Return(msg, nil).
 			Once()
 
+		mockQueue.EXPECT().
+			Ack(ctx, msg).
+			Return(nil).
+			Once()
+
 		mockSender.EXPECT().
 			Send(ctx, msg).
+			Return(models.SendResult{}, models.PermanentSendError).
+			Once()
+
+		mockRepo.EXPECT().
+			SetMessageAsFailed(ctx, msg.ID).
+			Return(expectedMsg, nil).
+			Once()
+
+		mockRepo.EXPECT().
+			CreateDeadLetter(ctx, models.DeadLetter{
+				MessageId: msg.ID,
+				Payload:   common.ValueToJSON(msg),
+				Reason:    models.ReasonRetriesExhausted,
+				Error:     models.PermanentSendError.Error(),
+			}).
 			Return(nil).
 			Once()
 
+		service := services.NewSmsService(retryCfg, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.SendFromQueue(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.StatusFailed, actualMsg.Status)
+	})
+
+	t.Run("should return message to queue when can not update its status", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "1",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+			Attempts: 1,
+		}
+		expectedErr := fmt.Errorf("connection refused")
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockQueue.EXPECT().
+			Pop(ctx).
+			Return(msg, nil).
+			Once()
+
+		mockSender.EXPECT().
+			Send(ctx, msg).
+			Return(models.SendResult{}, models.SendError).
+			Once()
+
 		mockRepo.EXPECT().
-			SetMessageAsSent(ctx, msg.ID).
-			Return(models.Sms{}, models.MessageNotExistError).
+			SetMessageAsFailed(ctx, msg.ID).
+			Return(models.Sms{}, expectedErr).
+			Once()
+
+		mockQueue.EXPECT().
+			Nack(ctx, msg).
+			Return(nil).
 			Once()
 
 		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
 
 		actualMsg, actualErr := service.SendFromQueue(ctx)
 		assert.Error(t, actualErr)
-		assert.Equal(t, models.MessageNotExistError, actualErr)
+		assert.Equal(t, expectedErr, actualErr)
+		assert.Equal(t, models.Sms{}, actualMsg)
+	})
+
+	t.Run("should retry setting message accepted by provider as sent", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "1",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+			Attempts: 1,
+		}
+		sendRes := models.SendResult{Provider: "primary", MessageId: "provider-1"}
+		expectedMsg := msg
+		expectedMsg.Status = models.StatusSent
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockQueue.EXPECT().
+			Pop(ctx).
+			Return(msg, nil).
+			Once()
+
+		mockSender.EXPECT().
+			Send(ctx, msg).
+			Return(sendRes, nil).
+			Once()
+
+		mockRepo.EXPECT().
+			SetMessageAsSent(ctx, msg.ID, sendRes).
+			Return(models.Sms{}, fmt.Errorf("connection refused")).
+			Once()
+
+		mockRepo.EXPECT().
+			SetMessageAsSent(ctx, msg.ID, sendRes).
+			Return(expectedMsg, nil).
+			Once()
+
+		mockQueue.EXPECT().
+			Ack(ctx, msg).
+			Return(nil).
+			Once()
+
+		service := services.NewSmsService(services.SmsServiceConfig{
+			Retry: services.RetryConfig{
+				Record: services.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+			},
+		}, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.SendFromQueue(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedMsg, actualMsg)
+	})
+
+	t.Run("should keep send result and acknowledge message accepted by provider when can not set it as sent", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "1",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+			Attempts: 1,
+		}
+		sendRes := models.SendResult{Provider: "primary", MessageId: "provider-1"}
+		expectedErr := fmt.Errorf("connection refused")
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockQueue.EXPECT().
+			Pop(ctx).
+			Return(msg, nil).
+			Once()
+
+		mockSender.EXPECT().
+			Send(ctx, msg).
+			Return(sendRes, nil).
+			Once()
+
+		mockRepo.EXPECT().
+			SetMessageAsSent(ctx, msg.ID, sendRes).
+			Return(models.Sms{}, expectedErr).
+			Once()
+
+		mockQueue.EXPECT().
+			SetAccepted(ctx, msg.ID, sendRes).
+			Return(nil).
+			Once()
+
+		mockQueue.EXPECT().
+			Ack(ctx, msg).
+			Return(nil).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.SendFromQueue(ctx)
+		assert.Equal(t, expectedErr, actualErr)
+		assert.Equal(t, models.Sms{}, actualMsg)
+	})
+
+	t.Run("should return InvalidQueueError when can not read from queue", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockQueue.EXPECT().
+			Pop(ctx).
+			Return(models.Sms{}, models.InvalidQueueError).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.SendFromQueue(ctx)
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.InvalidQueueError, actualErr)
+		assert.Equal(t, models.Sms{}, actualMsg)
+	})
+
+	t.Run("should dead-letter undecodable payload", func(t *testing.T) {
+		ctx := context.Background()
+		decodeErr := fmt.Errorf("invalid character 'x' looking for beginning of value")
+		popErr := &models.PayloadError{
+			Payload: "xyz",
+			Err:     decodeErr,
+		}
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockQueue.EXPECT().
+			Pop(ctx).
+			Return(models.Sms{}, popErr).
+			Once()
+
+		mockRepo.EXPECT().
+			CreateDeadLetter(ctx, models.DeadLetter{
+				Payload: "xyz",
+				Reason:  models.ReasonUndecodable,
+				Error:   decodeErr.Error(),
+			}).
+			Return(nil).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.SendFromQueue(ctx)
+		assert.Error(t, actualErr)
+		assert.ErrorIs(t, actualErr, models.UndecodableMessageError)
 		assert.Equal(t, models.Sms{}, actualMsg)
 	})
+
+	t.Run("should dead-letter message when can not return it to invalid queue", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "1",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+			Attempts: 1,
+		}
+		expectedErr := fmt.Errorf("connection refused")
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockQueue.EXPECT().
+			Pop(ctx).
+			Return(msg, nil).
+			Once()
+
+		mockSender.EXPECT().
+			Send(ctx, msg).
+			Return(models.SendResult{}, models.TemporarySendError).
+			Once()
+
+		mockRepo.EXPECT().
+			SetMessageAsFailed(ctx, msg.ID).
+			Return(models.Sms{}, expectedErr).
+			Once()
+
+		mockQueue.EXPECT().
+			Nack(ctx, msg).
+			Return(models.InvalidQueueError).
+			Once()
+
+		mockRepo.EXPECT().
+			CreateDeadLetter(ctx, models.DeadLetter{
+				MessageId: msg.ID,
+				Payload:   common.ValueToJSON(msg),
+				Reason:    models.ReasonInvalidQueue,
+				Error:     models.InvalidQueueError.Error(),
+			}).
+			Return(nil).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.SendFromQueue(ctx)
+		assert.Error(t, actualErr)
+		assert.Equal(t, expectedErr, actualErr)
+		assert.Equal(t, models.Sms{}, actualMsg)
+	})
+
+	t.Run("should return MessageNotExistError when message not exist", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			CreateDate: &shared.CreateDate{
+				CreatedAt: time.Now(),
+			},
+			UpdateDate: &shared.UpdateDate{
+				UpdatedAt: time.Now(),
+			},
+			UserId:   "1",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockQueue.EXPECT().
+			Pop(ctx).
+			Return(msg, nil).
+			Once()
+
+		mockQueue.EXPECT().
+			Ack(ctx, msg).
+			Return(nil).
+			Once()
+
+		mockSender.EXPECT().
+			Send(ctx, msg).
+			Return(models.SendResult{Provider: "primary", MessageId: "provider-1"}, nil).
+			Once()
+
+		mockRepo.EXPECT().
+			SetMessageAsSent(ctx, msg.ID, models.SendResult{Provider: "primary", MessageId: "provider-1"}).
+			Return(models.Sms{}, models.MessageNotExistError).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.SendFromQueue(ctx)
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.MessageNotExistError, actualErr)
+		assert.Equal(t, models.Sms{}, actualMsg)
+	})
+}
+
+func TestSmsService_RecoverUnacked(t *testing.T) {
+	cfg := services.SmsServiceConfig{}
+
+	t.Run("should return recovered count", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockQueue.EXPECT().
+			RecoverUnacked(ctx).
+			Return(3, nil).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualCount, actualErr := service.RecoverUnacked(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 3, actualCount)
+	})
+
+	t.Run("should return InvalidQueueError when queue is not valid", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockQueue.EXPECT().
+			RecoverUnacked(ctx).
+			Return(0, models.InvalidQueueError).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualCount, actualErr := service.RecoverUnacked(ctx)
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.InvalidQueueError, actualErr)
+		assert.Equal(t, 0, actualCount)
+	})
+}
+
+func TestSmsService_ReconcileStuckMessages(t *testing.T) {
+	cfg := services.SmsServiceConfig{
+		Reconcile: services.ReconcileConfig{
+			StuckThreshold: 10 * time.Minute,
+			BatchSize:      50,
+			MaxAttempts:    3,
+		},
+	}
+	enqueuedBefore := mock.MatchedBy(func(enqueuedBefore time.Time) bool {
+		return time.Since(enqueuedBefore) >= cfg.Reconcile.StuckThreshold
+	})
+	t.Run("should reschedule or fail stranded messages and skip queued ones", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		stale := []models.Sms{
+			{
+				Entity:   &shared.Entity{ID: "1"},
+				UserId:   "1",
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+				Attempts: 1,
+			},
+			{
+				Entity:   &shared.Entity{ID: "2"},
+				UserId:   "1",
+				Content:  "Test Content 2",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+				Attempts: 1,
+			},
+			{
+				Entity:   &shared.Entity{ID: "3"},
+				UserId:   "1",
+				Content:  "Test Content 3",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+				Attempts: 3,
+			},
+		}
+		failedMsg := stale[2]
+		failedMsg.Status = models.StatusFailed
+
+		mockRepo.EXPECT().
+			GetStaleEnqueuedMessages(ctx, enqueuedBefore, cfg.Reconcile.BatchSize).
+			Return(stale, nil).
+			Once()
+
+		mockQueue.EXPECT().
+			GetQueuedIds(ctx, []string{"1", "2", "3"}).
+			Return([]string{"2"}, nil).
+			Once()
+
+		mockQueue.EXPECT().
+			GetAccepted(ctx, []string{"1", "3"}).
+			Return(nil, nil).
+			Once()
+
+		mockRepo.EXPECT().
+			SetMessageAsFailed(ctx, "3").
+			Return(failedMsg, nil).
+			Once()
+
+		mockRepo.EXPECT().
+			CreateDeadLetter(ctx, models.DeadLetter{
+				MessageId: "3",
+				Payload:   common.ValueToJSON(stale[2]),
+				Reason:    models.ReasonRetriesExhausted,
+				Error:     "message stranded in enqueued status",
+			}).
+			Return(nil).
+			Once()
+
+		mockRepo.EXPECT().
+			RescheduleStaleMessages(ctx, []string{"1"}, enqueuedBefore).
+			Return(1, nil).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualRes, actualErr := service.ReconcileStuckMessages(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 1, actualRes.Rescheduled)
+		assert.Equal(t, []models.Sms{failedMsg}, actualRes.Failed)
+	})
+
+	t.Run("should not count messages sent or enqueued again meanwhile as rescheduled", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		stale := []models.Sms{
+			{
+				Entity:   &shared.Entity{ID: "1"},
+				UserId:   "1",
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+				Attempts: 1,
+			},
+			{
+				Entity:   &shared.Entity{ID: "2"},
+				UserId:   "1",
+				Content:  "Test Content 2",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+				Attempts: 1,
+			},
+		}
+
+		mockRepo.EXPECT().
+			GetStaleEnqueuedMessages(ctx, enqueuedBefore, cfg.Reconcile.BatchSize).
+			Return(stale, nil).
+			Once()
+
+		mockQueue.EXPECT().
+			GetQueuedIds(ctx, []string{"1", "2"}).
+			Return(nil, nil).
+			Once()
+
+		mockQueue.EXPECT().
+			GetAccepted(ctx, []string{"1", "2"}).
+			Return(nil, nil).
+			Once()
+
+		mockRepo.EXPECT().
+			RescheduleStaleMessages(ctx, []string{"1", "2"}, enqueuedBefore).
+			Return(1, nil).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualRes, actualErr := service.ReconcileStuckMessages(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.ReconcileResult{Rescheduled: 1}, actualRes)
+	})
+
+	t.Run("should record accepted messages as sent instead of rescheduling them", func(t *testing.T) {
+		ctx := context.Background()
+		sendRes := models.SendResult{Provider: "primary", MessageId: "provider-1"}
+		sentMsg := models.Sms{
+			Entity:   &shared.Entity{ID: "1"},
+			UserId:   "1",
+			Content:  "Test Content 1",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusSent,
+			Attempts: 1,
+		}
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		stale := []models.Sms{
+			{
+				Entity:   &shared.Entity{ID: "1"},
+				UserId:   "1",
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+				Attempts: 1,
+			},
+			{
+				Entity:   &shared.Entity{ID: "2"},
+				UserId:   "1",
+				Content:  "Test Content 2",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+				Attempts: 1,
+			},
+		}
+
+		mockRepo.EXPECT().
+			GetStaleEnqueuedMessages(ctx, enqueuedBefore, cfg.Reconcile.BatchSize).
+			Return(stale, nil).
+			Once()
+
+		mockQueue.EXPECT().
+			GetQueuedIds(ctx, []string{"1", "2"}).
+			Return(nil, nil).
+			Once()
+
+		mockQueue.EXPECT().
+			GetAccepted(ctx, []string{"1", "2"}).
+			Return(map[string]models.SendResult{"1": sendRes, "2": sendRes}, nil).
+			Once()
+
+		mockRepo.EXPECT().
+			SetMessageAsSent(ctx, "1", sendRes).
+			Return(sentMsg, nil).
+			Once()
+
+		mockRepo.EXPECT().
+			SetMessageAsSent(ctx, "2", sendRes).
+			Return(models.Sms{}, fmt.Errorf("connection refused")).
+			Once()
+
+		mockQueue.EXPECT().
+			RemoveAccepted(ctx, []string{"1"}).
+			Return(nil).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualRes, actualErr := service.ReconcileStuckMessages(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.ReconcileResult{Sent: []models.Sms{sentMsg}}, actualRes)
+	})
+
+	t.Run("should return empty result when no message is stale", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockRepo.EXPECT().
+			GetStaleEnqueuedMessages(ctx, enqueuedBefore, cfg.Reconcile.BatchSize).
+			Return([]models.Sms{}, nil).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualRes, actualErr := service.ReconcileStuckMessages(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.ReconcileResult{}, actualRes)
+	})
+
+	t.Run("should return InvalidQueueError when queue is not valid", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		stale := []models.Sms{
+			{
+				Entity:   &shared.Entity{ID: "1"},
+				UserId:   "1",
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+				Attempts: 1,
+			},
+		}
+
+		mockRepo.EXPECT().
+			GetStaleEnqueuedMessages(ctx, enqueuedBefore, cfg.Reconcile.BatchSize).
+			Return(stale, nil).
+			Once()
+
+		mockQueue.EXPECT().
+			GetQueuedIds(ctx, []string{"1"}).
+			Return(nil, models.InvalidQueueError).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualRes, actualErr := service.ReconcileStuckMessages(ctx)
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.InvalidQueueError, actualErr)
+		assert.Equal(t, models.ReconcileResult{}, actualRes)
+	})
+
+	t.Run("should return failed messages with error when can not reschedule", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		stale := []models.Sms{
+			{
+				Entity:   &shared.Entity{ID: "1"},
+				UserId:   "1",
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+				Attempts: 1,
+			},
+			{
+				Entity:   &shared.Entity{ID: "2"},
+				UserId:   "1",
+				Content:  "Test Content 2",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+				Attempts: 5,
+			},
+		}
+		failedMsg := stale[1]
+		failedMsg.Status = models.StatusFailed
+
+		mockRepo.EXPECT().
+			GetStaleEnqueuedMessages(ctx, enqueuedBefore, cfg.Reconcile.BatchSize).
+			Return(stale, nil).
+			Once()
+
+		mockQueue.EXPECT().
+			GetQueuedIds(ctx, []string{"1", "2"}).
+			Return(nil, nil).
+			Once()
+
+		mockQueue.EXPECT().
+			GetAccepted(ctx, []string{"1", "2"}).
+			Return(nil, nil).
+			Once()
+
+		mockRepo.EXPECT().
+			SetMessageAsFailed(ctx, "2").
+			Return(failedMsg, nil).
+			Once()
+
+		mockRepo.EXPECT().
+			CreateDeadLetter(ctx, models.DeadLetter{
+				MessageId: "2",
+				Payload:   common.ValueToJSON(stale[1]),
+				Reason:    models.ReasonRetriesExhausted,
+				Error:     "message stranded in enqueued status",
+			}).
+			Return(nil).
+			Once()
+
+		mockRepo.EXPECT().
+			RescheduleStaleMessages(ctx, []string{"1"}, enqueuedBefore).
+			Return(0, fmt.Errorf("db error")).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualRes, actualErr := service.ReconcileStuckMessages(ctx)
+		assert.Error(t, actualErr)
+		assert.Equal(t, "db error", actualErr.Error())
+		assert.Equal(t, []models.Sms{failedMsg}, actualRes.Failed)
+	})
+}
+
+func TestSmsService_GetDeadLetters(t *testing.T) {
+	cfg := services.SmsServiceConfig{}
+
+	t.Run("should return dead letters", func(t *testing.T) {
+		ctx := context.Background()
+		expected := []models.DeadLetter{
+			{
+				Entity:    &shared.Entity{ID: "2"},
+				MessageId: "7",
+				Payload:   `{"ID":"7"}`,
+				Reason:    models.ReasonRetriesExhausted,
+				Error:     "failed to send message",
+			},
+			{
+				Entity:  &shared.Entity{ID: "1"},
+				Payload: "xyz",
+				Reason:  models.ReasonUndecodable,
+				Error:   "invalid character",
+			},
+		}
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockRepo.EXPECT().
+			GetDeadLetters(ctx, 0, 10).
+			Return(expected, nil).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualLetters, actualErr := service.GetDeadLetters(ctx, 0, 10)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expected, actualLetters)
+	})
+}
+
+func TestSmsService_RequeueDeadLetter(t *testing.T) {
+	cfg := services.SmsServiceConfig{}
+
+	t.Run("should requeue message of dead letter", func(t *testing.T) {
+		ctx := context.Background()
+		expected := models.Sms{
+			Entity:   &shared.Entity{ID: "7"},
+			UserId:   "1",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusScheduled,
+		}
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockRepo.EXPECT().
+			RequeueDeadLetter(ctx, "2").
+			Return(expected, nil).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.RequeueDeadLetter(ctx, "2")
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expected, actualMsg)
+	})
+
+	t.Run("should return NotRequeueableError when dead letter has no message", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockRepo.EXPECT().
+			RequeueDeadLetter(ctx, "1").
+			Return(models.Sms{}, models.NotRequeueableError).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualMsg, actualErr := service.RequeueDeadLetter(ctx, "1")
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.NotRequeueableError, actualErr)
+		assert.Equal(t, models.Sms{}, actualMsg)
+	})
+}
+
+func TestSmsService_DeleteDeadLetter(t *testing.T) {
+	cfg := services.SmsServiceConfig{}
+
+	t.Run("should delete dead letter", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockRepo.EXPECT().
+			DeleteDeadLetter(ctx, "1").
+			Return(nil).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualErr := service.DeleteDeadLetter(ctx, "1")
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("should return DeadLetterNotExistError when dead letter not exists", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockRepo.EXPECT().
+			DeleteDeadLetter(ctx, "1").
+			Return(models.DeadLetterNotExistError).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualErr := service.DeleteDeadLetter(ctx, "1")
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.DeadLetterNotExistError, actualErr)
+	})
+}
+
+func TestSmsService_PurgeDeadLetters(t *testing.T) {
+	cfg := services.SmsServiceConfig{}
+
+	t.Run("should return purged count", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockSender := mocks.NewMockISmsSender(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockRepo.EXPECT().
+			PurgeDeadLetters(ctx).
+			Return(3, nil).
+			Once()
+
+		service := services.NewSmsService(cfg, mockRepo, mockSender, mockQueue)
+
+		actualPurged, actualErr := service.PurgeDeadLetters(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 3, actualPurged)
+	})
 }
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
 	mock "github.com/stretchr/testify/mock"
@@ -38,6 +39,135 @@ func (_m *MockIUserRepository) EXPECT() *MockIUserRepository_Expecter {
 	return &MockIUserRepository_Expecter{mock: &_m.Mock}
 }
 
+// CaptureBalanceHold provides a mock function for the type MockIUserRepository
+func (_mock *MockIUserRepository) CaptureBalanceHold(ctx context.Context, messageId string) (models.BalanceHold, error) {
+	ret := _mock.Called(ctx, messageId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CaptureBalanceHold")
+	}
+
+	var r0 models.BalanceHold
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (models.BalanceHold, error)); ok {
+		return returnFunc(ctx, messageId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) models.BalanceHold); ok {
+		r0 = returnFunc(ctx, messageId)
+	} else {
+		r0 = ret.Get(0).(models.BalanceHold)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, messageId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserRepository_CaptureBalanceHold_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CaptureBalanceHold'
+type MockIUserRepository_CaptureBalanceHold_Call struct {
+	*mock.Call
+}
+
+// CaptureBalanceHold is a helper method to define mock.On call
+//   - ctx context.Context
+//   - messageId string
+func (_e *MockIUserRepository_Expecter) CaptureBalanceHold(ctx interface{}, messageId interface{}) *MockIUserRepository_CaptureBalanceHold_Call {
+	return &MockIUserRepository_CaptureBalanceHold_Call{Call: _e.mock.On("CaptureBalanceHold", ctx, messageId)}
+}
+
+func (_c *MockIUserRepository_CaptureBalanceHold_Call) Run(run func(ctx context.Context, messageId string)) *MockIUserRepository_CaptureBalanceHold_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserRepository_CaptureBalanceHold_Call) Return(balanceHold models.BalanceHold, err error) *MockIUserRepository_CaptureBalanceHold_Call {
+	_c.Call.Return(balanceHold, err)
+	return _c
+}
+
+func (_c *MockIUserRepository_CaptureBalanceHold_Call) RunAndReturn(run func(ctx context.Context, messageId string) (models.BalanceHold, error)) *MockIUserRepository_CaptureBalanceHold_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateBalanceHolds provides a mock function for the type MockIUserRepository
+func (_mock *MockIUserRepository) CreateBalanceHolds(ctx context.Context, userId string, holds []models.BalanceHold) error {
+	ret := _mock.Called(ctx, userId, holds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBalanceHolds")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []models.BalanceHold) error); ok {
+		r0 = returnFunc(ctx, userId, holds)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIUserRepository_CreateBalanceHolds_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateBalanceHolds'
+type MockIUserRepository_CreateBalanceHolds_Call struct {
+	*mock.Call
+}
+
+// CreateBalanceHolds is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId string
+//   - holds []models.BalanceHold
+func (_e *MockIUserRepository_Expecter) CreateBalanceHolds(ctx interface{}, userId interface{}, holds interface{}) *MockIUserRepository_CreateBalanceHolds_Call {
+	return &MockIUserRepository_CreateBalanceHolds_Call{Call: _e.mock.On("CreateBalanceHolds", ctx, userId, holds)}
+}
+
+func (_c *MockIUserRepository_CreateBalanceHolds_Call) Run(run func(ctx context.Context, userId string, holds []models.BalanceHold)) *MockIUserRepository_CreateBalanceHolds_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []models.BalanceHold
+		if args[2] != nil {
+			arg2 = args[2].([]models.BalanceHold)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserRepository_CreateBalanceHolds_Call) Return(err error) *MockIUserRepository_CreateBalanceHolds_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIUserRepository_CreateBalanceHolds_Call) RunAndReturn(run func(ctx context.Context, userId string, holds []models.BalanceHold) error) *MockIUserRepository_CreateBalanceHolds_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateUser provides a mock function for the type MockIUserRepository
 func (_mock *MockIUserRepository) CreateUser(ctx context.Context, user models.User) (models.User, error) {
 	ret := _mock.Called(ctx, user)
@@ -104,6 +234,244 @@ func (_c *MockIUserRepository_CreateUser_Call) RunAndReturn(run func(ctx context
 	return _c
 }
 
+// GetBalanceStatement provides a mock function for the type MockIUserRepository
+func (_mock *MockIUserRepository) GetBalanceStatement(ctx context.Context, userId string, from time.Time, to time.Time) (models.BalanceStatement, error) {
+	ret := _mock.Called(ctx, userId, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalanceStatement")
+	}
+
+	var r0 models.BalanceStatement
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) (models.BalanceStatement, error)); ok {
+		return returnFunc(ctx, userId, from, to)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) models.BalanceStatement); ok {
+		r0 = returnFunc(ctx, userId, from, to)
+	} else {
+		r0 = ret.Get(0).(models.BalanceStatement)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = returnFunc(ctx, userId, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserRepository_GetBalanceStatement_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBalanceStatement'
+type MockIUserRepository_GetBalanceStatement_Call struct {
+	*mock.Call
+}
+
+// GetBalanceStatement is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId string
+//   - from time.Time
+//   - to time.Time
+func (_e *MockIUserRepository_Expecter) GetBalanceStatement(ctx interface{}, userId interface{}, from interface{}, to interface{}) *MockIUserRepository_GetBalanceStatement_Call {
+	return &MockIUserRepository_GetBalanceStatement_Call{Call: _e.mock.On("GetBalanceStatement", ctx, userId, from, to)}
+}
+
+func (_c *MockIUserRepository_GetBalanceStatement_Call) Run(run func(ctx context.Context, userId string, from time.Time, to time.Time)) *MockIUserRepository_GetBalanceStatement_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserRepository_GetBalanceStatement_Call) Return(balanceStatement models.BalanceStatement, err error) *MockIUserRepository_GetBalanceStatement_Call {
+	_c.Call.Return(balanceStatement, err)
+	return _c
+}
+
+func (_c *MockIUserRepository_GetBalanceStatement_Call) RunAndReturn(run func(ctx context.Context, userId string, from time.Time, to time.Time) (models.BalanceStatement, error)) *MockIUserRepository_GetBalanceStatement_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBalanceTransactions provides a mock function for the type MockIUserRepository
+func (_mock *MockIUserRepository) GetBalanceTransactions(ctx context.Context, userId string, filter models.TransactionFilter, skip int, limit int) ([]models.BalanceTransaction, error) {
+	ret := _mock.Called(ctx, userId, filter, skip, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalanceTransactions")
+	}
+
+	var r0 []models.BalanceTransaction
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.TransactionFilter, int, int) ([]models.BalanceTransaction, error)); ok {
+		return returnFunc(ctx, userId, filter, skip, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.TransactionFilter, int, int) []models.BalanceTransaction); ok {
+		r0 = returnFunc(ctx, userId, filter, skip, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.BalanceTransaction)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, models.TransactionFilter, int, int) error); ok {
+		r1 = returnFunc(ctx, userId, filter, skip, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserRepository_GetBalanceTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBalanceTransactions'
+type MockIUserRepository_GetBalanceTransactions_Call struct {
+	*mock.Call
+}
+
+// GetBalanceTransactions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId string
+//   - filter models.TransactionFilter
+//   - skip int
+//   - limit int
+func (_e *MockIUserRepository_Expecter) GetBalanceTransactions(ctx interface{}, userId interface{}, filter interface{}, skip interface{}, limit interface{}) *MockIUserRepository_GetBalanceTransactions_Call {
+	return &MockIUserRepository_GetBalanceTransactions_Call{Call: _e.mock.On("GetBalanceTransactions", ctx, userId, filter, skip, limit)}
+}
+
+func (_c *MockIUserRepository_GetBalanceTransactions_Call) Run(run func(ctx context.Context, userId string, filter models.TransactionFilter, skip int, limit int)) *MockIUserRepository_GetBalanceTransactions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 models.TransactionFilter
+		if args[2] != nil {
+			arg2 = args[2].(models.TransactionFilter)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		var arg4 int
+		if args[4] != nil {
+			arg4 = args[4].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserRepository_GetBalanceTransactions_Call) Return(balanceTransactions []models.BalanceTransaction, err error) *MockIUserRepository_GetBalanceTransactions_Call {
+	_c.Call.Return(balanceTransactions, err)
+	return _c
+}
+
+func (_c *MockIUserRepository_GetBalanceTransactions_Call) RunAndReturn(run func(ctx context.Context, userId string, filter models.TransactionFilter, skip int, limit int) ([]models.BalanceTransaction, error)) *MockIUserRepository_GetBalanceTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUnsettledBalanceHolds provides a mock function for the type MockIUserRepository
+func (_mock *MockIUserRepository) GetUnsettledBalanceHolds(ctx context.Context, finishedBefore time.Time, limit int) ([]models.UnsettledHold, error) {
+	ret := _mock.Called(ctx, finishedBefore, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUnsettledBalanceHolds")
+	}
+
+	var r0 []models.UnsettledHold
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]models.UnsettledHold, error)); ok {
+		return returnFunc(ctx, finishedBefore, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) []models.UnsettledHold); ok {
+		r0 = returnFunc(ctx, finishedBefore, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.UnsettledHold)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = returnFunc(ctx, finishedBefore, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserRepository_GetUnsettledBalanceHolds_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUnsettledBalanceHolds'
+type MockIUserRepository_GetUnsettledBalanceHolds_Call struct {
+	*mock.Call
+}
+
+// GetUnsettledBalanceHolds is a helper method to define mock.On call
+//   - ctx context.Context
+//   - finishedBefore time.Time
+//   - limit int
+func (_e *MockIUserRepository_Expecter) GetUnsettledBalanceHolds(ctx interface{}, finishedBefore interface{}, limit interface{}) *MockIUserRepository_GetUnsettledBalanceHolds_Call {
+	return &MockIUserRepository_GetUnsettledBalanceHolds_Call{Call: _e.mock.On("GetUnsettledBalanceHolds", ctx, finishedBefore, limit)}
+}
+
+func (_c *MockIUserRepository_GetUnsettledBalanceHolds_Call) Run(run func(ctx context.Context, finishedBefore time.Time, limit int)) *MockIUserRepository_GetUnsettledBalanceHolds_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserRepository_GetUnsettledBalanceHolds_Call) Return(unsettledHolds []models.UnsettledHold, err error) *MockIUserRepository_GetUnsettledBalanceHolds_Call {
+	_c.Call.Return(unsettledHolds, err)
+	return _c
+}
+
+func (_c *MockIUserRepository_GetUnsettledBalanceHolds_Call) RunAndReturn(run func(ctx context.Context, finishedBefore time.Time, limit int) ([]models.UnsettledHold, error)) *MockIUserRepository_GetUnsettledBalanceHolds_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetUser provides a mock function for the type MockIUserRepository
 func (_mock *MockIUserRepository) GetUser(ctx context.Context, id string) (models.User, error) {
 	ret := _mock.Called(ctx, id)
@@ -170,9 +538,153 @@ func (_c *MockIUserRepository_GetUser_Call) RunAndReturn(run func(ctx context.Co
 	return _c
 }
 
+// ReleaseBalanceHold provides a mock function for the type MockIUserRepository
+func (_mock *MockIUserRepository) ReleaseBalanceHold(ctx context.Context, messageId string) (models.BalanceHold, error) {
+	ret := _mock.Called(ctx, messageId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseBalanceHold")
+	}
+
+	var r0 models.BalanceHold
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (models.BalanceHold, error)); ok {
+		return returnFunc(ctx, messageId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) models.BalanceHold); ok {
+		r0 = returnFunc(ctx, messageId)
+	} else {
+		r0 = ret.Get(0).(models.BalanceHold)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, messageId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserRepository_ReleaseBalanceHold_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseBalanceHold'
+type MockIUserRepository_ReleaseBalanceHold_Call struct {
+	*mock.Call
+}
+
+// ReleaseBalanceHold is a helper method to define mock.On call
+//   - ctx context.Context
+//   - messageId string
+func (_e *MockIUserRepository_Expecter) ReleaseBalanceHold(ctx interface{}, messageId interface{}) *MockIUserRepository_ReleaseBalanceHold_Call {
+	return &MockIUserRepository_ReleaseBalanceHold_Call{Call: _e.mock.On("ReleaseBalanceHold", ctx, messageId)}
+}
+
+func (_c *MockIUserRepository_ReleaseBalanceHold_Call) Run(run func(ctx context.Context, messageId string)) *MockIUserRepository_ReleaseBalanceHold_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserRepository_ReleaseBalanceHold_Call) Return(balanceHold models.BalanceHold, err error) *MockIUserRepository_ReleaseBalanceHold_Call {
+	_c.Call.Return(balanceHold, err)
+	return _c
+}
+
+func (_c *MockIUserRepository_ReleaseBalanceHold_Call) RunAndReturn(run func(ctx context.Context, messageId string) (models.BalanceHold, error)) *MockIUserRepository_ReleaseBalanceHold_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateUserAccount provides a mock function for the type MockIUserRepository
+func (_mock *MockIUserRepository) UpdateUserAccount(ctx context.Context, id string, accountType models.AccountType, creditLimit int64) (models.User, error) {
+	ret := _mock.Called(ctx, id, accountType, creditLimit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserAccount")
+	}
+
+	var r0 models.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.AccountType, int64) (models.User, error)); ok {
+		return returnFunc(ctx, id, accountType, creditLimit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.AccountType, int64) models.User); ok {
+		r0 = returnFunc(ctx, id, accountType, creditLimit)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, models.AccountType, int64) error); ok {
+		r1 = returnFunc(ctx, id, accountType, creditLimit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserRepository_UpdateUserAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUserAccount'
+type MockIUserRepository_UpdateUserAccount_Call struct {
+	*mock.Call
+}
+
+// UpdateUserAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - accountType models.AccountType
+//   - creditLimit int64
+func (_e *MockIUserRepository_Expecter) UpdateUserAccount(ctx interface{}, id interface{}, accountType interface{}, creditLimit interface{}) *MockIUserRepository_UpdateUserAccount_Call {
+	return &MockIUserRepository_UpdateUserAccount_Call{Call: _e.mock.On("UpdateUserAccount", ctx, id, accountType, creditLimit)}
+}
+
+func (_c *MockIUserRepository_UpdateUserAccount_Call) Run(run func(ctx context.Context, id string, accountType models.AccountType, creditLimit int64)) *MockIUserRepository_UpdateUserAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 models.AccountType
+		if args[2] != nil {
+			arg2 = args[2].(models.AccountType)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserRepository_UpdateUserAccount_Call) Return(user models.User, err error) *MockIUserRepository_UpdateUserAccount_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockIUserRepository_UpdateUserAccount_Call) RunAndReturn(run func(ctx context.Context, id string, accountType models.AccountType, creditLimit int64) (models.User, error)) *MockIUserRepository_UpdateUserAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateUserBalance provides a mock function for the type MockIUserRepository
-func (_mock *MockIUserRepository) UpdateUserBalance(ctx context.Context, id string, amount int64) (int64, error) {
-	ret := _mock.Called(ctx, id, amount)
+func (_mock *MockIUserRepository) UpdateUserBalance(ctx context.Context, id string, txs []models.BalanceTransaction) (int64, error) {
+	ret := _mock.Called(ctx, id, txs)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateUserBalance")
@@ -180,16 +692,16 @@ func (_mock *MockIUserRepository) UpdateUserBalance(ctx context.Context, id stri
 
 	var r0 int64
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) (int64, error)); ok {
-		return returnFunc(ctx, id, amount)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []models.BalanceTransaction) (int64, error)); ok {
+		return returnFunc(ctx, id, txs)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) int64); ok {
-		r0 = returnFunc(ctx, id, amount)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []models.BalanceTransaction) int64); ok {
+		r0 = returnFunc(ctx, id, txs)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
-		r1 = returnFunc(ctx, id, amount)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, []models.BalanceTransaction) error); ok {
+		r1 = returnFunc(ctx, id, txs)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -204,12 +716,12 @@ type MockIUserRepository_UpdateUserBalance_Call struct {
 // UpdateUserBalance is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id string
-//   - amount int64
-func (_e *MockIUserRepository_Expecter) UpdateUserBalance(ctx interface{}, id interface{}, amount interface{}) *MockIUserRepository_UpdateUserBalance_Call {
-	return &MockIUserRepository_UpdateUserBalance_Call{Call: _e.mock.On("UpdateUserBalance", ctx, id, amount)}
+//   - txs []models.BalanceTransaction
+func (_e *MockIUserRepository_Expecter) UpdateUserBalance(ctx interface{}, id interface{}, txs interface{}) *MockIUserRepository_UpdateUserBalance_Call {
+	return &MockIUserRepository_UpdateUserBalance_Call{Call: _e.mock.On("UpdateUserBalance", ctx, id, txs)}
 }
 
-func (_c *MockIUserRepository_UpdateUserBalance_Call) Run(run func(ctx context.Context, id string, amount int64)) *MockIUserRepository_UpdateUserBalance_Call {
+func (_c *MockIUserRepository_UpdateUserBalance_Call) Run(run func(ctx context.Context, id string, txs []models.BalanceTransaction)) *MockIUserRepository_UpdateUserBalance_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -219,9 +731,9 @@ func (_c *MockIUserRepository_UpdateUserBalance_Call) Run(run func(ctx context.C
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
-		var arg2 int64
+		var arg2 []models.BalanceTransaction
 		if args[2] != nil {
-			arg2 = args[2].(int64)
+			arg2 = args[2].([]models.BalanceTransaction)
 		}
 		run(
 			arg0,
@@ -237,7 +749,79 @@ func (_c *MockIUserRepository_UpdateUserBalance_Call) Return(n int64, err error)
 	return _c
 }
 
-func (_c *MockIUserRepository_UpdateUserBalance_Call) RunAndReturn(run func(ctx context.Context, id string, amount int64) (int64, error)) *MockIUserRepository_UpdateUserBalance_Call {
+func (_c *MockIUserRepository_UpdateUserBalance_Call) RunAndReturn(run func(ctx context.Context, id string, txs []models.BalanceTransaction) (int64, error)) *MockIUserRepository_UpdateUserBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateUserEnqueueWeight provides a mock function for the type MockIUserRepository
+func (_mock *MockIUserRepository) UpdateUserEnqueueWeight(ctx context.Context, id string, weight int) (models.User, error) {
+	ret := _mock.Called(ctx, id, weight)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserEnqueueWeight")
+	}
+
+	var r0 models.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) (models.User, error)); ok {
+		return returnFunc(ctx, id, weight)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) models.User); ok {
+		r0 = returnFunc(ctx, id, weight)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = returnFunc(ctx, id, weight)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserRepository_UpdateUserEnqueueWeight_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUserEnqueueWeight'
+type MockIUserRepository_UpdateUserEnqueueWeight_Call struct {
+	*mock.Call
+}
+
+// UpdateUserEnqueueWeight is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - weight int
+func (_e *MockIUserRepository_Expecter) UpdateUserEnqueueWeight(ctx interface{}, id interface{}, weight interface{}) *MockIUserRepository_UpdateUserEnqueueWeight_Call {
+	return &MockIUserRepository_UpdateUserEnqueueWeight_Call{Call: _e.mock.On("UpdateUserEnqueueWeight", ctx, id, weight)}
+}
+
+func (_c *MockIUserRepository_UpdateUserEnqueueWeight_Call) Run(run func(ctx context.Context, id string, weight int)) *MockIUserRepository_UpdateUserEnqueueWeight_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserRepository_UpdateUserEnqueueWeight_Call) Return(user models.User, err error) *MockIUserRepository_UpdateUserEnqueueWeight_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockIUserRepository_UpdateUserEnqueueWeight_Call) RunAndReturn(run func(ctx context.Context, id string, weight int) (models.User, error)) *MockIUserRepository_UpdateUserEnqueueWeight_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
 	mock "github.com/stretchr/testify/mock"
@@ -38,6 +39,72 @@ func (_m *MockIUserService) EXPECT() *MockIUserService_Expecter {
 	return &MockIUserService_Expecter{mock: &_m.Mock}
 }
 
+// CaptureHold provides a mock function for the type MockIUserService
+func (_mock *MockIUserService) CaptureHold(ctx context.Context, messageId string) (models.BalanceHold, error) {
+	ret := _mock.Called(ctx, messageId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CaptureHold")
+	}
+
+	var r0 models.BalanceHold
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (models.BalanceHold, error)); ok {
+		return returnFunc(ctx, messageId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) models.BalanceHold); ok {
+		r0 = returnFunc(ctx, messageId)
+	} else {
+		r0 = ret.Get(0).(models.BalanceHold)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, messageId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserService_CaptureHold_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CaptureHold'
+type MockIUserService_CaptureHold_Call struct {
+	*mock.Call
+}
+
+// CaptureHold is a helper method to define mock.On call
+//   - ctx context.Context
+//   - messageId string
+func (_e *MockIUserService_Expecter) CaptureHold(ctx interface{}, messageId interface{}) *MockIUserService_CaptureHold_Call {
+	return &MockIUserService_CaptureHold_Call{Call: _e.mock.On("CaptureHold", ctx, messageId)}
+}
+
+func (_c *MockIUserService_CaptureHold_Call) Run(run func(ctx context.Context, messageId string)) *MockIUserService_CaptureHold_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserService_CaptureHold_Call) Return(balanceHold models.BalanceHold, err error) *MockIUserService_CaptureHold_Call {
+	_c.Call.Return(balanceHold, err)
+	return _c
+}
+
+func (_c *MockIUserService_CaptureHold_Call) RunAndReturn(run func(ctx context.Context, messageId string) (models.BalanceHold, error)) *MockIUserService_CaptureHold_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateUser provides a mock function for the type MockIUserService
 func (_mock *MockIUserService) CreateUser(ctx context.Context, user models.User) (models.User, error) {
 	ret := _mock.Called(ctx, user)
@@ -104,58 +171,60 @@ func (_c *MockIUserService_CreateUser_Call) RunAndReturn(run func(ctx context.Co
 	return _c
 }
 
-// DecreaseUserBalance provides a mock function for the type MockIUserService
-func (_mock *MockIUserService) DecreaseUserBalance(ctx context.Context, userId string, amount int64) (int64, error) {
-	ret := _mock.Called(ctx, userId, amount)
+// GetUnsettledHolds provides a mock function for the type MockIUserService
+func (_mock *MockIUserService) GetUnsettledHolds(ctx context.Context, finishedBefore time.Time, limit int) ([]models.UnsettledHold, error) {
+	ret := _mock.Called(ctx, finishedBefore, limit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DecreaseUserBalance")
+		panic("no return value specified for GetUnsettledHolds")
 	}
 
-	var r0 int64
+	var r0 []models.UnsettledHold
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) (int64, error)); ok {
-		return returnFunc(ctx, userId, amount)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]models.UnsettledHold, error)); ok {
+		return returnFunc(ctx, finishedBefore, limit)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) int64); ok {
-		r0 = returnFunc(ctx, userId, amount)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) []models.UnsettledHold); ok {
+		r0 = returnFunc(ctx, finishedBefore, limit)
 	} else {
-		r0 = ret.Get(0).(int64)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.UnsettledHold)
+		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
-		r1 = returnFunc(ctx, userId, amount)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = returnFunc(ctx, finishedBefore, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockIUserService_DecreaseUserBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DecreaseUserBalance'
-type MockIUserService_DecreaseUserBalance_Call struct {
+// MockIUserService_GetUnsettledHolds_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUnsettledHolds'
+type MockIUserService_GetUnsettledHolds_Call struct {
 	*mock.Call
 }
 
-// DecreaseUserBalance is a helper method to define mock.On call
+// GetUnsettledHolds is a helper method to define mock.On call
 //   - ctx context.Context
-//   - userId string
-//   - amount int64
-func (_e *MockIUserService_Expecter) DecreaseUserBalance(ctx interface{}, userId interface{}, amount interface{}) *MockIUserService_DecreaseUserBalance_Call {
-	return &MockIUserService_DecreaseUserBalance_Call{Call: _e.mock.On("DecreaseUserBalance", ctx, userId, amount)}
+//   - finishedBefore time.Time
+//   - limit int
+func (_e *MockIUserService_Expecter) GetUnsettledHolds(ctx interface{}, finishedBefore interface{}, limit interface{}) *MockIUserService_GetUnsettledHolds_Call {
+	return &MockIUserService_GetUnsettledHolds_Call{Call: _e.mock.On("GetUnsettledHolds", ctx, finishedBefore, limit)}
 }
 
-func (_c *MockIUserService_DecreaseUserBalance_Call) Run(run func(ctx context.Context, userId string, amount int64)) *MockIUserService_DecreaseUserBalance_Call {
+func (_c *MockIUserService_GetUnsettledHolds_Call) Run(run func(ctx context.Context, finishedBefore time.Time, limit int)) *MockIUserService_GetUnsettledHolds_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 time.Time
 		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg1 = args[1].(time.Time)
 		}
-		var arg2 int64
+		var arg2 int
 		if args[2] != nil {
-			arg2 = args[2].(int64)
+			arg2 = args[2].(int)
 		}
 		run(
 			arg0,
@@ -166,12 +235,12 @@ func (_c *MockIUserService_DecreaseUserBalance_Call) Run(run func(ctx context.Co
 	return _c
 }
 
-func (_c *MockIUserService_DecreaseUserBalance_Call) Return(n int64, err error) *MockIUserService_DecreaseUserBalance_Call {
-	_c.Call.Return(n, err)
+func (_c *MockIUserService_GetUnsettledHolds_Call) Return(unsettledHolds []models.UnsettledHold, err error) *MockIUserService_GetUnsettledHolds_Call {
+	_c.Call.Return(unsettledHolds, err)
 	return _c
 }
 
-func (_c *MockIUserService_DecreaseUserBalance_Call) RunAndReturn(run func(ctx context.Context, userId string, amount int64) (int64, error)) *MockIUserService_DecreaseUserBalance_Call {
+func (_c *MockIUserService_GetUnsettledHolds_Call) RunAndReturn(run func(ctx context.Context, finishedBefore time.Time, limit int) ([]models.UnsettledHold, error)) *MockIUserService_GetUnsettledHolds_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -242,9 +311,230 @@ func (_c *MockIUserService_GetUser_Call) RunAndReturn(run func(ctx context.Conte
 	return _c
 }
 
+// GetUserStatement provides a mock function for the type MockIUserService
+func (_mock *MockIUserService) GetUserStatement(ctx context.Context, userId string, month time.Time) (models.BalanceStatement, error) {
+	ret := _mock.Called(ctx, userId, month)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserStatement")
+	}
+
+	var r0 models.BalanceStatement
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time) (models.BalanceStatement, error)); ok {
+		return returnFunc(ctx, userId, month)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time) models.BalanceStatement); ok {
+		r0 = returnFunc(ctx, userId, month)
+	} else {
+		r0 = ret.Get(0).(models.BalanceStatement)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = returnFunc(ctx, userId, month)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserService_GetUserStatement_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserStatement'
+type MockIUserService_GetUserStatement_Call struct {
+	*mock.Call
+}
+
+// GetUserStatement is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId string
+//   - month time.Time
+func (_e *MockIUserService_Expecter) GetUserStatement(ctx interface{}, userId interface{}, month interface{}) *MockIUserService_GetUserStatement_Call {
+	return &MockIUserService_GetUserStatement_Call{Call: _e.mock.On("GetUserStatement", ctx, userId, month)}
+}
+
+func (_c *MockIUserService_GetUserStatement_Call) Run(run func(ctx context.Context, userId string, month time.Time)) *MockIUserService_GetUserStatement_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserService_GetUserStatement_Call) Return(balanceStatement models.BalanceStatement, err error) *MockIUserService_GetUserStatement_Call {
+	_c.Call.Return(balanceStatement, err)
+	return _c
+}
+
+func (_c *MockIUserService_GetUserStatement_Call) RunAndReturn(run func(ctx context.Context, userId string, month time.Time) (models.BalanceStatement, error)) *MockIUserService_GetUserStatement_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserTransactions provides a mock function for the type MockIUserService
+func (_mock *MockIUserService) GetUserTransactions(ctx context.Context, userId string, filter models.TransactionFilter, skip int, limit int) ([]models.BalanceTransaction, error) {
+	ret := _mock.Called(ctx, userId, filter, skip, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserTransactions")
+	}
+
+	var r0 []models.BalanceTransaction
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.TransactionFilter, int, int) ([]models.BalanceTransaction, error)); ok {
+		return returnFunc(ctx, userId, filter, skip, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.TransactionFilter, int, int) []models.BalanceTransaction); ok {
+		r0 = returnFunc(ctx, userId, filter, skip, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.BalanceTransaction)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, models.TransactionFilter, int, int) error); ok {
+		r1 = returnFunc(ctx, userId, filter, skip, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserService_GetUserTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserTransactions'
+type MockIUserService_GetUserTransactions_Call struct {
+	*mock.Call
+}
+
+// GetUserTransactions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId string
+//   - filter models.TransactionFilter
+//   - skip int
+//   - limit int
+func (_e *MockIUserService_Expecter) GetUserTransactions(ctx interface{}, userId interface{}, filter interface{}, skip interface{}, limit interface{}) *MockIUserService_GetUserTransactions_Call {
+	return &MockIUserService_GetUserTransactions_Call{Call: _e.mock.On("GetUserTransactions", ctx, userId, filter, skip, limit)}
+}
+
+func (_c *MockIUserService_GetUserTransactions_Call) Run(run func(ctx context.Context, userId string, filter models.TransactionFilter, skip int, limit int)) *MockIUserService_GetUserTransactions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 models.TransactionFilter
+		if args[2] != nil {
+			arg2 = args[2].(models.TransactionFilter)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		var arg4 int
+		if args[4] != nil {
+			arg4 = args[4].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserService_GetUserTransactions_Call) Return(balanceTransactions []models.BalanceTransaction, err error) *MockIUserService_GetUserTransactions_Call {
+	_c.Call.Return(balanceTransactions, err)
+	return _c
+}
+
+func (_c *MockIUserService_GetUserTransactions_Call) RunAndReturn(run func(ctx context.Context, userId string, filter models.TransactionFilter, skip int, limit int) ([]models.BalanceTransaction, error)) *MockIUserService_GetUserTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HoldUserBalance provides a mock function for the type MockIUserService
+func (_mock *MockIUserService) HoldUserBalance(ctx context.Context, userId string, holds []models.BalanceHold) error {
+	ret := _mock.Called(ctx, userId, holds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HoldUserBalance")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []models.BalanceHold) error); ok {
+		r0 = returnFunc(ctx, userId, holds)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIUserService_HoldUserBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HoldUserBalance'
+type MockIUserService_HoldUserBalance_Call struct {
+	*mock.Call
+}
+
+// HoldUserBalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId string
+//   - holds []models.BalanceHold
+func (_e *MockIUserService_Expecter) HoldUserBalance(ctx interface{}, userId interface{}, holds interface{}) *MockIUserService_HoldUserBalance_Call {
+	return &MockIUserService_HoldUserBalance_Call{Call: _e.mock.On("HoldUserBalance", ctx, userId, holds)}
+}
+
+func (_c *MockIUserService_HoldUserBalance_Call) Run(run func(ctx context.Context, userId string, holds []models.BalanceHold)) *MockIUserService_HoldUserBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []models.BalanceHold
+		if args[2] != nil {
+			arg2 = args[2].([]models.BalanceHold)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserService_HoldUserBalance_Call) Return(err error) *MockIUserService_HoldUserBalance_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIUserService_HoldUserBalance_Call) RunAndReturn(run func(ctx context.Context, userId string, holds []models.BalanceHold) error) *MockIUserService_HoldUserBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // IncreaseUserBalance provides a mock function for the type MockIUserService
-func (_mock *MockIUserService) IncreaseUserBalance(ctx context.Context, userId string, amount int64) (int64, error) {
-	ret := _mock.Called(ctx, userId, amount)
+func (_mock *MockIUserService) IncreaseUserBalance(ctx context.Context, userId string, changes []models.BalanceChange) (int64, error) {
+	ret := _mock.Called(ctx, userId, changes)
 
 	if len(ret) == 0 {
 		panic("no return value specified for IncreaseUserBalance")
@@ -252,16 +542,16 @@ func (_mock *MockIUserService) IncreaseUserBalance(ctx context.Context, userId s
 
 	var r0 int64
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) (int64, error)); ok {
-		return returnFunc(ctx, userId, amount)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []models.BalanceChange) (int64, error)); ok {
+		return returnFunc(ctx, userId, changes)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) int64); ok {
-		r0 = returnFunc(ctx, userId, amount)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []models.BalanceChange) int64); ok {
+		r0 = returnFunc(ctx, userId, changes)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
-		r1 = returnFunc(ctx, userId, amount)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, []models.BalanceChange) error); ok {
+		r1 = returnFunc(ctx, userId, changes)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -276,12 +566,12 @@ type MockIUserService_IncreaseUserBalance_Call struct {
 // IncreaseUserBalance is a helper method to define mock.On call
 //   - ctx context.Context
 //   - userId string
-//   - amount int64
-func (_e *MockIUserService_Expecter) IncreaseUserBalance(ctx interface{}, userId interface{}, amount interface{}) *MockIUserService_IncreaseUserBalance_Call {
-	return &MockIUserService_IncreaseUserBalance_Call{Call: _e.mock.On("IncreaseUserBalance", ctx, userId, amount)}
+//   - changes []models.BalanceChange
+func (_e *MockIUserService_Expecter) IncreaseUserBalance(ctx interface{}, userId interface{}, changes interface{}) *MockIUserService_IncreaseUserBalance_Call {
+	return &MockIUserService_IncreaseUserBalance_Call{Call: _e.mock.On("IncreaseUserBalance", ctx, userId, changes)}
 }
 
-func (_c *MockIUserService_IncreaseUserBalance_Call) Run(run func(ctx context.Context, userId string, amount int64)) *MockIUserService_IncreaseUserBalance_Call {
+func (_c *MockIUserService_IncreaseUserBalance_Call) Run(run func(ctx context.Context, userId string, changes []models.BalanceChange)) *MockIUserService_IncreaseUserBalance_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -291,9 +581,9 @@ func (_c *MockIUserService_IncreaseUserBalance_Call) Run(run func(ctx context.Co
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
-		var arg2 int64
+		var arg2 []models.BalanceChange
 		if args[2] != nil {
-			arg2 = args[2].(int64)
+			arg2 = args[2].([]models.BalanceChange)
 		}
 		run(
 			arg0,
@@ -309,7 +599,223 @@ func (_c *MockIUserService_IncreaseUserBalance_Call) Return(n int64, err error)
 	return _c
 }
 
-func (_c *MockIUserService_IncreaseUserBalance_Call) RunAndReturn(run func(ctx context.Context, userId string, amount int64) (int64, error)) *MockIUserService_IncreaseUserBalance_Call {
+func (_c *MockIUserService_IncreaseUserBalance_Call) RunAndReturn(run func(ctx context.Context, userId string, changes []models.BalanceChange) (int64, error)) *MockIUserService_IncreaseUserBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReleaseHold provides a mock function for the type MockIUserService
+func (_mock *MockIUserService) ReleaseHold(ctx context.Context, messageId string) (models.BalanceHold, error) {
+	ret := _mock.Called(ctx, messageId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseHold")
+	}
+
+	var r0 models.BalanceHold
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (models.BalanceHold, error)); ok {
+		return returnFunc(ctx, messageId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) models.BalanceHold); ok {
+		r0 = returnFunc(ctx, messageId)
+	} else {
+		r0 = ret.Get(0).(models.BalanceHold)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, messageId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserService_ReleaseHold_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseHold'
+type MockIUserService_ReleaseHold_Call struct {
+	*mock.Call
+}
+
+// ReleaseHold is a helper method to define mock.On call
+//   - ctx context.Context
+//   - messageId string
+func (_e *MockIUserService_Expecter) ReleaseHold(ctx interface{}, messageId interface{}) *MockIUserService_ReleaseHold_Call {
+	return &MockIUserService_ReleaseHold_Call{Call: _e.mock.On("ReleaseHold", ctx, messageId)}
+}
+
+func (_c *MockIUserService_ReleaseHold_Call) Run(run func(ctx context.Context, messageId string)) *MockIUserService_ReleaseHold_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserService_ReleaseHold_Call) Return(balanceHold models.BalanceHold, err error) *MockIUserService_ReleaseHold_Call {
+	_c.Call.Return(balanceHold, err)
+	return _c
+}
+
+func (_c *MockIUserService_ReleaseHold_Call) RunAndReturn(run func(ctx context.Context, messageId string) (models.BalanceHold, error)) *MockIUserService_ReleaseHold_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetUserAccount provides a mock function for the type MockIUserService
+func (_mock *MockIUserService) SetUserAccount(ctx context.Context, userId string, accountType models.AccountType, creditLimit int64) (models.User, error) {
+	ret := _mock.Called(ctx, userId, accountType, creditLimit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserAccount")
+	}
+
+	var r0 models.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.AccountType, int64) (models.User, error)); ok {
+		return returnFunc(ctx, userId, accountType, creditLimit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, models.AccountType, int64) models.User); ok {
+		r0 = returnFunc(ctx, userId, accountType, creditLimit)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, models.AccountType, int64) error); ok {
+		r1 = returnFunc(ctx, userId, accountType, creditLimit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserService_SetUserAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetUserAccount'
+type MockIUserService_SetUserAccount_Call struct {
+	*mock.Call
+}
+
+// SetUserAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId string
+//   - accountType models.AccountType
+//   - creditLimit int64
+func (_e *MockIUserService_Expecter) SetUserAccount(ctx interface{}, userId interface{}, accountType interface{}, creditLimit interface{}) *MockIUserService_SetUserAccount_Call {
+	return &MockIUserService_SetUserAccount_Call{Call: _e.mock.On("SetUserAccount", ctx, userId, accountType, creditLimit)}
+}
+
+func (_c *MockIUserService_SetUserAccount_Call) Run(run func(ctx context.Context, userId string, accountType models.AccountType, creditLimit int64)) *MockIUserService_SetUserAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 models.AccountType
+		if args[2] != nil {
+			arg2 = args[2].(models.AccountType)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserService_SetUserAccount_Call) Return(user models.User, err error) *MockIUserService_SetUserAccount_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockIUserService_SetUserAccount_Call) RunAndReturn(run func(ctx context.Context, userId string, accountType models.AccountType, creditLimit int64) (models.User, error)) *MockIUserService_SetUserAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetUserEnqueueWeight provides a mock function for the type MockIUserService
+func (_mock *MockIUserService) SetUserEnqueueWeight(ctx context.Context, userId string, weight int) (models.User, error) {
+	ret := _mock.Called(ctx, userId, weight)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserEnqueueWeight")
+	}
+
+	var r0 models.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) (models.User, error)); ok {
+		return returnFunc(ctx, userId, weight)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) models.User); ok {
+		r0 = returnFunc(ctx, userId, weight)
+	} else {
+		r0 = ret.Get(0).(models.User)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = returnFunc(ctx, userId, weight)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserService_SetUserEnqueueWeight_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetUserEnqueueWeight'
+type MockIUserService_SetUserEnqueueWeight_Call struct {
+	*mock.Call
+}
+
+// SetUserEnqueueWeight is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userId string
+//   - weight int
+func (_e *MockIUserService_Expecter) SetUserEnqueueWeight(ctx interface{}, userId interface{}, weight interface{}) *MockIUserService_SetUserEnqueueWeight_Call {
+	return &MockIUserService_SetUserEnqueueWeight_Call{Call: _e.mock.On("SetUserEnqueueWeight", ctx, userId, weight)}
+}
+
+func (_c *MockIUserService_SetUserEnqueueWeight_Call) Run(run func(ctx context.Context, userId string, weight int)) *MockIUserService_SetUserEnqueueWeight_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserService_SetUserEnqueueWeight_Call) Return(user models.User, err error) *MockIUserService_SetUserEnqueueWeight_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockIUserService_SetUserEnqueueWeight_Call) RunAndReturn(run func(ctx context.Context, userId string, weight int) (models.User, error)) *MockIUserService_SetUserEnqueueWeight_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,33 @@
+package models
+
+import "github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+
+type HoldStatus string
+
+const (
+	HoldStatusHeld     HoldStatus = "held"
+	HoldStatusCaptured HoldStatus = "captured"
+	HoldStatusReleased HoldStatus = "released"
+)
+
+// BalanceHold reserves the cost of a message against the available balance
+// until the message is sent, when it is captured, or fails or is canceled,
+// when it is released.
+type BalanceHold struct {
+	*shared.Entity
+	*shared.CreateDate
+	*shared.UpdateDate
+
+	UserId    string
+	MessageId string
+	Amount    int64
+	Status    HoldStatus
+}
+
+// UnsettledHold is a hold still held after its message was sent, failed or
+// was canceled. Capture is set when the message was sent, so its hold is
+// captured instead of released.
+type UnsettledHold struct {
+	MessageId string
+	Capture   bool
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+)
+
+type TransactionType string
+
+const (
+	TransactionTopUp         TransactionType = "topup"
+	TransactionMessageCharge TransactionType = "charge"
+	TransactionRefund        TransactionType = "refund"
+	TransactionAdjustment    TransactionType = "adjustment"
+)
+
+// BalanceTransaction is a ledger entry of a single balance movement. The
+// balance of a user always equals the sum of its transaction amounts.
+type BalanceTransaction struct {
+	*shared.Entity
+	*shared.CreateDate
+
+	UserId string
+	Type   TransactionType
+	// Amount is positive for credits and negative for debits.
+	Amount       int64
+	BalanceAfter int64
+	// MessageId is set for message charges and refunds.
+	MessageId string
+	// Reference is a free form payment or support reference.
+	Reference string
+}
+
+// BalanceChange describes why a balance is increased. Amount is always
+// positive.
+type BalanceChange struct {
+	Type      TransactionType
+	Amount    int64
+	MessageId string
+	Reference string
+}
+
+type TransactionFilter struct {
+	Type      TransactionType
+	MessageId string
+	From      time.Time
+	To        time.Time
+}
+
+// BalanceStatement summarizes the ledger of a user over a period. Totals are
+// signed like ledger amounts, so ClosingBalance is OpeningBalance plus all of
+// them.
+type BalanceStatement struct {
+	UserId          string
+	From            time.Time
+	To              time.Time
+	OpeningBalance  int64
+	ClosingBalance  int64
+	TopUps          int64
+	Charges         int64
+	Refunds         int64
+	Adjustments     int64
+	ChargedMessages int
+}
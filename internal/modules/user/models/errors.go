@@ -3,8 +3,12 @@ package models
 import "errors"
 
 var (
-	EmptyNameError           = errors.New("user name is empty")
-	UserNotExistError        = errors.New("user does not exist")
-	InsufficientBalanceError = errors.New("insufficient balance")
-	InvalidBalanceError      = errors.New("invalid balance")
+	EmptyNameError            = errors.New("user name is empty")
+	UserNotExistError         = errors.New("user does not exist")
+	InsufficientBalanceError  = errors.New("insufficient balance")
+	InvalidBalanceError       = errors.New("invalid balance")
+	InvalidEnqueueWeightError = errors.New("invalid enqueue weight")
+	HoldNotExistError         = errors.New("balance hold does not exist")
+	InvalidAccountTypeError   = errors.New("invalid account type")
+	InvalidCreditLimitError   = errors.New("invalid credit limit")
 )
@@ -2,6 +2,13 @@ package models
 
 import "github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
 
+type AccountType string
+
+const (
+	AccountPrepaid  AccountType = "prepaid"
+	AccountPostpaid AccountType = "postpaid"
+)
+
 type User struct {
 	*shared.Entity
 	*shared.CreateDate
@@ -9,4 +16,25 @@ type User struct {
 
 	Name    string
 	Balance int64
+	// HeldBalance is the part of Balance reserved for messages that are not
+	// sent yet.
+	HeldBalance int64
+	// EnqueueWeight is the user share of enqueue slots relative to other
+	// users with due messages.
+	EnqueueWeight int
+	AccountType   AccountType
+	// CreditLimit is how far below zero the balance of a postpaid account
+	// may go. It is always zero for prepaid accounts.
+	CreditLimit int64
+}
+
+// AvailableBalance is the balance that can be spent on new messages,
+// including the credit of postpaid accounts.
+func (u User) AvailableBalance() int64 {
+	available := u.Balance - u.HeldBalance
+	if u.AccountType == AccountPostpaid {
+		available += u.CreditLimit
+	}
+
+	return available
 }
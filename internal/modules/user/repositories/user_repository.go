@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
 )
@@ -9,5 +10,13 @@ import (
 type IUserRepository interface {
 	CreateUser(ctx context.Context, user models.User) (models.User, error)
 	GetUser(ctx context.Context, id string) (models.User, error)
-	UpdateUserBalance(ctx context.Context, id string, amount int64) (int64, error)
+	UpdateUserBalance(ctx context.Context, id string, txs []models.BalanceTransaction) (int64, error)
+	GetBalanceTransactions(ctx context.Context, userId string, filter models.TransactionFilter, skip int, limit int) ([]models.BalanceTransaction, error)
+	CreateBalanceHolds(ctx context.Context, userId string, holds []models.BalanceHold) error
+	CaptureBalanceHold(ctx context.Context, messageId string) (models.BalanceHold, error)
+	ReleaseBalanceHold(ctx context.Context, messageId string) (models.BalanceHold, error)
+	GetUnsettledBalanceHolds(ctx context.Context, finishedBefore time.Time, limit int) ([]models.UnsettledHold, error)
+	UpdateUserEnqueueWeight(ctx context.Context, id string, weight int) (models.User, error)
+	UpdateUserAccount(ctx context.Context, id string, accountType models.AccountType, creditLimit int64) (models.User, error)
+	GetBalanceStatement(ctx context.Context, userId string, from time.Time, to time.Time) (models.BalanceStatement, error)
 }
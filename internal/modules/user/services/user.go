@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/repositories"
@@ -12,8 +13,15 @@ import (
 type IUserService interface {
 	CreateUser(ctx context.Context, user models.User) (models.User, error)
 	GetUser(ctx context.Context, id string) (models.User, error)
-	IncreaseUserBalance(ctx context.Context, userId string, amount int64) (int64, error)
-	DecreaseUserBalance(ctx context.Context, userId string, amount int64) (int64, error)
+	IncreaseUserBalance(ctx context.Context, userId string, changes []models.BalanceChange) (int64, error)
+	HoldUserBalance(ctx context.Context, userId string, holds []models.BalanceHold) error
+	CaptureHold(ctx context.Context, messageId string) (models.BalanceHold, error)
+	ReleaseHold(ctx context.Context, messageId string) (models.BalanceHold, error)
+	GetUnsettledHolds(ctx context.Context, finishedBefore time.Time, limit int) ([]models.UnsettledHold, error)
+	GetUserTransactions(ctx context.Context, userId string, filter models.TransactionFilter, skip int, limit int) ([]models.BalanceTransaction, error)
+	SetUserEnqueueWeight(ctx context.Context, userId string, weight int) (models.User, error)
+	SetUserAccount(ctx context.Context, userId string, accountType models.AccountType, creditLimit int64) (models.User, error)
+	GetUserStatement(ctx context.Context, userId string, month time.Time) (models.BalanceStatement, error)
 }
 
 type UserService struct {
@@ -52,44 +60,195 @@ func (u *UserService) GetUser(ctx context.Context, id string) (models.User, erro
 	return res, nil
 }
 
-func (u *UserService) IncreaseUserBalance(ctx context.Context, userId string, amount int64) (int64, error) {
-	pkgLog.Debug("increasing user balance for user id %s amount %d", userId, amount)
-	if amount == 0 {
+func (u *UserService) IncreaseUserBalance(ctx context.Context, userId string, changes []models.BalanceChange) (int64, error) {
+	pkgLog.Debug("increasing user balance for user id %s with %d changes", userId, len(changes))
+	txs, err := toBalanceTransactions(userId, changes)
+	if err != nil {
+		pkgLog.Error(err, "negative amount for user id %s", userId)
+		return 0, err
+	}
+	if len(txs) == 0 {
 		pkgLog.Debug("change amount is zero for user id %s, skipping", userId)
 		return 0, nil
 	}
-	if amount < 0 {
-		pkgLog.Error(models.InvalidBalanceError, "negative amount for user id %s", userId)
-		return 0, models.InvalidBalanceError
-	}
 
-	newBalance, err := u.userRepo.UpdateUserBalance(ctx, userId, amount)
+	newBalance, err := u.userRepo.UpdateUserBalance(ctx, userId, txs)
 	if err != nil {
 		pkgLog.Error(err, "failed to increase user balance for user id %s", userId)
 		return 0, err
 	}
 
-	pkgLog.Debug("increased user balance for user id %s amount %d to %d", userId, amount, newBalance)
+	pkgLog.Debug("increased user balance for user id %s to %d", userId, newBalance)
 	return newBalance, nil
 }
 
-func (u *UserService) DecreaseUserBalance(ctx context.Context, userId string, amount int64) (int64, error) {
-	pkgLog.Debug("decreasing user balance for user id %s amount %d", userId, amount)
-	if amount == 0 {
-		pkgLog.Debug("change amount is zero for user id %s, skipping", userId)
-		return 0, nil
+func (u *UserService) HoldUserBalance(ctx context.Context, userId string, holds []models.BalanceHold) error {
+	pkgLog.Debug("holding user balance for user id %s with %d holds", userId, len(holds))
+	nonZero := make([]models.BalanceHold, 0, len(holds))
+	for i := range holds {
+		if holds[i].Amount < 0 {
+			pkgLog.Error(models.InvalidBalanceError, "negative hold amount for user id %s", userId)
+			return models.InvalidBalanceError
+		}
+		if holds[i].Amount == 0 {
+			continue
+		}
+		holds[i].UserId = userId
+		nonZero = append(nonZero, holds[i])
 	}
-	if amount < 0 {
-		pkgLog.Error(models.InvalidBalanceError, "negative amount for user id %s", userId)
-		return 0, models.InvalidBalanceError
+	holds = nonZero
+	if len(holds) == 0 {
+		pkgLog.Debug("no holds for user id %s, skipping", userId)
+		return nil
+	}
+
+	if err := u.userRepo.CreateBalanceHolds(ctx, userId, holds); err != nil {
+		pkgLog.Error(err, "failed to hold user balance for user id %s", userId)
+		return err
 	}
 
-	newBalance, err := u.userRepo.UpdateUserBalance(ctx, userId, -amount)
+	pkgLog.Debug("held user balance for user id %s with %d holds", userId, len(holds))
+	return nil
+}
+
+func (u *UserService) CaptureHold(ctx context.Context, messageId string) (models.BalanceHold, error) {
+	pkgLog.Debug("capturing balance hold of message %s", messageId)
+	res, err := u.userRepo.CaptureBalanceHold(ctx, messageId)
 	if err != nil {
-		pkgLog.Error(err, "failed to decrease user balance for user id %s", userId)
-		return 0, err
+		pkgLog.Error(err, "failed to capture balance hold of message %s", messageId)
+		return models.BalanceHold{}, err
 	}
 
-	pkgLog.Debug("decreased user balance for user id %s amount %d to %d", userId, amount, newBalance)
-	return newBalance, nil
+	pkgLog.Debug("captured balance hold of message %s amount %d", messageId, res.Amount)
+	return res, nil
+}
+
+func (u *UserService) ReleaseHold(ctx context.Context, messageId string) (models.BalanceHold, error) {
+	pkgLog.Debug("releasing balance hold of message %s", messageId)
+	res, err := u.userRepo.ReleaseBalanceHold(ctx, messageId)
+	if err != nil {
+		pkgLog.Error(err, "failed to release balance hold of message %s", messageId)
+		return models.BalanceHold{}, err
+	}
+
+	pkgLog.Debug("released balance hold of message %s amount %d", messageId, res.Amount)
+	return res, nil
+}
+
+// GetUnsettledHolds returns holds left held by messages that finished before
+// finishedBefore, whose settlement was lost.
+func (u *UserService) GetUnsettledHolds(ctx context.Context, finishedBefore time.Time, limit int) ([]models.UnsettledHold, error) {
+	pkgLog.Debug("getting holds of messages finished before %s", finishedBefore)
+	res, err := u.userRepo.GetUnsettledBalanceHolds(ctx, finishedBefore, limit)
+	if err != nil {
+		pkgLog.Error(err, "failed to get unsettled balance holds")
+		return nil, err
+	}
+
+	pkgLog.Debug("got %d unsettled balance holds", len(res))
+	return res, nil
+}
+
+func (u *UserService) GetUserTransactions(
+	ctx context.Context,
+	userId string,
+	filter models.TransactionFilter,
+	skip int,
+	limit int,
+) ([]models.BalanceTransaction, error) {
+	pkgLog.Debug("getting balance transactions for user id %s", userId)
+	res, err := u.userRepo.GetBalanceTransactions(ctx, userId, filter, skip, limit)
+	if err != nil {
+		pkgLog.Error(err, "failed to get balance transactions for user id %s", userId)
+		return nil, err
+	}
+
+	pkgLog.Debug("got %d balance transactions for user id %s", len(res), userId)
+	return res, nil
+}
+
+func (u *UserService) SetUserEnqueueWeight(ctx context.Context, userId string, weight int) (models.User, error) {
+	pkgLog.Debug("setting enqueue weight of user id %s to %d", userId, weight)
+	if weight <= 0 {
+		pkgLog.Error(models.InvalidEnqueueWeightError, "non-positive enqueue weight for user id %s", userId)
+		return models.User{}, models.InvalidEnqueueWeightError
+	}
+
+	res, err := u.userRepo.UpdateUserEnqueueWeight(ctx, userId, weight)
+	if err != nil {
+		pkgLog.Error(err, "failed to set enqueue weight of user id %s", userId)
+		return models.User{}, err
+	}
+
+	pkgLog.Debug("set enqueue weight of user id %s to %d", userId, weight)
+	return res, nil
+}
+
+// SetUserAccount switches the user between prepaid and postpaid accounts.
+// Only postpaid accounts may have a credit limit.
+func (u *UserService) SetUserAccount(
+	ctx context.Context,
+	userId string,
+	accountType models.AccountType,
+	creditLimit int64,
+) (models.User, error) {
+	pkgLog.Debug("setting account of user id %s to %s with credit limit %d", userId, accountType, creditLimit)
+	if accountType != models.AccountPrepaid && accountType != models.AccountPostpaid {
+		pkgLog.Error(models.InvalidAccountTypeError, "invalid account type %s for user id %s", accountType, userId)
+		return models.User{}, models.InvalidAccountTypeError
+	}
+	if creditLimit < 0 || (accountType == models.AccountPrepaid && creditLimit != 0) {
+		pkgLog.Error(models.InvalidCreditLimitError, "invalid credit limit %d for user id %s", creditLimit, userId)
+		return models.User{}, models.InvalidCreditLimitError
+	}
+
+	res, err := u.userRepo.UpdateUserAccount(ctx, userId, accountType, creditLimit)
+	if err != nil {
+		pkgLog.Error(err, "failed to set account of user id %s", userId)
+		return models.User{}, err
+	}
+
+	pkgLog.Debug("set account of user id %s to %s with credit limit %d", userId, accountType, creditLimit)
+	return res, nil
+}
+
+// GetUserStatement returns the usage statement of the calendar month that
+// contains month, in the location of month.
+func (u *UserService) GetUserStatement(ctx context.Context, userId string, month time.Time) (models.BalanceStatement, error) {
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	to := from.AddDate(0, 1, 0)
+
+	pkgLog.Debug("getting statement of user id %s for %s", userId, from.Format("2006-01"))
+	res, err := u.userRepo.GetBalanceStatement(ctx, userId, from, to)
+	if err != nil {
+		pkgLog.Error(err, "failed to get statement of user id %s for %s", userId, from.Format("2006-01"))
+		return models.BalanceStatement{}, err
+	}
+
+	pkgLog.Debug("got statement of user id %s for %s", userId, from.Format("2006-01"))
+	return res, nil
+}
+
+// toBalanceTransactions builds ledger entries from the changes. Zero changes
+// are dropped and negative ones are rejected.
+func toBalanceTransactions(userId string, changes []models.BalanceChange) ([]models.BalanceTransaction, error) {
+	txs := make([]models.BalanceTransaction, 0, len(changes))
+	for i := range changes {
+		if changes[i].Amount < 0 {
+			return nil, models.InvalidBalanceError
+		}
+		if changes[i].Amount == 0 {
+			continue
+		}
+
+		txs = append(txs, models.BalanceTransaction{
+			UserId:    userId,
+			Type:      changes[i].Type,
+			Amount:    changes[i].Amount,
+			MessageId: changes[i].MessageId,
+			Reference: changes[i].Reference,
+		})
+	}
+
+	return txs, nil
 }
@@ -2,6 +2,7 @@ package services_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/services"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestUserService_CreateUser(t *testing.T) {
@@ -121,16 +123,19 @@ func TestUserService_IncreaseUserBalance(t *testing.T) {
 
 	t.Run("should update user", func(t *testing.T) {
 		inputAmount := int64(100)
+		inputChanges := []models.BalanceChange{{Type: models.TransactionTopUp, Amount: inputAmount}}
 		ctx := context.Background()
 		mockRepo := mocks.NewMockIUserRepository(t)
 
 		mockRepo.EXPECT().
-			UpdateUserBalance(ctx, inputID, inputAmount).
+			UpdateUserBalance(ctx, inputID, []models.BalanceTransaction{
+				{UserId: inputID, Type: models.TransactionTopUp, Amount: inputAmount},
+			}).
 			Return(100, nil).
 			Once()
 
 		service := services.NewUserService(mockRepo)
-		actualBalance, actualErr := service.IncreaseUserBalance(ctx, inputID, inputAmount)
+		actualBalance, actualErr := service.IncreaseUserBalance(ctx, inputID, inputChanges)
 
 		assert.NoError(t, actualErr)
 		assert.Equal(t, inputAmount, actualBalance)
@@ -138,11 +143,12 @@ func TestUserService_IncreaseUserBalance(t *testing.T) {
 
 	t.Run("should do nothing when amount eq 0", func(t *testing.T) {
 		inputAmount := int64(0)
+		inputChanges := []models.BalanceChange{{Type: models.TransactionTopUp, Amount: inputAmount}}
 		ctx := context.Background()
 		mockRepo := mocks.NewMockIUserRepository(t)
 
 		service := services.NewUserService(mockRepo)
-		actualBalance, actualErr := service.IncreaseUserBalance(ctx, inputID, inputAmount)
+		actualBalance, actualErr := service.IncreaseUserBalance(ctx, inputID, inputChanges)
 
 		assert.NoError(t, actualErr)
 		assert.Equal(t, int64(0), actualBalance)
@@ -150,16 +156,19 @@ func TestUserService_IncreaseUserBalance(t *testing.T) {
 
 	t.Run("should return UserNotExistError when user not exists", func(t *testing.T) {
 		inputAmount := int64(100)
+		inputChanges := []models.BalanceChange{{Type: models.TransactionTopUp, Amount: inputAmount}}
 		ctx := context.Background()
 		mockRepo := mocks.NewMockIUserRepository(t)
 
 		mockRepo.EXPECT().
-			UpdateUserBalance(ctx, inputID, inputAmount).
+			UpdateUserBalance(ctx, inputID, []models.BalanceTransaction{
+				{UserId: inputID, Type: models.TransactionTopUp, Amount: inputAmount},
+			}).
 			Return(0, models.UserNotExistError).
 			Once()
 
 		service := services.NewUserService(mockRepo)
-		actualBalance, actualErr := service.IncreaseUserBalance(ctx, inputID, inputAmount)
+		actualBalance, actualErr := service.IncreaseUserBalance(ctx, inputID, inputChanges)
 
 		assert.Error(t, actualErr)
 		assert.Equal(t, models.UserNotExistError, actualErr)
@@ -168,11 +177,12 @@ func TestUserService_IncreaseUserBalance(t *testing.T) {
 
 	t.Run("should return InvalidBalanceError when user exists but amount lt 0", func(t *testing.T) {
 		inputAmount := int64(-100)
+		inputChanges := []models.BalanceChange{{Type: models.TransactionTopUp, Amount: inputAmount}}
 		ctx := context.Background()
 		mockRepo := mocks.NewMockIUserRepository(t)
 
 		service := services.NewUserService(mockRepo)
-		actualBalance, actualErr := service.IncreaseUserBalance(ctx, inputID, inputAmount)
+		actualBalance, actualErr := service.IncreaseUserBalance(ctx, inputID, inputChanges)
 
 		assert.Error(t, actualErr)
 		assert.Equal(t, models.InvalidBalanceError, actualErr)
@@ -180,84 +190,378 @@ func TestUserService_IncreaseUserBalance(t *testing.T) {
 	})
 }
 
-func TestUserService_DecreaseUserBalance(t *testing.T) {
+func TestUserService_SetUserEnqueueWeight(t *testing.T) {
 	inputID := "1"
 
-	t.Run("should update user", func(t *testing.T) {
-		inputAmount := int64(100)
+	t.Run("should update user weight", func(t *testing.T) {
+		inputWeight := 3
 		ctx := context.Background()
 		mockRepo := mocks.NewMockIUserRepository(t)
+		expectedUser := models.User{Name: "test", EnqueueWeight: inputWeight}
 
 		mockRepo.EXPECT().
-			UpdateUserBalance(ctx, inputID, -inputAmount).
-			Return(100, nil).
+			UpdateUserEnqueueWeight(ctx, inputID, inputWeight).
+			Return(expectedUser, nil).
 			Once()
 
 		service := services.NewUserService(mockRepo)
-		actualBalance, actualErr := service.DecreaseUserBalance(ctx, inputID, inputAmount)
+		actualUser, actualErr := service.SetUserEnqueueWeight(ctx, inputID, inputWeight)
 
 		assert.NoError(t, actualErr)
-		assert.Equal(t, inputAmount, actualBalance)
+		assert.Equal(t, expectedUser, actualUser)
 	})
 
-	t.Run("should do nothing when amount eq 0", func(t *testing.T) {
-		inputAmount := int64(0)
+	t.Run("should return InvalidEnqueueWeightError when weight lte 0", func(t *testing.T) {
 		ctx := context.Background()
 		mockRepo := mocks.NewMockIUserRepository(t)
 
 		service := services.NewUserService(mockRepo)
-		actualBalance, actualErr := service.DecreaseUserBalance(ctx, inputID, inputAmount)
+		actualUser, actualErr := service.SetUserEnqueueWeight(ctx, inputID, 0)
 
-		assert.NoError(t, actualErr)
-		assert.Equal(t, int64(0), actualBalance)
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.InvalidEnqueueWeightError, actualErr)
+		assert.Equal(t, models.User{}, actualUser)
 	})
 
 	t.Run("should return UserNotExistError when user not exists", func(t *testing.T) {
-		inputAmount := int64(100)
+		inputWeight := 2
 		ctx := context.Background()
 		mockRepo := mocks.NewMockIUserRepository(t)
 
 		mockRepo.EXPECT().
-			UpdateUserBalance(ctx, inputID, -inputAmount).
-			Return(0, models.UserNotExistError).
+			UpdateUserEnqueueWeight(ctx, inputID, inputWeight).
+			Return(models.User{}, models.UserNotExistError).
 			Once()
 
 		service := services.NewUserService(mockRepo)
-		actualBalance, actualErr := service.DecreaseUserBalance(ctx, inputID, inputAmount)
+		actualUser, actualErr := service.SetUserEnqueueWeight(ctx, inputID, inputWeight)
 
 		assert.Error(t, actualErr)
 		assert.Equal(t, models.UserNotExistError, actualErr)
-		assert.Equal(t, int64(0), actualBalance)
+		assert.Equal(t, models.User{}, actualUser)
 	})
+}
 
-	t.Run("should return InvalidBalanceError when user exists but amount lt 0", func(t *testing.T) {
-		inputAmount := int64(-100)
+func TestUserService_GetUserTransactions(t *testing.T) {
+	inputID := "1"
+	inputFilter := models.TransactionFilter{Type: models.TransactionRefund}
+
+	t.Run("should return user transactions", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIUserRepository(t)
+		expectedTxs := []models.BalanceTransaction{
+			{UserId: inputID, Type: models.TransactionRefund, Amount: 100, BalanceAfter: 200, MessageId: "10"},
+		}
+
+		mockRepo.EXPECT().
+			GetBalanceTransactions(ctx, inputID, inputFilter, 0, 10).
+			Return(expectedTxs, nil).
+			Once()
+
+		service := services.NewUserService(mockRepo)
+		actualTxs, actualErr := service.GetUserTransactions(ctx, inputID, inputFilter, 0, 10)
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedTxs, actualTxs)
+	})
+
+	t.Run("should return error when can not get transactions", func(t *testing.T) {
 		ctx := context.Background()
 		mockRepo := mocks.NewMockIUserRepository(t)
+		expectedErr := fmt.Errorf("some error")
+
+		mockRepo.EXPECT().
+			GetBalanceTransactions(ctx, inputID, inputFilter, 0, 10).
+			Return(nil, expectedErr).
+			Once()
 
 		service := services.NewUserService(mockRepo)
-		actualBalance, actualErr := service.DecreaseUserBalance(ctx, inputID, inputAmount)
+		actualTxs, actualErr := service.GetUserTransactions(ctx, inputID, inputFilter, 0, 10)
+
+		assert.Error(t, actualErr)
+		assert.Equal(t, expectedErr, actualErr)
+		assert.Nil(t, actualTxs)
+	})
+}
+
+func TestUserService_HoldUserBalance(t *testing.T) {
+	inputID := "1"
+
+	t.Run("should hold user balance", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIUserRepository(t)
+
+		mockRepo.EXPECT().
+			CreateBalanceHolds(ctx, inputID, []models.BalanceHold{
+				{UserId: inputID, MessageId: "10", Amount: 100},
+			}).
+			Return(nil).
+			Once()
+
+		service := services.NewUserService(mockRepo)
+		actualErr := service.HoldUserBalance(ctx, inputID, []models.BalanceHold{
+			{MessageId: "10", Amount: 100},
+			{MessageId: "11", Amount: 0},
+		})
+
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("should return InvalidBalanceError when amount lt 0", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIUserRepository(t)
+
+		service := services.NewUserService(mockRepo)
+		actualErr := service.HoldUserBalance(ctx, inputID, []models.BalanceHold{
+			{MessageId: "10", Amount: -100},
+		})
 
 		assert.Error(t, actualErr)
 		assert.Equal(t, models.InvalidBalanceError, actualErr)
-		assert.Equal(t, int64(0), actualBalance)
 	})
 
-	t.Run("should return InsufficientBalanceError when user exists but final balance lt 0", func(t *testing.T) {
-		inputAmount := int64(100)
+	t.Run("should return InsufficientBalanceError when available balance is not enough", func(t *testing.T) {
 		ctx := context.Background()
 		mockRepo := mocks.NewMockIUserRepository(t)
 
 		mockRepo.EXPECT().
-			UpdateUserBalance(ctx, inputID, -inputAmount).
-			Return(0, models.InsufficientBalanceError).
+			CreateBalanceHolds(ctx, inputID, []models.BalanceHold{
+				{UserId: inputID, MessageId: "10", Amount: 100},
+			}).
+			Return(models.InsufficientBalanceError).
 			Once()
 
 		service := services.NewUserService(mockRepo)
-		actualBalance, actualErr := service.DecreaseUserBalance(ctx, inputID, inputAmount)
+		actualErr := service.HoldUserBalance(ctx, inputID, []models.BalanceHold{
+			{MessageId: "10", Amount: 100},
+		})
 
 		assert.Error(t, actualErr)
 		assert.Equal(t, models.InsufficientBalanceError, actualErr)
-		assert.Equal(t, int64(0), actualBalance)
+	})
+}
+
+func TestUserService_CaptureHold(t *testing.T) {
+	t.Run("should capture balance hold", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIUserRepository(t)
+		expectedHold := models.BalanceHold{UserId: "1", MessageId: "10", Amount: 100, Status: models.HoldStatusCaptured}
+
+		mockRepo.EXPECT().
+			CaptureBalanceHold(ctx, "10").
+			Return(expectedHold, nil).
+			Once()
+
+		service := services.NewUserService(mockRepo)
+		actualHold, actualErr := service.CaptureHold(ctx, "10")
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedHold, actualHold)
+	})
+
+	t.Run("should return HoldNotExistError when message has no hold", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIUserRepository(t)
+
+		mockRepo.EXPECT().
+			CaptureBalanceHold(ctx, "10").
+			Return(models.BalanceHold{}, models.HoldNotExistError).
+			Once()
+
+		service := services.NewUserService(mockRepo)
+		actualHold, actualErr := service.CaptureHold(ctx, "10")
+
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.HoldNotExistError, actualErr)
+		assert.Equal(t, models.BalanceHold{}, actualHold)
+	})
+}
+
+func TestUserService_ReleaseHold(t *testing.T) {
+	t.Run("should release balance hold", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIUserRepository(t)
+		expectedHold := models.BalanceHold{UserId: "1", MessageId: "10", Amount: 100, Status: models.HoldStatusReleased}
+
+		mockRepo.EXPECT().
+			ReleaseBalanceHold(ctx, "10").
+			Return(expectedHold, nil).
+			Once()
+
+		service := services.NewUserService(mockRepo)
+		actualHold, actualErr := service.ReleaseHold(ctx, "10")
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedHold, actualHold)
+	})
+
+	t.Run("should return HoldNotExistError when message has no hold", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIUserRepository(t)
+
+		mockRepo.EXPECT().
+			ReleaseBalanceHold(ctx, "10").
+			Return(models.BalanceHold{}, models.HoldNotExistError).
+			Once()
+
+		service := services.NewUserService(mockRepo)
+		actualHold, actualErr := service.ReleaseHold(ctx, "10")
+
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.HoldNotExistError, actualErr)
+		assert.Equal(t, models.BalanceHold{}, actualHold)
+	})
+}
+
+func TestUserService_GetUnsettledHolds(t *testing.T) {
+	t.Run("should return unsettled holds", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIUserRepository(t)
+		finishedBefore := time.Now().Add(-5 * time.Minute)
+		expectedHolds := []models.UnsettledHold{
+			{MessageId: "10", Capture: true},
+			{MessageId: "11", Capture: false},
+		}
+
+		mockRepo.EXPECT().
+			GetUnsettledBalanceHolds(ctx, finishedBefore, 100).
+			Return(expectedHolds, nil).
+			Once()
+
+		service := services.NewUserService(mockRepo)
+		actualHolds, actualErr := service.GetUnsettledHolds(ctx, finishedBefore, 100)
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedHolds, actualHolds)
+	})
+
+	t.Run("should return error when repository fails", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIUserRepository(t)
+		finishedBefore := time.Now().Add(-5 * time.Minute)
+
+		mockRepo.EXPECT().
+			GetUnsettledBalanceHolds(ctx, finishedBefore, 100).
+			Return(nil, fmt.Errorf("db error")).
+			Once()
+
+		service := services.NewUserService(mockRepo)
+		actualHolds, actualErr := service.GetUnsettledHolds(ctx, finishedBefore, 100)
+
+		assert.Error(t, actualErr)
+		assert.Nil(t, actualHolds)
+	})
+}
+
+func TestUserService_SetUserAccount(t *testing.T) {
+	inputID := "1"
+
+	t.Run("should switch user to postpaid with credit limit", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIUserRepository(t)
+		expectedUser := models.User{Name: "test", AccountType: models.AccountPostpaid, CreditLimit: 5000}
+
+		mockRepo.EXPECT().
+			UpdateUserAccount(ctx, inputID, models.AccountPostpaid, int64(5000)).
+			Return(expectedUser, nil).
+			Once()
+
+		service := services.NewUserService(mockRepo)
+		actualUser, actualErr := service.SetUserAccount(ctx, inputID, models.AccountPostpaid, 5000)
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedUser, actualUser)
+	})
+
+	t.Run("should return InvalidAccountTypeError when account type is unknown", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIUserRepository(t)
+
+		service := services.NewUserService(mockRepo)
+		actualUser, actualErr := service.SetUserAccount(ctx, inputID, "credit", 0)
+
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.InvalidAccountTypeError, actualErr)
+		assert.Equal(t, models.User{}, actualUser)
+	})
+
+	t.Run("should return InvalidCreditLimitError when credit limit is invalid", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIUserRepository(t)
+
+		service := services.NewUserService(mockRepo)
+
+		_, actualErr := service.SetUserAccount(ctx, inputID, models.AccountPrepaid, 100)
+		assert.Equal(t, models.InvalidCreditLimitError, actualErr)
+
+		_, actualErr = service.SetUserAccount(ctx, inputID, models.AccountPostpaid, -1)
+		assert.Equal(t, models.InvalidCreditLimitError, actualErr)
+	})
+
+	t.Run("should return InsufficientBalanceError when balance does not fit in the new limit", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIUserRepository(t)
+
+		mockRepo.EXPECT().
+			UpdateUserAccount(ctx, inputID, models.AccountPrepaid, int64(0)).
+			Return(models.User{}, models.InsufficientBalanceError).
+			Once()
+
+		service := services.NewUserService(mockRepo)
+		actualUser, actualErr := service.SetUserAccount(ctx, inputID, models.AccountPrepaid, 0)
+
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.InsufficientBalanceError, actualErr)
+		assert.Equal(t, models.User{}, actualUser)
+	})
+}
+
+func TestUserService_GetUserStatement(t *testing.T) {
+	inputID := "1"
+
+	t.Run("should return statement of the calendar month", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIUserRepository(t)
+
+		from := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+		expectedStatement := models.BalanceStatement{
+			UserId:          inputID,
+			From:            from,
+			To:              to,
+			OpeningBalance:  1000,
+			ClosingBalance:  -200,
+			Charges:         -1200,
+			ChargedMessages: 12,
+		}
+
+		mockRepo.EXPECT().
+			GetBalanceStatement(ctx, inputID, from, to).
+			Return(expectedStatement, nil).
+			Once()
+
+		service := services.NewUserService(mockRepo)
+		actualStatement, actualErr := service.GetUserStatement(ctx, inputID, time.Date(2026, time.February, 17, 13, 4, 0, 0, time.UTC))
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedStatement, actualStatement)
+	})
+
+	t.Run("should return error when can not get statement", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIUserRepository(t)
+
+		expectedErr := fmt.Errorf("some error")
+
+		mockRepo.EXPECT().
+			GetBalanceStatement(ctx, inputID, mock.Anything, mock.Anything).
+			Return(models.BalanceStatement{}, expectedErr).
+			Once()
+
+		service := services.NewUserService(mockRepo)
+		_, actualErr := service.GetUserStatement(ctx, inputID, time.Now())
+
+		assert.Error(t, actualErr)
+		assert.Equal(t, expectedErr, actualErr)
 	})
 }
@@ -0,0 +1,9 @@
+package models
+
+import "errors"
+
+var (
+	InvalidUrlError       = errors.New("invalid webhook url")
+	WebhookNotExistError  = errors.New("webhook does not exist")
+	DeliveryNotExistError = errors.New("webhook delivery does not exist")
+)
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+)
+
+const (
+	EventMessageStatus = "message.status"
+	EventTest          = "webhook.test"
+)
+
+// Webhook is an endpoint of a user that receives signed callbacks of events.
+type Webhook struct {
+	*shared.Entity
+	*shared.CreateDate
+	*shared.UpdateDate
+
+	UserId string
+	Url    string
+	Secret string
+}
+
+type DeliveryStatus int
+
+const (
+	DeliveryPending DeliveryStatus = iota
+	DeliverySucceeded
+	DeliveryFailed
+)
+
+// Delivery is a callback of an event to a webhook and the log of its attempts.
+type Delivery struct {
+	*shared.Entity
+	*shared.CreateDate
+	*shared.UpdateDate
+
+	WebhookId     string
+	Event         string
+	Payload       string
+	Status        DeliveryStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	// ResponseStatus is the http status of the last attempt, zero when the
+	// endpoint could not be reached.
+	ResponseStatus int
+	Error          string
+
+	// Webhook is the target of the delivery, set on deliveries claimed for
+	// dispatch.
+	Webhook Webhook
+}
+
+// Request is a signed callback sent to a webhook.
+type Request struct {
+	DeliveryId string
+	Url        string
+	Event      string
+	Timestamp  int64
+	Signature  string
+	Body       []byte
+}
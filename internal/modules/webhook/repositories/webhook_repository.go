@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/models"
+)
+
+type IWebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error)
+	GetWebhooks(ctx context.Context, userId string) ([]models.Webhook, error)
+	GetWebhook(ctx context.Context, userId string, id string) (models.Webhook, error)
+	UpdateWebhookSecret(ctx context.Context, userId string, id string, secret string) (models.Webhook, error)
+	DeleteWebhook(ctx context.Context, userId string, id string) error
+	// CreateUserDeliveries queues delivery of an event to every webhook of a
+	// user and returns the number of queued deliveries.
+	CreateUserDeliveries(ctx context.Context, userId string, delivery models.Delivery) (int, error)
+	CreateDelivery(ctx context.Context, delivery models.Delivery) (models.Delivery, error)
+	// ClaimDueDeliveries returns pending deliveries due at the given time with
+	// their webhooks, and postpones them by lease so other dispatchers skip
+	// them while they are being sent.
+	ClaimDueDeliveries(ctx context.Context, at time.Time, lease time.Duration, limit int) ([]models.Delivery, error)
+	UpdateDelivery(ctx context.Context, delivery models.Delivery) error
+	GetDeliveries(ctx context.Context, webhookId string, skip int, limit int) ([]models.Delivery, error)
+}
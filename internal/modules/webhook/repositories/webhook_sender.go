@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/models"
+)
+
+type IWebhookSender interface {
+	// Send posts req and returns the http status of the response.
+	Send(ctx context.Context, req models.Request) (int, error)
+}
@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/repositories"
+
+	smssrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/services"
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+)
+
+const (
+	maxUrlLength = 2048
+	secretPrefix = "whsec_"
+)
+
+type WebhookServiceConfig struct {
+	Retry     smssrv.RetryPolicy `mapstructure:"retry"`
+	BatchSize int                `mapstructure:"batch_size"`
+	// Lease is how long a claimed delivery is hidden from other dispatchers.
+	// It must outlast a send attempt.
+	Lease time.Duration `mapstructure:"lease"`
+}
+
+type IWebhookService interface {
+	CreateWebhook(ctx context.Context, userId string, rawUrl string) (models.Webhook, error)
+	GetWebhooks(ctx context.Context, userId string) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, userId string, id string) error
+	RotateSecret(ctx context.Context, userId string, id string) (models.Webhook, error)
+	TestWebhook(ctx context.Context, userId string, id string) (models.Delivery, error)
+	GetDeliveries(ctx context.Context, userId string, id string, skip int, limit int) ([]models.Delivery, error)
+	Publish(ctx context.Context, userId string, event string, data any) (int, error)
+	DispatchDue(ctx context.Context) (int, error)
+}
+
+type WebhookService struct {
+	webhookRepo   repositories.IWebhookRepository
+	webhookSender repositories.IWebhookSender
+	cfg           WebhookServiceConfig
+}
+
+type envelope struct {
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      any       `json:"data"`
+}
+
+func NewWebhookService(
+	cfg WebhookServiceConfig,
+	webhookRepo repositories.IWebhookRepository,
+	webhookSender repositories.IWebhookSender,
+) *WebhookService {
+	if cfg.Retry.MaxAttempts <= 0 {
+		cfg.Retry = smssrv.RetryPolicy{
+			MaxAttempts:    8,
+			InitialBackoff: 10 * time.Second,
+			MaxBackoff:     time.Hour,
+			Multiplier:     3,
+			Jitter:         0.2,
+		}
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = time.Minute
+	}
+
+	return &WebhookService{
+		cfg:           cfg,
+		webhookRepo:   webhookRepo,
+		webhookSender: webhookSender,
+	}
+}
+
+// Sign returns the hex HMAC-SHA256 of "{timestamp}.{body}" with secret, which
+// receivers recompute to verify a callback.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = fmt.Fprintf(mac, "%d.", timestamp)
+	_, _ = mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (w *WebhookService) CreateWebhook(ctx context.Context, userId string, rawUrl string) (models.Webhook, error) {
+	pkgLog.Debug("creating webhook for user %s", userId)
+	if !validUrl(rawUrl) {
+		pkgLog.Error(models.InvalidUrlError, "invalid webhook url %s", rawUrl)
+		return models.Webhook{}, models.InvalidUrlError
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		pkgLog.Error(err, "failed to generate webhook secret")
+		return models.Webhook{}, err
+	}
+
+	res, err := w.webhookRepo.CreateWebhook(ctx, models.Webhook{
+		UserId: userId,
+		Url:    rawUrl,
+		Secret: secret,
+	})
+	if err != nil {
+		pkgLog.Error(err, "failed to create webhook for user %s", userId)
+		return models.Webhook{}, err
+	}
+
+	pkgLog.Debug("created webhook %s for user %s", res.ID, userId)
+	return res, nil
+}
+
+func (w *WebhookService) GetWebhooks(ctx context.Context, userId string) ([]models.Webhook, error) {
+	pkgLog.Debug("getting webhooks of user %s", userId)
+	res, err := w.webhookRepo.GetWebhooks(ctx, userId)
+	if err != nil {
+		pkgLog.Error(err, "failed to get webhooks of user %s", userId)
+		return nil, err
+	}
+
+	pkgLog.Debug("got %d webhooks of user %s", len(res), userId)
+	return res, nil
+}
+
+func (w *WebhookService) DeleteWebhook(ctx context.Context, userId string, id string) error {
+	pkgLog.Debug("deleting webhook %s of user %s", id, userId)
+	if err := w.webhookRepo.DeleteWebhook(ctx, userId, id); err != nil {
+		pkgLog.Error(err, "failed to delete webhook %s of user %s", id, userId)
+		return err
+	}
+
+	pkgLog.Debug("deleted webhook %s of user %s", id, userId)
+	return nil
+}
+
+// RotateSecret replaces the secret of a webhook. Pending deliveries are signed
+// with the new secret.
+func (w *WebhookService) RotateSecret(ctx context.Context, userId string, id string) (models.Webhook, error) {
+	pkgLog.Debug("rotating secret of webhook %s of user %s", id, userId)
+	secret, err := newSecret()
+	if err != nil {
+		pkgLog.Error(err, "failed to generate webhook secret")
+		return models.Webhook{}, err
+	}
+
+	res, err := w.webhookRepo.UpdateWebhookSecret(ctx, userId, id, secret)
+	if err != nil {
+		pkgLog.Error(err, "failed to rotate secret of webhook %s of user %s", id, userId)
+		return models.Webhook{}, err
+	}
+
+	pkgLog.Debug("rotated secret of webhook %s of user %s", id, userId)
+	return res, nil
+}
+
+// TestWebhook sends a test event to a webhook right away and logs the
+// outcome. Test deliveries are not retried.
+func (w *WebhookService) TestWebhook(ctx context.Context, userId string, id string) (models.Delivery, error) {
+	pkgLog.Debug("testing webhook %s of user %s", id, userId)
+	webhook, err := w.webhookRepo.GetWebhook(ctx, userId, id)
+	if err != nil {
+		pkgLog.Error(err, "failed to get webhook %s of user %s", id, userId)
+		return models.Delivery{}, err
+	}
+
+	payload, err := newPayload(models.EventTest, map[string]string{
+		"webhookId": webhook.ID,
+	})
+	if err != nil {
+		pkgLog.Error(err, "failed to encode test event")
+		return models.Delivery{}, err
+	}
+
+	delivery := w.attempt(ctx, models.Delivery{
+		WebhookId: webhook.ID,
+		Event:     models.EventTest,
+		Payload:   payload,
+		Webhook:   webhook,
+	}, 1)
+
+	res, err := w.webhookRepo.CreateDelivery(ctx, delivery)
+	if err != nil {
+		pkgLog.Error(err, "failed to log test delivery of webhook %s", id)
+		return models.Delivery{}, err
+	}
+
+	pkgLog.Debug("tested webhook %s of user %s with response status %d", id, userId, res.ResponseStatus)
+	return res, nil
+}
+
+func (w *WebhookService) GetDeliveries(
+	ctx context.Context,
+	userId string,
+	id string,
+	skip int,
+	limit int,
+) ([]models.Delivery, error) {
+	pkgLog.Debug("getting deliveries of webhook %s of user %s", id, userId)
+	if _, err := w.webhookRepo.GetWebhook(ctx, userId, id); err != nil {
+		pkgLog.Error(err, "failed to get webhook %s of user %s", id, userId)
+		return nil, err
+	}
+
+	res, err := w.webhookRepo.GetDeliveries(ctx, id, skip, limit)
+	if err != nil {
+		pkgLog.Error(err, "failed to get deliveries of webhook %s", id)
+		return nil, err
+	}
+
+	pkgLog.Debug("got %d deliveries of webhook %s", len(res), id)
+	return res, nil
+}
+
+// Publish queues an event for every webhook of a user and returns the number
+// of queued deliveries.
+func (w *WebhookService) Publish(ctx context.Context, userId string, event string, data any) (int, error) {
+	pkgLog.Debug("publishing %s event to webhooks of user %s", event, userId)
+	payload, err := newPayload(event, data)
+	if err != nil {
+		pkgLog.Error(err, "failed to encode %s event", event)
+		return 0, err
+	}
+
+	queued, err := w.webhookRepo.CreateUserDeliveries(ctx, userId, models.Delivery{
+		Event:         event,
+		Payload:       payload,
+		Status:        models.DeliveryPending,
+		NextAttemptAt: time.Now(),
+	})
+	if err != nil {
+		pkgLog.Error(err, "failed to queue %s event of user %s", event, userId)
+		return 0, err
+	}
+
+	pkgLog.Debug("queued %d deliveries of %s event of user %s", queued, event, userId)
+	return queued, nil
+}
+
+// DispatchDue sends a batch of due deliveries and returns how many were
+// attempted. Failed attempts are rescheduled with backoff until the retry
+// policy gives up.
+func (w *WebhookService) DispatchDue(ctx context.Context) (int, error) {
+	deliveries, err := w.webhookRepo.ClaimDueDeliveries(ctx, time.Now(), w.cfg.Lease, w.cfg.BatchSize)
+	if err != nil {
+		pkgLog.Error(err, "failed to claim due webhook deliveries")
+		return 0, err
+	}
+
+	for i := range deliveries {
+		delivery := w.attempt(ctx, deliveries[i], w.cfg.Retry.MaxAttempts)
+		if err := w.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+			pkgLog.Error(err, "failed to update webhook delivery %s", delivery.ID)
+		}
+	}
+
+	if len(deliveries) > 0 {
+		pkgLog.Debug("dispatched %d webhook deliveries", len(deliveries))
+	}
+	return len(deliveries), nil
+}
+
+// attempt sends a delivery once and records the outcome on it.
+func (w *WebhookService) attempt(ctx context.Context, delivery models.Delivery, maxAttempts int) models.Delivery {
+	timestamp := time.Now().Unix()
+	body := []byte(delivery.Payload)
+	req := models.Request{
+		Url:       delivery.Webhook.Url,
+		Event:     delivery.Event,
+		Timestamp: timestamp,
+		Signature: Sign(delivery.Webhook.Secret, timestamp, body),
+		Body:      body,
+	}
+	if delivery.Entity != nil {
+		req.DeliveryId = delivery.ID
+	}
+
+	status, err := w.webhookSender.Send(ctx, req)
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+	delivery.Error = ""
+	if err == nil && (status < 200 || status >= 300) {
+		err = fmt.Errorf("unexpected response status %d", status)
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliverySucceeded
+	case delivery.Attempts >= maxAttempts:
+		pkgLog.Error(err, "giving up webhook delivery to %s after %d attempts", req.Url, delivery.Attempts)
+		delivery.Status = models.DeliveryFailed
+		delivery.Error = err.Error()
+	default:
+		pkgLog.Error(err, "failed webhook delivery to %s on attempt %d", req.Url, delivery.Attempts)
+		delivery.Status = models.DeliveryPending
+		delivery.NextAttemptAt = time.Now().Add(w.cfg.Retry.Backoff(delivery.Attempts))
+		delivery.Error = err.Error()
+	}
+
+	return delivery
+}
+
+func newPayload(event string, data any) (string, error) {
+	payload, err := json.Marshal(envelope{
+		Event:     event,
+		CreatedAt: time.Now(),
+		Data:      data,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(payload), nil
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return secretPrefix + hex.EncodeToString(b), nil
+}
+
+func validUrl(rawUrl string) bool {
+	if len(rawUrl) > maxUrlLength {
+		return false
+	}
+
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
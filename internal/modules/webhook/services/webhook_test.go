@@ -0,0 +1,418 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/mocks"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/services"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	smssrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/services"
+)
+
+var cfg = services.WebhookServiceConfig{
+	Retry: smssrv.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     time.Hour,
+		Multiplier:     2,
+	},
+	BatchSize: 10,
+	Lease:     time.Minute,
+}
+
+func TestSign(t *testing.T) {
+	t.Run("should sign timestamp and body with secret", func(t *testing.T) {
+		actualSignature := services.Sign("secret", 1700000000, []byte(`{"event":"webhook.test"}`))
+
+		assert.Equal(t, "042f3b47c22ad43f11b4eba519f00f6117768eb96f279f564687920ae2fd1e14", actualSignature)
+	})
+}
+
+func TestWebhookService_CreateWebhook(t *testing.T) {
+	t.Run("should create webhook with generated secret", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIWebhookRepository(t)
+		mockSender := mocks.NewMockIWebhookSender(t)
+
+		mockRepo.EXPECT().
+			CreateWebhook(ctx, mock.MatchedBy(func(w models.Webhook) bool {
+				return w.UserId == "1" &&
+					w.Url == "https://example.com/hooks/sms" &&
+					strings.HasPrefix(w.Secret, "whsec_") &&
+					len(w.Secret) == 54
+			})).
+			RunAndReturn(func(_ context.Context, w models.Webhook) (models.Webhook, error) {
+				w.Entity = &shared.Entity{ID: "5"}
+				return w, nil
+			}).
+			Once()
+
+		service := services.NewWebhookService(cfg, mockRepo, mockSender)
+		actualWebhook, actualErr := service.CreateWebhook(ctx, "1", "https://example.com/hooks/sms")
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, "5", actualWebhook.ID)
+		assert.NotEmpty(t, actualWebhook.Secret)
+	})
+
+	t.Run("should return InvalidUrlError when url is not http", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIWebhookRepository(t)
+		mockSender := mocks.NewMockIWebhookSender(t)
+
+		service := services.NewWebhookService(cfg, mockRepo, mockSender)
+
+		for _, rawUrl := range []string{"", "example.com/hooks", "ftp://example.com/hooks", "https://"} {
+			_, actualErr := service.CreateWebhook(ctx, "1", rawUrl)
+			assert.Equal(t, models.InvalidUrlError, actualErr, rawUrl)
+		}
+	})
+}
+
+func TestWebhookService_RotateSecret(t *testing.T) {
+	t.Run("should replace secret of webhook", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIWebhookRepository(t)
+		mockSender := mocks.NewMockIWebhookSender(t)
+
+		expectedWebhook := models.Webhook{
+			Entity: &shared.Entity{ID: "5"},
+			UserId: "1",
+			Url:    "https://example.com/hooks/sms",
+			Secret: "whsec_test",
+		}
+		mockRepo.EXPECT().
+			UpdateWebhookSecret(ctx, "1", "5", mock.MatchedBy(func(secret string) bool {
+				return strings.HasPrefix(secret, "whsec_") && secret != expectedWebhook.Secret
+			})).
+			Return(expectedWebhook, nil).
+			Once()
+
+		service := services.NewWebhookService(cfg, mockRepo, mockSender)
+		actualWebhook, actualErr := service.RotateSecret(ctx, "1", "5")
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedWebhook, actualWebhook)
+	})
+
+	t.Run("should return WebhookNotExistError when webhook does not exist", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIWebhookRepository(t)
+		mockSender := mocks.NewMockIWebhookSender(t)
+
+		mockRepo.EXPECT().
+			UpdateWebhookSecret(ctx, "1", "5", mock.Anything).
+			Return(models.Webhook{}, models.WebhookNotExistError).
+			Once()
+
+		service := services.NewWebhookService(cfg, mockRepo, mockSender)
+		_, actualErr := service.RotateSecret(ctx, "1", "5")
+
+		assert.Equal(t, models.WebhookNotExistError, actualErr)
+	})
+}
+
+func TestWebhookService_TestWebhook(t *testing.T) {
+	t.Run("should send signed test event and log it", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIWebhookRepository(t)
+		mockSender := mocks.NewMockIWebhookSender(t)
+
+		webhook := models.Webhook{
+			Entity: &shared.Entity{ID: "5"},
+			UserId: "1",
+			Url:    "https://example.com/hooks/sms",
+			Secret: "whsec_test",
+		}
+		mockRepo.EXPECT().
+			GetWebhook(ctx, "1", "5").
+			Return(webhook, nil).
+			Once()
+
+		mockSender.EXPECT().
+			Send(ctx, mock.MatchedBy(func(req models.Request) bool {
+				return req.Url == webhook.Url &&
+					req.Event == models.EventTest &&
+					req.Signature == services.Sign(webhook.Secret, req.Timestamp, req.Body)
+			})).
+			Return(204, nil).
+			Once()
+
+		mockRepo.EXPECT().
+			CreateDelivery(ctx, mock.MatchedBy(func(d models.Delivery) bool {
+				return d.WebhookId == "5" &&
+					d.Event == models.EventTest &&
+					d.Status == models.DeliverySucceeded &&
+					d.Attempts == 1 &&
+					d.ResponseStatus == 204
+			})).
+			RunAndReturn(func(_ context.Context, d models.Delivery) (models.Delivery, error) {
+				return d, nil
+			}).
+			Once()
+
+		service := services.NewWebhookService(cfg, mockRepo, mockSender)
+		actualDelivery, actualErr := service.TestWebhook(ctx, "1", "5")
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.DeliverySucceeded, actualDelivery.Status)
+	})
+
+	t.Run("should log failed test event without retrying", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIWebhookRepository(t)
+		mockSender := mocks.NewMockIWebhookSender(t)
+
+		webhook := models.Webhook{
+			Entity: &shared.Entity{ID: "5"},
+			UserId: "1",
+			Url:    "https://example.com/hooks/sms",
+			Secret: "whsec_test",
+		}
+		mockRepo.EXPECT().
+			GetWebhook(ctx, "1", "5").
+			Return(webhook, nil).
+			Once()
+
+		mockSender.EXPECT().
+			Send(ctx, mock.Anything).
+			Return(0, errors.New("connection refused")).
+			Once()
+
+		mockRepo.EXPECT().
+			CreateDelivery(ctx, mock.Anything).
+			RunAndReturn(func(_ context.Context, d models.Delivery) (models.Delivery, error) {
+				return d, nil
+			}).
+			Once()
+
+		service := services.NewWebhookService(cfg, mockRepo, mockSender)
+		actualDelivery, actualErr := service.TestWebhook(ctx, "1", "5")
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.DeliveryFailed, actualDelivery.Status)
+		assert.Equal(t, "connection refused", actualDelivery.Error)
+	})
+}
+
+func TestWebhookService_Publish(t *testing.T) {
+	t.Run("should queue event for webhooks of user", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIWebhookRepository(t)
+		mockSender := mocks.NewMockIWebhookSender(t)
+
+		mockRepo.EXPECT().
+			CreateUserDeliveries(ctx, "1", mock.MatchedBy(func(d models.Delivery) bool {
+				var payload struct {
+					Event string            `json:"event"`
+					Data  map[string]string `json:"data"`
+				}
+				if err := json.Unmarshal([]byte(d.Payload), &payload); err != nil {
+					return false
+				}
+
+				return d.Event == models.EventMessageStatus &&
+					d.Status == models.DeliveryPending &&
+					!d.NextAttemptAt.IsZero() &&
+					payload.Event == models.EventMessageStatus &&
+					payload.Data["status"] == "Sent"
+			})).
+			Return(2, nil).
+			Once()
+
+		service := services.NewWebhookService(cfg, mockRepo, mockSender)
+		actualQueued, actualErr := service.Publish(ctx, "1", models.EventMessageStatus, map[string]string{"status": "Sent"})
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 2, actualQueued)
+	})
+}
+
+func TestWebhookService_DispatchDue(t *testing.T) {
+	t.Run("should mark successful deliveries as succeeded", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIWebhookRepository(t)
+		mockSender := mocks.NewMockIWebhookSender(t)
+
+		delivery := models.Delivery{
+			Entity:    &shared.Entity{ID: "9"},
+			WebhookId: "5",
+			Event:     models.EventMessageStatus,
+			Payload:   `{"event":"message.status"}`,
+			Status:    models.DeliveryPending,
+			Attempts:  0,
+			Webhook: models.Webhook{
+				Entity: &shared.Entity{ID: "5"},
+				UserId: "1",
+				Url:    "https://example.com/hooks/sms",
+				Secret: "whsec_test",
+			},
+		}
+		mockRepo.EXPECT().
+			ClaimDueDeliveries(ctx, mock.Anything, time.Minute, 10).
+			Return([]models.Delivery{delivery}, nil).
+			Once()
+
+		mockSender.EXPECT().
+			Send(ctx, mock.MatchedBy(func(req models.Request) bool {
+				return req.DeliveryId == "9" &&
+					string(req.Body) == delivery.Payload &&
+					req.Signature == services.Sign("whsec_test", req.Timestamp, req.Body)
+			})).
+			Return(200, nil).
+			Once()
+
+		mockRepo.EXPECT().
+			UpdateDelivery(ctx, mock.MatchedBy(func(d models.Delivery) bool {
+				return d.ID == "9" &&
+					d.Status == models.DeliverySucceeded &&
+					d.Attempts == 1 &&
+					d.ResponseStatus == 200 &&
+					d.Error == ""
+			})).
+			Return(nil).
+			Once()
+
+		service := services.NewWebhookService(cfg, mockRepo, mockSender)
+		actualDispatched, actualErr := service.DispatchDue(ctx)
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 1, actualDispatched)
+	})
+
+	t.Run("should reschedule failed deliveries with backoff", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIWebhookRepository(t)
+		mockSender := mocks.NewMockIWebhookSender(t)
+
+		delivery := models.Delivery{
+			Entity:    &shared.Entity{ID: "9"},
+			WebhookId: "5",
+			Event:     models.EventMessageStatus,
+			Payload:   `{"event":"message.status"}`,
+			Status:    models.DeliveryPending,
+			Attempts:  1,
+			Webhook: models.Webhook{
+				Entity: &shared.Entity{ID: "5"},
+				UserId: "1",
+				Url:    "https://example.com/hooks/sms",
+				Secret: "whsec_test",
+			},
+		}
+		mockRepo.EXPECT().
+			ClaimDueDeliveries(ctx, mock.Anything, time.Minute, 10).
+			Return([]models.Delivery{delivery}, nil).
+			Once()
+
+		mockSender.EXPECT().
+			Send(ctx, mock.Anything).
+			Return(503, nil).
+			Once()
+
+		mockRepo.EXPECT().
+			UpdateDelivery(ctx, mock.MatchedBy(func(d models.Delivery) bool {
+				backoff := time.Until(d.NextAttemptAt)
+				return d.Status == models.DeliveryPending &&
+					d.Attempts == 2 &&
+					d.ResponseStatus == 503 &&
+					d.Error == "unexpected response status 503" &&
+					backoff > time.Minute && backoff <= 2*time.Minute
+			})).
+			Return(nil).
+			Once()
+
+		service := services.NewWebhookService(cfg, mockRepo, mockSender)
+		actualDispatched, actualErr := service.DispatchDue(ctx)
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 1, actualDispatched)
+	})
+
+	t.Run("should fail deliveries when attempts are exhausted", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIWebhookRepository(t)
+		mockSender := mocks.NewMockIWebhookSender(t)
+
+		delivery := models.Delivery{
+			Entity:    &shared.Entity{ID: "9"},
+			WebhookId: "5",
+			Event:     models.EventMessageStatus,
+			Payload:   `{"event":"message.status"}`,
+			Status:    models.DeliveryPending,
+			Attempts:  2,
+			Webhook: models.Webhook{
+				Entity: &shared.Entity{ID: "5"},
+				UserId: "1",
+				Url:    "https://example.com/hooks/sms",
+				Secret: "whsec_test",
+			},
+		}
+		mockRepo.EXPECT().
+			ClaimDueDeliveries(ctx, mock.Anything, time.Minute, 10).
+			Return([]models.Delivery{delivery}, nil).
+			Once()
+
+		mockSender.EXPECT().
+			Send(ctx, mock.Anything).
+			Return(0, errors.New("timeout")).
+			Once()
+
+		mockRepo.EXPECT().
+			UpdateDelivery(ctx, mock.MatchedBy(func(d models.Delivery) bool {
+				return d.Status == models.DeliveryFailed &&
+					d.Attempts == 3 &&
+					d.Error == "timeout"
+			})).
+			Return(nil).
+			Once()
+
+		service := services.NewWebhookService(cfg, mockRepo, mockSender)
+		_, actualErr := service.DispatchDue(ctx)
+
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("should return error when deliveries can not be claimed", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIWebhookRepository(t)
+		mockSender := mocks.NewMockIWebhookSender(t)
+
+		expectedErr := errors.New("test error")
+		mockRepo.EXPECT().
+			ClaimDueDeliveries(ctx, mock.Anything, time.Minute, 10).
+			Return(nil, expectedErr).
+			Once()
+
+		service := services.NewWebhookService(cfg, mockRepo, mockSender)
+		_, actualErr := service.DispatchDue(ctx)
+
+		assert.Equal(t, expectedErr, actualErr)
+	})
+}
+
+func TestWebhookService_GetDeliveries(t *testing.T) {
+	t.Run("should return WebhookNotExistError when webhook belongs to another user", func(t *testing.T) {
+		ctx := context.Background()
+		mockRepo := mocks.NewMockIWebhookRepository(t)
+		mockSender := mocks.NewMockIWebhookSender(t)
+
+		mockRepo.EXPECT().
+			GetWebhook(ctx, "2", "5").
+			Return(models.Webhook{}, models.WebhookNotExistError).
+			Once()
+
+		service := services.NewWebhookService(cfg, mockRepo, mockSender)
+		_, actualErr := service.GetDeliveries(ctx, "2", "5", 0, 10)
+
+		assert.Equal(t, models.WebhookNotExistError, actualErr)
+	})
+}
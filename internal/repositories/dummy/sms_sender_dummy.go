@@ -13,6 +13,6 @@ func NewSmsSender() *SmsSender {
 	return &SmsSender{}
 }
 
-func (d *SmsSender) Send(_ context.Context, _ models.Sms) error {
-	return nil
+func (d *SmsSender) Send(_ context.Context, _ models.Sms) (models.SendResult, error) {
+	return models.SendResult{}, nil
 }
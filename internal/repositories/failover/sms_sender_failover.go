@@ -0,0 +1,123 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/repositories"
+
+	pkgBreaker "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/circuitbreaker"
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+	pkgMetrics "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/metrics"
+)
+
+var (
+	EmptyChainError = errors.New("failover chain has no provider")
+)
+
+type Config struct {
+	Providers []string          `mapstructure:"providers"`
+	Breaker   pkgBreaker.Config `mapstructure:"breaker"`
+}
+
+type member struct {
+	name    string
+	sender  repositories.ISmsSender
+	breaker *pkgBreaker.Breaker
+}
+
+// SmsSender tries its providers in order and moves to the next one when a
+// provider fails temporarily or its circuit breaker is open.
+type SmsSender struct {
+	name    string
+	members []member
+}
+
+func NewSmsSender(name string, cfg Config, senders map[string]repositories.ISmsSender) (*SmsSender, error) {
+	if len(cfg.Providers) == 0 {
+		return nil, fmt.Errorf("%w: %s", EmptyChainError, name)
+	}
+
+	members := make([]member, len(cfg.Providers))
+	for i, provider := range cfg.Providers {
+		sender, ok := senders[provider]
+		if !ok {
+			return nil, fmt.Errorf("unknown sms provider %s in failover chain %s", provider, name)
+		}
+
+		breaker := pkgBreaker.NewBreaker(provider, cfg.Breaker)
+		breaker.OnStateChange(func(provider string, from pkgBreaker.State, to pkgBreaker.State) {
+			pkgLog.Warn("circuit of provider %s in chain %s changed from %s to %s", provider, name, from, to)
+			pkgMetrics.ProviderCircuitStateMetric.WithLabelValues(name, provider).Set(float64(to))
+		})
+		pkgMetrics.ProviderCircuitStateMetric.WithLabelValues(name, provider).Set(float64(pkgBreaker.StateClosed))
+
+		members[i] = member{
+			name:    provider,
+			sender:  sender,
+			breaker: breaker,
+		}
+	}
+
+	return &SmsSender{
+		name:    name,
+		members: members,
+	}, nil
+}
+
+// Send returns PermanentSendError from the first provider that rejects the
+// message, since the rejection is about the message rather than the provider.
+// Any other error is recorded on the provider breaker and the next provider is
+// tried. Throttled providers are skipped without counting as failures.
+func (s *SmsSender) Send(ctx context.Context, msg models.Sms) (models.SendResult, error) {
+	var lastErr error
+	for _, m := range s.members {
+		if err := ctx.Err(); err != nil {
+			return models.SendResult{}, err
+		}
+		if err := m.breaker.Allow(); err != nil {
+			pkgLog.Debug("skipping provider %s in chain %s: %s", m.name, s.name, err.Error())
+			continue
+		}
+
+		res, err := m.sender.Send(ctx, msg)
+		if err == nil {
+			m.breaker.Success()
+			if res.Provider == "" {
+				res.Provider = m.name
+			}
+			return res, nil
+		}
+
+		if errors.Is(err, models.PermanentSendError) {
+			m.breaker.Success()
+			return models.SendResult{}, err
+		}
+		if ctx.Err() != nil {
+			m.breaker.Discard()
+			return models.SendResult{}, err
+		}
+		if errors.Is(err, models.ProviderThrottledError) {
+			// a throttled provider is healthy, it is just busy
+			m.breaker.Discard()
+			pkgLog.Debug("provider %s in chain %s is throttled", m.name, s.name)
+			lastErr = err
+			continue
+		}
+
+		m.breaker.Failure()
+		pkgLog.Error(err, "provider %s in chain %s failed to send message %s", m.name, s.name, msg.ID)
+		lastErr = err
+	}
+
+	if lastErr == nil {
+		return models.SendResult{}, fmt.Errorf("%w: no healthy provider in chain %s", models.TemporarySendError, s.name)
+	}
+	if !errors.Is(lastErr, models.TemporarySendError) && !errors.Is(lastErr, models.ProviderThrottledError) {
+		return models.SendResult{}, fmt.Errorf("%w: %s", models.TemporarySendError, lastErr.Error())
+	}
+
+	return models.SendResult{}, lastErr
+}
@@ -0,0 +1,221 @@
+package failover_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/mocks"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/repositories"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/failover"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/pkg/circuitbreaker"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSmsSender_Send(t *testing.T) {
+	metrics.RegisterMetrics()
+
+	cfg := failover.Config{
+		Providers: []string{"first", "second"},
+		Breaker: circuitbreaker.Config{
+			ConsecutiveFailures: 1,
+			OpenTimeout:         time.Hour,
+		},
+	}
+
+	t.Run("should send with first provider", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		mockFirst := mocks.NewMockISmsSender(t)
+		mockSecond := mocks.NewMockISmsSender(t)
+
+		mockFirst.EXPECT().
+			Send(ctx, msg).
+			Return(models.SendResult{MessageId: "1"}, nil).
+			Once()
+
+		sender, err := failover.NewSmsSender("chain", cfg, map[string]repositories.ISmsSender{
+			"first":  mockFirst,
+			"second": mockSecond,
+		})
+		assert.NoError(t, err)
+
+		actualRes, actualErr := sender.Send(ctx, msg)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.SendResult{Provider: "first", MessageId: "1"}, actualRes)
+	})
+
+	t.Run("should fail over to next provider and skip open circuit", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		mockFirst := mocks.NewMockISmsSender(t)
+		mockSecond := mocks.NewMockISmsSender(t)
+
+		mockFirst.EXPECT().
+			Send(ctx, msg).
+			Return(models.SendResult{}, models.TemporarySendError).
+			Once()
+
+		mockSecond.EXPECT().
+			Send(ctx, msg).
+			Return(models.SendResult{MessageId: "2"}, nil).
+			Twice()
+
+		sender, err := failover.NewSmsSender("failover", cfg, map[string]repositories.ISmsSender{
+			"first":  mockFirst,
+			"second": mockSecond,
+		})
+		assert.NoError(t, err)
+
+		actualRes, actualErr := sender.Send(ctx, msg)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.SendResult{Provider: "second", MessageId: "2"}, actualRes)
+		assert.Equal(t, float64(circuitbreaker.StateOpen), testutil.ToFloat64(metrics.ProviderCircuitStateMetric.WithLabelValues("failover", "first")))
+		assert.Equal(t, float64(circuitbreaker.StateClosed), testutil.ToFloat64(metrics.ProviderCircuitStateMetric.WithLabelValues("failover", "second")))
+
+		actualRes, actualErr = sender.Send(ctx, msg)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, "second", actualRes.Provider)
+	})
+
+	t.Run("should skip throttled provider without opening circuit", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		mockFirst := mocks.NewMockISmsSender(t)
+		mockSecond := mocks.NewMockISmsSender(t)
+
+		mockFirst.EXPECT().
+			Send(ctx, msg).
+			Return(models.SendResult{}, models.ProviderThrottledError).
+			Twice()
+
+		mockSecond.EXPECT().
+			Send(ctx, msg).
+			Return(models.SendResult{MessageId: "2"}, nil).
+			Twice()
+
+		sender, err := failover.NewSmsSender("throttled", cfg, map[string]repositories.ISmsSender{
+			"first":  mockFirst,
+			"second": mockSecond,
+		})
+		assert.NoError(t, err)
+
+		actualRes, actualErr := sender.Send(ctx, msg)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, "second", actualRes.Provider)
+		assert.Equal(t, float64(circuitbreaker.StateClosed), testutil.ToFloat64(metrics.ProviderCircuitStateMetric.WithLabelValues("throttled", "first")))
+
+		actualRes, actualErr = sender.Send(ctx, msg)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, "second", actualRes.Provider)
+	})
+
+	t.Run("should not fail over when message is rejected", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		mockFirst := mocks.NewMockISmsSender(t)
+		mockSecond := mocks.NewMockISmsSender(t)
+
+		mockFirst.EXPECT().
+			Send(ctx, msg).
+			Return(models.SendResult{}, models.PermanentSendError).
+			Once()
+
+		sender, err := failover.NewSmsSender("chain", cfg, map[string]repositories.ISmsSender{
+			"first":  mockFirst,
+			"second": mockSecond,
+		})
+		assert.NoError(t, err)
+
+		_, actualErr := sender.Send(ctx, msg)
+		assert.ErrorIs(t, actualErr, models.PermanentSendError)
+	})
+
+	t.Run("should return TemporarySendError when all providers are exhausted", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		mockFirst := mocks.NewMockISmsSender(t)
+		mockSecond := mocks.NewMockISmsSender(t)
+
+		mockFirst.EXPECT().
+			Send(ctx, msg).
+			Return(models.SendResult{}, models.TemporarySendError).
+			Once()
+
+		mockSecond.EXPECT().
+			Send(ctx, msg).
+			Return(models.SendResult{}, context.DeadlineExceeded).
+			Once()
+
+		sender, err := failover.NewSmsSender("chain", cfg, map[string]repositories.ISmsSender{
+			"first":  mockFirst,
+			"second": mockSecond,
+		})
+		assert.NoError(t, err)
+
+		_, actualErr := sender.Send(ctx, msg)
+		assert.ErrorIs(t, actualErr, models.TemporarySendError)
+
+		_, actualErr = sender.Send(ctx, msg)
+		assert.ErrorIs(t, actualErr, models.TemporarySendError)
+	})
+
+	t.Run("should return EmptyChainError when chain has no provider", func(t *testing.T) {
+		_, actualErr := failover.NewSmsSender("chain", failover.Config{}, nil)
+		assert.ErrorIs(t, actualErr, failover.EmptyChainError)
+	})
+}
@@ -0,0 +1,232 @@
+package httpsender
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+)
+
+const (
+	BodyFormatJSON = "json"
+	BodyFormatForm = "form"
+
+	maxResponseSize = 1 << 20
+)
+
+var defaultRetryableStatusCodes = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// BodyField maps one field of the provider request body to a value. Value may
+// contain the {id}, {user_id}, {receiver} and {content} placeholders.
+type BodyField struct {
+	Name  string `mapstructure:"name"`
+	Value string `mapstructure:"value"`
+}
+
+type Config struct {
+	URL                  string        `mapstructure:"url"`
+	Method               string        `mapstructure:"method"`
+	Timeout              time.Duration `mapstructure:"timeout"`
+	AuthHeader           string        `mapstructure:"auth_header"`
+	AuthValue            string        `mapstructure:"auth_value"`
+	BodyFormat           string        `mapstructure:"body_format"`
+	BodyFields           []BodyField   `mapstructure:"body_fields"`
+	SuccessStatusCodes   []int         `mapstructure:"success_status_codes"`
+	SuccessPath          string        `mapstructure:"success_path"`
+	SuccessValue         string        `mapstructure:"success_value"`
+	MessageIdPath        string        `mapstructure:"message_id_path"`
+	RetryableStatusCodes []int         `mapstructure:"retryable_status_codes"`
+}
+
+type SmsSender struct {
+	client *http.Client
+	cfg    Config
+}
+
+func NewSmsSender(cfg Config) *SmsSender {
+	if cfg.Method == "" {
+		cfg.Method = http.MethodPost
+	}
+	if cfg.BodyFormat == "" {
+		cfg.BodyFormat = BodyFormatJSON
+	}
+	if len(cfg.RetryableStatusCodes) == 0 {
+		cfg.RetryableStatusCodes = defaultRetryableStatusCodes
+	}
+
+	return &SmsSender{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+	}
+}
+
+func (s *SmsSender) Send(ctx context.Context, msg models.Sms) (models.SendResult, error) {
+	req, err := s.buildRequest(ctx, msg)
+	if err != nil {
+		return models.SendResult{}, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return models.SendResult{}, fmt.Errorf("%w: %s", models.TemporarySendError, err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return models.SendResult{}, fmt.Errorf("%w: %s", models.TemporarySendError, err.Error())
+	}
+
+	if slices.Contains(s.cfg.RetryableStatusCodes, resp.StatusCode) {
+		return models.SendResult{}, fmt.Errorf("%w: provider responded with status %d", models.TemporarySendError, resp.StatusCode)
+	}
+	if !s.isSuccessStatus(resp.StatusCode) {
+		return models.SendResult{}, fmt.Errorf("%w: provider responded with status %d", models.PermanentSendError, resp.StatusCode)
+	}
+
+	if s.cfg.SuccessPath == "" && s.cfg.MessageIdPath == "" {
+		return models.SendResult{}, nil
+	}
+
+	var payload any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return models.SendResult{}, fmt.Errorf("%w: invalid provider response: %s", models.PermanentSendError, err.Error())
+	}
+
+	if s.cfg.SuccessPath != "" {
+		value, ok := lookupPath(payload, s.cfg.SuccessPath)
+		if !ok || value != s.cfg.SuccessValue {
+			return models.SendResult{}, fmt.Errorf("%w: provider rejected message with %s=%s", models.PermanentSendError, s.cfg.SuccessPath, value)
+		}
+	}
+
+	res := models.SendResult{}
+	if s.cfg.MessageIdPath != "" {
+		res.MessageId, _ = lookupPath(payload, s.cfg.MessageIdPath)
+	}
+
+	return res, nil
+}
+
+func (s *SmsSender) buildRequest(ctx context.Context, msg models.Sms) (*http.Request, error) {
+	msgId := ""
+	if msg.Entity != nil {
+		msgId = msg.ID
+	}
+	placeholders := []string{
+		"{id}", msgId,
+		"{user_id}", msg.UserId,
+		"{receiver}", msg.Receiver,
+		"{content}", msg.Content,
+	}
+
+	escaped := make([]string, len(placeholders))
+	for i := range placeholders {
+		escaped[i] = placeholders[i]
+		if i%2 == 1 {
+			escaped[i] = url.QueryEscape(placeholders[i])
+		}
+	}
+	reqUrl := strings.NewReplacer(escaped...).Replace(s.cfg.URL)
+
+	replacer := strings.NewReplacer(placeholders...)
+	var body io.Reader
+	contentType := ""
+	if len(s.cfg.BodyFields) > 0 {
+		switch s.cfg.BodyFormat {
+		case BodyFormatForm:
+			values := url.Values{}
+			for _, f := range s.cfg.BodyFields {
+				values.Set(f.Name, replacer.Replace(f.Value))
+			}
+			body = strings.NewReader(values.Encode())
+			contentType = "application/x-www-form-urlencoded"
+		case BodyFormatJSON:
+			values := make(map[string]string, len(s.cfg.BodyFields))
+			for _, f := range s.cfg.BodyFields {
+				values[f.Name] = replacer.Replace(f.Value)
+			}
+			bodyBytes, err := json.Marshal(values)
+			if err != nil {
+				return nil, err
+			}
+			body = bytes.NewReader(bodyBytes)
+			contentType = "application/json"
+		default:
+			return nil, fmt.Errorf("unsupported body format %s", s.cfg.BodyFormat)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, s.cfg.Method, reqUrl, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if s.cfg.AuthHeader != "" {
+		req.Header.Set(s.cfg.AuthHeader, s.cfg.AuthValue)
+	}
+
+	return req, nil
+}
+
+func (s *SmsSender) isSuccessStatus(status int) bool {
+	if len(s.cfg.SuccessStatusCodes) == 0 {
+		return status >= 200 && status < 300
+	}
+
+	return slices.Contains(s.cfg.SuccessStatusCodes, status)
+}
+
+// lookupPath walks a decoded JSON document with a dot separated path such as
+// "data.messages.0.id" and returns the value found there as a string.
+func lookupPath(v any, path string) (string, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			next, ok := node[key]
+			if !ok {
+				return "", false
+			}
+			v = next
+		case []any:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return "", false
+			}
+			v = node[idx]
+		default:
+			return "", false
+		}
+	}
+
+	switch value := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return value, true
+	default:
+		return fmt.Sprint(value), true
+	}
+}
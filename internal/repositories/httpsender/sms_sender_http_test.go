@@ -0,0 +1,218 @@
+package httpsender_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/httpsender"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSmsSender_Send(t *testing.T) {
+	t.Run("should send json body and return provider message id", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		var actualBody map[string]string
+		var actualAuth string
+		var actualPath string
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actualAuth = r.Header.Get("Authorization")
+			actualPath = r.URL.RequestURI()
+			_ = json.NewDecoder(r.Body).Decode(&actualBody)
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"status":"ok","data":{"messages":[{"id":12345}]}}`))
+		}))
+		defer provider.Close()
+
+		sender := httpsender.NewSmsSender(httpsender.Config{
+			URL:        provider.URL + "/v1/send?ref={id}",
+			Timeout:    time.Second,
+			AuthHeader: "Authorization",
+			AuthValue:  "Bearer token",
+			BodyFields: []httpsender.BodyField{
+				{Name: "to", Value: "{receiver}"},
+				{Name: "text", Value: "{content}"},
+				{Name: "sender", Value: "gateway-{user_id}"},
+			},
+			SuccessPath:   "status",
+			SuccessValue:  "ok",
+			MessageIdPath: "data.messages.0.id",
+		})
+
+		actualRes, actualErr := sender.Send(ctx, msg)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, "12345", actualRes.MessageId)
+		assert.Equal(t, "Bearer token", actualAuth)
+		assert.Equal(t, "/v1/send?ref=1", actualPath)
+		assert.Equal(t, map[string]string{
+			"to":     msg.Receiver,
+			"text":   msg.Content,
+			"sender": "gateway-2",
+		}, actualBody)
+	})
+
+	t.Run("should send form body", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		var actualReceiver, actualContent, actualContentType string
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actualContentType = r.Header.Get("Content-Type")
+			actualReceiver = r.FormValue("receptor")
+			actualContent = r.FormValue("message")
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer provider.Close()
+
+		sender := httpsender.NewSmsSender(httpsender.Config{
+			URL:        provider.URL,
+			BodyFormat: httpsender.BodyFormatForm,
+			BodyFields: []httpsender.BodyField{
+				{Name: "receptor", Value: "{receiver}"},
+				{Name: "message", Value: "{content}"},
+			},
+		})
+
+		actualRes, actualErr := sender.Send(ctx, msg)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.SendResult{}, actualRes)
+		assert.Equal(t, "application/x-www-form-urlencoded", actualContentType)
+		assert.Equal(t, msg.Receiver, actualReceiver)
+		assert.Equal(t, msg.Content, actualContent)
+	})
+
+	t.Run("should return TemporarySendError when provider responds with retryable status", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer provider.Close()
+
+		sender := httpsender.NewSmsSender(httpsender.Config{
+			URL: provider.URL,
+		})
+
+		_, actualErr := sender.Send(ctx, msg)
+		assert.Error(t, actualErr)
+		assert.ErrorIs(t, actualErr, models.TemporarySendError)
+	})
+
+	t.Run("should return TemporarySendError when provider is unreachable", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		providerUrl := provider.URL
+		provider.Close()
+
+		sender := httpsender.NewSmsSender(httpsender.Config{
+			URL: providerUrl,
+		})
+
+		_, actualErr := sender.Send(ctx, msg)
+		assert.Error(t, actualErr)
+		assert.ErrorIs(t, actualErr, models.TemporarySendError)
+	})
+
+	t.Run("should return PermanentSendError when provider responds with non retryable status", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer provider.Close()
+
+		sender := httpsender.NewSmsSender(httpsender.Config{
+			URL: provider.URL,
+		})
+
+		_, actualErr := sender.Send(ctx, msg)
+		assert.Error(t, actualErr)
+		assert.ErrorIs(t, actualErr, models.PermanentSendError)
+	})
+
+	t.Run("should return PermanentSendError when success path does not match", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"return":{"status":411}}`))
+		}))
+		defer provider.Close()
+
+		sender := httpsender.NewSmsSender(httpsender.Config{
+			URL:          provider.URL,
+			SuccessPath:  "return.status",
+			SuccessValue: "200",
+		})
+
+		_, actualErr := sender.Send(ctx, msg)
+		assert.Error(t, actualErr)
+		assert.ErrorIs(t, actualErr, models.PermanentSendError)
+	})
+}
@@ -0,0 +1,85 @@
+package pgsql
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/common"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+)
+
+type apiKeyEntity struct {
+	ID               uint
+	UserId           uint
+	Name             string
+	Prefix           string
+	KeyHash          string
+	Scopes           string
+	RequireSignature bool
+	SigningSecret    string
+	RevokedAt        *time.Time
+	CreatedAt        time.Time
+}
+
+func (a *apiKeyEntity) TableName() string {
+	return "api_keys"
+}
+
+func fromApiKey(k models.ApiKey) apiKeyEntity {
+	scopes := make([]string, len(k.Scopes))
+	for i := range k.Scopes {
+		scopes[i] = string(k.Scopes[i])
+	}
+
+	ke := apiKeyEntity{
+		UserId:           common.ParseUIntWithFallback(k.UserId, 0),
+		Name:             k.Name,
+		Prefix:           k.Prefix,
+		KeyHash:          k.Hash,
+		Scopes:           strings.Join(scopes, ","),
+		RequireSignature: k.RequireSignature,
+		SigningSecret:    k.SigningSecret,
+	}
+
+	if k.Entity != nil {
+		ke.ID = common.ParseUIntWithFallback(k.ID, 0)
+	}
+	if k.CreateDate != nil {
+		ke.CreatedAt = k.CreatedAt
+	}
+	if !k.RevokedAt.IsZero() {
+		ke.RevokedAt = &k.RevokedAt
+	}
+
+	return ke
+}
+
+func toApiKey(ke apiKeyEntity) models.ApiKey {
+	k := models.ApiKey{
+		Entity: &shared.Entity{
+			ID: fmt.Sprintf("%d", ke.ID),
+		},
+		CreateDate: &shared.CreateDate{
+			CreatedAt: ke.CreatedAt,
+		},
+		UserId:           fmt.Sprintf("%d", ke.UserId),
+		Name:             ke.Name,
+		Prefix:           ke.Prefix,
+		Hash:             ke.KeyHash,
+		RequireSignature: ke.RequireSignature,
+		SigningSecret:    ke.SigningSecret,
+	}
+
+	for _, scope := range strings.Split(ke.Scopes, ",") {
+		if scope != "" {
+			k.Scopes = append(k.Scopes, models.Scope(scope))
+		}
+	}
+	if ke.RevokedAt != nil {
+		k.RevokedAt = *ke.RevokedAt
+	}
+
+	return k
+}
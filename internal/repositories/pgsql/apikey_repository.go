@@ -0,0 +1,78 @@
+package pgsql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *Repository) CreateApiKey(ctx context.Context, key models.ApiKey) (models.ApiKey, error) {
+	ke := fromApiKey(key)
+	if ke.CreatedAt.IsZero() {
+		ke.CreatedAt = time.Now()
+	}
+
+	if err := r.db(ctx).Create(&ke).Error; err != nil {
+		return models.ApiKey{}, err
+	}
+
+	return toApiKey(ke), nil
+}
+
+func (r *Repository) GetApiKeyByHash(ctx context.Context, hash string) (models.ApiKey, error) {
+	ke := apiKeyEntity{}
+
+	err := r.db(ctx).First(&ke, "key_hash = ?", hash).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ApiKey{}, models.ApiKeyNotExistError
+		}
+
+		return models.ApiKey{}, err
+	}
+
+	return toApiKey(ke), nil
+}
+
+func (r *Repository) GetApiKeys(ctx context.Context, userId string) ([]models.ApiKey, error) {
+	var kes []apiKeyEntity
+
+	err := r.db(ctx).
+		Where("user_id = ?", userId).
+		Order("id ASC").
+		Find(&kes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ks := make([]models.ApiKey, len(kes))
+	for i := range kes {
+		ks[i] = toApiKey(kes[i])
+	}
+
+	return ks, nil
+}
+
+// RevokeApiKey marks a key of a user as revoked. Revoking a revoked key keeps
+// its first revocation time.
+func (r *Repository) RevokeApiKey(ctx context.Context, userId string, id string) (models.ApiKey, error) {
+	ke := apiKeyEntity{}
+
+	res := r.db(ctx).
+		Model(&ke).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND user_id = ?", id, userId).
+		Update("revoked_at", gorm.Expr("COALESCE(revoked_at, ?)", time.Now()))
+	if res.Error != nil {
+		return models.ApiKey{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return models.ApiKey{}, models.ApiKeyNotExistError
+	}
+
+	return toApiKey(ke), nil
+}
@@ -0,0 +1,84 @@
+package pgsql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/models"
+	"github.com/stretchr/testify/assert"
+
+	umodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+)
+
+func TestRepository_CreateApiKey(t *testing.T) {
+	t.Run("should create api key and get it by hash", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{Name: "AshkanAbd"})
+		assert.NoError(t, err)
+
+		createdKey, actualErr := repo.CreateApiKey(ctx, models.ApiKey{
+			UserId: createdUser.ID,
+			Name:   "backend",
+			Prefix: "sgw_01234567",
+			Hash:   "hash-1",
+			Scopes: []models.Scope{models.ScopeRead, models.ScopeSend},
+		})
+		assert.NoError(t, actualErr)
+		assert.NotNil(t, createdKey.Entity)
+
+		actualKey, actualErr := repo.GetApiKeyByHash(ctx, "hash-1")
+		assert.NoError(t, actualErr)
+		assert.Equal(t, createdUser.ID, actualKey.UserId)
+		assert.Equal(t, []models.Scope{models.ScopeRead, models.ScopeSend}, actualKey.Scopes)
+		assert.False(t, actualKey.IsRevoked())
+
+		_, actualErr = repo.GetApiKeyByHash(ctx, "hash-2")
+		assert.Equal(t, models.ApiKeyNotExistError, actualErr)
+	})
+}
+
+func TestRepository_RevokeApiKey(t *testing.T) {
+	t.Run("should revoke api key of user only", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{Name: "AshkanAbd"})
+		assert.NoError(t, err)
+
+		createdKey, err := repo.CreateApiKey(ctx, models.ApiKey{
+			UserId: createdUser.ID,
+			Prefix: "sgw_01234567",
+			Hash:   "hash-1",
+			Scopes: []models.Scope{models.ScopeRead},
+		})
+		assert.NoError(t, err)
+
+		_, actualErr := repo.RevokeApiKey(ctx, "0", createdKey.ID)
+		assert.Equal(t, models.ApiKeyNotExistError, actualErr)
+
+		actualKey, actualErr := repo.RevokeApiKey(ctx, createdUser.ID, createdKey.ID)
+		assert.NoError(t, actualErr)
+		assert.True(t, actualKey.IsRevoked())
+
+		actualKeys, actualErr := repo.GetApiKeys(ctx, createdUser.ID)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 1, len(actualKeys))
+		assert.True(t, actualKeys[0].IsRevoked())
+	})
+}
@@ -0,0 +1,63 @@
+package pgsql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/common"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+)
+
+type balanceHoldEntity struct {
+	ID        uint
+	UserId    uint
+	MessageId uint
+	Amount    int64
+	Status    string `gorm:"default:held"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (b *balanceHoldEntity) TableName() string {
+	return "balance_holds"
+}
+
+func fromBalanceHold(b models.BalanceHold) balanceHoldEntity {
+	be := balanceHoldEntity{
+		UserId:    common.ParseUIntWithFallback(b.UserId, 0),
+		MessageId: common.ParseUIntWithFallback(b.MessageId, 0),
+		Amount:    b.Amount,
+		Status:    string(b.Status),
+	}
+
+	if b.Entity != nil {
+		be.ID = common.ParseUIntWithFallback(b.ID, 0)
+	}
+	if b.CreateDate != nil {
+		be.CreatedAt = b.CreatedAt
+	}
+	if b.UpdateDate != nil {
+		be.UpdatedAt = b.UpdatedAt
+	}
+
+	return be
+}
+
+func toBalanceHold(be balanceHoldEntity) models.BalanceHold {
+	return models.BalanceHold{
+		Entity: &shared.Entity{
+			ID: fmt.Sprintf("%d", be.ID),
+		},
+		CreateDate: &shared.CreateDate{
+			CreatedAt: be.CreatedAt,
+		},
+		UpdateDate: &shared.UpdateDate{
+			UpdatedAt: be.UpdatedAt,
+		},
+		UserId:    fmt.Sprintf("%d", be.UserId),
+		MessageId: fmt.Sprintf("%d", be.MessageId),
+		Amount:    be.Amount,
+		Status:    models.HoldStatus(be.Status),
+	}
+}
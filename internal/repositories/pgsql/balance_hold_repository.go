@@ -0,0 +1,198 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	smsmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+)
+
+// CreateBalanceHolds reserves the hold amounts on the user held balance. It
+// fails with InsufficientBalanceError when the available balance can not
+// cover them.
+func (r *Repository) CreateBalanceHolds(ctx context.Context, userId string, holds []models.BalanceHold) error {
+	var amount int64
+	bes := make([]balanceHoldEntity, len(holds))
+	for i := range holds {
+		amount += holds[i].Amount
+		bes[i] = fromBalanceHold(holds[i])
+		bes[i].Status = string(models.HoldStatusHeld)
+	}
+
+	err := r.db(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.WithContext(ctx).Model(&userEntity{}).
+			Where("id = ?", userId).
+			Updates(map[string]any{
+				"held_balance": gorm.Expr("held_balance + ?", amount),
+				"updated_at":   gorm.Expr("now()"),
+			})
+
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return models.UserNotExistError
+		}
+
+		if len(bes) == 0 {
+			return nil
+		}
+
+		return tx.WithContext(ctx).Create(bes).Error
+	})
+
+	if err != nil {
+		if strings.Contains(err.Error(), "user_insufficient_balance") {
+			return models.InsufficientBalanceError
+		}
+		return err
+	}
+
+	return nil
+}
+
+// CaptureBalanceHold charges the held amount of the message from the user
+// balance and records the charge in the ledger.
+func (r *Repository) CaptureBalanceHold(ctx context.Context, messageId string) (models.BalanceHold, error) {
+	var he balanceHoldEntity
+
+	err := r.db(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := settleHold(ctx, tx, messageId, models.HoldStatusCaptured, &he); err != nil {
+			return err
+		}
+
+		var ue userEntity
+		res := tx.WithContext(ctx).Model(&ue).
+			Where("id = ?", he.UserId).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "balance"}}}).
+			Updates(map[string]any{
+				"balance":      gorm.Expr("balance - ?", he.Amount),
+				"held_balance": gorm.Expr("held_balance - ?", he.Amount),
+				"updated_at":   gorm.Expr("now()"),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		messageId := he.MessageId
+		return tx.WithContext(ctx).Create(&balanceTransactionEntity{
+			UserId:       he.UserId,
+			Type:         string(models.TransactionMessageCharge),
+			Amount:       -he.Amount,
+			BalanceAfter: ue.Balance,
+			MessageId:    &messageId,
+		}).Error
+	})
+
+	if err != nil {
+		return models.BalanceHold{}, err
+	}
+
+	return toBalanceHold(he), nil
+}
+
+// ReleaseBalanceHold returns the held amount of the message to the user
+// available balance.
+func (r *Repository) ReleaseBalanceHold(ctx context.Context, messageId string) (models.BalanceHold, error) {
+	var he balanceHoldEntity
+
+	err := r.db(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := settleHold(ctx, tx, messageId, models.HoldStatusReleased, &he); err != nil {
+			return err
+		}
+
+		return tx.WithContext(ctx).Model(&userEntity{}).
+			Where("id = ?", he.UserId).
+			Updates(map[string]any{
+				"held_balance": gorm.Expr("held_balance - ?", he.Amount),
+				"updated_at":   gorm.Expr("now()"),
+			}).Error
+	})
+
+	if err != nil {
+		return models.BalanceHold{}, err
+	}
+
+	return toBalanceHold(he), nil
+}
+
+// unsettledHoldsQuery finds held holds of messages that left the pending
+// statuses before @finished. Sent messages are captured and the rest are
+// released.
+const unsettledHoldsQuery = `
+SELECT h.message_id, m.status IN @sent AS capture
+FROM balance_holds h
+JOIN messages m ON m.id = h.message_id
+WHERE h.status = @held AND m.status IN @finished_statuses AND m.updated_at < @finished
+ORDER BY h.id
+LIMIT @limit`
+
+type unsettledHoldRow struct {
+	MessageId uint
+	Capture   bool
+}
+
+// GetUnsettledBalanceHolds returns holds still held after their message was
+// sent, failed or was canceled before finishedBefore.
+func (r *Repository) GetUnsettledBalanceHolds(ctx context.Context, finishedBefore time.Time, limit int) ([]models.UnsettledHold, error) {
+	sent := []smsmodels.SmsStatus{
+		smsmodels.StatusSent,
+		smsmodels.StatusDelivered,
+		smsmodels.StatusUndelivered,
+		smsmodels.StatusExpired,
+	}
+	finished := append([]smsmodels.SmsStatus{smsmodels.StatusFailed, smsmodels.StatusCanceled}, sent...)
+
+	var rows []unsettledHoldRow
+	err := r.db(ctx).
+		Raw(unsettledHoldsQuery,
+			sql.Named("sent", sent),
+			sql.Named("held", string(models.HoldStatusHeld)),
+			sql.Named("finished_statuses", finished),
+			sql.Named("finished", finishedBefore),
+			sql.Named("limit", limit),
+		).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]models.UnsettledHold, len(rows))
+	for i := range rows {
+		res[i] = models.UnsettledHold{
+			MessageId: fmt.Sprintf("%d", rows[i].MessageId),
+			Capture:   rows[i].Capture,
+		}
+	}
+
+	return res, nil
+}
+
+// settleHold moves the active hold of the message to status.
+func settleHold(ctx context.Context, tx *gorm.DB, messageId string, status models.HoldStatus, he *balanceHoldEntity) error {
+	res := tx.WithContext(ctx).Model(he).
+		Clauses(clause.Returning{}).
+		Where("message_id = ? AND status = ?", messageId, string(models.HoldStatusHeld)).
+		Updates(map[string]any{
+			"status":     string(status),
+			"updated_at": gorm.Expr("now()"),
+		})
+
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return models.HoldNotExistError
+	}
+
+	return nil
+}
@@ -0,0 +1,308 @@
+package pgsql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	"github.com/stretchr/testify/assert"
+
+	umodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+)
+
+func TestRepository_CreateBalanceHolds(t *testing.T) {
+	t.Run("should hold available balance", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{Name: "AshkanAbd", Balance: 1000})
+		assert.NoError(t, err)
+
+		createdMsgs, err := repo.CreateScheduleMessages(ctx, []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusScheduled,
+			},
+		})
+		assert.NoError(t, err)
+
+		err = repo.CreateBalanceHolds(ctx, createdUser.ID, []umodels.BalanceHold{
+			{UserId: createdUser.ID, MessageId: createdMsgs[0].ID, Amount: 100},
+		})
+		assert.NoError(t, err)
+
+		actualUser, err := repo.GetUser(ctx, createdUser.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1000), actualUser.Balance)
+		assert.Equal(t, int64(100), actualUser.HeldBalance)
+		assert.Equal(t, int64(900), actualUser.AvailableBalance())
+	})
+
+	t.Run("should return InsufficientBalanceError when available balance is not enough", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{Name: "AshkanAbd", Balance: 1000})
+		assert.NoError(t, err)
+
+		createdMsgs, err := repo.CreateScheduleMessages(ctx, []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusScheduled,
+			},
+		})
+		assert.NoError(t, err)
+		createdMsg := createdMsgs[0]
+
+		err = repo.CreateBalanceHolds(ctx, createdUser.ID, []umodels.BalanceHold{
+			{UserId: createdUser.ID, MessageId: createdMsgs[0].ID, Amount: 100},
+		})
+		assert.NoError(t, err)
+
+		actualErr := repo.CreateBalanceHolds(ctx, createdUser.ID, []umodels.BalanceHold{
+			{UserId: createdUser.ID, MessageId: createdMsg.ID, Amount: 950},
+		})
+		assert.Error(t, actualErr)
+		assert.Equal(t, umodels.InsufficientBalanceError, actualErr)
+	})
+}
+
+func TestRepository_CaptureBalanceHold(t *testing.T) {
+	t.Run("should charge held amount and record it in the ledger", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{Name: "AshkanAbd", Balance: 1000})
+		assert.NoError(t, err)
+
+		createdMsgs, err := repo.CreateScheduleMessages(ctx, []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusScheduled,
+			},
+		})
+		assert.NoError(t, err)
+		createdMsg := createdMsgs[0]
+
+		err = repo.CreateBalanceHolds(ctx, createdUser.ID, []umodels.BalanceHold{
+			{UserId: createdUser.ID, MessageId: createdMsgs[0].ID, Amount: 100},
+		})
+		assert.NoError(t, err)
+
+		actualHold, actualErr := repo.CaptureBalanceHold(ctx, createdMsg.ID)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, umodels.HoldStatusCaptured, actualHold.Status)
+		assert.Equal(t, int64(100), actualHold.Amount)
+
+		actualUser, err := repo.GetUser(ctx, createdUser.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(900), actualUser.Balance)
+		assert.Equal(t, int64(0), actualUser.HeldBalance)
+
+		filter := umodels.TransactionFilter{Type: umodels.TransactionMessageCharge}
+		actualTxs, err := repo.GetBalanceTransactions(ctx, createdUser.ID, filter, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(actualTxs))
+		assert.Equal(t, int64(-100), actualTxs[0].Amount)
+		assert.Equal(t, int64(900), actualTxs[0].BalanceAfter)
+		assert.Equal(t, createdMsg.ID, actualTxs[0].MessageId)
+	})
+
+	t.Run("should return HoldNotExistError when hold is already settled", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{Name: "AshkanAbd", Balance: 1000})
+		assert.NoError(t, err)
+
+		createdMsgs, err := repo.CreateScheduleMessages(ctx, []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusScheduled,
+			},
+		})
+		assert.NoError(t, err)
+		createdMsg := createdMsgs[0]
+
+		err = repo.CreateBalanceHolds(ctx, createdUser.ID, []umodels.BalanceHold{
+			{UserId: createdUser.ID, MessageId: createdMsgs[0].ID, Amount: 100},
+		})
+		assert.NoError(t, err)
+
+		_, err = repo.ReleaseBalanceHold(ctx, createdMsg.ID)
+		assert.NoError(t, err)
+
+		actualHold, actualErr := repo.CaptureBalanceHold(ctx, createdMsg.ID)
+		assert.Error(t, actualErr)
+		assert.Equal(t, umodels.HoldNotExistError, actualErr)
+		assert.Equal(t, umodels.BalanceHold{}, actualHold)
+	})
+}
+
+func TestRepository_ReleaseBalanceHold(t *testing.T) {
+	t.Run("should return held amount to available balance", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{Name: "AshkanAbd", Balance: 1000})
+		assert.NoError(t, err)
+
+		createdMsgs, err := repo.CreateScheduleMessages(ctx, []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusScheduled,
+			},
+		})
+		assert.NoError(t, err)
+		createdMsg := createdMsgs[0]
+
+		err = repo.CreateBalanceHolds(ctx, createdUser.ID, []umodels.BalanceHold{
+			{UserId: createdUser.ID, MessageId: createdMsgs[0].ID, Amount: 100},
+		})
+		assert.NoError(t, err)
+
+		actualHold, actualErr := repo.ReleaseBalanceHold(ctx, createdMsg.ID)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, umodels.HoldStatusReleased, actualHold.Status)
+
+		actualUser, err := repo.GetUser(ctx, createdUser.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1000), actualUser.Balance)
+		assert.Equal(t, int64(0), actualUser.HeldBalance)
+	})
+}
+
+func TestRepository_GetUnsettledBalanceHolds(t *testing.T) {
+	t.Run("should return holds of finished messages only", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{Name: "AshkanAbd", Balance: 1000})
+		assert.NoError(t, err)
+
+		sentMsgs, err := repo.CreateScheduleMessages(ctx, []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusScheduled,
+			},
+		})
+		assert.NoError(t, err)
+
+		err = repo.CreateBalanceHolds(ctx, createdUser.ID, []umodels.BalanceHold{
+			{UserId: createdUser.ID, MessageId: sentMsgs[0].ID, Amount: 100},
+		})
+		assert.NoError(t, err)
+
+		_, err = repo.EnqueueMessages(ctx, 1)
+		assert.NoError(t, err)
+		_, err = repo.SetMessageAsSent(ctx, sentMsgs[0].ID, models.SendResult{Provider: "default", MessageId: "remote-1"})
+		assert.NoError(t, err)
+
+		canceledMsgs, err := repo.CreateScheduleMessages(ctx, []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 2",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusScheduled,
+			},
+		})
+		assert.NoError(t, err)
+
+		err = repo.CreateBalanceHolds(ctx, createdUser.ID, []umodels.BalanceHold{
+			{UserId: createdUser.ID, MessageId: canceledMsgs[0].ID, Amount: 100},
+		})
+		assert.NoError(t, err)
+
+		_, err = repo.CancelScheduledMessage(ctx, createdUser.ID, canceledMsgs[0].ID)
+		assert.NoError(t, err)
+
+		pendingMsgs, err := repo.CreateScheduleMessages(ctx, []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 3",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusScheduled,
+			},
+		})
+		assert.NoError(t, err)
+
+		err = repo.CreateBalanceHolds(ctx, createdUser.ID, []umodels.BalanceHold{
+			{UserId: createdUser.ID, MessageId: pendingMsgs[0].ID, Amount: 100},
+		})
+		assert.NoError(t, err)
+
+		actualHolds, actualErr := repo.GetUnsettledBalanceHolds(ctx, time.Now().Add(time.Minute), 10)
+		assert.NoError(t, actualErr)
+		assert.ElementsMatch(t, []umodels.UnsettledHold{
+			{MessageId: sentMsgs[0].ID, Capture: true},
+			{MessageId: canceledMsgs[0].ID, Capture: false},
+		}, actualHolds)
+
+		actualHolds, actualErr = repo.GetUnsettledBalanceHolds(ctx, time.Now().Add(-time.Minute), 10)
+		assert.NoError(t, actualErr)
+		assert.Empty(t, actualHolds)
+	})
+}
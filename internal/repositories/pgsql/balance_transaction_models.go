@@ -0,0 +1,70 @@
+package pgsql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/common"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+)
+
+type balanceTransactionEntity struct {
+	ID           uint
+	UserId       uint
+	Type         string
+	Amount       int64
+	BalanceAfter int64
+	MessageId    *uint
+	Reference    string
+	CreatedAt    time.Time
+}
+
+func (b *balanceTransactionEntity) TableName() string {
+	return "balance_transactions"
+}
+
+func fromBalanceTransaction(b models.BalanceTransaction) balanceTransactionEntity {
+	be := balanceTransactionEntity{
+		UserId:       common.ParseUIntWithFallback(b.UserId, 0),
+		Type:         string(b.Type),
+		Amount:       b.Amount,
+		BalanceAfter: b.BalanceAfter,
+		Reference:    b.Reference,
+	}
+
+	if b.MessageId != "" {
+		messageId := common.ParseUIntWithFallback(b.MessageId, 0)
+		be.MessageId = &messageId
+	}
+	if b.Entity != nil {
+		be.ID = common.ParseUIntWithFallback(b.ID, 0)
+	}
+	if b.CreateDate != nil {
+		be.CreatedAt = b.CreatedAt
+	}
+
+	return be
+}
+
+func toBalanceTransaction(be balanceTransactionEntity) models.BalanceTransaction {
+	b := models.BalanceTransaction{
+		Entity: &shared.Entity{
+			ID: fmt.Sprintf("%d", be.ID),
+		},
+		CreateDate: &shared.CreateDate{
+			CreatedAt: be.CreatedAt,
+		},
+		UserId:       fmt.Sprintf("%d", be.UserId),
+		Type:         models.TransactionType(be.Type),
+		Amount:       be.Amount,
+		BalanceAfter: be.BalanceAfter,
+		Reference:    be.Reference,
+	}
+
+	if be.MessageId != nil {
+		b.MessageId = fmt.Sprintf("%d", *be.MessageId)
+	}
+
+	return b
+}
@@ -0,0 +1,106 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+)
+
+func (r *Repository) GetBalanceTransactions(
+	ctx context.Context,
+	userId string,
+	filter models.TransactionFilter,
+	skip int,
+	limit int,
+) ([]models.BalanceTransaction, error) {
+	var bes []balanceTransactionEntity
+
+	query := r.db(ctx).
+		Where("user_id = ?", userId)
+
+	if filter.Type != "" {
+		query = query.Where("type = ?", string(filter.Type))
+	}
+	if filter.MessageId != "" {
+		query = query.Where("message_id = ?", filter.MessageId)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	err := query.
+		Order("id DESC").
+		Limit(limit).
+		Offset(skip).
+		Find(&bes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	bs := make([]models.BalanceTransaction, len(bes))
+	for i := range bes {
+		bs[i] = toBalanceTransaction(bes[i])
+	}
+
+	return bs, nil
+}
+
+// balanceStatementQuery sums the ledger of a user over a period by type. The
+// opening balance is the balance after the last entry before the period.
+const balanceStatementQuery = `
+SELECT COALESCE((SELECT balance_after
+                 FROM balance_transactions
+                 WHERE user_id = @user AND created_at < @from
+                 ORDER BY id DESC
+                 LIMIT 1), 0)                                      AS opening_balance,
+       COALESCE(SUM(amount) FILTER (WHERE type = @topup), 0)      AS top_ups,
+       COALESCE(SUM(amount) FILTER (WHERE type = @charge), 0)     AS charges,
+       COALESCE(SUM(amount) FILTER (WHERE type = @refund), 0)     AS refunds,
+       COALESCE(SUM(amount) FILTER (WHERE type = @adjustment), 0) AS adjustments,
+       COUNT(*) FILTER (WHERE type = @charge)                     AS charged_messages
+FROM balance_transactions
+WHERE user_id = @user AND created_at >= @from AND created_at < @to`
+
+func (r *Repository) GetBalanceStatement(ctx context.Context, userId string, from time.Time, to time.Time) (models.BalanceStatement, error) {
+	var row struct {
+		OpeningBalance  int64
+		TopUps          int64
+		Charges         int64
+		Refunds         int64
+		Adjustments     int64
+		ChargedMessages int
+	}
+
+	err := r.db(ctx).
+		Raw(balanceStatementQuery,
+			sql.Named("user", userId),
+			sql.Named("from", from),
+			sql.Named("to", to),
+			sql.Named("topup", string(models.TransactionTopUp)),
+			sql.Named("charge", string(models.TransactionMessageCharge)),
+			sql.Named("refund", string(models.TransactionRefund)),
+			sql.Named("adjustment", string(models.TransactionAdjustment)),
+		).
+		Scan(&row).Error
+	if err != nil {
+		return models.BalanceStatement{}, err
+	}
+
+	return models.BalanceStatement{
+		UserId:          userId,
+		From:            from,
+		To:              to,
+		OpeningBalance:  row.OpeningBalance,
+		ClosingBalance:  row.OpeningBalance + row.TopUps + row.Charges + row.Refunds + row.Adjustments,
+		TopUps:          row.TopUps,
+		Charges:         row.Charges,
+		Refunds:         row.Refunds,
+		Adjustments:     row.Adjustments,
+		ChargedMessages: row.ChargedMessages,
+	}, nil
+}
@@ -0,0 +1,152 @@
+package pgsql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_GetBalanceTransactions(t *testing.T) {
+	t.Run("should record every balance change in the ledger", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, models.User{Name: "AshkanAbd", Balance: 1000})
+		assert.NoError(t, err)
+
+		_, err = repo.UpdateUserBalance(ctx, createdUser.ID, []models.BalanceTransaction{
+			{Type: models.TransactionTopUp, Amount: 500, Reference: "payment-1"},
+		})
+		assert.NoError(t, err)
+
+		_, err = repo.UpdateUserBalance(ctx, createdUser.ID, []models.BalanceTransaction{
+			{Type: models.TransactionMessageCharge, Amount: -100},
+			{Type: models.TransactionMessageCharge, Amount: -100},
+		})
+		assert.NoError(t, err)
+
+		actualTxs, actualErr := repo.GetBalanceTransactions(ctx, createdUser.ID, models.TransactionFilter{}, 0, 10)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 4, len(actualTxs))
+
+		var sum int64
+		for _, tx := range actualTxs {
+			sum += tx.Amount
+		}
+		actualUser, err := repo.GetUser(ctx, createdUser.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, actualUser.Balance, sum)
+		assert.Equal(t, actualUser.Balance, actualTxs[0].BalanceAfter)
+
+		assert.Equal(t, models.TransactionMessageCharge, actualTxs[0].Type)
+		assert.Equal(t, int64(1300), actualTxs[0].BalanceAfter)
+		assert.Equal(t, int64(1400), actualTxs[1].BalanceAfter)
+		assert.Equal(t, models.TransactionTopUp, actualTxs[2].Type)
+		assert.Equal(t, "payment-1", actualTxs[2].Reference)
+		assert.Equal(t, models.TransactionAdjustment, actualTxs[3].Type)
+	})
+
+	t.Run("should filter transactions by type", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, models.User{Name: "AshkanAbd", Balance: 1000})
+		assert.NoError(t, err)
+
+		_, err = repo.UpdateUserBalance(ctx, createdUser.ID, []models.BalanceTransaction{
+			{Type: models.TransactionRefund, Amount: 100},
+		})
+		assert.NoError(t, err)
+
+		filter := models.TransactionFilter{Type: models.TransactionRefund}
+		actualTxs, actualErr := repo.GetBalanceTransactions(ctx, createdUser.ID, filter, 0, 10)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 1, len(actualTxs))
+		assert.Equal(t, int64(100), actualTxs[0].Amount)
+		assert.Equal(t, int64(1100), actualTxs[0].BalanceAfter)
+	})
+
+	t.Run("should not record transactions when balance is insufficient", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, models.User{Name: "AshkanAbd"})
+		assert.NoError(t, err)
+
+		_, err = repo.UpdateUserBalance(ctx, createdUser.ID, []models.BalanceTransaction{
+			{Type: models.TransactionMessageCharge, Amount: -100},
+		})
+		assert.Equal(t, models.InsufficientBalanceError, err)
+
+		actualTxs, actualErr := repo.GetBalanceTransactions(ctx, createdUser.ID, models.TransactionFilter{}, 0, 10)
+		assert.NoError(t, actualErr)
+		assert.Empty(t, actualTxs)
+	})
+}
+
+func TestRepository_GetBalanceStatement(t *testing.T) {
+	t.Run("should sum the ledger of the period by type", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, models.User{Name: "AshkanAbd", Balance: 1000})
+		assert.NoError(t, err)
+
+		from := time.Now()
+
+		_, err = repo.UpdateUserBalance(ctx, createdUser.ID, []models.BalanceTransaction{
+			{Type: models.TransactionTopUp, Amount: 500},
+		})
+		assert.NoError(t, err)
+
+		_, err = repo.UpdateUserBalance(ctx, createdUser.ID, []models.BalanceTransaction{
+			{Type: models.TransactionMessageCharge, Amount: -100},
+			{Type: models.TransactionMessageCharge, Amount: -100},
+			{Type: models.TransactionRefund, Amount: 100},
+		})
+		assert.NoError(t, err)
+
+		to := time.Now().Add(time.Minute)
+
+		actualStatement, actualErr := repo.GetBalanceStatement(ctx, createdUser.ID, from, to)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, int64(1000), actualStatement.OpeningBalance)
+		assert.Equal(t, int64(1400), actualStatement.ClosingBalance)
+		assert.Equal(t, int64(500), actualStatement.TopUps)
+		assert.Equal(t, int64(-200), actualStatement.Charges)
+		assert.Equal(t, int64(100), actualStatement.Refunds)
+		assert.Equal(t, int64(0), actualStatement.Adjustments)
+		assert.Equal(t, 2, actualStatement.ChargedMessages)
+	})
+}
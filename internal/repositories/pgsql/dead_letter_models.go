@@ -0,0 +1,64 @@
+package pgsql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/common"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+)
+
+type deadLetterEntity struct {
+	ID        uint
+	MessageId *uint
+	Payload   string
+	Reason    string
+	Error     string
+	CreatedAt time.Time
+}
+
+func (d *deadLetterEntity) TableName() string {
+	return "dead_letters"
+}
+
+func fromDeadLetter(d models.DeadLetter) deadLetterEntity {
+	de := deadLetterEntity{
+		Payload: d.Payload,
+		Reason:  string(d.Reason),
+		Error:   d.Error,
+	}
+
+	if d.MessageId != "" {
+		messageId := common.ParseUIntWithFallback(d.MessageId, 0)
+		de.MessageId = &messageId
+	}
+	if d.Entity != nil {
+		de.ID = common.ParseUIntWithFallback(d.ID, 0)
+	}
+	if d.CreateDate != nil {
+		de.CreatedAt = d.CreatedAt
+	}
+
+	return de
+}
+
+func toDeadLetter(de deadLetterEntity) models.DeadLetter {
+	d := models.DeadLetter{
+		Entity: &shared.Entity{
+			ID: fmt.Sprintf("%d", de.ID),
+		},
+		CreateDate: &shared.CreateDate{
+			CreatedAt: de.CreatedAt,
+		},
+		Payload: de.Payload,
+		Reason:  models.DeadLetterReason(de.Reason),
+		Error:   de.Error,
+	}
+
+	if de.MessageId != nil {
+		d.MessageId = fmt.Sprintf("%d", *de.MessageId)
+	}
+
+	return d
+}
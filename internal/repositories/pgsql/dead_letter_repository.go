@@ -0,0 +1,131 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *Repository) CreateDeadLetter(ctx context.Context, letter models.DeadLetter) error {
+	de := fromDeadLetter(letter)
+	if de.CreatedAt.IsZero() {
+		de.CreatedAt = time.Now()
+	}
+
+	return r.db(ctx).Create(&de).Error
+}
+
+func (r *Repository) GetDeadLetters(ctx context.Context, skip int, limit int) ([]models.DeadLetter, error) {
+	var des []deadLetterEntity
+
+	err := r.db(ctx).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(skip).
+		Find(&des).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ds := make([]models.DeadLetter, len(des))
+	for i := range des {
+		ds[i] = toDeadLetter(des[i])
+	}
+
+	return ds, nil
+}
+
+func (r *Repository) GetDeadLetter(ctx context.Context, id string) (models.DeadLetter, error) {
+	de := deadLetterEntity{}
+
+	err := r.db(ctx).First(&de, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.DeadLetter{}, models.DeadLetterNotExistError
+		}
+
+		return models.DeadLetter{}, err
+	}
+
+	return toDeadLetter(de), nil
+}
+
+// RequeueDeadLetter schedules the failed message of a dead letter for a fresh
+// round of attempts and removes the dead letter.
+func (r *Repository) RequeueDeadLetter(ctx context.Context, id string) (models.Sms, error) {
+	se := smsEntity{}
+
+	err := r.db(ctx).Transaction(func(tx *gorm.DB) error {
+		de := deadLetterEntity{}
+		err := tx.WithContext(ctx).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&de, "id = ?", id).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.DeadLetterNotExistError
+			}
+
+			return err
+		}
+		if de.MessageId == nil {
+			return models.NotRequeueableError
+		}
+
+		now := time.Now()
+		res := tx.WithContext(ctx).
+			Model(&se).
+			Clauses(clause.Returning{}).
+			Where("id = ? AND status = ?", *de.MessageId, models.StatusFailed).
+			Updates(map[string]any{
+				"status":          models.StatusScheduled,
+				"attempts":        0,
+				"next_attempt_at": now,
+				"updated_at":      now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return models.MessageNotExistError
+		}
+
+		return tx.WithContext(ctx).Delete(&de).Error
+	}, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+	})
+
+	if err != nil {
+		return models.Sms{}, err
+	}
+
+	return toMessage(se), nil
+}
+
+func (r *Repository) DeleteDeadLetter(ctx context.Context, id string) error {
+	res := r.db(ctx).Delete(&deadLetterEntity{}, "id = ?", id)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return models.DeadLetterNotExistError
+	}
+
+	return nil
+}
+
+func (r *Repository) PurgeDeadLetters(ctx context.Context) (int, error) {
+	res := r.db(ctx).
+		Session(&gorm.Session{AllowGlobalUpdate: true}).
+		Delete(&deadLetterEntity{})
+	if res.Error != nil {
+		return 0, res.Error
+	}
+
+	return int(res.RowsAffected), nil
+}
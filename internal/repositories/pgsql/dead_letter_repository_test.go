@@ -0,0 +1,219 @@
+package pgsql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	"github.com/stretchr/testify/assert"
+
+	umodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+)
+
+func TestRepository_CreateDeadLetter(t *testing.T) {
+	t.Run("should create dead letters with and without message", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{
+			Name:    "AshkanAbd",
+			Balance: 0,
+		})
+		assert.NoError(t, err)
+
+		_, err = repo.CreateScheduleMessages(ctx, []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusFailed,
+			},
+		})
+		assert.NoError(t, err)
+
+		userMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, true)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(userMsgs))
+
+		actualErr := repo.CreateDeadLetter(ctx, models.DeadLetter{
+			Payload: "xyz",
+			Reason:  models.ReasonUndecodable,
+			Error:   "invalid character",
+		})
+		assert.NoError(t, actualErr)
+
+		actualErr = repo.CreateDeadLetter(ctx, models.DeadLetter{
+			MessageId: userMsgs[0].ID,
+			Payload:   `{"ID":"1"}`,
+			Reason:    models.ReasonRetriesExhausted,
+			Error:     "failed to send message",
+		})
+		assert.NoError(t, actualErr)
+
+		actualLetters, err := repo.GetDeadLetters(ctx, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(actualLetters))
+		assert.Equal(t, userMsgs[0].ID, actualLetters[0].MessageId)
+		assert.Equal(t, `{"ID":"1"}`, actualLetters[0].Payload)
+		assert.Equal(t, models.ReasonRetriesExhausted, actualLetters[0].Reason)
+		assert.Equal(t, "failed to send message", actualLetters[0].Error)
+		assert.False(t, actualLetters[0].CreatedAt.IsZero())
+		assert.Equal(t, "", actualLetters[1].MessageId)
+		assert.Equal(t, "xyz", actualLetters[1].Payload)
+		assert.Equal(t, models.ReasonUndecodable, actualLetters[1].Reason)
+
+		actualLetter, err := repo.GetDeadLetter(ctx, actualLetters[1].ID)
+		assert.NoError(t, err)
+		assert.Equal(t, actualLetters[1], actualLetter)
+	})
+}
+
+func TestRepository_GetDeadLetter(t *testing.T) {
+	t.Run("should return DeadLetterNotExistError when dead letter not exists", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		actualLetter, actualErr := repo.GetDeadLetter(ctx, "1")
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.DeadLetterNotExistError, actualErr)
+		assert.Equal(t, models.DeadLetter{}, actualLetter)
+	})
+}
+
+func TestRepository_RequeueDeadLetter(t *testing.T) {
+	t.Run("should reschedule failed message and delete dead letter", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{
+			Name:    "AshkanAbd",
+			Balance: 0,
+		})
+		assert.NoError(t, err)
+
+		_, err = repo.CreateScheduleMessages(ctx, []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusFailed,
+				Attempts: 3,
+			},
+		})
+		assert.NoError(t, err)
+
+		userMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, true)
+		assert.NoError(t, err)
+
+		err = repo.CreateDeadLetter(ctx, models.DeadLetter{
+			MessageId: userMsgs[0].ID,
+			Payload:   `{"ID":"1"}`,
+			Reason:    models.ReasonRetriesExhausted,
+		})
+		assert.NoError(t, err)
+
+		letters, err := repo.GetDeadLetters(ctx, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(letters))
+
+		actualMsg, actualErr := repo.RequeueDeadLetter(ctx, letters[0].ID)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, userMsgs[0].ID, actualMsg.ID)
+		assert.Equal(t, models.StatusScheduled, actualMsg.Status)
+		assert.Equal(t, 0, actualMsg.Attempts)
+
+		_, err = repo.GetDeadLetter(ctx, letters[0].ID)
+		assert.Equal(t, models.DeadLetterNotExistError, err)
+	})
+
+	t.Run("should return NotRequeueableError when dead letter has no message", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		err = repo.CreateDeadLetter(ctx, models.DeadLetter{
+			Payload: "xyz",
+			Reason:  models.ReasonUndecodable,
+		})
+		assert.NoError(t, err)
+
+		letters, err := repo.GetDeadLetters(ctx, 0, 10)
+		assert.NoError(t, err)
+
+		actualMsg, actualErr := repo.RequeueDeadLetter(ctx, letters[0].ID)
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.NotRequeueableError, actualErr)
+		assert.Equal(t, models.Sms{}, actualMsg)
+
+		_, err = repo.GetDeadLetter(ctx, letters[0].ID)
+		assert.NoError(t, err)
+	})
+}
+
+func TestRepository_PurgeDeadLetters(t *testing.T) {
+	t.Run("should delete all dead letters", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		for range 3 {
+			err = repo.CreateDeadLetter(ctx, models.DeadLetter{
+				Payload: "xyz",
+				Reason:  models.ReasonUndecodable,
+			})
+			assert.NoError(t, err)
+		}
+
+		letters, err := repo.GetDeadLetters(ctx, 0, 10)
+		assert.NoError(t, err)
+
+		actualErr := repo.DeleteDeadLetter(ctx, letters[0].ID)
+		assert.NoError(t, actualErr)
+
+		actualErr = repo.DeleteDeadLetter(ctx, letters[0].ID)
+		assert.Equal(t, models.DeadLetterNotExistError, actualErr)
+
+		actualPurged, actualErr := repo.PurgeDeadLetters(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 2, actualPurged)
+
+		letters, err = repo.GetDeadLetters(ctx, 0, 10)
+		assert.NoError(t, err)
+		assert.Empty(t, letters)
+	})
+}
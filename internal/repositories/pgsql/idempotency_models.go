@@ -0,0 +1,56 @@
+package pgsql
+
+import (
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/idempotency/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+)
+
+type idempotencyKeyEntity struct {
+	Scope       string `gorm:"primaryKey"`
+	Key         string `gorm:"primaryKey"`
+	RequestHash string
+	Status      int
+	StatusCode  int
+	Response    []byte
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+}
+
+func (i *idempotencyKeyEntity) TableName() string {
+	return "idempotency_keys"
+}
+
+func fromIdempotencyKey(k models.IdempotencyKey) idempotencyKeyEntity {
+	ke := idempotencyKeyEntity{
+		Scope:       k.Scope,
+		Key:         k.Key,
+		RequestHash: k.RequestHash,
+		Status:      int(k.Status),
+		StatusCode:  k.StatusCode,
+		Response:    k.Response,
+		ExpiresAt:   k.ExpiresAt,
+	}
+
+	if k.CreateDate != nil {
+		ke.CreatedAt = k.CreatedAt
+	}
+
+	return ke
+}
+
+func toIdempotencyKey(ke idempotencyKeyEntity) models.IdempotencyKey {
+	return models.IdempotencyKey{
+		CreateDate: &shared.CreateDate{
+			CreatedAt: ke.CreatedAt,
+		},
+		Scope:       ke.Scope,
+		Key:         ke.Key,
+		RequestHash: ke.RequestHash,
+		Status:      models.KeyStatus(ke.Status),
+		StatusCode:  ke.StatusCode,
+		Response:    ke.Response,
+		ExpiresAt:   ke.ExpiresAt,
+	}
+}
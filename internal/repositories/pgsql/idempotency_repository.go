@@ -0,0 +1,100 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/idempotency/models"
+	"gorm.io/gorm"
+)
+
+// reserveKeyQuery inserts a key, taking over a stored key only when it has
+// expired. No row is returned when an unexpired key already exists.
+const reserveKeyQuery = `
+INSERT INTO idempotency_keys (scope, key, request_hash, status, status_code, response, expires_at, created_at)
+VALUES (@scope, @key, @request_hash, @status, 0, NULL, @expires_at, @created_at)
+ON CONFLICT (scope, key) DO UPDATE
+    SET request_hash = EXCLUDED.request_hash,
+        status       = EXCLUDED.status,
+        status_code  = 0,
+        response     = NULL,
+        expires_at   = EXCLUDED.expires_at,
+        created_at   = EXCLUDED.created_at
+    WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+RETURNING *`
+
+func (r *Repository) ReserveKey(
+	ctx context.Context,
+	key models.IdempotencyKey,
+) (models.IdempotencyKey, bool, error) {
+	ke := fromIdempotencyKey(key)
+	if ke.CreatedAt.IsZero() {
+		ke.CreatedAt = time.Now()
+	}
+
+	var reserved []idempotencyKeyEntity
+	err := r.db(ctx).
+		Raw(reserveKeyQuery,
+			sql.Named("scope", ke.Scope),
+			sql.Named("key", ke.Key),
+			sql.Named("request_hash", ke.RequestHash),
+			sql.Named("status", ke.Status),
+			sql.Named("expires_at", ke.ExpiresAt),
+			sql.Named("created_at", ke.CreatedAt),
+		).
+		Scan(&reserved).Error
+	if err != nil {
+		return models.IdempotencyKey{}, false, err
+	}
+	if len(reserved) > 0 {
+		return toIdempotencyKey(reserved[0]), true, nil
+	}
+
+	var existing idempotencyKeyEntity
+	err = r.db(ctx).
+		Where("scope = ? AND key = ?", ke.Scope, ke.Key).
+		First(&existing).Error
+	if err != nil {
+		// The stored key was released between both statements.
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.IdempotencyKey{}, false, models.KeyInProgressError
+		}
+
+		return models.IdempotencyKey{}, false, err
+	}
+
+	return toIdempotencyKey(existing), false, nil
+}
+
+// CompleteKey stores the response of a key in progress.
+func (r *Repository) CompleteKey(ctx context.Context, key models.IdempotencyKey) error {
+	return r.db(ctx).
+		Model(&idempotencyKeyEntity{}).
+		Where("scope = ? AND key = ? AND status = ?", key.Scope, key.Key, int(models.KeyInProgress)).
+		Updates(map[string]any{
+			"status":      int(key.Status),
+			"status_code": key.StatusCode,
+			"response":    key.Response,
+			"expires_at":  key.ExpiresAt,
+		}).Error
+}
+
+// ReleaseKey deletes a key in progress. Completed keys are kept.
+func (r *Repository) ReleaseKey(ctx context.Context, scope string, key string) error {
+	return r.db(ctx).
+		Where("scope = ? AND key = ? AND status = ?", scope, key, int(models.KeyInProgress)).
+		Delete(&idempotencyKeyEntity{}).Error
+}
+
+func (r *Repository) DeleteExpiredKeys(ctx context.Context, at time.Time) (int, error) {
+	res := r.db(ctx).
+		Where("expires_at <= ?", at).
+		Delete(&idempotencyKeyEntity{})
+	if res.Error != nil {
+		return 0, res.Error
+	}
+
+	return int(res.RowsAffected), nil
+}
@@ -0,0 +1,234 @@
+package pgsql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/idempotency/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_ReserveKey(t *testing.T) {
+	t.Run("should reserve key once and return stored key afterwards", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		now := time.Now()
+		actualKey, actualReserved, actualErr := repo.ReserveKey(ctx, models.IdempotencyKey{
+			CreateDate: &shared.CreateDate{
+				CreatedAt: now,
+			},
+			Scope:       "POST /api/user/1/sms/single",
+			Key:         "key-1",
+			RequestHash: "hash-1",
+			Status:      models.KeyInProgress,
+			ExpiresAt:   now.Add(time.Minute),
+		})
+		assert.NoError(t, actualErr)
+		assert.True(t, actualReserved)
+		assert.Equal(t, models.KeyInProgress, actualKey.Status)
+
+		actualKey, actualReserved, actualErr = repo.ReserveKey(ctx, models.IdempotencyKey{
+			CreateDate: &shared.CreateDate{
+				CreatedAt: now,
+			},
+			Scope:       "POST /api/user/1/sms/single",
+			Key:         "key-1",
+			RequestHash: "hash-2",
+			Status:      models.KeyInProgress,
+			ExpiresAt:   now.Add(time.Minute),
+		})
+		assert.NoError(t, actualErr)
+		assert.False(t, actualReserved)
+		assert.Equal(t, "hash-1", actualKey.RequestHash)
+	})
+
+	t.Run("should take over expired key", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		now := time.Now()
+		_, _, err = repo.ReserveKey(ctx, models.IdempotencyKey{
+			CreateDate: &shared.CreateDate{
+				CreatedAt: now.Add(-time.Hour),
+			},
+			Scope:       "POST /api/user/1/sms/single",
+			Key:         "key-1",
+			RequestHash: "hash-1",
+			Status:      models.KeyInProgress,
+			ExpiresAt:   now.Add(-time.Hour + time.Minute),
+		})
+		assert.NoError(t, err)
+
+		actualKey, actualReserved, actualErr := repo.ReserveKey(ctx, models.IdempotencyKey{
+			CreateDate: &shared.CreateDate{
+				CreatedAt: now,
+			},
+			Scope:       "POST /api/user/1/sms/single",
+			Key:         "key-1",
+			RequestHash: "hash-2",
+			Status:      models.KeyInProgress,
+			ExpiresAt:   now.Add(time.Minute),
+		})
+		assert.NoError(t, actualErr)
+		assert.True(t, actualReserved)
+		assert.Equal(t, "hash-2", actualKey.RequestHash)
+	})
+}
+
+func TestRepository_CompleteKey(t *testing.T) {
+	t.Run("should store response of key in progress", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		now := time.Now()
+		inputKey := models.IdempotencyKey{
+			CreateDate: &shared.CreateDate{
+				CreatedAt: now,
+			},
+			Scope:       "POST /api/user/1/sms/single",
+			Key:         "key-1",
+			RequestHash: "hash-1",
+			Status:      models.KeyInProgress,
+			ExpiresAt:   now.Add(time.Minute),
+		}
+		_, _, err = repo.ReserveKey(ctx, inputKey)
+		assert.NoError(t, err)
+
+		inputKey.Status = models.KeyCompleted
+		inputKey.StatusCode = 200
+		inputKey.Response = []byte(`{"data":null,"message":"ok"}`)
+		inputKey.ExpiresAt = now.Add(time.Hour)
+		actualErr := repo.CompleteKey(ctx, inputKey)
+		assert.NoError(t, actualErr)
+
+		actualKey, actualReserved, err := repo.ReserveKey(ctx, models.IdempotencyKey{
+			CreateDate: &shared.CreateDate{
+				CreatedAt: now,
+			},
+			Scope:       "POST /api/user/1/sms/single",
+			Key:         "key-1",
+			RequestHash: "hash-1",
+			Status:      models.KeyInProgress,
+			ExpiresAt:   now.Add(time.Minute),
+		})
+		assert.NoError(t, err)
+		assert.False(t, actualReserved)
+		assert.Equal(t, models.KeyCompleted, actualKey.Status)
+		assert.Equal(t, 200, actualKey.StatusCode)
+		assert.Equal(t, inputKey.Response, actualKey.Response)
+	})
+}
+
+func TestRepository_ReleaseKey(t *testing.T) {
+	t.Run("should delete key in progress", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		now := time.Now()
+		inputKey := models.IdempotencyKey{
+			CreateDate: &shared.CreateDate{
+				CreatedAt: now,
+			},
+			Scope:       "POST /api/user/1/sms/single",
+			Key:         "key-1",
+			RequestHash: "hash-1",
+			Status:      models.KeyInProgress,
+			ExpiresAt:   now.Add(time.Minute),
+		}
+		_, _, err = repo.ReserveKey(ctx, inputKey)
+		assert.NoError(t, err)
+
+		actualErr := repo.ReleaseKey(ctx, inputKey.Scope, inputKey.Key)
+		assert.NoError(t, actualErr)
+
+		_, actualReserved, err := repo.ReserveKey(ctx, models.IdempotencyKey{
+			CreateDate: &shared.CreateDate{
+				CreatedAt: now,
+			},
+			Scope:       "POST /api/user/1/sms/single",
+			Key:         "key-1",
+			RequestHash: "hash-2",
+			Status:      models.KeyInProgress,
+			ExpiresAt:   now.Add(time.Minute),
+		})
+		assert.NoError(t, err)
+		assert.True(t, actualReserved)
+	})
+}
+
+func TestRepository_DeleteExpiredKeys(t *testing.T) {
+	t.Run("should delete only expired keys", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		now := time.Now()
+		expiredKey := models.IdempotencyKey{
+			CreateDate: &shared.CreateDate{
+				CreatedAt: now.Add(-time.Hour),
+			},
+			Scope:       "POST /api/user/1/sms/single",
+			Key:         "key-1",
+			RequestHash: "hash-1",
+			Status:      models.KeyInProgress,
+			ExpiresAt:   now.Add(-time.Hour + time.Minute),
+		}
+		_, _, err = repo.ReserveKey(ctx, expiredKey)
+		assert.NoError(t, err)
+
+		liveKey := models.IdempotencyKey{
+			CreateDate: &shared.CreateDate{
+				CreatedAt: now,
+			},
+			Scope:       "POST /api/user/1/sms/single",
+			Key:         "key-1",
+			RequestHash: "hash-2",
+			Status:      models.KeyInProgress,
+			ExpiresAt:   now.Add(time.Minute),
+		}
+		liveKey.Key = "key-2"
+		_, _, err = repo.ReserveKey(ctx, liveKey)
+		assert.NoError(t, err)
+
+		actualDeleted, actualErr := repo.DeleteExpiredKeys(ctx, now)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 1, actualDeleted)
+	})
+}
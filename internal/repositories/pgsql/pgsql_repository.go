@@ -1,14 +1,25 @@
 package pgsql
 
 import (
+	"context"
+	"sync"
+
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+
 	pkgPgSql "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/pgsql"
 )
 
+type txKey struct{}
+
 type Repository struct {
 	conn *gorm.DB
+
+	enqueueM sync.Mutex
+	// enqueueCursors are the last users served of each lane by EnqueueMessages.
+	enqueueCursors map[models.SmsPriority]uint
 }
 
 func NewRepository(pgsqlConn *pkgPgSql.Connector) (*Repository, error) {
@@ -22,6 +33,24 @@ func NewRepository(pgsqlConn *pkgPgSql.Connector) (*Repository, error) {
 	}
 
 	return &Repository{
-		conn: conn,
+		conn:           conn,
+		enqueueCursors: make(map[models.SmsPriority]uint),
 	}, nil
 }
+
+// Do runs fn in a transaction. Repository calls made with the context given
+// to fn join the transaction, and their own transactions become savepoints.
+func (r *Repository) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.db(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// db returns the transaction carried by ctx, or the connection when there is none.
+func (r *Repository) db(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+
+	return r.conn.WithContext(ctx)
+}
@@ -0,0 +1,90 @@
+package pgsql_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	"github.com/stretchr/testify/assert"
+
+	umodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+)
+
+func TestRepository_Do(t *testing.T) {
+	t.Run("should commit balance debit and scheduled messages together", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{Name: "AshkanAbd", Balance: 1000})
+		assert.NoError(t, err)
+
+		actualErr := repo.Do(ctx, func(txCtx context.Context) error {
+			if _, err := repo.UpdateUserBalance(txCtx, createdUser.ID, []umodels.BalanceTransaction{
+				{Type: umodels.TransactionMessageCharge, Amount: -100},
+			}); err != nil {
+				return err
+			}
+
+			_, err := repo.CreateScheduleMessages(txCtx, []models.Sms{
+				{
+					UserId:   createdUser.ID,
+					Content:  "Test Content 1",
+					Receiver: "09123456789",
+					Cost:     100,
+					Status:   models.StatusScheduled,
+				},
+			})
+			return err
+		})
+		assert.NoError(t, actualErr)
+
+		actualUser, err := repo.GetUser(ctx, createdUser.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(900), actualUser.Balance)
+
+		actualMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, false)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(actualMsgs))
+	})
+
+	t.Run("should roll back balance debit when scheduling fails", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{Name: "AshkanAbd", Balance: 1000})
+		assert.NoError(t, err)
+
+		expectedErr := fmt.Errorf("some error")
+
+		actualErr := repo.Do(ctx, func(txCtx context.Context) error {
+			if _, err := repo.UpdateUserBalance(txCtx, createdUser.ID, []umodels.BalanceTransaction{
+				{Type: umodels.TransactionMessageCharge, Amount: -100},
+			}); err != nil {
+				return err
+			}
+
+			return expectedErr
+		})
+		assert.Error(t, actualErr)
+		assert.Equal(t, expectedErr, actualErr)
+
+		actualUser, err := repo.GetUser(ctx, createdUser.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1000), actualUser.Balance)
+	})
+}
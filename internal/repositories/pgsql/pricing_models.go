@@ -0,0 +1,129 @@
+package pgsql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/common"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/pricing/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+)
+
+type priceListEntity struct {
+	ID        uint
+	Name      string
+	IsDefault bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (p *priceListEntity) TableName() string {
+	return "price_lists"
+}
+
+func fromPriceList(p models.PriceList) priceListEntity {
+	pe := priceListEntity{
+		Name:      p.Name,
+		IsDefault: p.IsDefault,
+	}
+
+	if p.Entity != nil {
+		pe.ID = common.ParseUIntWithFallback(p.ID, 0)
+	}
+	if p.CreateDate != nil {
+		pe.CreatedAt = p.CreatedAt
+	}
+	if p.UpdateDate != nil {
+		pe.UpdatedAt = p.UpdatedAt
+	}
+
+	return pe
+}
+
+func toPriceList(pe priceListEntity) models.PriceList {
+	return models.PriceList{
+		Entity: &shared.Entity{
+			ID: fmt.Sprintf("%d", pe.ID),
+		},
+		CreateDate: &shared.CreateDate{
+			CreatedAt: pe.CreatedAt,
+		},
+		UpdateDate: &shared.UpdateDate{
+			UpdatedAt: pe.UpdatedAt,
+		},
+		Name:      pe.Name,
+		IsDefault: pe.IsDefault,
+	}
+}
+
+type priceEntity struct {
+	ID            uint
+	PriceListId   *uint
+	UserId        *uint
+	Prefix        string
+	Price         int
+	EffectiveFrom time.Time
+	CreatedAt     time.Time
+}
+
+func (p *priceEntity) TableName() string {
+	return "prices"
+}
+
+func fromPrice(p models.Price) priceEntity {
+	pe := priceEntity{
+		Prefix:        p.Prefix,
+		Price:         p.Price,
+		EffectiveFrom: p.EffectiveFrom,
+	}
+
+	if p.PriceListId != "" {
+		priceListId := common.ParseUIntWithFallback(p.PriceListId, 0)
+		pe.PriceListId = &priceListId
+	}
+	if p.UserId != "" {
+		userId := common.ParseUIntWithFallback(p.UserId, 0)
+		pe.UserId = &userId
+	}
+	if p.Entity != nil {
+		pe.ID = common.ParseUIntWithFallback(p.ID, 0)
+	}
+	if p.CreateDate != nil {
+		pe.CreatedAt = p.CreatedAt
+	}
+
+	return pe
+}
+
+func toPrice(pe priceEntity) models.Price {
+	p := models.Price{
+		Entity: &shared.Entity{
+			ID: fmt.Sprintf("%d", pe.ID),
+		},
+		CreateDate: &shared.CreateDate{
+			CreatedAt: pe.CreatedAt,
+		},
+		Prefix:        pe.Prefix,
+		Price:         pe.Price,
+		EffectiveFrom: pe.EffectiveFrom,
+	}
+
+	if pe.PriceListId != nil {
+		p.PriceListId = fmt.Sprintf("%d", *pe.PriceListId)
+	}
+	if pe.UserId != nil {
+		p.UserId = fmt.Sprintf("%d", *pe.UserId)
+	}
+
+	return p
+}
+
+type userPriceListEntity struct {
+	UserId      uint `gorm:"primaryKey"`
+	PriceListId uint
+	CreatedAt   time.Time
+}
+
+func (u *userPriceListEntity) TableName() string {
+	return "user_price_lists"
+}
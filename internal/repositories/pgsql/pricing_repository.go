@@ -0,0 +1,209 @@
+package pgsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/common"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/pricing/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// effectivePricesQuery selects, for every prefix of a scope, its latest price
+// that has taken effect.
+const effectivePricesQuery = `
+SELECT DISTINCT ON (prefix) *
+FROM prices
+WHERE %s AND effective_from <= @at
+ORDER BY prefix, effective_from DESC, id DESC`
+
+// CreatePriceList creates a price list. A new default price list replaces the
+// current one.
+func (r *Repository) CreatePriceList(ctx context.Context, list models.PriceList) (models.PriceList, error) {
+	pe := fromPriceList(list)
+
+	err := r.db(ctx).Transaction(func(tx *gorm.DB) error {
+		if pe.IsDefault {
+			err := tx.WithContext(ctx).
+				Model(&priceListEntity{}).
+				Where("is_default").
+				Updates(map[string]any{
+					"is_default": false,
+					"updated_at": time.Now(),
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.WithContext(ctx).Create(&pe).Error
+	})
+	if err != nil {
+		return models.PriceList{}, mapPricingError(err)
+	}
+
+	return toPriceList(pe), nil
+}
+
+func (r *Repository) GetPriceLists(ctx context.Context, skip int, limit int) ([]models.PriceList, error) {
+	var pes []priceListEntity
+
+	err := r.db(ctx).
+		Order("id").
+		Limit(limit).
+		Offset(skip).
+		Find(&pes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ps := make([]models.PriceList, len(pes))
+	for i := range pes {
+		ps[i] = toPriceList(pes[i])
+	}
+
+	return ps, nil
+}
+
+func (r *Repository) CreatePrices(ctx context.Context, prices []models.Price) ([]models.Price, error) {
+	if len(prices) == 0 {
+		return nil, nil
+	}
+
+	pes := make([]priceEntity, len(prices))
+	for i := range prices {
+		pes[i] = fromPrice(prices[i])
+	}
+
+	if err := r.db(ctx).Create(&pes).Error; err != nil {
+		return nil, mapPricingError(err)
+	}
+
+	ps := make([]models.Price, len(pes))
+	for i := range pes {
+		ps[i] = toPrice(pes[i])
+	}
+
+	return ps, nil
+}
+
+func (r *Repository) GetPrices(ctx context.Context, filter models.PriceFilter, skip int, limit int) ([]models.Price, error) {
+	var pes []priceEntity
+
+	query := r.db(ctx)
+	if filter.PriceListId != "" {
+		query = query.Where("price_list_id = ?", filter.PriceListId)
+	}
+	if filter.UserId != "" {
+		query = query.Where("user_id = ?", filter.UserId)
+	}
+
+	err := query.
+		Order("prefix, effective_from DESC, id DESC").
+		Limit(limit).
+		Offset(skip).
+		Find(&pes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ps := make([]models.Price, len(pes))
+	for i := range pes {
+		ps[i] = toPrice(pes[i])
+	}
+
+	return ps, nil
+}
+
+func (r *Repository) DeletePrice(ctx context.Context, id string) error {
+	res := r.db(ctx).Delete(&priceEntity{}, "id = ?", id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return models.PriceNotExistError
+	}
+
+	return nil
+}
+
+// AssignUserPriceList assigns a price list to the user, replacing the current
+// one. An empty priceListId unassigns it.
+func (r *Repository) AssignUserPriceList(ctx context.Context, userId string, priceListId string) error {
+	if priceListId == "" {
+		return r.db(ctx).Delete(&userPriceListEntity{}, "user_id = ?", userId).Error
+	}
+
+	ue := userPriceListEntity{
+		UserId:      common.ParseUIntWithFallback(userId, 0),
+		PriceListId: common.ParseUIntWithFallback(priceListId, 0),
+		CreatedAt:   time.Now(),
+	}
+
+	err := r.db(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"price_list_id", "created_at"}),
+		}).
+		Create(&ue).Error
+	if err != nil {
+		return mapPricingError(err)
+	}
+
+	return nil
+}
+
+// GetPriceBook returns the prices in effect at the given time from the user
+// overrides, the user price list and the default price list.
+func (r *Repository) GetPriceBook(ctx context.Context, userId string, at time.Time) (models.PriceBook, error) {
+	scopes := []string{
+		"user_id = @user",
+		"price_list_id = (SELECT price_list_id FROM user_price_lists WHERE user_id = @user)",
+		"price_list_id = (SELECT id FROM price_lists WHERE is_default)",
+	}
+
+	res := make([][]models.Price, len(scopes))
+	for i := range scopes {
+		var pes []priceEntity
+
+		err := r.db(ctx).
+			Raw(fmt.Sprintf(effectivePricesQuery, scopes[i]), sql.Named("user", userId), sql.Named("at", at)).
+			Scan(&pes).Error
+		if err != nil {
+			return models.PriceBook{}, err
+		}
+
+		res[i] = make([]models.Price, len(pes))
+		for j := range pes {
+			res[i][j] = toPrice(pes[j])
+		}
+	}
+
+	return models.PriceBook{
+		Overrides: res[0],
+		Plan:      res[1],
+		Default:   res[2],
+	}, nil
+}
+
+func mapPricingError(err error) error {
+	switch {
+	case strings.Contains(err.Error(), "price_list_name_empty"):
+		return models.EmptyPriceListNameError
+	case strings.Contains(err.Error(), "fk_price_lists_prices"),
+		strings.Contains(err.Error(), "fk_price_lists_user_price_lists"):
+		return models.PriceListNotExistError
+	case strings.Contains(err.Error(), "price_scope_invalid"):
+		return models.InvalidPriceScopeError
+	case strings.Contains(err.Error(), "price_prefix_invalid"):
+		return models.InvalidPrefixError
+	case strings.Contains(err.Error(), "price_negative"):
+		return models.InvalidPriceError
+	default:
+		return err
+	}
+}
@@ -0,0 +1,174 @@
+package pgsql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/pricing/models"
+	"github.com/stretchr/testify/assert"
+
+	umodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+)
+
+func TestRepository_CreatePriceList(t *testing.T) {
+	t.Run("should replace the default price list", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		_, err = repo.CreatePriceList(ctx, models.PriceList{Name: "Standard", IsDefault: true})
+		assert.NoError(t, err)
+
+		_, err = repo.CreatePriceList(ctx, models.PriceList{Name: "Premium", IsDefault: true})
+		assert.NoError(t, err)
+
+		lists, err := repo.GetPriceLists(ctx, 0, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(lists))
+		assert.False(t, lists[0].IsDefault)
+		assert.True(t, lists[1].IsDefault)
+	})
+
+	t.Run("should return EmptyPriceListNameError when name is empty", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		_, actualErr := repo.CreatePriceList(ctx, models.PriceList{})
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.EmptyPriceListNameError, actualErr)
+	})
+}
+
+func TestRepository_CreatePrices(t *testing.T) {
+	t.Run("should return PriceListNotExistError when price list does not exist", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		_, actualErr := repo.CreatePrices(ctx, []models.Price{
+			{PriceListId: "1000", Prefix: "98", Price: 100, EffectiveFrom: time.Now()},
+		})
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.PriceListNotExistError, actualErr)
+	})
+}
+
+func TestRepository_DeletePrice(t *testing.T) {
+	t.Run("should delete price", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		list, err := repo.CreatePriceList(ctx, models.PriceList{Name: "Standard"})
+		assert.NoError(t, err)
+
+		prices, err := repo.CreatePrices(ctx, []models.Price{
+			{PriceListId: list.ID, Prefix: "98", Price: 100, EffectiveFrom: time.Now()},
+		})
+		assert.NoError(t, err)
+
+		actualErr := repo.DeletePrice(ctx, prices[0].ID)
+		assert.NoError(t, actualErr)
+
+		actualErr = repo.DeletePrice(ctx, prices[0].ID)
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.PriceNotExistError, actualErr)
+	})
+}
+
+func TestRepository_GetPriceBook(t *testing.T) {
+	t.Run("should return prices in effect by source", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{Name: "AshkanAbd"})
+		assert.NoError(t, err)
+
+		defaultList, err := repo.CreatePriceList(ctx, models.PriceList{Name: "Standard", IsDefault: true})
+		assert.NoError(t, err)
+		planList, err := repo.CreatePriceList(ctx, models.PriceList{Name: "Premium"})
+		assert.NoError(t, err)
+
+		now := time.Now()
+		_, err = repo.CreatePrices(ctx, []models.Price{
+			{PriceListId: defaultList.ID, Prefix: "98", Price: 150, EffectiveFrom: now.Add(-2 * time.Hour)},
+			{PriceListId: defaultList.ID, Prefix: "98", Price: 160, EffectiveFrom: now.Add(-time.Hour)},
+			{PriceListId: defaultList.ID, Prefix: "98", Price: 170, EffectiveFrom: now.Add(time.Hour)},
+			{PriceListId: planList.ID, Prefix: "98912", Price: 110, EffectiveFrom: now.Add(-time.Hour)},
+			{UserId: createdUser.ID, Prefix: "98935", Price: 80, EffectiveFrom: now.Add(-time.Hour)},
+		})
+		assert.NoError(t, err)
+
+		err = repo.AssignUserPriceList(ctx, createdUser.ID, planList.ID)
+		assert.NoError(t, err)
+
+		book, actualErr := repo.GetPriceBook(ctx, createdUser.ID, now)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 1, len(book.Overrides))
+		assert.Equal(t, 80, book.Overrides[0].Price)
+		assert.Equal(t, 1, len(book.Plan))
+		assert.Equal(t, 110, book.Plan[0].Price)
+		assert.Equal(t, 1, len(book.Default))
+		assert.Equal(t, 160, book.Default[0].Price)
+
+		err = repo.AssignUserPriceList(ctx, createdUser.ID, "")
+		assert.NoError(t, err)
+
+		book, actualErr = repo.GetPriceBook(ctx, createdUser.ID, now.Add(2*time.Hour))
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 0, len(book.Plan))
+		assert.Equal(t, 170, book.Default[0].Price)
+	})
+
+	t.Run("should return PriceListNotExistError when assigning a missing price list", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{Name: "AshkanAbd"})
+		assert.NoError(t, err)
+
+		actualErr := repo.AssignUserPriceList(ctx, createdUser.ID, "1000")
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.PriceListNotExistError, actualErr)
+	})
+}
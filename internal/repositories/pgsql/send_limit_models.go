@@ -0,0 +1,44 @@
+package pgsql
+
+import (
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/common"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/models"
+)
+
+type sendLimitEntity struct {
+	UserId      uint `gorm:"primaryKey"`
+	PerSecond   int
+	PerMinute   int
+	Daily       int
+	Monthly     int
+	MaxBulkSize int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (s *sendLimitEntity) TableName() string {
+	return "send_limits"
+}
+
+func fromLimits(userId string, l models.Limits) sendLimitEntity {
+	return sendLimitEntity{
+		UserId:      common.ParseUIntWithFallback(userId, 0),
+		PerSecond:   l.PerSecond,
+		PerMinute:   l.PerMinute,
+		Daily:       l.Daily,
+		Monthly:     l.Monthly,
+		MaxBulkSize: l.MaxBulkSize,
+	}
+}
+
+func toLimits(se sendLimitEntity) models.Limits {
+	return models.Limits{
+		PerSecond:   se.PerSecond,
+		PerMinute:   se.PerMinute,
+		Daily:       se.Daily,
+		Monthly:     se.Monthly,
+		MaxBulkSize: se.MaxBulkSize,
+	}
+}
@@ -0,0 +1,51 @@
+package pgsql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *Repository) GetUserLimits(ctx context.Context, userId string) (models.Limits, error) {
+	se := sendLimitEntity{}
+
+	err := r.db(ctx).First(&se, "user_id = ?", userId).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Limits{}, models.LimitsNotExistError
+		}
+
+		return models.Limits{}, err
+	}
+
+	return toLimits(se), nil
+}
+
+// SetUserLimits stores the limits of a user, replacing the ones it had.
+func (r *Repository) SetUserLimits(ctx context.Context, userId string, limits models.Limits) (models.Limits, error) {
+	se := fromLimits(userId, limits)
+	se.CreatedAt = time.Now()
+	se.UpdatedAt = se.CreatedAt
+
+	err := r.db(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"per_second", "per_minute", "daily", "monthly", "max_bulk_size", "updated_at",
+			}),
+		}).
+		Create(&se).Error
+	if err != nil {
+		if strings.Contains(err.Error(), "send_limits_positive") {
+			return models.Limits{}, models.InvalidLimitsError
+		}
+		return models.Limits{}, err
+	}
+
+	return toLimits(se), nil
+}
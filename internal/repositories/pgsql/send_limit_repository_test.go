@@ -0,0 +1,62 @@
+package pgsql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/models"
+	"github.com/stretchr/testify/assert"
+
+	umodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+)
+
+func TestRepository_SetUserLimits(t *testing.T) {
+	t.Run("should store limits and replace them", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		user, err := repo.CreateUser(ctx, umodels.User{Name: "AshkanAbd"})
+		assert.NoError(t, err)
+
+		_, actualErr := repo.GetUserLimits(ctx, user.ID)
+		assert.Equal(t, models.LimitsNotExistError, actualErr)
+
+		expectedLimits := models.Limits{PerSecond: 10, PerMinute: 300, Daily: 1000, Monthly: 20000, MaxBulkSize: 100}
+		actualLimits, actualErr := repo.SetUserLimits(ctx, user.ID, expectedLimits)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedLimits, actualLimits)
+
+		expectedLimits = models.Limits{Daily: 50}
+		_, actualErr = repo.SetUserLimits(ctx, user.ID, expectedLimits)
+		assert.NoError(t, actualErr)
+
+		actualLimits, actualErr = repo.GetUserLimits(ctx, user.ID)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedLimits, actualLimits)
+	})
+
+	t.Run("should return InvalidLimitsError when a limit is negative", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		user, err := repo.CreateUser(ctx, umodels.User{Name: "AshkanAbd"})
+		assert.NoError(t, err)
+
+		_, actualErr := repo.SetUserLimits(ctx, user.ID, models.Limits{Daily: -1})
+		assert.Equal(t, models.InvalidLimitsError, actualErr)
+	})
+}
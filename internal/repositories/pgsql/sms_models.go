@@ -2,6 +2,7 @@ package pgsql
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/AshkanAbd/arvancloud_sms_gateway/common"
@@ -10,14 +11,29 @@ import (
 )
 
 type smsEntity struct {
-	ID        uint
-	UserId    uint
-	Content   string
-	Receiver  string
-	Cost      int
-	Status    int
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID            uint
+	UserId        uint
+	Content       string
+	Receiver      string
+	Country       string
+	Operator      string
+	Encoding      int
+	Segments      int `gorm:"default:1"`
+	Cost          int
+	Status        int
+	Tags          string
+	Provider      string
+	SendAt        time.Time
+	Priority      int
+	Attempts      int
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+
+	ProviderMessageId string
+	SentAt            *time.Time
+	DoneAt            *time.Time
+	DeliveryError     string
 }
 
 func (u *smsEntity) TableName() string {
@@ -26,13 +42,32 @@ func (u *smsEntity) TableName() string {
 
 func fromMessage(s models.Sms) smsEntity {
 	se := smsEntity{
-		UserId:   common.ParseUIntWithFallback(s.UserId, 0),
-		Content:  s.Content,
-		Receiver: s.Receiver,
-		Cost:     s.Cost,
-		Status:   int(s.Status),
+		UserId:        common.ParseUIntWithFallback(s.UserId, 0),
+		Content:       s.Content,
+		Receiver:      s.Receiver,
+		Country:       s.Country,
+		Operator:      s.Operator,
+		Encoding:      int(s.Encoding),
+		Segments:      s.Segments,
+		Cost:          s.Cost,
+		Status:        int(s.Status),
+		Tags:          strings.Join(s.Tags, ","),
+		Provider:      s.Provider,
+		SendAt:        s.SendAt,
+		Priority:      int(s.Priority),
+		Attempts:      s.Attempts,
+		NextAttemptAt: s.NextAttemptAt,
+
+		ProviderMessageId: s.ProviderMessageId,
+		DeliveryError:     s.DeliveryError,
 	}
 
+	if !s.SentAt.IsZero() {
+		se.SentAt = &s.SentAt
+	}
+	if !s.DoneAt.IsZero() {
+		se.DoneAt = &s.DoneAt
+	}
 	if s.Entity != nil {
 		se.ID = common.ParseUIntWithFallback(s.ID, 0)
 	}
@@ -47,7 +82,7 @@ func fromMessage(s models.Sms) smsEntity {
 }
 
 func toMessage(se smsEntity) models.Sms {
-	return models.Sms{
+	s := models.Sms{
 		Entity: &shared.Entity{
 			ID: fmt.Sprintf("%d", se.ID),
 		},
@@ -57,10 +92,40 @@ func toMessage(se smsEntity) models.Sms {
 		UpdateDate: &shared.UpdateDate{
 			UpdatedAt: se.UpdatedAt,
 		},
-		UserId:   fmt.Sprintf("%d", se.UserId),
-		Content:  se.Content,
-		Receiver: se.Receiver,
-		Cost:     se.Cost,
-		Status:   models.SmsStatus(se.Status),
+		UserId:        fmt.Sprintf("%d", se.UserId),
+		Content:       se.Content,
+		Receiver:      se.Receiver,
+		Country:       se.Country,
+		Operator:      se.Operator,
+		Encoding:      models.SmsEncoding(se.Encoding),
+		Segments:      se.Segments,
+		Cost:          se.Cost,
+		Status:        models.SmsStatus(se.Status),
+		Tags:          splitTags(se.Tags),
+		Provider:      se.Provider,
+		SendAt:        se.SendAt,
+		Priority:      models.SmsPriority(se.Priority),
+		Attempts:      se.Attempts,
+		NextAttemptAt: se.NextAttemptAt,
+
+		ProviderMessageId: se.ProviderMessageId,
+		DeliveryError:     se.DeliveryError,
+	}
+
+	if se.SentAt != nil {
+		s.SentAt = *se.SentAt
 	}
+	if se.DoneAt != nil {
+		s.DoneAt = *se.DoneAt
+	}
+
+	return s
+}
+
+func splitTags(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+
+	return strings.Split(tags, ",")
 }
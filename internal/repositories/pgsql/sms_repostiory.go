@@ -1,8 +1,10 @@
 package pgsql
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"slices"
 	"strings"
 	"time"
 
@@ -11,13 +13,20 @@ import (
 	"gorm.io/gorm/clause"
 )
 
-func (r *Repository) CreateScheduleMessages(ctx context.Context, msgs []models.Sms) error {
+func (r *Repository) CreateScheduleMessages(ctx context.Context, msgs []models.Sms) ([]models.Sms, error) {
+	now := time.Now()
 	ses := make([]smsEntity, len(msgs))
 	for i := range msgs {
 		ses[i] = fromMessage(msgs[i])
+		if ses[i].SendAt.IsZero() {
+			ses[i].SendAt = now
+		}
+		if ses[i].NextAttemptAt.IsZero() {
+			ses[i].NextAttemptAt = ses[i].SendAt
+		}
 	}
 
-	err := r.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err := r.db(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.WithContext(ctx).Create(ses).
 			Error; err != nil {
 			return err
@@ -28,21 +37,26 @@ func (r *Repository) CreateScheduleMessages(ctx context.Context, msgs []models.S
 
 	if err != nil {
 		if strings.Contains(err.Error(), "message_content_empty") {
-			return models.EmptyContentError
+			return nil, models.EmptyContentError
 		}
 		if strings.Contains(err.Error(), "message_receiver_empty") {
-			return models.EmptyReceiverError
+			return nil, models.EmptyReceiverError
 		}
-		return err
+		return nil, err
 	}
 
-	return nil
+	ss := make([]models.Sms, len(ses))
+	for i := range ses {
+		ss[i] = toMessage(ses[i])
+	}
+
+	return ss, nil
 }
 
 func (r *Repository) GetMessagesByUserId(ctx context.Context, userId string, skip int, limit int, desc bool) ([]models.Sms, error) {
 	var ses []smsEntity
 
-	query := r.conn.WithContext(ctx).
+	query := r.db(ctx).
 		Where("user_id = ?", userId).
 		Limit(limit).
 		Offset(skip)
@@ -69,7 +83,7 @@ func (r *Repository) GetMessagesByUserId(ctx context.Context, userId string, ski
 func (r *Repository) SetMessageAsFailed(ctx context.Context, id string) (models.Sms, error) {
 	se := smsEntity{}
 
-	res := r.conn.WithContext(ctx).
+	res := r.db(ctx).
 		Model(&se).
 		Clauses(clause.Returning{}).
 		Where("id = ? AND status = ?", id, models.StatusEnqueued).
@@ -89,16 +103,48 @@ func (r *Repository) SetMessageAsFailed(ctx context.Context, id string) (models.
 	return toMessage(se), nil
 }
 
-func (r *Repository) SetMessageAsSent(ctx context.Context, id string) (models.Sms, error) {
+func (r *Repository) SetMessageAsSent(ctx context.Context, id string, res models.SendResult) (models.Sms, error) {
 	se := smsEntity{}
+	now := time.Now()
 
-	res := r.conn.WithContext(ctx).
+	updateRes := r.db(ctx).
 		Model(&se).
 		Clauses(clause.Returning{}).
 		Where("id = ? AND status = ?", id, models.StatusEnqueued).
 		Updates(map[string]any{
-			"status":     models.StatusSent,
-			"updated_at": time.Now(),
+			"status":              models.StatusSent,
+			"provider":            res.Provider,
+			"provider_message_id": res.MessageId,
+			"sent_at":             now,
+			"updated_at":          now,
+		})
+
+	if updateRes.Error != nil {
+		return models.Sms{}, updateRes.Error
+	}
+
+	if updateRes.RowsAffected == 0 {
+		return models.Sms{}, models.MessageNotExistError
+	}
+
+	return toMessage(se), nil
+}
+
+// SetMessageDeliveryState moves the sent message the provider knows by
+// report.MessageId to its final delivery state. Reports of messages already
+// in a final state are ignored with MessageNotExistError.
+func (r *Repository) SetMessageDeliveryState(ctx context.Context, report models.DeliveryReport) (models.Sms, error) {
+	se := smsEntity{}
+
+	res := r.db(ctx).
+		Model(&se).
+		Clauses(clause.Returning{}).
+		Where("provider = ? AND provider_message_id = ? AND status = ?", report.Provider, report.MessageId, models.StatusSent).
+		Updates(map[string]any{
+			"status":         report.Status,
+			"done_at":        report.DoneAt,
+			"delivery_error": report.Error,
+			"updated_at":     time.Now(),
 		})
 
 	if res.Error != nil {
@@ -112,31 +158,136 @@ func (r *Repository) SetMessageAsSent(ctx context.Context, id string) (models.Sm
 	return toMessage(se), nil
 }
 
+func (r *Repository) SetMessageAsRetrying(ctx context.Context, id string, nextAttemptAt time.Time) (models.Sms, error) {
+	se := smsEntity{}
+
+	res := r.db(ctx).
+		Model(&se).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND status = ?", id, models.StatusEnqueued).
+		Updates(map[string]any{
+			"status":          models.StatusRetrying,
+			"next_attempt_at": nextAttemptAt,
+			"updated_at":      time.Now(),
+		})
+
+	if res.Error != nil {
+		return models.Sms{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return models.Sms{}, models.MessageNotExistError
+	}
+
+	return toMessage(se), nil
+}
+
+// fairEnqueueQuery enqueues the due messages of one priority lane with
+// weighted fair queuing across users. Users with due messages in the lane are
+// found with a loose index scan over messages_due_users_idx, starting after the
+// last user served and wrapping around, and at most limit of them are visited.
+// Each user's oldest due messages are ranked by their position divided by the
+// user weight, so a large backlog of one user only takes its share of the
+// slots. Rows are locked after the ranking with SKIP LOCKED, so concurrent
+// gateways never enqueue a message twice.
+const fairEnqueueQuery = `
+WITH RECURSIVE after_users AS (
+	(
+		SELECT user_id FROM messages
+		WHERE priority = @priority AND status IN @due AND next_attempt_at <= @now AND user_id > @after
+		ORDER BY user_id
+		LIMIT 1
+	)
+	UNION ALL
+	SELECT (
+		SELECT m.user_id FROM messages m
+		WHERE m.priority = @priority AND m.status IN @due AND m.next_attempt_at <= @now AND m.user_id > a.user_id
+		ORDER BY m.user_id
+		LIMIT 1
+	)
+	FROM after_users a
+	WHERE a.user_id IS NOT NULL
+), before_users AS (
+	(
+		SELECT user_id FROM messages
+		WHERE priority = @priority AND status IN @due AND next_attempt_at <= @now AND user_id <= @after
+		ORDER BY user_id
+		LIMIT 1
+	)
+	UNION ALL
+	SELECT (
+		SELECT m.user_id FROM messages m
+		WHERE m.priority = @priority AND m.status IN @due AND m.next_attempt_at <= @now
+			AND m.user_id > b.user_id AND m.user_id <= @after
+		ORDER BY m.user_id
+		LIMIT 1
+	)
+	FROM before_users b
+	WHERE b.user_id IS NOT NULL
+), due_users AS (
+	SELECT user_id FROM (
+		SELECT user_id FROM after_users WHERE user_id IS NOT NULL
+		UNION ALL
+		SELECT user_id FROM before_users WHERE user_id IS NOT NULL
+	) candidates
+	LIMIT @limit
+)
+UPDATE messages
+SET status = @enqueued, attempts = attempts + 1, updated_at = @now
+WHERE id IN (
+	SELECT id FROM messages
+	WHERE id IN (
+		SELECT due.id
+		FROM due_users
+		JOIN users u ON u.id = due_users.user_id
+		CROSS JOIN LATERAL (
+			SELECT id, next_attempt_at, ROW_NUMBER() OVER (ORDER BY next_attempt_at, id) AS position
+			FROM messages
+			WHERE user_id = u.id AND priority = @priority AND status IN @due AND next_attempt_at <= @now
+			ORDER BY next_attempt_at, id
+			LIMIT @limit
+		) due
+		ORDER BY due.position::float8 / u.enqueue_weight, due.next_attempt_at, due.id
+		LIMIT @limit
+	) AND status IN @due
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING *`
+
+// EnqueueMessages marks up to count due messages as enqueued. Higher
+// priorities are taken first, so a bulk backlog does not delay urgent ones,
+// and each lane is shared fairly between users.
 func (r *Repository) EnqueueMessages(ctx context.Context, count int) ([]models.Sms, error) {
 	var ses []smsEntity
 
-	err := r.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		res := tx.WithContext(ctx).
-			Model(&ses).
-			Where("id IN (?)",
-				tx.WithContext(ctx).
-					Model(&smsEntity{}).
-					Select("id").
-					Where("status", models.StatusScheduled).
-					Order("created_at ASC, id ASC").
-					Limit(count).
-					Clauses(clause.Locking{
-						Strength: "UPDATE",
-						Options:  "SKIP LOCKED",
-					}),
-			).Clauses(clause.Returning{}).
-			Updates(map[string]any{
-				"status":     models.StatusEnqueued,
-				"updated_at": time.Now(),
+	err := r.db(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		for _, priority := range models.Priorities {
+			if len(ses) >= count {
+				break
+			}
+
+			var laneSes []smsEntity
+			res := tx.WithContext(ctx).
+				Raw(fairEnqueueQuery,
+					sql.Named("enqueued", models.StatusEnqueued),
+					sql.Named("now", now),
+					sql.Named("priority", priority),
+					sql.Named("due", []models.SmsStatus{models.StatusScheduled, models.StatusRetrying}),
+					sql.Named("limit", count-len(ses)),
+					sql.Named("after", r.enqueueCursor(priority)),
+				).
+				Scan(&laneSes)
+
+			if res.Error != nil {
+				return res.Error
+			}
+			r.advanceEnqueueCursor(priority, laneSes)
+			// RETURNING has no defined order
+			slices.SortFunc(laneSes, func(a, b smsEntity) int {
+				return cmp.Compare(a.ID, b.ID)
 			})
-
-		if res.Error != nil {
-			return res.Error
+			ses = append(ses, laneSes...)
 		}
 
 		return nil
@@ -156,14 +307,63 @@ func (r *Repository) EnqueueMessages(ctx context.Context, count int) ([]models.S
 	return ss, nil
 }
 
+func (r *Repository) enqueueCursor(priority models.SmsPriority) uint {
+	r.enqueueM.Lock()
+	defer r.enqueueM.Unlock()
+
+	return r.enqueueCursors[priority]
+}
+
+// advanceEnqueueCursor moves the cursor of a lane to the last user served in
+// the wrapped scan order, so users the scan did not reach come first next time.
+func (r *Repository) advanceEnqueueCursor(priority models.SmsPriority, ses []smsEntity) {
+	r.enqueueM.Lock()
+	defer r.enqueueM.Unlock()
+
+	after := r.enqueueCursors[priority]
+	var last uint
+	wrapped := false
+	for i := range ses {
+		userId := ses[i].UserId
+		switch {
+		case userId <= after && (!wrapped || userId > last):
+			last, wrapped = userId, true
+		case userId > after && !wrapped && userId > last:
+			last = userId
+		}
+	}
+	r.enqueueCursors[priority] = last
+}
+
+func (r *Repository) GetStaleEnqueuedMessages(ctx context.Context, enqueuedBefore time.Time, limit int) ([]models.Sms, error) {
+	var ses []smsEntity
+
+	err := r.db(ctx).
+		Where("status = ? AND updated_at < ?", models.StatusEnqueued, enqueuedBefore).
+		Order("updated_at ASC, id ASC").
+		Limit(limit).
+		Find(&ses).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ss := make([]models.Sms, len(ses))
+	for i := range ses {
+		ss[i] = toMessage(ses[i])
+	}
+
+	return ss, nil
+}
+
 func (r *Repository) RescheduledMessages(ctx context.Context, ids []string) error {
-	err := r.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err := r.db(ctx).Transaction(func(tx *gorm.DB) error {
 		res := tx.WithContext(ctx).
 			Model(&smsEntity{}).
 			Clauses(clause.Returning{}).
-			Where("id IN ?", ids).
+			Where("id IN ? AND status = ?", ids, models.StatusEnqueued).
 			Updates(map[string]any{
 				"status":     models.StatusScheduled,
+				"attempts":   gorm.Expr("GREATEST(attempts - 1, 0)"),
 				"updated_at": time.Now(),
 			})
 
@@ -182,3 +382,67 @@ func (r *Repository) RescheduledMessages(ctx context.Context, ids []string) erro
 
 	return nil
 }
+
+func (r *Repository) RescheduleStaleMessages(ctx context.Context, ids []string, enqueuedBefore time.Time) (int, error) {
+	res := r.db(ctx).
+		Model(&smsEntity{}).
+		Where("id IN ? AND status = ? AND updated_at < ?", ids, models.StatusEnqueued, enqueuedBefore).
+		Updates(map[string]any{
+			"status":     models.StatusScheduled,
+			"attempts":   gorm.Expr("GREATEST(attempts - 1, 0)"),
+			"updated_at": time.Now(),
+		})
+
+	if res.Error != nil {
+		return 0, res.Error
+	}
+
+	return int(res.RowsAffected), nil
+}
+
+func (r *Repository) CancelScheduledMessage(ctx context.Context, userId string, id string) (models.Sms, error) {
+	se := smsEntity{}
+
+	res := r.db(ctx).
+		Model(&se).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND user_id = ? AND status = ?", id, userId, models.StatusScheduled).
+		Updates(map[string]any{
+			"status":     models.StatusCanceled,
+			"updated_at": time.Now(),
+		})
+
+	if res.Error != nil {
+		return models.Sms{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return models.Sms{}, models.MessageNotExistError
+	}
+
+	return toMessage(se), nil
+}
+
+func (r *Repository) ChangeMessageSendAt(ctx context.Context, userId string, id string, sendAt time.Time) (models.Sms, error) {
+	se := smsEntity{}
+
+	res := r.db(ctx).
+		Model(&se).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND user_id = ? AND status = ?", id, userId, models.StatusScheduled).
+		Updates(map[string]any{
+			"send_at":         sendAt,
+			"next_attempt_at": sendAt,
+			"updated_at":      time.Now(),
+		})
+
+	if res.Error != nil {
+		return models.Sms{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return models.Sms{}, models.MessageNotExistError
+	}
+
+	return toMessage(se), nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
 	"github.com/stretchr/testify/assert"
@@ -42,7 +43,7 @@ func TestRepository_CreateScheduleMessages(t *testing.T) {
 			},
 		}
 
-		err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
 		assert.NoError(t, err)
 
 		row := conn.GetConnection().QueryRow("select count(1) from messages")
@@ -55,6 +56,48 @@ func TestRepository_CreateScheduleMessages(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("should store country and operator of receiver", func(t *testing.T) {
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		ctx := context.Background()
+
+		tmpUser := umodels.User{
+			Name:    "AshkanAbd",
+			Balance: 0,
+		}
+		createdUser, err := repo.CreateUser(ctx, tmpUser)
+		assert.NoError(t, err)
+
+		inputMsgs := []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content",
+				Receiver: "+989123456789",
+				Country:  "IR",
+				Operator: "MCI",
+				Cost:     100,
+				Status:   models.StatusScheduled,
+			},
+		}
+
+		createdMsgs, err := repo.CreateScheduleMessages(ctx, inputMsgs)
+		assert.NoError(t, err)
+
+		actualMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, false)
+		assert.NoError(t, err)
+		assert.Len(t, actualMsgs, 1)
+
+		actualMsg := actualMsgs[0]
+		assert.Equal(t, createdMsgs[0].ID, actualMsg.ID)
+		assert.Equal(t, "+989123456789", actualMsg.Receiver)
+		assert.Equal(t, "IR", actualMsg.Country)
+		assert.Equal(t, "MCI", actualMsg.Operator)
+
+		err = cleanDB(conn)
+		assert.NoError(t, err)
+	})
+
 	t.Run("should return EmptyContentError when content is empty and not create any messages", func(t *testing.T) {
 		conn, repo, err := initDB()
 		assert.NoError(t, err)
@@ -85,7 +128,7 @@ func TestRepository_CreateScheduleMessages(t *testing.T) {
 			},
 		}
 
-		err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
 		assert.Error(t, err)
 		assert.Equal(t, models.EmptyContentError, err)
 
@@ -129,7 +172,7 @@ func TestRepository_CreateScheduleMessages(t *testing.T) {
 			},
 		}
 
-		err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
 		assert.Error(t, err)
 		assert.Equal(t, models.EmptyReceiverError, err)
 
@@ -175,7 +218,7 @@ func TestRepository_GetMessagesByUserId(t *testing.T) {
 			},
 		}
 
-		err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
 		assert.NoError(t, err)
 
 		actualMsgs, actualErr := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, true)
@@ -231,7 +274,7 @@ func TestRepository_GetMessagesByUserId(t *testing.T) {
 			},
 		}
 
-		err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
 		assert.NoError(t, err)
 
 		actualMsgs, actualErr := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, false)
@@ -286,7 +329,7 @@ func TestRepository_GetMessagesByUserId(t *testing.T) {
 			},
 		}
 
-		err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
 		assert.NoError(t, err)
 
 		actualMsgs, actualErr := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 1, false)
@@ -338,7 +381,7 @@ func TestRepository_GetMessagesByUserId(t *testing.T) {
 			},
 		}
 
-		err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
 		assert.NoError(t, err)
 
 		actualMsgs, actualErr := repo.GetMessagesByUserId(ctx, createdUser.ID, 1, 1, false)
@@ -390,7 +433,7 @@ func TestRepository_SetMessageAsFailed(t *testing.T) {
 			},
 		}
 
-		err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
 		assert.NoError(t, err)
 
 		userMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, true)
@@ -437,7 +480,7 @@ func TestRepository_SetMessageAsFailed(t *testing.T) {
 			},
 		}
 
-		err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
 		assert.NoError(t, err)
 
 		userMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, true)
@@ -495,14 +538,14 @@ func TestRepository_SetMessageAsSent(t *testing.T) {
 			},
 		}
 
-		err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
 		assert.NoError(t, err)
 
 		userMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, true)
 		assert.NoError(t, err)
 		assert.Equal(t, len(inputMsgs), len(userMsgs))
 
-		actualMsg, actualErr := repo.SetMessageAsSent(ctx, userMsgs[0].ID)
+		actualMsg, actualErr := repo.SetMessageAsSent(ctx, userMsgs[0].ID, models.SendResult{Provider: "primary", MessageId: "p-1"})
 		assert.NoError(t, actualErr)
 		assert.Equal(t, userMsgs[0].ID, actualMsg.ID)
 		assert.Equal(t, userMsgs[0].CreatedAt, actualMsg.CreatedAt)
@@ -511,6 +554,9 @@ func TestRepository_SetMessageAsSent(t *testing.T) {
 		assert.Equal(t, userMsgs[0].Receiver, actualMsg.Receiver)
 		assert.Equal(t, userMsgs[0].Cost, actualMsg.Cost)
 		assert.Equal(t, models.StatusSent, actualMsg.Status)
+		assert.Equal(t, "primary", actualMsg.Provider)
+		assert.Equal(t, "p-1", actualMsg.ProviderMessageId)
+		assert.False(t, actualMsg.SentAt.IsZero())
 		assert.True(t, userMsgs[0].UpdatedAt.Before(actualMsg.UpdatedAt))
 	})
 
@@ -542,17 +588,81 @@ func TestRepository_SetMessageAsSent(t *testing.T) {
 			},
 		}
 
-		err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
 		assert.NoError(t, err)
 
 		userMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, true)
 		assert.NoError(t, err)
 		assert.Equal(t, len(inputMsgs), len(userMsgs))
 
-		_, actualErr := repo.SetMessageAsSent(ctx, userMsgs[0].ID)
+		_, actualErr := repo.SetMessageAsSent(ctx, userMsgs[0].ID, models.SendResult{Provider: "primary"})
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.MessageNotExistError, actualErr)
+	})
+
+	t.Run("should return MessageNotExistError when message not exists", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		_, actualErr := repo.SetMessageAsSent(ctx, "1", models.SendResult{Provider: "primary"})
 		assert.Error(t, actualErr)
 		assert.Equal(t, models.MessageNotExistError, actualErr)
 	})
+}
+
+func TestRepository_SetMessageAsRetrying(t *testing.T) {
+	t.Run("should set message as retrying", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		tmpUser := umodels.User{
+			Name:    "AshkanAbd",
+			Balance: 0,
+		}
+		createdUser, err := repo.CreateUser(ctx, tmpUser)
+		assert.NoError(t, err)
+
+		inputMsgs := []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+				Attempts: 1,
+			},
+		}
+
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		assert.NoError(t, err)
+
+		userMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, true)
+		assert.NoError(t, err)
+		assert.Equal(t, len(inputMsgs), len(userMsgs))
+
+		nextAttemptAt := time.Now().Add(time.Minute)
+		actualMsg, actualErr := repo.SetMessageAsRetrying(ctx, userMsgs[0].ID, nextAttemptAt)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, userMsgs[0].ID, actualMsg.ID)
+		assert.Equal(t, models.StatusRetrying, actualMsg.Status)
+		assert.Equal(t, 1, actualMsg.Attempts)
+		assert.WithinDuration(t, nextAttemptAt, actualMsg.NextAttemptAt, time.Millisecond)
+		assert.True(t, userMsgs[0].UpdatedAt.Before(actualMsg.UpdatedAt))
+	})
 
 	t.Run("should return MessageNotExistError when message not exists", func(t *testing.T) {
 		ctx := context.Background()
@@ -565,7 +675,7 @@ func TestRepository_SetMessageAsSent(t *testing.T) {
 			assert.NoError(t, err)
 		}()
 
-		_, actualErr := repo.SetMessageAsSent(ctx, "1")
+		_, actualErr := repo.SetMessageAsRetrying(ctx, "1", time.Now())
 		assert.Error(t, actualErr)
 		assert.Equal(t, models.MessageNotExistError, actualErr)
 	})
@@ -615,7 +725,7 @@ func TestRepository_EnqueueMessages(t *testing.T) {
 			},
 		}
 
-		err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
 		assert.NoError(t, err)
 
 		userMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, true)
@@ -638,6 +748,257 @@ func TestRepository_EnqueueMessages(t *testing.T) {
 		}
 	})
 
+	t.Run("should enqueue higher priority messages first", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		tmpUser := umodels.User{
+			Name:    "AshkanAbd",
+			Balance: 0,
+		}
+		createdUser, err := repo.CreateUser(ctx, tmpUser)
+		assert.NoError(t, err)
+
+		inputMsgs := []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusScheduled,
+				Priority: models.PriorityLow,
+			},
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 2",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusScheduled,
+			},
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 3",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusScheduled,
+				Priority: models.PriorityHigh,
+			},
+		}
+
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		assert.NoError(t, err)
+
+		actualMsgs, actualErr := repo.EnqueueMessages(ctx, 2)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 2, len(actualMsgs))
+		assert.Equal(t, "Test Content 3", actualMsgs[0].Content)
+		assert.Equal(t, models.PriorityHigh, actualMsgs[0].Priority)
+		assert.Equal(t, "Test Content 2", actualMsgs[1].Content)
+		assert.Equal(t, models.PriorityNormal, actualMsgs[1].Priority)
+
+		actualMsgs, actualErr = repo.EnqueueMessages(ctx, 2)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 1, len(actualMsgs))
+		assert.Equal(t, models.PriorityLow, actualMsgs[0].Priority)
+	})
+
+	t.Run("should not enqueue messages before their send time", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		tmpUser := umodels.User{
+			Name:    "AshkanAbd",
+			Balance: 0,
+		}
+		createdUser, err := repo.CreateUser(ctx, tmpUser)
+		assert.NoError(t, err)
+
+		inputMsgs := []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusScheduled,
+				SendAt:   time.Now().Add(time.Hour),
+			},
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 2",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusScheduled,
+				SendAt:   time.Now().Add(-time.Minute),
+			},
+		}
+
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		assert.NoError(t, err)
+
+		actualMsgs, actualErr := repo.EnqueueMessages(ctx, 10)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 1, len(actualMsgs))
+		assert.Equal(t, "Test Content 2", actualMsgs[0].Content)
+		assert.Equal(t, models.StatusEnqueued, actualMsgs[0].Status)
+	})
+
+	t.Run("should enqueue due retrying messages and increase their attempts", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		tmpUser := umodels.User{
+			Name:    "AshkanAbd",
+			Balance: 0,
+		}
+		createdUser, err := repo.CreateUser(ctx, tmpUser)
+		assert.NoError(t, err)
+
+		inputMsgs := []models.Sms{
+			{
+				UserId:        createdUser.ID,
+				Content:       "Test Content 1",
+				Receiver:      "09123456789",
+				Cost:          100,
+				Status:        models.StatusRetrying,
+				Attempts:      1,
+				NextAttemptAt: time.Now().Add(-time.Minute),
+			},
+			{
+				UserId:        createdUser.ID,
+				Content:       "Test Content 2",
+				Receiver:      "09123456789",
+				Cost:          100,
+				Status:        models.StatusRetrying,
+				Attempts:      1,
+				NextAttemptAt: time.Now().Add(time.Hour),
+			},
+		}
+
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		assert.NoError(t, err)
+
+		actualMsgs, actualErr := repo.EnqueueMessages(ctx, 10)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 1, len(actualMsgs))
+		assert.Equal(t, inputMsgs[0].Content, actualMsgs[0].Content)
+		assert.Equal(t, models.StatusEnqueued, actualMsgs[0].Status)
+		assert.Equal(t, 2, actualMsgs[0].Attempts)
+	})
+
+	t.Run("should share enqueue slots between users by their weights", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		bulkUser, err := repo.CreateUser(ctx, umodels.User{Name: "Bulk"})
+		assert.NoError(t, err)
+		bulkUser, err = repo.UpdateUserEnqueueWeight(ctx, bulkUser.ID, 3)
+		assert.NoError(t, err)
+
+		smallUser, err := repo.CreateUser(ctx, umodels.User{Name: "Small"})
+		assert.NoError(t, err)
+
+		var inputMsgs []models.Sms
+		for _, userId := range []string{bulkUser.ID, bulkUser.ID, bulkUser.ID, bulkUser.ID, bulkUser.ID, smallUser.ID, smallUser.ID} {
+			inputMsgs = append(inputMsgs, models.Sms{
+				UserId:   userId,
+				Content:  "Test Content",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusScheduled,
+			})
+		}
+
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		assert.NoError(t, err)
+
+		actualMsgs, actualErr := repo.EnqueueMessages(ctx, 4)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 4, len(actualMsgs))
+
+		perUser := map[string]int{}
+		for _, msg := range actualMsgs {
+			perUser[msg.UserId]++
+		}
+		assert.Equal(t, 3, perUser[bulkUser.ID])
+		assert.Equal(t, 1, perUser[smallUser.ID])
+	})
+
+	t.Run("should rotate enqueue slots between users when they outnumber the slots", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		var userIds []string
+		var inputMsgs []models.Sms
+		for _, name := range []string{"First", "Second", "Third"} {
+			user, err := repo.CreateUser(ctx, umodels.User{Name: name})
+			assert.NoError(t, err)
+			userIds = append(userIds, user.ID)
+
+			for range 2 {
+				inputMsgs = append(inputMsgs, models.Sms{
+					UserId:   user.ID,
+					Content:  "Test Content",
+					Receiver: "09123456789",
+					Cost:     100,
+					Status:   models.StatusScheduled,
+				})
+			}
+		}
+
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		assert.NoError(t, err)
+
+		perUser := map[string]int{}
+		for range 3 {
+			actualMsgs, actualErr := repo.EnqueueMessages(ctx, 2)
+			assert.NoError(t, actualErr)
+			assert.Equal(t, 2, len(actualMsgs))
+
+			for _, msg := range actualMsgs {
+				perUser[msg.UserId]++
+			}
+		}
+
+		for _, userId := range userIds {
+			assert.Equal(t, 2, perUser[userId])
+		}
+	})
+
 	t.Run("should not enqueue if no message was in schedule", func(t *testing.T) {
 		ctx := context.Background()
 
@@ -681,7 +1042,7 @@ func TestRepository_EnqueueMessages(t *testing.T) {
 		}
 		countInput := len(inputMsgs)
 
-		err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
 		assert.NoError(t, err)
 
 		userMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, true)
@@ -737,7 +1098,7 @@ func TestRepository_RescheduledMessages(t *testing.T) {
 			},
 		}
 
-		err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
 		assert.NoError(t, err)
 
 		userMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, true)
@@ -770,3 +1131,367 @@ func TestRepository_RescheduledMessages(t *testing.T) {
 		}
 	})
 }
+
+func TestRepository_RescheduleStaleMessages(t *testing.T) {
+	t.Run("should reschedule only messages still enqueued since given time", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		tmpUser := umodels.User{
+			Name:    "AshkanAbd",
+			Balance: 0,
+		}
+		createdUser, err := repo.CreateUser(ctx, tmpUser)
+		assert.NoError(t, err)
+
+		inputMsgs := []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+			},
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 2",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusSent,
+			},
+		}
+
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		assert.NoError(t, err)
+
+		userMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, false)
+		assert.NoError(t, err)
+		ids := []string{userMsgs[0].ID, userMsgs[1].ID}
+
+		actualRescheduled, actualErr := repo.RescheduleStaleMessages(ctx, ids, userMsgs[0].UpdatedAt)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 0, actualRescheduled)
+
+		actualRescheduled, actualErr = repo.RescheduleStaleMessages(ctx, ids, time.Now().Add(time.Minute))
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 1, actualRescheduled)
+
+		actualMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, false)
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusScheduled, actualMsgs[0].Status)
+		assert.Equal(t, models.StatusSent, actualMsgs[1].Status)
+	})
+}
+
+func TestRepository_GetStaleEnqueuedMessages(t *testing.T) {
+	t.Run("should return enqueued messages not updated since given time", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		tmpUser := umodels.User{
+			Name:    "AshkanAbd",
+			Balance: 0,
+		}
+		createdUser, err := repo.CreateUser(ctx, tmpUser)
+		assert.NoError(t, err)
+
+		inputMsgs := []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+			},
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 2",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusSent,
+			},
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 3",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+			},
+		}
+
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		assert.NoError(t, err)
+
+		userMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, false)
+		assert.NoError(t, err)
+		assert.Equal(t, len(inputMsgs), len(userMsgs))
+
+		actualMsgs, actualErr := repo.GetStaleEnqueuedMessages(ctx, time.Now().Add(-time.Hour), 10)
+		assert.NoError(t, actualErr)
+		assert.Empty(t, actualMsgs)
+
+		actualMsgs, actualErr = repo.GetStaleEnqueuedMessages(ctx, time.Now().Add(time.Second), 10)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 2, len(actualMsgs))
+		assert.Equal(t, userMsgs[0].ID, actualMsgs[0].ID)
+		assert.Equal(t, userMsgs[2].ID, actualMsgs[1].ID)
+		for i := range actualMsgs {
+			assert.Equal(t, models.StatusEnqueued, actualMsgs[i].Status)
+		}
+
+		actualMsgs, actualErr = repo.GetStaleEnqueuedMessages(ctx, time.Now().Add(time.Second), 1)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 1, len(actualMsgs))
+		assert.Equal(t, userMsgs[0].ID, actualMsgs[0].ID)
+	})
+}
+
+func TestRepository_CancelScheduledMessage(t *testing.T) {
+	t.Run("should cancel only scheduled message of user", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		tmpUser := umodels.User{
+			Name:    "AshkanAbd",
+			Balance: 0,
+		}
+		createdUser, err := repo.CreateUser(ctx, tmpUser)
+		assert.NoError(t, err)
+
+		inputMsgs := []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusScheduled,
+				SendAt:   time.Now().Add(time.Hour),
+			},
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 2",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+			},
+		}
+
+		_, err = repo.CreateScheduleMessages(ctx, inputMsgs)
+		assert.NoError(t, err)
+
+		userMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, false)
+		assert.NoError(t, err)
+		assert.Equal(t, len(inputMsgs), len(userMsgs))
+
+		_, actualErr := repo.CancelScheduledMessage(ctx, "0", userMsgs[0].ID)
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.MessageNotExistError, actualErr)
+
+		_, actualErr = repo.CancelScheduledMessage(ctx, createdUser.ID, userMsgs[1].ID)
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.MessageNotExistError, actualErr)
+
+		actualMsg, actualErr := repo.CancelScheduledMessage(ctx, createdUser.ID, userMsgs[0].ID)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, userMsgs[0].ID, actualMsg.ID)
+		assert.Equal(t, userMsgs[0].Cost, actualMsg.Cost)
+		assert.Equal(t, models.StatusCanceled, actualMsg.Status)
+
+		actualMsgs, actualErr := repo.EnqueueMessages(ctx, 10)
+		assert.NoError(t, actualErr)
+		assert.Empty(t, actualMsgs)
+	})
+}
+
+func TestRepository_ChangeMessageSendAt(t *testing.T) {
+	t.Run("should change send time of scheduled message", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		tmpUser := umodels.User{
+			Name:    "AshkanAbd",
+			Balance: 0,
+		}
+		createdUser, err := repo.CreateUser(ctx, tmpUser)
+		assert.NoError(t, err)
+
+		_, err = repo.CreateScheduleMessages(ctx, []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusScheduled,
+				SendAt:   time.Now().Add(time.Hour),
+			},
+		})
+		assert.NoError(t, err)
+
+		userMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, false)
+		assert.NoError(t, err)
+
+		actualMsgs, actualErr := repo.EnqueueMessages(ctx, 10)
+		assert.NoError(t, actualErr)
+		assert.Empty(t, actualMsgs)
+
+		sendAt := time.Now().Add(-time.Second).UTC().Truncate(time.Microsecond)
+		actualMsg, actualErr := repo.ChangeMessageSendAt(ctx, createdUser.ID, userMsgs[0].ID, sendAt)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, userMsgs[0].ID, actualMsg.ID)
+		assert.True(t, sendAt.Equal(actualMsg.SendAt))
+		assert.True(t, sendAt.Equal(actualMsg.NextAttemptAt))
+		assert.Equal(t, models.StatusScheduled, actualMsg.Status)
+
+		actualMsgs, actualErr = repo.EnqueueMessages(ctx, 10)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 1, len(actualMsgs))
+		assert.Equal(t, userMsgs[0].ID, actualMsgs[0].ID)
+	})
+
+	t.Run("should return MessageNotExistError when message is not scheduled", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		actualMsg, actualErr := repo.ChangeMessageSendAt(ctx, "1", "1", time.Now())
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.MessageNotExistError, actualErr)
+		assert.Equal(t, models.Sms{}, actualMsg)
+	})
+}
+
+func TestRepository_SetMessageDeliveryState(t *testing.T) {
+	t.Run("should set final delivery state of sent message", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{
+			Name:    "AshkanAbd",
+			Balance: 0,
+		})
+		assert.NoError(t, err)
+
+		_, err = repo.CreateScheduleMessages(ctx, []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+			},
+		})
+		assert.NoError(t, err)
+
+		userMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, true)
+		assert.NoError(t, err)
+
+		_, err = repo.SetMessageAsSent(ctx, userMsgs[0].ID, models.SendResult{Provider: "primary", MessageId: "p-1"})
+		assert.NoError(t, err)
+
+		doneAt := time.Now().Truncate(time.Second)
+		actualMsg, actualErr := repo.SetMessageDeliveryState(ctx, models.DeliveryReport{
+			Provider:  "primary",
+			MessageId: "p-1",
+			Status:    models.StatusUndelivered,
+			DoneAt:    doneAt,
+			Error:     "001",
+		})
+		assert.NoError(t, actualErr)
+		assert.Equal(t, userMsgs[0].ID, actualMsg.ID)
+		assert.Equal(t, models.StatusUndelivered, actualMsg.Status)
+		assert.True(t, doneAt.Equal(actualMsg.DoneAt))
+		assert.Equal(t, "001", actualMsg.DeliveryError)
+
+		_, actualErr = repo.SetMessageDeliveryState(ctx, models.DeliveryReport{
+			Provider:  "primary",
+			MessageId: "p-1",
+			Status:    models.StatusDelivered,
+			DoneAt:    doneAt,
+		})
+		assert.ErrorIs(t, actualErr, models.MessageNotExistError)
+	})
+
+	t.Run("should return MessageNotExistError when provider does not match", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{
+			Name:    "AshkanAbd",
+			Balance: 0,
+		})
+		assert.NoError(t, err)
+
+		_, err = repo.CreateScheduleMessages(ctx, []models.Sms{
+			{
+				UserId:   createdUser.ID,
+				Content:  "Test Content",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+			},
+		})
+		assert.NoError(t, err)
+
+		userMsgs, err := repo.GetMessagesByUserId(ctx, createdUser.ID, 0, 10, true)
+		assert.NoError(t, err)
+
+		_, err = repo.SetMessageAsSent(ctx, userMsgs[0].ID, models.SendResult{Provider: "primary", MessageId: "p-1"})
+		assert.NoError(t, err)
+
+		_, actualErr := repo.SetMessageDeliveryState(ctx, models.DeliveryReport{
+			Provider:  "backup",
+			MessageId: "p-1",
+			Status:    models.StatusDelivered,
+			DoneAt:    time.Now(),
+		})
+		assert.ErrorIs(t, actualErr, models.MessageNotExistError)
+	})
+}
@@ -11,11 +11,15 @@ import (
 )
 
 type userEntity struct {
-	ID        uint
-	Name      string
-	Balance   int64
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID            uint
+	Name          string
+	Balance       int64
+	HeldBalance   int64
+	EnqueueWeight int    `gorm:"default:1"`
+	AccountType   string `gorm:"default:prepaid"`
+	CreditLimit   int64
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
 
 func (u *userEntity) TableName() string {
@@ -24,8 +28,12 @@ func (u *userEntity) TableName() string {
 
 func fromUser(u models.User) userEntity {
 	ue := userEntity{
-		Name:    strings.Trim(u.Name, " "),
-		Balance: u.Balance,
+		Name:          strings.Trim(u.Name, " "),
+		Balance:       u.Balance,
+		HeldBalance:   u.HeldBalance,
+		EnqueueWeight: u.EnqueueWeight,
+		AccountType:   string(u.AccountType),
+		CreditLimit:   u.CreditLimit,
 	}
 
 	if u.Entity != nil {
@@ -52,7 +60,11 @@ func toUser(ue userEntity) models.User {
 		UpdateDate: &shared.UpdateDate{
 			UpdatedAt: ue.UpdatedAt,
 		},
-		Name:    ue.Name,
-		Balance: ue.Balance,
+		Name:          ue.Name,
+		Balance:       ue.Balance,
+		HeldBalance:   ue.HeldBalance,
+		EnqueueWeight: ue.EnqueueWeight,
+		AccountType:   models.AccountType(ue.AccountType),
+		CreditLimit:   ue.CreditLimit,
 	}
 }
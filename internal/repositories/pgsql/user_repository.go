@@ -16,12 +16,29 @@ import (
 func (r *Repository) CreateUser(ctx context.Context, user models.User) (models.User, error) {
 	ue := fromUser(user)
 
-	res := r.conn.WithContext(ctx).Create(&ue)
-	if res.Error != nil {
-		if strings.Contains(res.Error.Error(), "user_name_empty") {
+	err := r.db(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.WithContext(ctx).Create(&ue).Error; err != nil {
+			return err
+		}
+
+		if ue.Balance == 0 {
+			return nil
+		}
+
+		// the initial balance is recorded, so the ledger stays in sync
+		return tx.WithContext(ctx).Create(&balanceTransactionEntity{
+			UserId:       ue.ID,
+			Type:         string(models.TransactionAdjustment),
+			Amount:       ue.Balance,
+			BalanceAfter: ue.Balance,
+			Reference:    "initial balance",
+		}).Error
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "user_name_empty") {
 			return models.User{}, models.EmptyNameError
 		}
-		return models.User{}, res.Error
+		return models.User{}, err
 	}
 
 	user.Entity = &shared.Entity{
@@ -33,7 +50,7 @@ func (r *Repository) CreateUser(ctx context.Context, user models.User) (models.U
 func (r *Repository) GetUser(ctx context.Context, id string) (models.User, error) {
 	ue := userEntity{}
 
-	err := r.conn.WithContext(ctx).First(&ue, "id = ?", id).Error
+	err := r.db(ctx).First(&ue, "id = ?", id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return models.User{}, models.UserNotExistError
@@ -45,13 +62,21 @@ func (r *Repository) GetUser(ctx context.Context, id string) (models.User, error
 	return toUser(ue), nil
 }
 
-func (r *Repository) UpdateUserBalance(ctx context.Context, id string, amount int64) (int64, error) {
+// UpdateUserBalance applies the sum of the given transaction amounts to the
+// user balance and records the transactions in the ledger, in one database
+// transaction.
+func (r *Repository) UpdateUserBalance(ctx context.Context, id string, txs []models.BalanceTransaction) (int64, error) {
 	var ue userEntity
 
-	err := r.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	var amount int64
+	for i := range txs {
+		amount += txs[i].Amount
+	}
+
+	err := r.db(ctx).Transaction(func(tx *gorm.DB) error {
 		res := tx.WithContext(ctx).Model(&ue).
 			Where("id = ?", id).
-			Clauses(clause.Returning{Columns: []clause.Column{{Name: "balance"}}}).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "balance"}}}).
 			Updates(
 				map[string]any{
 					"balance":    gorm.Expr("balance + ?", amount),
@@ -67,7 +92,20 @@ func (r *Repository) UpdateUserBalance(ctx context.Context, id string, amount in
 			return models.UserNotExistError
 		}
 
-		return nil
+		if len(txs) == 0 {
+			return nil
+		}
+
+		bes := make([]balanceTransactionEntity, len(txs))
+		balance := ue.Balance - amount
+		for i := range txs {
+			balance += txs[i].Amount
+			bes[i] = fromBalanceTransaction(txs[i])
+			bes[i].UserId = ue.ID
+			bes[i].BalanceAfter = balance
+		}
+
+		return tx.WithContext(ctx).Create(bes).Error
 	}, &sql.TxOptions{
 		Isolation: sql.LevelRepeatableRead,
 	})
@@ -81,3 +119,65 @@ func (r *Repository) UpdateUserBalance(ctx context.Context, id string, amount in
 
 	return ue.Balance, nil
 }
+
+func (r *Repository) UpdateUserEnqueueWeight(ctx context.Context, id string, weight int) (models.User, error) {
+	ue := userEntity{}
+
+	res := r.db(ctx).
+		Model(&ue).
+		Clauses(clause.Returning{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"enqueue_weight": weight,
+			"updated_at":     gorm.Expr("now()"),
+		})
+
+	if res.Error != nil {
+		if strings.Contains(res.Error.Error(), "user_enqueue_weight_positive") {
+			return models.User{}, models.InvalidEnqueueWeightError
+		}
+		return models.User{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return models.User{}, models.UserNotExistError
+	}
+
+	return toUser(ue), nil
+}
+
+// UpdateUserAccount changes the account type and credit limit of the user. It
+// fails with InsufficientBalanceError when the balance and holds of the user
+// no longer fit in the new credit limit.
+func (r *Repository) UpdateUserAccount(ctx context.Context, id string, accountType models.AccountType, creditLimit int64) (models.User, error) {
+	ue := userEntity{}
+
+	res := r.db(ctx).
+		Model(&ue).
+		Clauses(clause.Returning{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"account_type": string(accountType),
+			"credit_limit": creditLimit,
+			"updated_at":   gorm.Expr("now()"),
+		})
+
+	if res.Error != nil {
+		if strings.Contains(res.Error.Error(), "user_account_type_invalid") {
+			return models.User{}, models.InvalidAccountTypeError
+		}
+		if strings.Contains(res.Error.Error(), "user_credit_limit_invalid") {
+			return models.User{}, models.InvalidCreditLimitError
+		}
+		if strings.Contains(res.Error.Error(), "user_insufficient_balance") {
+			return models.User{}, models.InsufficientBalanceError
+		}
+		return models.User{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return models.User{}, models.UserNotExistError
+	}
+
+	return toUser(ue), nil
+}
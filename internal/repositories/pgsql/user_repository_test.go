@@ -162,7 +162,9 @@ func TestRepository_UpdateUserBalance(t *testing.T) {
 		beforeUpdateUser, err := repo.GetUser(ctx, createdUser.ID)
 		assert.NoError(t, err)
 
-		actualBalance, actualErr := repo.UpdateUserBalance(ctx, createdUser.ID, inputAmount)
+		actualBalance, actualErr := repo.UpdateUserBalance(ctx, createdUser.ID, []models.BalanceTransaction{
+			{Type: models.TransactionTopUp, Amount: inputAmount},
+		})
 		assert.NoError(t, actualErr)
 		assert.Equal(t, beforeUpdateUser.Balance+inputAmount, actualBalance)
 
@@ -176,7 +178,9 @@ func TestRepository_UpdateUserBalance(t *testing.T) {
 
 		ctx := context.Background()
 
-		actualBalance, actualErr := repo.UpdateUserBalance(ctx, "1", 100)
+		actualBalance, actualErr := repo.UpdateUserBalance(ctx, "1", []models.BalanceTransaction{
+			{Type: models.TransactionTopUp, Amount: 100},
+		})
 		assert.Error(t, actualErr)
 		assert.Equal(t, models.UserNotExistError, actualErr)
 		assert.Equal(t, int64(0), actualBalance)
@@ -184,26 +188,130 @@ func TestRepository_UpdateUserBalance(t *testing.T) {
 		err = cleanDB(conn)
 		assert.NoError(t, err)
 	})
+}
 
-	t.Run("should return InsufficientBalanceError if balance lt 0", func(t *testing.T) {
+func TestRepository_UpdateUserEnqueueWeight(t *testing.T) {
+	t.Run("should update user enqueue weight", func(t *testing.T) {
 		conn, repo, err := initDB()
 		assert.NoError(t, err)
 
-		inputUser := models.User{
-			Name:    "AshkanAbd",
-			Balance: 0,
-		}
-		inputAmount := int64(-100)
+		ctx := context.Background()
+
+		createdUser, err := repo.CreateUser(ctx, models.User{Name: "AshkanAbd"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, createdUser.EnqueueWeight)
+
+		actualUser, actualErr := repo.UpdateUserEnqueueWeight(ctx, createdUser.ID, 4)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 4, actualUser.EnqueueWeight)
+
+		err = cleanDB(conn)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should return UserNotExistError if user not exists", func(t *testing.T) {
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
 
 		ctx := context.Background()
 
-		createdUser, err := repo.CreateUser(ctx, inputUser)
+		actualUser, actualErr := repo.UpdateUserEnqueueWeight(ctx, "1", 4)
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.UserNotExistError, actualErr)
+		assert.Equal(t, models.User{}, actualUser)
+
+		err = cleanDB(conn)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should return InvalidEnqueueWeightError if weight lte 0", func(t *testing.T) {
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		ctx := context.Background()
+
+		createdUser, err := repo.CreateUser(ctx, models.User{Name: "AshkanAbd"})
 		assert.NoError(t, err)
 
-		actualBalance, actualErr := repo.UpdateUserBalance(ctx, createdUser.ID, inputAmount)
+		actualUser, actualErr := repo.UpdateUserEnqueueWeight(ctx, createdUser.ID, 0)
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.InvalidEnqueueWeightError, actualErr)
+		assert.Equal(t, models.User{}, actualUser)
+
+		err = cleanDB(conn)
+		assert.NoError(t, err)
+	})
+}
+
+func TestRepository_UpdateUserAccount(t *testing.T) {
+	t.Run("should allow negative balance down to the credit limit of postpaid user", func(t *testing.T) {
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		ctx := context.Background()
+
+		createdUser, err := repo.CreateUser(ctx, models.User{Name: "AshkanAbd", Balance: 100})
+		assert.NoError(t, err)
+
+		actualUser, actualErr := repo.UpdateUserAccount(ctx, createdUser.ID, models.AccountPostpaid, 1000)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.AccountPostpaid, actualUser.AccountType)
+		assert.Equal(t, int64(1000), actualUser.CreditLimit)
+
+		newBalance, actualErr := repo.UpdateUserBalance(ctx, createdUser.ID, []models.BalanceTransaction{
+			{Type: models.TransactionMessageCharge, Amount: -1100},
+		})
+		assert.NoError(t, actualErr)
+		assert.Equal(t, int64(-1000), newBalance)
+
+		_, actualErr = repo.UpdateUserBalance(ctx, createdUser.ID, []models.BalanceTransaction{
+			{Type: models.TransactionMessageCharge, Amount: -1},
+		})
 		assert.Error(t, actualErr)
 		assert.Equal(t, models.InsufficientBalanceError, actualErr)
-		assert.Equal(t, int64(0), actualBalance)
+
+		err = cleanDB(conn)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should return InsufficientBalanceError when switching a negative balance to prepaid", func(t *testing.T) {
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		ctx := context.Background()
+
+		createdUser, err := repo.CreateUser(ctx, models.User{Name: "AshkanAbd"})
+		assert.NoError(t, err)
+
+		_, err = repo.UpdateUserAccount(ctx, createdUser.ID, models.AccountPostpaid, 1000)
+		assert.NoError(t, err)
+
+		_, err = repo.UpdateUserBalance(ctx, createdUser.ID, []models.BalanceTransaction{
+			{Type: models.TransactionMessageCharge, Amount: -100},
+		})
+		assert.NoError(t, err)
+
+		actualUser, actualErr := repo.UpdateUserAccount(ctx, createdUser.ID, models.AccountPrepaid, 0)
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.InsufficientBalanceError, actualErr)
+		assert.Equal(t, models.User{}, actualUser)
+
+		err = cleanDB(conn)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should return InvalidCreditLimitError when prepaid user has credit limit", func(t *testing.T) {
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		ctx := context.Background()
+
+		createdUser, err := repo.CreateUser(ctx, models.User{Name: "AshkanAbd"})
+		assert.NoError(t, err)
+
+		_, actualErr := repo.UpdateUserAccount(ctx, createdUser.ID, models.AccountPrepaid, 1000)
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.InvalidCreditLimitError, actualErr)
 
 		err = cleanDB(conn)
 		assert.NoError(t, err)
@@ -0,0 +1,125 @@
+package pgsql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/common"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+)
+
+type webhookEntity struct {
+	ID        uint
+	UserId    uint
+	Url       string
+	Secret    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (w *webhookEntity) TableName() string {
+	return "webhooks"
+}
+
+type webhookDeliveryEntity struct {
+	ID             uint
+	WebhookId      uint
+	Event          string
+	Payload        string
+	Status         int
+	Attempts       int
+	NextAttemptAt  time.Time
+	ResponseStatus int
+	Error          string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (w *webhookDeliveryEntity) TableName() string {
+	return "webhook_deliveries"
+}
+
+func fromWebhook(w models.Webhook) webhookEntity {
+	we := webhookEntity{
+		UserId: common.ParseUIntWithFallback(w.UserId, 0),
+		Url:    w.Url,
+		Secret: w.Secret,
+	}
+
+	if w.Entity != nil {
+		we.ID = common.ParseUIntWithFallback(w.ID, 0)
+	}
+	if w.CreateDate != nil {
+		we.CreatedAt = w.CreatedAt
+	}
+	if w.UpdateDate != nil {
+		we.UpdatedAt = w.UpdatedAt
+	}
+
+	return we
+}
+
+func toWebhook(we webhookEntity) models.Webhook {
+	return models.Webhook{
+		Entity: &shared.Entity{
+			ID: fmt.Sprintf("%d", we.ID),
+		},
+		CreateDate: &shared.CreateDate{
+			CreatedAt: we.CreatedAt,
+		},
+		UpdateDate: &shared.UpdateDate{
+			UpdatedAt: we.UpdatedAt,
+		},
+		UserId: fmt.Sprintf("%d", we.UserId),
+		Url:    we.Url,
+		Secret: we.Secret,
+	}
+}
+
+func fromDelivery(d models.Delivery) webhookDeliveryEntity {
+	de := webhookDeliveryEntity{
+		WebhookId:      common.ParseUIntWithFallback(d.WebhookId, 0),
+		Event:          d.Event,
+		Payload:        d.Payload,
+		Status:         int(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		ResponseStatus: d.ResponseStatus,
+		Error:          d.Error,
+	}
+
+	if d.Entity != nil {
+		de.ID = common.ParseUIntWithFallback(d.ID, 0)
+	}
+	if d.CreateDate != nil {
+		de.CreatedAt = d.CreatedAt
+	}
+	if d.UpdateDate != nil {
+		de.UpdatedAt = d.UpdatedAt
+	}
+
+	return de
+}
+
+func toDelivery(de webhookDeliveryEntity) models.Delivery {
+	return models.Delivery{
+		Entity: &shared.Entity{
+			ID: fmt.Sprintf("%d", de.ID),
+		},
+		CreateDate: &shared.CreateDate{
+			CreatedAt: de.CreatedAt,
+		},
+		UpdateDate: &shared.UpdateDate{
+			UpdatedAt: de.UpdatedAt,
+		},
+		WebhookId:      fmt.Sprintf("%d", de.WebhookId),
+		Event:          de.Event,
+		Payload:        de.Payload,
+		Status:         models.DeliveryStatus(de.Status),
+		Attempts:       de.Attempts,
+		NextAttemptAt:  de.NextAttemptAt,
+		ResponseStatus: de.ResponseStatus,
+		Error:          de.Error,
+	}
+}
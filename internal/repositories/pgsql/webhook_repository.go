@@ -0,0 +1,285 @@
+package pgsql
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// createUserDeliveriesQuery copies a delivery for every webhook of a user.
+const createUserDeliveriesQuery = `
+INSERT INTO webhook_deliveries (webhook_id, event, payload, status, attempts, next_attempt_at, response_status, error, created_at, updated_at)
+SELECT id, @event, @payload, @status, 0, @next_attempt_at, 0, '', @now, @now
+FROM webhooks
+WHERE user_id = @user_id`
+
+// claimDueDeliveriesQuery postpones due pending deliveries by the lease and
+// returns them. Rows are locked with SKIP LOCKED, so concurrent dispatchers
+// never claim a delivery twice.
+const claimDueDeliveriesQuery = `
+UPDATE webhook_deliveries
+SET next_attempt_at = @leased_until, updated_at = @now
+WHERE id IN (
+	SELECT id FROM webhook_deliveries
+	WHERE status = @pending AND next_attempt_at <= @now
+	ORDER BY next_attempt_at, id
+	LIMIT @limit
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING *`
+
+func (r *Repository) CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	we := fromWebhook(webhook)
+	now := time.Now()
+	if we.CreatedAt.IsZero() {
+		we.CreatedAt = now
+	}
+	if we.UpdatedAt.IsZero() {
+		we.UpdatedAt = now
+	}
+
+	if err := r.db(ctx).Create(&we).Error; err != nil {
+		return models.Webhook{}, err
+	}
+
+	return toWebhook(we), nil
+}
+
+func (r *Repository) GetWebhooks(ctx context.Context, userId string) ([]models.Webhook, error) {
+	var wes []webhookEntity
+
+	err := r.db(ctx).
+		Where("user_id = ?", userId).
+		Order("id ASC").
+		Find(&wes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ws := make([]models.Webhook, len(wes))
+	for i := range wes {
+		ws[i] = toWebhook(wes[i])
+	}
+
+	return ws, nil
+}
+
+func (r *Repository) GetWebhook(ctx context.Context, userId string, id string) (models.Webhook, error) {
+	we := webhookEntity{}
+
+	err := r.db(ctx).First(&we, "id = ? AND user_id = ?", id, userId).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Webhook{}, models.WebhookNotExistError
+		}
+
+		return models.Webhook{}, err
+	}
+
+	return toWebhook(we), nil
+}
+
+func (r *Repository) UpdateWebhookSecret(
+	ctx context.Context,
+	userId string,
+	id string,
+	secret string,
+) (models.Webhook, error) {
+	we := webhookEntity{}
+
+	res := r.db(ctx).
+		Model(&we).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND user_id = ?", id, userId).
+		Updates(map[string]any{
+			"secret":     secret,
+			"updated_at": time.Now(),
+		})
+	if res.Error != nil {
+		return models.Webhook{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return models.Webhook{}, models.WebhookNotExistError
+	}
+
+	return toWebhook(we), nil
+}
+
+func (r *Repository) DeleteWebhook(ctx context.Context, userId string, id string) error {
+	res := r.db(ctx).Delete(&webhookEntity{}, "id = ? AND user_id = ?", id, userId)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return models.WebhookNotExistError
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateUserDeliveries(ctx context.Context, userId string, delivery models.Delivery) (int, error) {
+	de := fromDelivery(delivery)
+	if de.NextAttemptAt.IsZero() {
+		de.NextAttemptAt = time.Now()
+	}
+
+	res := r.db(ctx).
+		Exec(createUserDeliveriesQuery,
+			sql.Named("event", de.Event),
+			sql.Named("payload", de.Payload),
+			sql.Named("status", de.Status),
+			sql.Named("next_attempt_at", de.NextAttemptAt),
+			sql.Named("now", time.Now()),
+			sql.Named("user_id", userId),
+		)
+	if res.Error != nil {
+		return 0, res.Error
+	}
+
+	return int(res.RowsAffected), nil
+}
+
+func (r *Repository) CreateDelivery(ctx context.Context, delivery models.Delivery) (models.Delivery, error) {
+	de := fromDelivery(delivery)
+	now := time.Now()
+	if de.CreatedAt.IsZero() {
+		de.CreatedAt = now
+	}
+	if de.UpdatedAt.IsZero() {
+		de.UpdatedAt = now
+	}
+	if de.NextAttemptAt.IsZero() {
+		de.NextAttemptAt = now
+	}
+
+	if err := r.db(ctx).Create(&de).Error; err != nil {
+		return models.Delivery{}, err
+	}
+
+	d := toDelivery(de)
+	d.Webhook = delivery.Webhook
+	return d, nil
+}
+
+func (r *Repository) ClaimDueDeliveries(
+	ctx context.Context,
+	at time.Time,
+	lease time.Duration,
+	limit int,
+) ([]models.Delivery, error) {
+	var des []webhookDeliveryEntity
+	var wes []webhookEntity
+
+	err := r.db(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.WithContext(ctx).
+			Raw(claimDueDeliveriesQuery,
+				sql.Named("leased_until", at.Add(lease)),
+				sql.Named("now", at),
+				sql.Named("pending", int(models.DeliveryPending)),
+				sql.Named("limit", limit),
+			).
+			Scan(&des)
+		if res.Error != nil {
+			return res.Error
+		}
+		if len(des) == 0 {
+			return nil
+		}
+
+		webhookIds := make([]uint, len(des))
+		for i := range des {
+			webhookIds[i] = des[i].WebhookId
+		}
+
+		return tx.WithContext(ctx).
+			Where("id IN ?", webhookIds).
+			Find(&wes).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING has no defined order
+	slices.SortFunc(des, func(a, b webhookDeliveryEntity) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	webhooks := make(map[uint]webhookEntity, len(wes))
+	for i := range wes {
+		webhooks[wes[i].ID] = wes[i]
+	}
+
+	ds := make([]models.Delivery, 0, len(des))
+	for i := range des {
+		we, ok := webhooks[des[i].WebhookId]
+		if !ok {
+			// The webhook was deleted after the delivery was claimed.
+			continue
+		}
+
+		d := toDelivery(des[i])
+		d.Webhook = toWebhook(we)
+		ds = append(ds, d)
+	}
+
+	return ds, nil
+}
+
+func (r *Repository) UpdateDelivery(ctx context.Context, delivery models.Delivery) error {
+	de := fromDelivery(delivery)
+
+	res := r.db(ctx).
+		Model(&webhookDeliveryEntity{}).
+		Where("id = ?", de.ID).
+		Updates(map[string]any{
+			"status":          de.Status,
+			"attempts":        de.Attempts,
+			"next_attempt_at": de.NextAttemptAt,
+			"response_status": de.ResponseStatus,
+			"error":           de.Error,
+			"updated_at":      time.Now(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return models.DeliveryNotExistError
+	}
+
+	return nil
+}
+
+func (r *Repository) GetDeliveries(
+	ctx context.Context,
+	webhookId string,
+	skip int,
+	limit int,
+) ([]models.Delivery, error) {
+	var des []webhookDeliveryEntity
+
+	err := r.db(ctx).
+		Where("webhook_id = ?", webhookId).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(skip).
+		Find(&des).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ds := make([]models.Delivery, len(des))
+	for i := range des {
+		ds[i] = toDelivery(des[i])
+	}
+
+	return ds, nil
+}
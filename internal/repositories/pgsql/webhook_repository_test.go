@@ -0,0 +1,185 @@
+package pgsql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/models"
+	"github.com/stretchr/testify/assert"
+
+	umodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+)
+
+func TestRepository_CreateWebhook(t *testing.T) {
+	t.Run("should create webhook and get it only for its user", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{Name: "AshkanAbd"})
+		assert.NoError(t, err)
+
+		actualWebhook, actualErr := repo.CreateWebhook(ctx, models.Webhook{
+			UserId: createdUser.ID,
+			Url:    "https://example.com/hooks",
+			Secret: "whsec_1",
+		})
+		assert.NoError(t, actualErr)
+		assert.NotNil(t, actualWebhook.Entity)
+		assert.Equal(t, createdUser.ID, actualWebhook.UserId)
+
+		gotWebhook, actualErr := repo.GetWebhook(ctx, createdUser.ID, actualWebhook.ID)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, "https://example.com/hooks", gotWebhook.Url)
+
+		_, actualErr = repo.GetWebhook(ctx, "0", actualWebhook.ID)
+		assert.Equal(t, models.WebhookNotExistError, actualErr)
+	})
+}
+
+func TestRepository_UpdateWebhookSecret(t *testing.T) {
+	t.Run("should replace secret of webhook", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{Name: "AshkanAbd"})
+		assert.NoError(t, err)
+
+		createdWebhook, err := repo.CreateWebhook(ctx, models.Webhook{
+			UserId: createdUser.ID,
+			Url:    "https://example.com/hooks",
+			Secret: "whsec_1",
+		})
+		assert.NoError(t, err)
+
+		actualWebhook, actualErr := repo.UpdateWebhookSecret(ctx, createdUser.ID, createdWebhook.ID, "whsec_2")
+		assert.NoError(t, actualErr)
+		assert.Equal(t, "whsec_2", actualWebhook.Secret)
+
+		_, actualErr = repo.UpdateWebhookSecret(ctx, "0", createdWebhook.ID, "whsec_3")
+		assert.Equal(t, models.WebhookNotExistError, actualErr)
+	})
+}
+
+func TestRepository_DeleteWebhook(t *testing.T) {
+	t.Run("should return WebhookNotExistError when webhook does not exist", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		actualErr := repo.DeleteWebhook(ctx, "1", "1")
+		assert.Equal(t, models.WebhookNotExistError, actualErr)
+	})
+}
+
+func TestRepository_ClaimDueDeliveries(t *testing.T) {
+	t.Run("should claim due deliveries of every webhook once", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{Name: "AshkanAbd"})
+		assert.NoError(t, err)
+
+		for _, url := range []string{"https://example.com/a", "https://example.com/b"} {
+			_, err = repo.CreateWebhook(ctx, models.Webhook{
+				UserId: createdUser.ID,
+				Url:    url,
+				Secret: "whsec_1",
+			})
+			assert.NoError(t, err)
+		}
+
+		now := time.Now()
+		actualQueued, actualErr := repo.CreateUserDeliveries(ctx, createdUser.ID, models.Delivery{
+			Event:         models.EventMessageStatus,
+			Payload:       `{"event":"message.status"}`,
+			Status:        models.DeliveryPending,
+			NextAttemptAt: now.Add(-time.Second),
+		})
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 2, actualQueued)
+
+		actualDeliveries, actualErr := repo.ClaimDueDeliveries(ctx, now, time.Minute, 10)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 2, len(actualDeliveries))
+		assert.Equal(t, "https://example.com/a", actualDeliveries[0].Webhook.Url)
+		assert.Equal(t, "https://example.com/b", actualDeliveries[1].Webhook.Url)
+
+		actualDeliveries, actualErr = repo.ClaimDueDeliveries(ctx, now, time.Minute, 10)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 0, len(actualDeliveries))
+	})
+}
+
+func TestRepository_UpdateDelivery(t *testing.T) {
+	t.Run("should store outcome of delivery attempt", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initDB()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanDB(conn)
+			assert.NoError(t, err)
+		}()
+
+		createdUser, err := repo.CreateUser(ctx, umodels.User{Name: "AshkanAbd"})
+		assert.NoError(t, err)
+
+		createdWebhook, err := repo.CreateWebhook(ctx, models.Webhook{
+			UserId: createdUser.ID,
+			Url:    "https://example.com/hooks",
+			Secret: "whsec_1",
+		})
+		assert.NoError(t, err)
+
+		createdDelivery, err := repo.CreateDelivery(ctx, models.Delivery{
+			WebhookId: createdWebhook.ID,
+			Event:     models.EventMessageStatus,
+			Payload:   `{"event":"message.status"}`,
+			Status:    models.DeliveryPending,
+		})
+		assert.NoError(t, err)
+
+		createdDelivery.Status = models.DeliveryFailed
+		createdDelivery.Attempts = 8
+		createdDelivery.ResponseStatus = 500
+		createdDelivery.Error = "unexpected response status 500"
+		actualErr := repo.UpdateDelivery(ctx, createdDelivery)
+		assert.NoError(t, actualErr)
+
+		actualDeliveries, actualErr := repo.GetDeliveries(ctx, createdWebhook.ID, 0, 10)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 1, len(actualDeliveries))
+		assert.Equal(t, models.DeliveryFailed, actualDeliveries[0].Status)
+		assert.Equal(t, 8, actualDeliveries[0].Attempts)
+		assert.Equal(t, 500, actualDeliveries[0].ResponseStatus)
+	})
+}
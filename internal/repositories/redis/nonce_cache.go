@@ -0,0 +1,12 @@
+package redis
+
+import (
+	"context"
+	"time"
+)
+
+const noncePrefix = "nonce:"
+
+func (r *Repository) ReserveNonce(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	return r.cacheClient.SetNX(ctx, noncePrefix+nonce, 1, ttl).Result()
+}
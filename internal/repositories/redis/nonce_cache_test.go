@@ -0,0 +1,56 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_ReserveNonce(t *testing.T) {
+	t.Run("should reserve nonce only once", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		actualFresh, actualErr := repo.ReserveNonce(ctx, "sgw_01234567:nonce-1", time.Minute)
+		assert.NoError(t, actualErr)
+		assert.True(t, actualFresh)
+
+		actualFresh, actualErr = repo.ReserveNonce(ctx, "sgw_01234567:nonce-1", time.Minute)
+		assert.NoError(t, actualErr)
+		assert.False(t, actualFresh)
+
+		actualFresh, actualErr = repo.ReserveNonce(ctx, "sgw_01234567:nonce-2", time.Minute)
+		assert.NoError(t, actualErr)
+		assert.True(t, actualFresh)
+	})
+
+	t.Run("should reserve nonce again after it expires", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		_, err = repo.ReserveNonce(ctx, "sgw_01234567:nonce-1", 50*time.Millisecond)
+		assert.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+
+		actualFresh, actualErr := repo.ReserveNonce(ctx, "sgw_01234567:nonce-1", time.Minute)
+		assert.NoError(t, actualErr)
+		assert.True(t, actualFresh)
+	})
+}
@@ -0,0 +1,96 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	"github.com/redis/go-redis/v9"
+)
+
+const throughputPrefix = "throughput:"
+
+// acquireSendScript frees in-flight slots whose lease expired, then takes an
+// in-flight slot and a token of the provider bucket, or nothing when either
+// is used up. The bucket refills by the redis clock so all gateway instances
+// share one clock.
+var acquireSendScript = redis.NewScript(`
+local bucket = KEYS[1]
+local inFlight = KEYS[2]
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local maxInFlight = tonumber(ARGV[3])
+local lease = tonumber(ARGV[4])
+local time = redis.call('TIME')
+local nowMs = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local now = nowMs / 1000
+
+if maxInFlight > 0 then
+	redis.call('ZREMRANGEBYSCORE', inFlight, '-inf', string.format('%d', nowMs))
+	if redis.call('ZCARD', inFlight) >= maxInFlight then
+		return {'in_flight', '0'}
+	end
+end
+
+if rate > 0 then
+	local state = redis.call('HMGET', bucket, 'tokens', 'ts')
+	local tokens = tonumber(state[1]) or burst
+	local ts = tonumber(state[2]) or now
+	tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+	if tokens < 1 then
+		return {'rate', tostring((1 - tokens) / rate)}
+	end
+
+	redis.call('HSET', bucket, 'tokens', tokens - 1, 'ts', now)
+	redis.call('PEXPIRE', bucket, string.format('%d', math.ceil(burst / rate * 1000) + 1000))
+end
+
+if maxInFlight > 0 then
+	redis.call('ZADD', inFlight, string.format('%d', nowMs + lease), ARGV[5])
+	redis.call('PEXPIRE', inFlight, string.format('%d', lease + 1000))
+end
+
+return {'', '0'}
+`)
+
+func (r *Repository) AcquireSend(
+	ctx context.Context,
+	provider string,
+	limit models.ProviderLimit,
+) (string, models.Throttle, error) {
+	token := ""
+	if limit.MaxInFlight > 0 {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return "", models.Throttle{}, err
+		}
+		token = hex.EncodeToString(b)
+	}
+
+	res, err := acquireSendScript.Run(ctx, r.cacheClient,
+		[]string{r.throughputKey(provider, "bucket"), r.throughputKey(provider, "in_flight")},
+		limit.TPS, limit.Burst, limit.MaxInFlight, limit.Lease.Milliseconds(), token,
+	).StringSlice()
+	if err != nil {
+		return "", models.Throttle{}, err
+	}
+	if res[0] != "" {
+		wait, err := parseSeconds(res[1])
+		if err != nil {
+			return "", models.Throttle{}, err
+		}
+
+		return "", models.Throttle{Reason: models.ThrottleReason(res[0]), Wait: wait}, nil
+	}
+
+	return token, models.Throttle{}, nil
+}
+
+func (r *Repository) ReleaseSend(ctx context.Context, provider string, token string) error {
+	return r.cacheClient.ZRem(ctx, r.throughputKey(provider, "in_flight"), token).Err()
+}
+
+func (r *Repository) throughputKey(provider string, name string) string {
+	return throughputPrefix + provider + ":" + name
+}
@@ -0,0 +1,91 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_AcquireSend(t *testing.T) {
+	t.Run("should throttle by rate when bucket is empty", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		limit := models.ProviderLimit{TPS: 1, Burst: 1}
+
+		token, throttle, actualErr := repo.AcquireSend(ctx, "rest", limit)
+		assert.NoError(t, actualErr)
+		assert.Empty(t, token)
+		assert.Equal(t, models.Throttle{}, throttle)
+
+		_, throttle, actualErr = repo.AcquireSend(ctx, "rest", limit)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.ThrottleRate, throttle.Reason)
+		assert.Greater(t, throttle.Wait, 900*time.Millisecond)
+		assert.LessOrEqual(t, throttle.Wait, time.Second)
+	})
+
+	t.Run("should throttle by in-flight sends until one is released", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		limit := models.ProviderLimit{MaxInFlight: 1, Lease: time.Minute}
+
+		token, throttle, actualErr := repo.AcquireSend(ctx, "rest", limit)
+		assert.NoError(t, actualErr)
+		assert.NotEmpty(t, token)
+		assert.Equal(t, models.Throttle{}, throttle)
+
+		_, throttle, actualErr = repo.AcquireSend(ctx, "rest", limit)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.ThrottleInFlight, throttle.Reason)
+
+		actualErr = repo.ReleaseSend(ctx, "rest", token)
+		assert.NoError(t, actualErr)
+
+		_, throttle, actualErr = repo.AcquireSend(ctx, "rest", limit)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.Throttle{}, throttle)
+	})
+
+	t.Run("should free in-flight send when its lease expires", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		limit := models.ProviderLimit{MaxInFlight: 1, Lease: 50 * time.Millisecond}
+
+		_, throttle, actualErr := repo.AcquireSend(ctx, "rest", limit)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.Throttle{}, throttle)
+
+		time.Sleep(100 * time.Millisecond)
+
+		_, throttle, actualErr = repo.AcquireSend(ctx, "rest", limit)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.Throttle{}, throttle)
+	})
+}
@@ -1,8 +1,10 @@
 package redis
 
 import (
+	"sync"
 	"time"
 
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
 	"github.com/redis/go-redis/v9"
 
 	pkgRedis "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/redis"
@@ -12,16 +14,131 @@ type Config struct {
 	QueueDB      int           `mapstructure:"queue_db"`
 	QueueName    string        `mapstructure:"queue_name"`
 	QueueTimeout time.Duration `mapstructure:"queue_timeout"`
+	// Reliable moves popped messages into a processing list until they are
+	// acknowledged, so messages of crashed workers can be re-delivered.
+	Reliable bool `mapstructure:"reliable"`
+	// VisibilityTimeout is how long a popped message is left to its worker
+	// before it is re-delivered, or in non-reliable mode, reconciled.
+	VisibilityTimeout time.Duration   `mapstructure:"visibility_timeout"`
+	PriorityWeights   PriorityWeights `mapstructure:"priority_weights"`
+	// CacheDB holds short-lived keys such as request nonces and send limits.
+	CacheDB int `mapstructure:"cache_db"`
+}
+
+// PriorityWeights is the share of pops each priority lane gets while every
+// lane has messages. A lane with zero weight is only served when the others
+// are empty.
+type PriorityWeights struct {
+	High   int `mapstructure:"high"`
+	Normal int `mapstructure:"normal"`
+	Low    int `mapstructure:"low"`
 }
 
 type Repository struct {
 	queueClient *redis.Client
+	cacheClient *redis.Client
 	cfg         Config
+
+	inflightM sync.Mutex
+	inflight  map[string]string
+
+	laneM       sync.Mutex
+	laneWeights map[models.SmsPriority]int
+	laneCurrent map[models.SmsPriority]int
 }
 
 func NewRepository(cfg Config, conn *pkgRedis.Connector) *Repository {
+	if cfg.VisibilityTimeout <= 0 {
+		cfg.VisibilityTimeout = 5 * time.Minute
+	}
+	if cfg.PriorityWeights.High <= 0 && cfg.PriorityWeights.Normal <= 0 && cfg.PriorityWeights.Low <= 0 {
+		cfg.PriorityWeights = PriorityWeights{
+			High:   6,
+			Normal: 3,
+			Low:    1,
+		}
+	}
+
 	return &Repository{
 		cfg:         cfg,
 		queueClient: conn.GetClient(cfg.QueueDB),
+		cacheClient: conn.GetClient(cfg.CacheDB),
+		inflight:    make(map[string]string),
+		laneWeights: map[models.SmsPriority]int{
+			models.PriorityHigh:   cfg.PriorityWeights.High,
+			models.PriorityNormal: cfg.PriorityWeights.Normal,
+			models.PriorityLow:    cfg.PriorityWeights.Low,
+		},
+		laneCurrent: make(map[models.SmsPriority]int),
 	}
 }
+
+// laneKey returns the list of a priority lane. Normal priority keeps the
+// plain queue name so messages queued before lanes existed are still served.
+func (r *Repository) laneKey(priority models.SmsPriority) string {
+	switch priority {
+	case models.PriorityHigh:
+		return r.cfg.QueueName + ":high"
+	case models.PriorityLow:
+		return r.cfg.QueueName + ":low"
+	default:
+		return r.cfg.QueueName
+	}
+}
+
+func (r *Repository) processingKey(priority models.SmsPriority) string {
+	return r.laneKey(priority) + ":processing"
+}
+
+func (r *Repository) deadlinesKey(priority models.SmsPriority) string {
+	return r.laneKey(priority) + ":deadlines"
+}
+
+// idsKey is a sorted set of the ids of queued messages, scored by
+// waitingScore while they wait or are held in a processing list, and by their
+// lease deadline while a worker holds them in non-reliable mode.
+func (r *Repository) idsKey() string {
+	return r.cfg.QueueName + ":ids"
+}
+
+// acceptedKey is the hash of send results of accepted messages whose sent
+// status is not recorded yet, by message id.
+func (r *Repository) acceptedKey() string {
+	return r.cfg.QueueName + ":accepted"
+}
+
+// nextLanes picks a lane with smooth weighted round-robin and returns it
+// followed by the other lanes from the most to the least urgent.
+func (r *Repository) nextLanes() []models.SmsPriority {
+	r.laneM.Lock()
+	total := 0
+	picked, found := models.PriorityNormal, false
+	for _, priority := range models.Priorities {
+		weight := r.laneWeights[priority]
+		if weight <= 0 {
+			continue
+		}
+
+		r.laneCurrent[priority] += weight
+		total += weight
+		if !found || r.laneCurrent[priority] > r.laneCurrent[picked] {
+			picked, found = priority, true
+		}
+	}
+	if found {
+		r.laneCurrent[picked] -= total
+	}
+	r.laneM.Unlock()
+
+	lanes := make([]models.SmsPriority, 0, len(models.Priorities))
+	if found {
+		lanes = append(lanes, picked)
+	}
+	for _, priority := range models.Priorities {
+		if !found || priority != picked {
+			lanes = append(lanes, priority)
+		}
+	}
+
+	return lanes
+}
@@ -0,0 +1,167 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/models"
+	"github.com/redis/go-redis/v9"
+)
+
+const rateLimitPrefix = "ratelimit:"
+
+// takeUsageScript checks every bucket and counter before taking from any of
+// them, so a send is either fully counted or not at all. Buckets are hashes
+// of their tokens and last refill, refilled by the time passed on the redis
+// clock so all gateway instances share one clock. A send larger than a bucket
+// passes when the bucket is full and leaves it in debt.
+var takeUsageScript = redis.NewScript(`
+local n = tonumber(ARGV[1])
+local bucketCount = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) + tonumber(time[2]) / 1000000
+
+local bucketWait = 0
+local counterWait = 0
+local tokens = {}
+for i = 1, bucketCount do
+	local rate = tonumber(ARGV[1 + i * 2])
+	local burst = tonumber(ARGV[2 + i * 2])
+	local state = redis.call('HMGET', KEYS[i], 'tokens', 'ts')
+	local available = tonumber(state[1]) or burst
+	local ts = tonumber(state[2]) or now
+	available = math.min(burst, available + math.max(0, now - ts) * rate)
+	tokens[i] = available
+
+	local need = math.min(n, burst)
+	if available < need then
+		bucketWait = math.max(bucketWait, (need - available) / rate)
+	end
+end
+
+local offset = 2 + bucketCount * 2
+for i = bucketCount + 1, #KEYS do
+	local limit = tonumber(ARGV[offset + (i - bucketCount) * 2 - 1])
+	local resetAt = tonumber(ARGV[offset + (i - bucketCount) * 2])
+	local used = tonumber(redis.call('GET', KEYS[i]) or '0')
+	if limit > 0 and used + n > limit then
+		counterWait = math.max(counterWait, resetAt / 1000 - now)
+	end
+end
+
+if bucketWait > 0 or counterWait > 0 then
+	return {tostring(bucketWait), tostring(counterWait)}
+end
+
+for i = 1, bucketCount do
+	local rate = tonumber(ARGV[1 + i * 2])
+	local burst = tonumber(ARGV[2 + i * 2])
+	local left = tokens[i] - n
+	redis.call('HSET', KEYS[i], 'tokens', left, 'ts', now)
+	redis.call('PEXPIRE', KEYS[i], string.format('%d', math.ceil((burst - left) / rate * 1000) + 1000))
+end
+for i = bucketCount + 1, #KEYS do
+	redis.call('INCRBY', KEYS[i], ARGV[1])
+	redis.call('PEXPIREAT', KEYS[i], ARGV[offset + (i - bucketCount) * 2])
+end
+
+return {'0', '0'}
+`)
+
+// returnUsageScript only decrements counters that still exist, so a counter
+// of a period that ended is not created again without an expiry.
+var returnUsageScript = redis.NewScript(`
+for i = 1, #KEYS do
+	if redis.call('EXISTS', KEYS[i]) == 1 then
+		redis.call('DECRBY', KEYS[i], ARGV[1])
+	end
+end
+
+return 0
+`)
+
+func (r *Repository) TakeUsage(
+	ctx context.Context,
+	buckets []models.Bucket,
+	counters []models.Counter,
+	n int,
+) (time.Duration, time.Duration, error) {
+	keys := make([]string, 0, len(buckets)+len(counters))
+	args := make([]any, 0, 2+2*len(buckets)+2*len(counters))
+	args = append(args, n, len(buckets))
+	for _, bucket := range buckets {
+		keys = append(keys, rateLimitPrefix+bucket.Key)
+		args = append(args, bucket.Rate, bucket.Burst)
+	}
+	for _, counter := range counters {
+		keys = append(keys, rateLimitPrefix+counter.Key)
+		args = append(args, counter.Limit, counter.ResetAt.UnixMilli())
+	}
+
+	res, err := takeUsageScript.Run(ctx, r.cacheClient, keys, args...).StringSlice()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	bucketWait, err := parseSeconds(res[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	counterWait, err := parseSeconds(res[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return bucketWait, counterWait, nil
+}
+
+func (r *Repository) ReturnUsage(ctx context.Context, counters []models.Counter, n int) error {
+	keys := make([]string, len(counters))
+	for i := range counters {
+		keys[i] = rateLimitPrefix + counters[i].Key
+	}
+
+	return returnUsageScript.Run(ctx, r.cacheClient, keys, n).Err()
+}
+
+func (r *Repository) GetUsage(ctx context.Context, counters []models.Counter) ([]int, error) {
+	if len(counters) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(counters))
+	for i := range counters {
+		keys[i] = rateLimitPrefix + counters[i].Key
+	}
+
+	values, err := r.cacheClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	used := make([]int, len(values))
+	for i, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		count, err := strconv.Atoi(str)
+		if err != nil {
+			return nil, err
+		}
+		used[i] = max(count, 0)
+	}
+
+	return used, nil
+}
+
+func parseSeconds(value string) (time.Duration, error) {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -0,0 +1,97 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_TakeUsage(t *testing.T) {
+	t.Run("should take tokens until bucket is empty", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		buckets := []models.Bucket{{Key: "1:second", Rate: 1, Burst: 2}}
+
+		bucketWait, counterWait, actualErr := repo.TakeUsage(ctx, buckets, nil, 2)
+		assert.NoError(t, actualErr)
+		assert.Zero(t, bucketWait)
+		assert.Zero(t, counterWait)
+
+		bucketWait, counterWait, actualErr = repo.TakeUsage(ctx, buckets, nil, 1)
+		assert.NoError(t, actualErr)
+		assert.Greater(t, bucketWait, 900*time.Millisecond)
+		assert.LessOrEqual(t, bucketWait, time.Second)
+		assert.Zero(t, counterWait)
+	})
+
+	t.Run("should let send larger than bucket pass when bucket is full", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		buckets := []models.Bucket{{Key: "1:second", Rate: 10, Burst: 10}}
+
+		bucketWait, _, actualErr := repo.TakeUsage(ctx, buckets, nil, 25)
+		assert.NoError(t, actualErr)
+		assert.Zero(t, bucketWait)
+
+		bucketWait, _, actualErr = repo.TakeUsage(ctx, buckets, nil, 1)
+		assert.NoError(t, actualErr)
+		assert.Greater(t, bucketWait, time.Second)
+	})
+
+	t.Run("should count usage and reject it over limit without taking tokens", func(t *testing.T) {
+		ctx := context.Background()
+
+		conn, repo, err := initRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		resetAt := time.Now().Add(time.Hour)
+		buckets := []models.Bucket{{Key: "1:minute", Rate: 1, Burst: 60}}
+		counters := []models.Counter{
+			{Key: "1:daily:20261017", Limit: 5, ResetAt: resetAt},
+			{Key: "1:monthly:202610", ResetAt: resetAt},
+		}
+
+		_, counterWait, actualErr := repo.TakeUsage(ctx, buckets, counters, 4)
+		assert.NoError(t, actualErr)
+		assert.Zero(t, counterWait)
+
+		_, counterWait, actualErr = repo.TakeUsage(ctx, buckets, counters, 2)
+		assert.NoError(t, actualErr)
+		assert.Greater(t, counterWait, 59*time.Minute)
+
+		actualUsage, actualErr := repo.GetUsage(ctx, counters)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, []int{4, 4}, actualUsage)
+
+		actualErr = repo.ReturnUsage(ctx, counters, 3)
+		assert.NoError(t, actualErr)
+
+		actualUsage, actualErr = repo.GetUsage(ctx, counters)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, []int{1, 1}, actualUsage)
+	})
+}
@@ -3,6 +3,7 @@ package redis
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/AshkanAbd/arvancloud_sms_gateway/common"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
@@ -11,15 +12,84 @@ import (
 
 const (
 	wrongTypeError = "WRONGTYPE Operation against a key holding the wrong kind of value"
+
+	// waitingScore marks ids that stay queued until they are acknowledged. It
+	// is negative since ZMSCORE reports missing members as 0.
+	waitingScore = -1
+
+	// emptyPollInterval is how often a reliable queue polls its lanes while
+	// they are all empty.
+	emptyPollInterval = 50 * time.Millisecond
 )
 
+// recoverScript moves in-flight messages whose visibility deadline passed back
+// to the queue. Messages without a deadline belong to workers that crashed
+// between BLMOVE and ZADD, so they get a fresh deadline instead.
+var recoverScript = redis.NewScript(`
+local processing = KEYS[1]
+local deadlines = KEYS[2]
+local queue = KEYS[3]
+local now = tonumber(ARGV[1])
+local orphanDeadline = tonumber(ARGV[2])
+
+local recovered = 0
+for _, payload in ipairs(redis.call('LRANGE', processing, 0, -1)) do
+	local deadline = redis.call('ZSCORE', deadlines, payload)
+	if not deadline then
+		redis.call('ZADD', deadlines, orphanDeadline, payload)
+	elseif tonumber(deadline) <= now then
+		redis.call('LREM', processing, 1, payload)
+		redis.call('ZREM', deadlines, payload)
+		redis.call('RPUSH', queue, payload)
+		recovered = recovered + 1
+	end
+end
+
+return recovered
+`)
+
+// popScript takes the oldest message of the first non-empty lane and, when it
+// has an id, leases it in the ids set in the same step, so a worker crashing
+// in between can not leave the id marked as waiting forever.
+var popScript = redis.NewScript(`
+local ids = KEYS[#KEYS]
+local leaseDeadline = tonumber(ARGV[1])
+
+for i = 1, #KEYS - 1 do
+	local payload = redis.call('RPOP', KEYS[i])
+	if payload then
+		local ok, msg = pcall(cjson.decode, payload)
+		if ok and type(msg) == 'table' and type(msg.ID) == 'string' and msg.ID ~= '' then
+			redis.call('ZADD', ids, leaseDeadline, msg.ID)
+		end
+		return {payload, i - 1}
+	end
+end
+
+return false
+`)
+
 func (r *Repository) Enqueue(ctx context.Context, msg []models.Sms) error {
-	strValues := make([]any, len(msg))
+	lanes := make(map[models.SmsPriority][]any)
+	var ids []redis.Z
 	for i := range msg {
-		strValues[i] = common.ValueToJSON(msg[i])
+		lanes[msg[i].Priority] = append(lanes[msg[i].Priority], common.ValueToJSON(msg[i]))
+		if msg[i].Entity != nil {
+			ids = append(ids, redis.Z{Score: waitingScore, Member: msg[i].ID})
+		}
 	}
 
-	_, err := r.queueClient.LPush(ctx, r.cfg.QueueName, strValues...).Result()
+	_, err := r.queueClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, priority := range models.Priorities {
+			if strValues, ok := lanes[priority]; ok {
+				pipe.LPush(ctx, r.laneKey(priority), strValues...)
+			}
+		}
+		if len(ids) > 0 {
+			pipe.ZAdd(ctx, r.idsKey(), ids...)
+		}
+		return nil
+	})
 	if err != nil {
 		if err.Error() == wrongTypeError {
 			return models.InvalidQueueError
@@ -32,7 +102,12 @@ func (r *Repository) Enqueue(ctx context.Context, msg []models.Sms) error {
 }
 
 func (r *Repository) GetLength(ctx context.Context) (int, error) {
-	count, err := r.queueClient.LLen(ctx, r.cfg.QueueName).Result()
+	cmds, err := r.queueClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, priority := range models.Priorities {
+			pipe.LLen(ctx, r.laneKey(priority))
+		}
+		return nil
+	})
 	if err != nil {
 		if err.Error() == wrongTypeError {
 			return 0, models.InvalidQueueError
@@ -41,11 +116,18 @@ func (r *Repository) GetLength(ctx context.Context) (int, error) {
 		return 0, err
 	}
 
-	return int(count), err
+	count := 0
+	for _, cmd := range cmds {
+		count += int(cmd.(*redis.IntCmd).Val())
+	}
+
+	return count, nil
 }
 
+// Pop takes the oldest message of the lane picked by priority weights, falling
+// back to the other lanes from the most to the least urgent when it is empty.
 func (r *Repository) Pop(ctx context.Context) (models.Sms, error) {
-	res, err := r.queueClient.BRPop(ctx, r.cfg.QueueTimeout, r.cfg.QueueName).Result()
+	payload, lane, err := r.popPayload(ctx, r.nextLanes())
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			return models.Sms{}, models.EmptyQueueError
@@ -57,10 +139,264 @@ func (r *Repository) Pop(ctx context.Context) (models.Sms, error) {
 		return models.Sms{}, err
 	}
 
+	if r.cfg.Reliable {
+		deadline := time.Now().Add(r.cfg.VisibilityTimeout)
+		if err := r.queueClient.ZAdd(ctx, r.deadlinesKey(lane), redis.Z{
+			Score:  float64(deadline.Unix()),
+			Member: payload,
+		}).Err(); err != nil {
+			return models.Sms{}, err
+		}
+	}
+
 	s := models.Sms{}
-	if err := common.JSONToValue[models.Sms](res[1], &s); err != nil {
-		return models.Sms{}, err
+	if err := common.JSONToValue[models.Sms](payload, &s); err != nil {
+		if r.cfg.Reliable {
+			// An undecodable payload never becomes decodable, so it is taken
+			// out of the processing list instead of being re-delivered.
+			if _, ackErr := r.queueClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.LRem(ctx, r.processingKey(lane), 1, payload)
+				pipe.ZRem(ctx, r.deadlinesKey(lane), payload)
+				return nil
+			}); ackErr != nil {
+				return models.Sms{}, ackErr
+			}
+		}
+
+		return models.Sms{}, &models.PayloadError{
+			Payload: payload,
+			Err:     err,
+		}
+	}
+
+	if r.cfg.Reliable && s.Entity != nil {
+		r.inflightM.Lock()
+		r.inflight[s.ID] = payload
+		r.inflightM.Unlock()
 	}
 
 	return s, nil
 }
+
+func (r *Repository) popPayload(ctx context.Context, lanes []models.SmsPriority) (string, models.SmsPriority, error) {
+	// neither BRPOP nor BLMOVE can lease the id in the same step or watch
+	// every lane into its own processing list, so the lanes are polled until
+	// the queue timeout instead
+	deadline := time.Now().Add(r.cfg.QueueTimeout)
+	for {
+		payload, lane, err := r.popLanes(ctx, lanes)
+		if !errors.Is(err, redis.Nil) {
+			return payload, lane, err
+		}
+
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return "", 0, redis.Nil
+		}
+		select {
+		case <-time.After(min(wait, emptyPollInterval)):
+		case <-ctx.Done():
+			return "", 0, ctx.Err()
+		}
+	}
+}
+
+// popLanes takes a message from the first non-empty lane, or returns
+// redis.Nil when they are all empty.
+func (r *Repository) popLanes(ctx context.Context, lanes []models.SmsPriority) (string, models.SmsPriority, error) {
+	if !r.cfg.Reliable {
+		// the popped message is only kept by this worker, so it stays queued
+		// for the lease and is considered lost after it
+		keys := make([]string, 0, len(lanes)+1)
+		for _, lane := range lanes {
+			keys = append(keys, r.laneKey(lane))
+		}
+		keys = append(keys, r.idsKey())
+
+		res, err := popScript.Run(ctx, r.queueClient, keys, time.Now().Add(r.cfg.VisibilityTimeout).Unix()).Slice()
+		if err != nil {
+			return "", 0, err
+		}
+
+		return res[0].(string), lanes[res[1].(int64)], nil
+	}
+
+	for _, lane := range lanes {
+		payload, err := r.queueClient.LMove(ctx, r.laneKey(lane), r.processingKey(lane), "RIGHT", "LEFT").Result()
+		if err == nil {
+			return payload, lane, nil
+		}
+		if !errors.Is(err, redis.Nil) {
+			return "", 0, err
+		}
+	}
+
+	return "", 0, redis.Nil
+}
+
+// Ack removes a popped message from the queued ids and, in reliable mode,
+// from the processing list.
+func (r *Repository) Ack(ctx context.Context, msg models.Sms) error {
+	_, err := r.queueClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if r.cfg.Reliable {
+			payload := r.takeInflight(msg)
+			pipe.LRem(ctx, r.processingKey(msg.Priority), 1, payload)
+			pipe.ZRem(ctx, r.deadlinesKey(msg.Priority), payload)
+		}
+		if msg.Entity != nil {
+			pipe.ZRem(ctx, r.idsKey(), msg.ID)
+		}
+		return nil
+	})
+	if err != nil && err.Error() == wrongTypeError {
+		return models.InvalidQueueError
+	}
+
+	return err
+}
+
+// Nack puts a popped message back at the head of its lane so it is delivered
+// again right away.
+func (r *Repository) Nack(ctx context.Context, msg models.Sms) error {
+	payload := r.takeInflight(msg)
+	_, err := r.queueClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if r.cfg.Reliable {
+			pipe.LRem(ctx, r.processingKey(msg.Priority), 1, payload)
+			pipe.ZRem(ctx, r.deadlinesKey(msg.Priority), payload)
+		}
+		pipe.RPush(ctx, r.laneKey(msg.Priority), payload)
+		if msg.Entity != nil {
+			pipe.ZAdd(ctx, r.idsKey(), redis.Z{Score: waitingScore, Member: msg.ID})
+		}
+		return nil
+	})
+	if err != nil && err.Error() == wrongTypeError {
+		return models.InvalidQueueError
+	}
+
+	return err
+}
+
+// RecoverUnacked re-delivers in-flight messages whose visibility timeout
+// expired and returns their count.
+func (r *Repository) RecoverUnacked(ctx context.Context) (int, error) {
+	if !r.cfg.Reliable {
+		return 0, nil
+	}
+
+	now := time.Now()
+	total := 0
+	for _, lane := range models.Priorities {
+		recovered, err := recoverScript.Run(ctx, r.queueClient,
+			[]string{r.processingKey(lane), r.deadlinesKey(lane), r.laneKey(lane)},
+			now.Unix(), now.Add(r.cfg.VisibilityTimeout).Unix(),
+		).Int()
+		if err != nil {
+			if err.Error() == wrongTypeError {
+				return 0, models.InvalidQueueError
+			}
+
+			return 0, err
+		}
+		total += recovered
+	}
+
+	return total, nil
+}
+
+// GetQueuedIds returns those of ids that are waiting, in a processing list,
+// or held by a worker whose lease has not expired.
+func (r *Repository) GetQueuedIds(ctx context.Context, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	scores, err := r.queueClient.ZMScore(ctx, r.idsKey(), ids...).Result()
+	if err != nil {
+		if err.Error() == wrongTypeError {
+			return nil, models.InvalidQueueError
+		}
+
+		return nil, err
+	}
+
+	now := float64(time.Now().Unix())
+	var queued []string
+	for i, score := range scores {
+		if score == waitingScore || score > now {
+			queued = append(queued, ids[i])
+		}
+	}
+
+	return queued, nil
+}
+
+func (r *Repository) SetAccepted(ctx context.Context, id string, res models.SendResult) error {
+	err := r.queueClient.HSet(ctx, r.acceptedKey(), id, common.ValueToJSON(res)).Err()
+	if err != nil && err.Error() == wrongTypeError {
+		return models.InvalidQueueError
+	}
+
+	return err
+}
+
+func (r *Repository) GetAccepted(ctx context.Context, ids []string) (map[string]models.SendResult, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	values, err := r.queueClient.HMGet(ctx, r.acceptedKey(), ids...).Result()
+	if err != nil {
+		if err.Error() == wrongTypeError {
+			return nil, models.InvalidQueueError
+		}
+
+		return nil, err
+	}
+
+	accepted := make(map[string]models.SendResult)
+	for i, value := range values {
+		payload, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		res := models.SendResult{}
+		if err := common.JSONToValue[models.SendResult](payload, &res); err != nil {
+			return nil, err
+		}
+		accepted[ids[i]] = res
+	}
+
+	return accepted, nil
+}
+
+func (r *Repository) RemoveAccepted(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err := r.queueClient.HDel(ctx, r.acceptedKey(), ids...).Err()
+	if err != nil && err.Error() == wrongTypeError {
+		return models.InvalidQueueError
+	}
+
+	return err
+}
+
+func (r *Repository) takeInflight(msg models.Sms) string {
+	if msg.Entity == nil {
+		return common.ValueToJSON(msg)
+	}
+
+	r.inflightM.Lock()
+	defer r.inflightM.Unlock()
+
+	payload, ok := r.inflight[msg.ID]
+	if !ok {
+		return common.ValueToJSON(msg)
+	}
+	delete(r.inflight, msg.ID)
+
+	return payload
+}
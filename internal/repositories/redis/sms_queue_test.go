@@ -10,9 +10,11 @@ import (
 	"github.com/AshkanAbd/arvancloud_sms_gateway/common"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/redis"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
 	"github.com/stretchr/testify/assert"
 
 	pkgRedis "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/redis"
+	goredis "github.com/redis/go-redis/v9"
 )
 
 const (
@@ -39,6 +41,26 @@ func initRedis() (*pkgRedis.Connector, *redis.Repository, error) {
 	return conn, repo, nil
 }
 
+func initReliableRedis() (*pkgRedis.Connector, *redis.Repository, error) {
+	connectorCfg := pkgRedis.Config{
+		Addr:     os.Getenv("REDIS_ADDRESS"),
+		Password: os.Getenv("REDIS_PASSWORD"),
+	}
+
+	conn := pkgRedis.NewConnector(connectorCfg)
+
+	repoCfg := redis.Config{
+		QueueDB:           queueDB,
+		QueueName:         queueName,
+		QueueTimeout:      queueTimeout,
+		Reliable:          true,
+		VisibilityTimeout: time.Minute,
+	}
+
+	repo := redis.NewRepository(repoCfg, conn)
+	return conn, repo, nil
+}
+
 func cleanupRedis(conn *pkgRedis.Connector) error {
 	if err := conn.GetClient(queueDB).FlushDB(context.Background()).Err(); err != nil {
 		return err
@@ -99,6 +121,65 @@ func TestRepository_Enqueue(t *testing.T) {
 		}
 	})
 
+	t.Run("should enqueue messages into their priority lanes", func(t *testing.T) {
+		conn, repo, err := initRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		ctx := context.Background()
+		client := conn.GetClient(queueDB)
+
+		actualErr := repo.Enqueue(ctx, []models.Sms{
+			{
+				UserId:   "1",
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+				Priority: models.PriorityHigh,
+			},
+			{
+				UserId:   "2",
+				Content:  "Test Content 2",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+			},
+			{
+				UserId:   "3",
+				Content:  "Test Content 3",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+				Priority: models.PriorityLow,
+			},
+		})
+		assert.NoError(t, actualErr)
+
+		for key, expectedUserId := range map[string]string{
+			queueName + ":high": "1",
+			queueName:           "2",
+			queueName + ":low":  "3",
+		} {
+			res, err := client.LRange(ctx, key, 0, -1).Result()
+			assert.NoError(t, err)
+			assert.Len(t, res, 1)
+
+			actualMsg := models.Sms{}
+			err = common.JSONToValue(res[0], &actualMsg)
+			assert.NoError(t, err)
+			assert.Equal(t, expectedUserId, actualMsg.UserId)
+		}
+
+		actualLen, actualErr := repo.GetLength(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 3, actualLen)
+	})
+
 	t.Run("should return InvalidQueueError when key is not list", func(t *testing.T) {
 		conn, repo, err := initRedis()
 		assert.NoError(t, err)
@@ -258,6 +339,92 @@ func TestRepository_Pop(t *testing.T) {
 		assert.Equal(t, models.InvalidQueueError, actualErr)
 	})
 
+	t.Run("should drain lanes by priority weights", func(t *testing.T) {
+		conn := pkgRedis.NewConnector(pkgRedis.Config{
+			Addr:     os.Getenv("REDIS_ADDRESS"),
+			Password: os.Getenv("REDIS_PASSWORD"),
+		})
+		repo := redis.NewRepository(redis.Config{
+			QueueDB:      queueDB,
+			QueueName:    queueName,
+			QueueTimeout: queueTimeout,
+			PriorityWeights: redis.PriorityWeights{
+				High:   2,
+				Normal: 1,
+			},
+		}, conn)
+
+		defer func() {
+			err := cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		ctx := context.Background()
+
+		var msgs []models.Sms
+		for _, priority := range []models.SmsPriority{
+			models.PriorityHigh, models.PriorityHigh, models.PriorityHigh,
+			models.PriorityNormal, models.PriorityNormal, models.PriorityNormal,
+			models.PriorityLow, models.PriorityLow,
+		} {
+			msgs = append(msgs, models.Sms{
+				UserId:   "1",
+				Content:  "Test Content",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+				Priority: priority,
+			})
+		}
+		err := repo.Enqueue(ctx, msgs)
+		assert.NoError(t, err)
+
+		expectedPriorities := []models.SmsPriority{
+			models.PriorityHigh, models.PriorityNormal, models.PriorityHigh,
+			models.PriorityHigh, models.PriorityNormal, models.PriorityNormal,
+			models.PriorityLow, models.PriorityLow,
+		}
+		for _, expected := range expectedPriorities {
+			actualMsg, actualErr := repo.Pop(ctx)
+			assert.NoError(t, actualErr)
+			assert.Equal(t, expected, actualMsg.Priority)
+		}
+
+		_, actualErr := repo.Pop(ctx)
+		assert.Equal(t, models.EmptyQueueError, actualErr)
+	})
+
+	t.Run("should return PayloadError and drop undecodable payload from processing", func(t *testing.T) {
+		conn, repo, err := initReliableRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		ctx := context.Background()
+		client := conn.GetClient(queueDB)
+
+		err = client.LPush(ctx, queueName, "xyz").Err()
+		assert.NoError(t, err)
+
+		actualMsg, actualErr := repo.Pop(ctx)
+		assert.Error(t, actualErr)
+		assert.ErrorIs(t, actualErr, models.UndecodableMessageError)
+		var payloadErr *models.PayloadError
+		assert.ErrorAs(t, actualErr, &payloadErr)
+		assert.Equal(t, "xyz", payloadErr.Payload)
+		assert.Equal(t, models.Sms{}, actualMsg)
+
+		processing, err := client.LLen(ctx, queueName+":processing").Result()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), processing)
+		deadlines, err := client.ZCard(ctx, queueName+":deadlines").Result()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), deadlines)
+	})
+
 	t.Run("should return EmptyQueueError when list is empty", func(t *testing.T) {
 		conn, repo, err := initRedis()
 		assert.NoError(t, err)
@@ -274,4 +441,410 @@ func TestRepository_Pop(t *testing.T) {
 		assert.Equal(t, models.EmptyQueueError, actualErr)
 		assert.Equal(t, models.Sms{}, actualMsg)
 	})
+
+	t.Run("should wait for message of any lane when reliable queue is empty", func(t *testing.T) {
+		conn, repo, err := initReliableRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		ctx := context.Background()
+
+		go func() {
+			time.Sleep(200 * time.Millisecond)
+			_ = repo.Enqueue(ctx, []models.Sms{
+				{
+					Entity:   &shared.Entity{ID: "1"},
+					UserId:   "1",
+					Content:  "Test Content",
+					Receiver: "09123456789",
+					Cost:     100,
+					Status:   models.StatusEnqueued,
+					Priority: models.PriorityLow,
+				},
+			})
+		}()
+
+		actualMsg, actualErr := repo.Pop(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, "1", actualMsg.ID)
+	})
+}
+
+func TestRepository_Ack(t *testing.T) {
+	t.Run("should keep popped message in processing until acknowledged", func(t *testing.T) {
+		conn, repo, err := initReliableRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		ctx := context.Background()
+		client := conn.GetClient(queueDB)
+
+		err = repo.Enqueue(ctx, []models.Sms{
+			{
+				Entity:   &shared.Entity{ID: "1"},
+				UserId:   "1",
+				Content:  "Test Content",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+			},
+		})
+		assert.NoError(t, err)
+
+		actualMsg, actualErr := repo.Pop(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, "1", actualMsg.ID)
+
+		processing, err := client.LLen(ctx, queueName+":processing").Result()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), processing)
+		deadlines, err := client.ZCard(ctx, queueName+":deadlines").Result()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deadlines)
+
+		actualErr = repo.Ack(ctx, actualMsg)
+		assert.NoError(t, actualErr)
+
+		processing, err = client.LLen(ctx, queueName+":processing").Result()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), processing)
+		deadlines, err = client.ZCard(ctx, queueName+":deadlines").Result()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), deadlines)
+	})
+}
+
+func TestRepository_Nack(t *testing.T) {
+	t.Run("should return popped message to queue", func(t *testing.T) {
+		conn, repo, err := initReliableRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		ctx := context.Background()
+		client := conn.GetClient(queueDB)
+
+		err = repo.Enqueue(ctx, []models.Sms{
+			{
+				Entity:   &shared.Entity{ID: "1"},
+				UserId:   "1",
+				Content:  "Test Content",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+			},
+		})
+		assert.NoError(t, err)
+
+		actualMsg, actualErr := repo.Pop(ctx)
+		assert.NoError(t, actualErr)
+
+		actualErr = repo.Nack(ctx, actualMsg)
+		assert.NoError(t, actualErr)
+
+		processing, err := client.LLen(ctx, queueName+":processing").Result()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), processing)
+
+		actualMsg, actualErr = repo.Pop(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, "1", actualMsg.ID)
+	})
+}
+
+func TestRepository_RecoverUnacked(t *testing.T) {
+	t.Run("should re-deliver messages with expired visibility timeout", func(t *testing.T) {
+		conn, repo, err := initReliableRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		ctx := context.Background()
+		client := conn.GetClient(queueDB)
+
+		err = repo.Enqueue(ctx, []models.Sms{
+			{
+				Entity:   &shared.Entity{ID: "1"},
+				UserId:   "1",
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+			},
+			{
+				Entity:   &shared.Entity{ID: "2"},
+				UserId:   "1",
+				Content:  "Test Content 2",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+			},
+		})
+		assert.NoError(t, err)
+
+		_, err = repo.Pop(ctx)
+		assert.NoError(t, err)
+		_, err = repo.Pop(ctx)
+		assert.NoError(t, err)
+
+		payloads, err := client.LRange(ctx, queueName+":processing", 0, -1).Result()
+		assert.NoError(t, err)
+		assert.Len(t, payloads, 2)
+		err = client.ZAdd(ctx, queueName+":deadlines", goredis.Z{
+			Score:  float64(time.Now().Add(-time.Second).Unix()),
+			Member: payloads[0],
+		}).Err()
+		assert.NoError(t, err)
+
+		actualRecovered, actualErr := repo.RecoverUnacked(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 1, actualRecovered)
+
+		queueLen, err := repo.GetLength(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, queueLen)
+		processing, err := client.LLen(ctx, queueName+":processing").Result()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), processing)
+	})
+
+	t.Run("should give orphan in-flight messages a deadline", func(t *testing.T) {
+		conn, repo, err := initReliableRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		ctx := context.Background()
+		client := conn.GetClient(queueDB)
+
+		err = client.LPush(ctx, queueName+":processing", common.ValueToJSON(&models.Sms{
+			UserId:   "1",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		})).Err()
+		assert.NoError(t, err)
+
+		actualRecovered, actualErr := repo.RecoverUnacked(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 0, actualRecovered)
+
+		deadlines, err := client.ZCard(ctx, queueName+":deadlines").Result()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deadlines)
+	})
+}
+
+func TestRepository_GetQueuedIds(t *testing.T) {
+	t.Run("should return ids of waiting and in-flight messages", func(t *testing.T) {
+		conn, repo, err := initReliableRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		ctx := context.Background()
+		msgs := []models.Sms{
+			{
+				Entity:   &shared.Entity{ID: "1"},
+				UserId:   "1",
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+			},
+			{
+				Entity:   &shared.Entity{ID: "2"},
+				UserId:   "1",
+				Content:  "Test Content 2",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+			},
+		}
+
+		err = repo.Enqueue(ctx, msgs)
+		assert.NoError(t, err)
+
+		_, err = repo.Pop(ctx)
+		assert.NoError(t, err)
+
+		actualIds, actualErr := repo.GetQueuedIds(ctx, []string{"1", "2", "3"})
+		assert.NoError(t, actualErr)
+		slices.Sort(actualIds)
+		assert.Equal(t, []string{"1", "2"}, actualIds)
+	})
+
+	t.Run("should keep popped message queued until acknowledged when queue is not reliable", func(t *testing.T) {
+		conn, repo, err := initRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity:   &shared.Entity{ID: "1"},
+			UserId:   "1",
+			Content:  "Test Content 1",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		err = repo.Enqueue(ctx, []models.Sms{msg})
+		assert.NoError(t, err)
+
+		poppedMsg, err := repo.Pop(ctx)
+		assert.NoError(t, err)
+
+		actualIds, actualErr := repo.GetQueuedIds(ctx, []string{"1"})
+		assert.NoError(t, actualErr)
+		assert.Equal(t, []string{"1"}, actualIds)
+
+		err = repo.Ack(ctx, poppedMsg)
+		assert.NoError(t, err)
+
+		actualIds, actualErr = repo.GetQueuedIds(ctx, []string{"1"})
+		assert.NoError(t, actualErr)
+		assert.Empty(t, actualIds)
+	})
+
+	t.Run("should lease id of popped message when queue is not reliable", func(t *testing.T) {
+		conn, repo, err := initRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		ctx := context.Background()
+		err = repo.Enqueue(ctx, []models.Sms{
+			{
+				Entity:   &shared.Entity{ID: "1"},
+				UserId:   "1",
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+			},
+		})
+		assert.NoError(t, err)
+
+		_, err = repo.Pop(ctx)
+		assert.NoError(t, err)
+
+		actualScore, actualErr := conn.GetClient(queueDB).ZScore(ctx, queueName+":ids", "1").Result()
+		assert.NoError(t, actualErr)
+		assert.Greater(t, actualScore, float64(time.Now().Unix()))
+	})
+
+	t.Run("should not return popped message whose lease expired when queue is not reliable", func(t *testing.T) {
+		conn, repo, err := initRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		ctx := context.Background()
+
+		err = repo.Enqueue(ctx, []models.Sms{
+			{
+				Entity:   &shared.Entity{ID: "1"},
+				UserId:   "1",
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+				Cost:     100,
+				Status:   models.StatusEnqueued,
+			},
+		})
+		assert.NoError(t, err)
+
+		_, err = repo.Pop(ctx)
+		assert.NoError(t, err)
+
+		err = conn.GetClient(queueDB).ZAdd(ctx, queueName+":ids", goredis.Z{
+			Score:  float64(time.Now().Add(-time.Second).Unix()),
+			Member: "1",
+		}).Err()
+		assert.NoError(t, err)
+
+		actualIds, actualErr := repo.GetQueuedIds(ctx, []string{"1"})
+		assert.NoError(t, actualErr)
+		assert.Empty(t, actualIds)
+	})
+
+	t.Run("should return InvalidQueueError when key is not sorted set", func(t *testing.T) {
+		conn, repo, err := initRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		ctx := context.Background()
+
+		err = conn.GetClient(queueDB).Set(ctx, queueName+":ids", 0, -1).Err()
+		assert.NoError(t, err)
+
+		actualIds, actualErr := repo.GetQueuedIds(ctx, []string{"1"})
+		assert.Error(t, actualErr)
+		assert.Equal(t, models.InvalidQueueError, actualErr)
+		assert.Nil(t, actualIds)
+	})
+}
+
+func TestRepository_SetAccepted(t *testing.T) {
+	t.Run("should keep send results until they are removed", func(t *testing.T) {
+		conn, repo, err := initRedis()
+		assert.NoError(t, err)
+
+		defer func() {
+			err = cleanupRedis(conn)
+			assert.NoError(t, err)
+		}()
+
+		ctx := context.Background()
+		sendRes := models.SendResult{Provider: "primary", MessageId: "provider-1"}
+
+		actualErr := repo.SetAccepted(ctx, "1", sendRes)
+		assert.NoError(t, actualErr)
+
+		actualAccepted, actualErr := repo.GetAccepted(ctx, []string{"1", "2"})
+		assert.NoError(t, actualErr)
+		assert.Equal(t, map[string]models.SendResult{"1": sendRes}, actualAccepted)
+
+		actualErr = repo.RemoveAccepted(ctx, []string{"1"})
+		assert.NoError(t, actualErr)
+
+		actualAccepted, actualErr = repo.GetAccepted(ctx, []string{"1", "2"})
+		assert.NoError(t, actualErr)
+		assert.Empty(t, actualAccepted)
+	})
 }
@@ -0,0 +1,129 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/repositories"
+)
+
+var (
+	UnknownProviderError = errors.New("unknown sms provider")
+	EmptyRuleError       = errors.New("routing rule has no condition")
+)
+
+// Rule routes a message to Provider when every non-empty condition matches.
+// A condition matches when any of its values matches the message. Prefixes
+// match the E.164 receiver with or without its plus.
+type Rule struct {
+	Provider string   `mapstructure:"provider"`
+	Prefixes []string `mapstructure:"prefixes"`
+	UserIds  []string `mapstructure:"user_ids"`
+	Tags     []string `mapstructure:"tags"`
+}
+
+type Config struct {
+	Default string `mapstructure:"default"`
+	Rules   []Rule `mapstructure:"rules"`
+}
+
+type SmsSender struct {
+	cfg     Config
+	senders map[string]repositories.ISmsSender
+}
+
+func NewSmsSender(cfg Config, senders map[string]repositories.ISmsSender) (*SmsSender, error) {
+	if _, ok := senders[cfg.Default]; !ok {
+		return nil, fmt.Errorf("%w: default route %s", UnknownProviderError, cfg.Default)
+	}
+	for i, rule := range cfg.Rules {
+		if _, ok := senders[rule.Provider]; !ok {
+			return nil, fmt.Errorf("%w: rule %d routes to %s", UnknownProviderError, i, rule.Provider)
+		}
+		if len(rule.Prefixes) == 0 && len(rule.UserIds) == 0 && len(rule.Tags) == 0 {
+			return nil, fmt.Errorf("%w: rule %d", EmptyRuleError, i)
+		}
+	}
+
+	return &SmsSender{
+		cfg:     cfg,
+		senders: senders,
+	}, nil
+}
+
+func (s *SmsSender) Close() error {
+	var errs []error
+	for _, sender := range s.senders {
+		if c, ok := sender.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *SmsSender) Send(ctx context.Context, msg models.Sms) (models.SendResult, error) {
+	provider := s.Route(msg)
+
+	res, err := s.senders[provider].Send(ctx, msg)
+	if err != nil {
+		return models.SendResult{}, err
+	}
+	if res.Provider == "" {
+		res.Provider = provider
+	}
+
+	return res, nil
+}
+
+// OnDeliveryReport registers handler on every provider that pushes delivery
+// reports, stamping the reports with the provider name messages are stored
+// with.
+func (s *SmsSender) OnDeliveryReport(handler func(models.DeliveryReport)) {
+	for name, sender := range s.senders {
+		reporter, ok := sender.(repositories.IDeliveryReporter)
+		if !ok {
+			continue
+		}
+
+		reporter.OnDeliveryReport(func(report models.DeliveryReport) {
+			report.Provider = name
+			handler(report)
+		})
+	}
+}
+
+// Route returns the provider of the first matching rule or the default route.
+func (s *SmsSender) Route(msg models.Sms) string {
+	for _, rule := range s.cfg.Rules {
+		if matchRule(rule, msg) {
+			return rule.Provider
+		}
+	}
+
+	return s.cfg.Default
+}
+
+func matchRule(rule Rule, msg models.Sms) bool {
+	number := strings.TrimPrefix(msg.Receiver, "+")
+	if len(rule.Prefixes) > 0 && !slices.ContainsFunc(rule.Prefixes, func(prefix string) bool {
+		return strings.HasPrefix(number, strings.TrimPrefix(prefix, "+"))
+	}) {
+		return false
+	}
+	if len(rule.UserIds) > 0 && !slices.Contains(rule.UserIds, msg.UserId) {
+		return false
+	}
+	if len(rule.Tags) > 0 && !slices.ContainsFunc(rule.Tags, func(tag string) bool {
+		return slices.Contains(msg.Tags, tag)
+	}) {
+		return false
+	}
+
+	return true
+}
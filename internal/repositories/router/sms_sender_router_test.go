@@ -0,0 +1,200 @@
+package router_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/mocks"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/repositories"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/router"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewSmsSender(t *testing.T) {
+	t.Run("should return UnknownProviderError when default route is not configured", func(t *testing.T) {
+		_, actualErr := router.NewSmsSender(router.Config{Default: "missing"}, map[string]repositories.ISmsSender{
+			"default": mocks.NewMockISmsSender(t),
+		})
+		assert.ErrorIs(t, actualErr, router.UnknownProviderError)
+	})
+
+	t.Run("should return UnknownProviderError when rule provider is not configured", func(t *testing.T) {
+		_, actualErr := router.NewSmsSender(router.Config{
+			Default: "default",
+			Rules:   []router.Rule{{Provider: "missing", Prefixes: []string{"98912"}}},
+		}, map[string]repositories.ISmsSender{
+			"default": mocks.NewMockISmsSender(t),
+		})
+		assert.ErrorIs(t, actualErr, router.UnknownProviderError)
+	})
+
+	t.Run("should return EmptyRuleError when rule has no condition", func(t *testing.T) {
+		_, actualErr := router.NewSmsSender(router.Config{
+			Default: "default",
+			Rules:   []router.Rule{{Provider: "default"}},
+		}, map[string]repositories.ISmsSender{
+			"default": mocks.NewMockISmsSender(t),
+		})
+		assert.ErrorIs(t, actualErr, router.EmptyRuleError)
+	})
+}
+
+func TestSmsSender_Route(t *testing.T) {
+	senders := map[string]repositories.ISmsSender{
+		"default": mocks.NewMockISmsSender(t),
+		"vip":     mocks.NewMockISmsSender(t),
+		"otp":     mocks.NewMockISmsSender(t),
+		"mci":     mocks.NewMockISmsSender(t),
+	}
+	sender, err := router.NewSmsSender(router.Config{
+		Default: "default",
+		Rules: []router.Rule{
+			{Provider: "vip", UserIds: []string{"7"}},
+			{Provider: "otp", Tags: []string{"otp"}, Prefixes: []string{"98912", "98935"}},
+			{Provider: "mci", Prefixes: []string{"98912", "98990"}},
+		},
+	}, senders)
+	assert.NoError(t, err)
+
+	t.Run("should route by user id before other rules", func(t *testing.T) {
+		assert.Equal(t, "vip", sender.Route(models.Sms{UserId: "7", Receiver: "+989123456789", Tags: []string{"otp"}}))
+	})
+
+	t.Run("should route by tag and prefix when both match", func(t *testing.T) {
+		assert.Equal(t, "otp", sender.Route(models.Sms{UserId: "1", Receiver: "+989351234567", Tags: []string{"otp"}}))
+	})
+
+	t.Run("should skip rule when only some conditions match", func(t *testing.T) {
+		assert.Equal(t, "mci", sender.Route(models.Sms{UserId: "1", Receiver: "+989901234567", Tags: []string{"otp"}}))
+	})
+
+	t.Run("should route by receiver prefix", func(t *testing.T) {
+		assert.Equal(t, "mci", sender.Route(models.Sms{UserId: "1", Receiver: "+989123456789"}))
+	})
+
+	t.Run("should match receiver prefix with or without plus", func(t *testing.T) {
+		plusSender, err := router.NewSmsSender(router.Config{
+			Default: "default",
+			Rules:   []router.Rule{{Provider: "mci", Prefixes: []string{"+98912"}}},
+		}, senders)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "mci", plusSender.Route(models.Sms{UserId: "1", Receiver: "+989123456789"}))
+		assert.Equal(t, "mci", plusSender.Route(models.Sms{UserId: "1", Receiver: "989123456789"}))
+	})
+
+	t.Run("should fallback to default route", func(t *testing.T) {
+		assert.Equal(t, "default", sender.Route(models.Sms{UserId: "1", Receiver: "+989361234567"}))
+	})
+}
+
+func TestSmsSender_Send(t *testing.T) {
+	t.Run("should send with routed provider and return its name", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "1",
+			Content:  "Test Content",
+			Receiver: "+989123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		mockDefault := mocks.NewMockISmsSender(t)
+		mockMci := mocks.NewMockISmsSender(t)
+
+		mockMci.EXPECT().
+			Send(ctx, msg).
+			Return(models.SendResult{MessageId: "mci-1"}, nil).
+			Once()
+
+		sender, err := router.NewSmsSender(router.Config{
+			Default: "default",
+			Rules:   []router.Rule{{Provider: "mci", Prefixes: []string{"98912"}}},
+		}, map[string]repositories.ISmsSender{
+			"default": mockDefault,
+			"mci":     mockMci,
+		})
+		assert.NoError(t, err)
+
+		actualRes, actualErr := sender.Send(ctx, msg)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.SendResult{Provider: "mci", MessageId: "mci-1"}, actualRes)
+	})
+
+	t.Run("should return provider error", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "1",
+			Content:  "Test Content",
+			Receiver: "+989123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		mockDefault := mocks.NewMockISmsSender(t)
+
+		mockDefault.EXPECT().
+			Send(ctx, msg).
+			Return(models.SendResult{}, models.PermanentSendError).
+			Once()
+
+		sender, err := router.NewSmsSender(router.Config{Default: "default"}, map[string]repositories.ISmsSender{
+			"default": mockDefault,
+		})
+		assert.NoError(t, err)
+
+		actualRes, actualErr := sender.Send(ctx, msg)
+		assert.ErrorIs(t, actualErr, models.PermanentSendError)
+		assert.Equal(t, models.SendResult{}, actualRes)
+	})
+}
+
+type reportingSender struct {
+	*mocks.MockISmsSender
+	*mocks.MockIDeliveryReporter
+}
+
+func TestSmsSender_OnDeliveryReport(t *testing.T) {
+	t.Run("should register handler on reporting providers with their names", func(t *testing.T) {
+		mockSmpp := reportingSender{
+			MockISmsSender:        mocks.NewMockISmsSender(t),
+			MockIDeliveryReporter: mocks.NewMockIDeliveryReporter(t),
+		}
+
+		var registered func(models.DeliveryReport)
+		mockSmpp.MockIDeliveryReporter.EXPECT().
+			OnDeliveryReport(mock.Anything).
+			Run(func(handler func(models.DeliveryReport)) {
+				registered = handler
+			}).
+			Return().
+			Once()
+
+		sender, err := router.NewSmsSender(router.Config{Default: "default"}, map[string]repositories.ISmsSender{
+			"default": mocks.NewMockISmsSender(t),
+			"smpp":    mockSmpp,
+		})
+		assert.NoError(t, err)
+
+		var actualReport models.DeliveryReport
+		sender.OnDeliveryReport(func(report models.DeliveryReport) {
+			actualReport = report
+		})
+
+		registered(models.DeliveryReport{MessageId: "smsc-1", Status: models.StatusDelivered})
+		assert.Equal(t, models.DeliveryReport{
+			Provider:  "smpp",
+			MessageId: "smsc-1",
+			Status:    models.StatusDelivered,
+		}, actualReport)
+	})
+}
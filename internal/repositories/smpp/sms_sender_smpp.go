@@ -0,0 +1,225 @@
+package smpp
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/pkg/gsm"
+
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+	pkgSmpp "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/smpp"
+)
+
+const (
+	esmClassTypeMask        = 0x3C
+	esmClassDeliveryReceipt = 0x04
+)
+
+var InvalidReceiptError = errors.New("invalid smpp delivery receipt")
+
+type Config struct {
+	Connection         pkgSmpp.Config `mapstructure:"connection"`
+	SourceAddr         string         `mapstructure:"source_addr"`
+	SourceAddrTon      uint8          `mapstructure:"source_addr_ton"`
+	SourceAddrNpi      uint8          `mapstructure:"source_addr_npi"`
+	DestAddrTon        uint8          `mapstructure:"dest_addr_ton"`
+	DestAddrNpi        uint8          `mapstructure:"dest_addr_npi"`
+	RegisteredDelivery bool           `mapstructure:"registered_delivery"`
+}
+
+type SmsSender struct {
+	client *pkgSmpp.Client
+	cfg    Config
+
+	reportM  sync.RWMutex
+	onReport func(models.DeliveryReport)
+}
+
+func NewSmsSender(cfg Config) *SmsSender {
+	s := &SmsSender{
+		cfg:    cfg,
+		client: pkgSmpp.NewClient(cfg.Connection),
+	}
+	s.client.OnDeliver(s.deliver)
+	s.client.Start()
+
+	return s
+}
+
+func (s *SmsSender) Close() error {
+	return s.client.Close()
+}
+
+func (s *SmsSender) Send(ctx context.Context, msg models.Sms) (models.SendResult, error) {
+	dataCoding, payload := encodeContent(msg.Content)
+
+	sm := pkgSmpp.ShortMessage{
+		SourceAddrTon:   s.cfg.SourceAddrTon,
+		SourceAddrNpi:   s.cfg.SourceAddrNpi,
+		SourceAddr:      s.cfg.SourceAddr,
+		DestAddrTon:     s.cfg.DestAddrTon,
+		DestAddrNpi:     s.cfg.DestAddrNpi,
+		DestinationAddr: msg.Receiver,
+		DataCoding:      dataCoding,
+		Message:         payload,
+	}
+	// E.164 receivers go out as international numbers without the plus, the
+	// configured numbering is left for receivers stored before normalization
+	if dest, ok := strings.CutPrefix(msg.Receiver, "+"); ok {
+		sm.DestAddrTon = pkgSmpp.TonInternational
+		sm.DestAddrNpi = pkgSmpp.NpiIsdn
+		sm.DestinationAddr = dest
+	}
+	if s.cfg.RegisteredDelivery {
+		sm.RegisteredDelivery = 1
+	}
+
+	id, err := s.client.Submit(ctx, sm)
+	if err != nil {
+		var statusErr pkgSmpp.StatusError
+		if errors.As(err, &statusErr) && !statusErr.Temporary() {
+			return models.SendResult{}, fmt.Errorf("%w: %s", models.PermanentSendError, err.Error())
+		}
+
+		return models.SendResult{}, fmt.Errorf("%w: %s", models.TemporarySendError, err.Error())
+	}
+
+	return models.SendResult{
+		MessageId: id,
+	}, nil
+}
+
+func encodeContent(content string) (uint8, []byte) {
+	if payload, ok := gsm.Encode(content); ok {
+		return pkgSmpp.DataCodingDefault, payload
+	}
+
+	units := utf16.Encode([]rune(content))
+	payload := make([]byte, len(units)*2)
+	for i, u := range units {
+		binary.BigEndian.PutUint16(payload[i*2:], u)
+	}
+
+	return pkgSmpp.DataCodingUCS2, payload
+}
+
+// OnDeliveryReport registers the handler of delivery receipts. Receipts are
+// only sent over a transceiver bind for messages submitted with
+// registered_delivery.
+func (s *SmsSender) OnDeliveryReport(handler func(models.DeliveryReport)) {
+	s.reportM.Lock()
+	defer s.reportM.Unlock()
+
+	s.onReport = handler
+}
+
+func (s *SmsSender) deliver(sm pkgSmpp.ShortMessage) {
+	if sm.EsmClass&esmClassTypeMask != esmClassDeliveryReceipt {
+		pkgLog.Debug("ignoring deliver_sm from %s that is not a delivery receipt", sm.SourceAddr)
+		return
+	}
+
+	report, err := parseReceipt(string(sm.Message))
+	if err != nil {
+		pkgLog.Error(err, "failed to parse delivery receipt %q", string(sm.Message))
+		return
+	}
+	if !report.Status.IsFinal() {
+		pkgLog.Debug("ignoring intermediate delivery receipt of message %s", report.MessageId)
+		return
+	}
+
+	s.reportM.RLock()
+	handler := s.onReport
+	s.reportM.RUnlock()
+
+	if handler == nil {
+		pkgLog.Warn("dropping delivery receipt of message %s without handler", report.MessageId)
+		return
+	}
+	handler(report)
+}
+
+// parseReceipt parses the text of a delivery receipt in the format of SMPP
+// 3.4 appendix B:
+//
+//	id:IIIIIIIIII sub:SSS dlvrd:DDD submit date:YYMMDDhhmm done date:YYMMDDhhmm stat:DDDDDDD err:E text:...
+//
+// Receipts of intermediate states are returned with their status unchanged
+// from StatusSent.
+func parseReceipt(text string) (models.DeliveryReport, error) {
+	id := receiptField(text, "id:")
+	stat := receiptField(text, "stat:")
+	if id == "" || stat == "" {
+		return models.DeliveryReport{}, InvalidReceiptError
+	}
+
+	report := models.DeliveryReport{
+		MessageId: id,
+		Status:    receiptStatus(stat),
+	}
+	if report.Status == models.StatusUndelivered || report.Status == models.StatusExpired {
+		report.Error = stat
+		if code := receiptField(text, "err:"); code != "" {
+			report.Error += ": " + code
+		}
+	}
+
+	doneDate := receiptField(text, "done date:")
+	for _, layout := range []string{"0601021504", "060102150405"} {
+		if doneAt, err := time.ParseInLocation(layout, doneDate, time.Local); err == nil {
+			report.DoneAt = doneAt
+			break
+		}
+	}
+
+	return report, nil
+}
+
+// receiptField returns the value of key, which ends at the next space. Keys
+// are matched case-insensitively.
+func receiptField(text string, key string) string {
+	i := indexFold(text, key)
+	if i < 0 {
+		return ""
+	}
+
+	value := text[i+len(key):]
+	if end := strings.IndexByte(value, ' '); end >= 0 {
+		value = value[:end]
+	}
+
+	return value
+}
+
+// indexFold is a case-insensitive strings.Index. It searches text
+// itself, so the returned index can slice text.
+func indexFold(text string, key string) int {
+	for i := 0; i+len(key) <= len(text); i++ {
+		if strings.EqualFold(text[i:i+len(key)], key) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func receiptStatus(stat string) models.SmsStatus {
+	switch strings.ToUpper(stat) {
+	case "DELIVRD":
+		return models.StatusDelivered
+	case "EXPIRED":
+		return models.StatusExpired
+	case "UNDELIV", "REJECTD", "DELETED", "UNKNOWN":
+		return models.StatusUndelivered
+	default:
+		return models.StatusSent
+	}
+}
@@ -0,0 +1,472 @@
+package smpp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/mocks"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/services"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/smpp"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+
+	pkgSmpp "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/smpp"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/pkg/smpp/smpptest"
+)
+
+func newSender(t *testing.T, smsc *smpptest.Server) *smpp.SmsSender {
+	sender := smpp.NewSmsSender(smpp.Config{
+		Connection: pkgSmpp.Config{
+			Address:             smsc.Addr(),
+			SystemId:            "gateway",
+			Password:            "secret",
+			ResponseTimeout:     time.Second,
+			MinReconnectBackoff: 10 * time.Millisecond,
+			MaxReconnectBackoff: 50 * time.Millisecond,
+		},
+		SourceAddr: "1000",
+	})
+	t.Cleanup(func() {
+		_ = sender.Close()
+	})
+
+	return sender
+}
+
+func TestSmsSender_Send(t *testing.T) {
+	t.Run("should submit message and return smsc message id", func(t *testing.T) {
+		ctx := context.Background()
+		smsc := smpptest.NewServer("gateway", "secret")
+		defer smsc.Close()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		sender := newSender(t, smsc)
+
+		actualRes, actualErr := sender.Send(ctx, msg)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, "smsc-1", actualRes.MessageId)
+
+		submitted := smsc.Submitted()
+		assert.Len(t, submitted, 1)
+		assert.Equal(t, "1000", submitted[0].SourceAddr)
+		assert.Equal(t, msg.Receiver, submitted[0].DestinationAddr)
+		assert.Equal(t, pkgSmpp.DataCodingDefault, submitted[0].DataCoding)
+		assert.Equal(t, []byte(msg.Content), submitted[0].Message)
+	})
+
+	t.Run("should submit e164 receiver as international number without plus", func(t *testing.T) {
+		ctx := context.Background()
+		smsc := smpptest.NewServer("gateway", "secret")
+		defer smsc.Close()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "+989123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		sender := newSender(t, smsc)
+
+		_, actualErr := sender.Send(ctx, msg)
+		assert.NoError(t, actualErr)
+
+		submitted := smsc.Submitted()
+		assert.Len(t, submitted, 1)
+		assert.Equal(t, "989123456789", submitted[0].DestinationAddr)
+		assert.Equal(t, pkgSmpp.TonInternational, submitted[0].DestAddrTon)
+		assert.Equal(t, pkgSmpp.NpiIsdn, submitted[0].DestAddrNpi)
+	})
+
+	t.Run("should submit non ascii content as ucs2", func(t *testing.T) {
+		ctx := context.Background()
+		smsc := smpptest.NewServer("gateway", "secret")
+		defer smsc.Close()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "سلام",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		sender := newSender(t, smsc)
+
+		_, actualErr := sender.Send(ctx, msg)
+		assert.NoError(t, actualErr)
+
+		submitted := smsc.Submitted()
+		assert.Len(t, submitted, 1)
+		assert.Equal(t, pkgSmpp.DataCodingUCS2, submitted[0].DataCoding)
+		assert.Equal(t, []byte{0x06, 0x33, 0x06, 0x44, 0x06, 0x27, 0x06, 0x45}, submitted[0].Message)
+	})
+
+	t.Run("should submit gsm characters with their GSM 03.38 codes", func(t *testing.T) {
+		ctx := context.Background()
+		smsc := smpptest.NewServer("gateway", "secret")
+		defer smsc.Close()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "@ 5€_",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		sender := newSender(t, smsc)
+
+		_, actualErr := sender.Send(ctx, msg)
+		assert.NoError(t, actualErr)
+
+		submitted := smsc.Submitted()
+		assert.Len(t, submitted, 1)
+		assert.Equal(t, pkgSmpp.DataCodingDefault, submitted[0].DataCoding)
+		assert.Equal(t, []byte{0x00, 0x20, 0x35, 0x1B, 0x65, 0x11}, submitted[0].Message)
+	})
+
+	t.Run("should return TemporarySendError when smsc throttles", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+		smsc := smpptest.NewServer("gateway", "secret")
+		defer smsc.Close()
+		smsc.SubmitStatus = func(sm pkgSmpp.ShortMessage) uint32 {
+			return pkgSmpp.StatusThrottled
+		}
+
+		sender := newSender(t, smsc)
+
+		_, actualErr := sender.Send(ctx, msg)
+		assert.Error(t, actualErr)
+		assert.ErrorIs(t, actualErr, models.TemporarySendError)
+	})
+
+	t.Run("should return PermanentSendError when smsc rejects message", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+		smsc := smpptest.NewServer("gateway", "secret")
+		defer smsc.Close()
+		smsc.SubmitStatus = func(sm pkgSmpp.ShortMessage) uint32 {
+			return pkgSmpp.StatusInvalidDestAddr
+		}
+
+		sender := newSender(t, smsc)
+
+		_, actualErr := sender.Send(ctx, msg)
+		assert.Error(t, actualErr)
+		assert.ErrorIs(t, actualErr, models.PermanentSendError)
+	})
+
+	t.Run("should return TemporarySendError when bind is rejected", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+		smsc := smpptest.NewServer("gateway", "other-secret")
+		defer smsc.Close()
+
+		sender := newSender(t, smsc)
+
+		_, actualErr := sender.Send(ctx, msg)
+		assert.Error(t, actualErr)
+		assert.ErrorIs(t, actualErr, models.TemporarySendError)
+	})
+
+	t.Run("should rebind after connection is lost", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+		smsc := smpptest.NewServer("gateway", "secret")
+		defer smsc.Close()
+
+		sender := newSender(t, smsc)
+
+		_, actualErr := sender.Send(ctx, msg)
+		assert.NoError(t, actualErr)
+
+		smsc.DropConnections()
+
+		assert.Eventually(t, func() bool {
+			_, err := sender.Send(ctx, msg)
+			return err == nil
+		}, 2*time.Second, 20*time.Millisecond)
+		assert.Equal(t, 2, smsc.Binds())
+	})
+}
+
+func TestSmsService_SendFromQueue_Smpp(t *testing.T) {
+	metrics.RegisterMetrics()
+
+	t.Run("should send message through smsc and update its status to sent", func(t *testing.T) {
+		ctx := context.Background()
+		smsc := smpptest.NewServer("gateway", "secret")
+		defer smsc.Close()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+		expectedMsg := msg
+		expectedMsg.Status = models.StatusSent
+
+		mockQueue := mocks.NewMockISmsQueue(t)
+		mockRepo := mocks.NewMockISmsRepository(t)
+
+		mockQueue.EXPECT().
+			Pop(ctx).
+			Return(msg, nil).
+			Once()
+
+		mockQueue.EXPECT().
+			Ack(ctx, msg).
+			Return(nil).
+			Once()
+
+		mockRepo.EXPECT().
+			SetMessageAsSent(ctx, msg.ID, models.SendResult{MessageId: "smsc-1"}).
+			Return(expectedMsg, nil).
+			Once()
+
+		service := services.NewSmsService(services.SmsServiceConfig{}, mockRepo, newSender(t, smsc), mockQueue)
+
+		actualMsg, actualErr := service.SendFromQueue(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.StatusSent, actualMsg.Status)
+		assert.Len(t, smsc.Submitted(), 1)
+	})
+}
+
+func TestSmsSender_OnDeliveryReport(t *testing.T) {
+	receipt := func(text string) pkgSmpp.ShortMessage {
+		return pkgSmpp.ShortMessage{
+			SourceAddr:      "09123456789",
+			DestinationAddr: "1000",
+			EsmClass:        0x04,
+			Message:         []byte(text),
+		}
+	}
+
+	t.Run("should report final delivery states of receipts", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+		smsc := smpptest.NewServer("gateway", "secret")
+		defer smsc.Close()
+
+		sender := newSender(t, smsc)
+		reports := make(chan models.DeliveryReport, 3)
+		sender.OnDeliveryReport(func(report models.DeliveryReport) {
+			reports <- report
+		})
+
+		_, err := sender.Send(ctx, msg)
+		assert.NoError(t, err)
+
+		smsc.Deliver(receipt("id:smsc-1 sub:001 dlvrd:001 submit date:2510171200 done date:2510171201 stat:DELIVRD err:000 text:Test"))
+		smsc.Deliver(receipt("id:smsc-2 sub:001 dlvrd:000 submit date:2510171200 done date:251017120130 stat:UNDELIV err:011 text:Test"))
+		smsc.Deliver(receipt("id:smsc-3 sub:001 dlvrd:000 submit date:2510171200 done date:2510181200 stat:EXPIRED err:000 text:Test"))
+
+		expected := []models.DeliveryReport{
+			{
+				MessageId: "smsc-1",
+				Status:    models.StatusDelivered,
+				DoneAt:    time.Date(2025, 10, 17, 12, 1, 0, 0, time.Local),
+			},
+			{
+				MessageId: "smsc-2",
+				Status:    models.StatusUndelivered,
+				DoneAt:    time.Date(2025, 10, 17, 12, 1, 30, 0, time.Local),
+				Error:     "UNDELIV: 011",
+			},
+			{
+				MessageId: "smsc-3",
+				Status:    models.StatusExpired,
+				DoneAt:    time.Date(2025, 10, 18, 12, 0, 0, 0, time.Local),
+				Error:     "EXPIRED: 000",
+			},
+		}
+		for _, expectedReport := range expected {
+			select {
+			case actualReport := <-reports:
+				assert.Equal(t, expectedReport, actualReport)
+			case <-time.After(time.Second):
+				t.Fatal("delivery report was not received")
+			}
+		}
+	})
+
+	t.Run("should ignore intermediate receipts and mobile originated messages", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+		smsc := smpptest.NewServer("gateway", "secret")
+		defer smsc.Close()
+
+		sender := newSender(t, smsc)
+		reports := make(chan models.DeliveryReport, 3)
+		sender.OnDeliveryReport(func(report models.DeliveryReport) {
+			reports <- report
+		})
+
+		_, err := sender.Send(ctx, msg)
+		assert.NoError(t, err)
+
+		smsc.Deliver(receipt("id:smsc-1 sub:001 dlvrd:000 submit date:2510171200 done date:2510171201 stat:ENROUTE err:000 text:Test"))
+		mo := receipt("id:smsc-2 stat:DELIVRD")
+		mo.EsmClass = 0
+		smsc.Deliver(mo)
+		smsc.Deliver(receipt("id:smsc-3 sub:001 dlvrd:001 submit date:2510171200 done date:2510171201 stat:DELIVRD err:000 text:Test"))
+
+		select {
+		case actualReport := <-reports:
+			assert.Equal(t, "smsc-3", actualReport.MessageId)
+		case <-time.After(time.Second):
+			t.Fatal("delivery report was not received")
+		}
+	})
+
+	t.Run("should parse receipt fields case-insensitively", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+		smsc := smpptest.NewServer("gateway", "secret")
+		defer smsc.Close()
+
+		sender := newSender(t, smsc)
+		reports := make(chan models.DeliveryReport, 1)
+		sender.OnDeliveryReport(func(report models.DeliveryReport) {
+			reports <- report
+		})
+
+		_, err := sender.Send(ctx, msg)
+		assert.NoError(t, err)
+
+		smsc.Deliver(receipt("ID:smsc-1 SUB:001 DLVRD:000 SUBMIT DATE:2510171200 DONE DATE:2510171201 STAT:UNDELIV ERR:011 TEXT:Test"))
+
+		select {
+		case actualReport := <-reports:
+			assert.Equal(t, models.DeliveryReport{
+				MessageId: "smsc-1",
+				Status:    models.StatusUndelivered,
+				DoneAt:    time.Date(2025, 10, 17, 12, 1, 0, 0, time.Local),
+				Error:     "UNDELIV: 011",
+			}, actualReport)
+		case <-time.After(time.Second):
+			t.Fatal("delivery report was not received")
+		}
+	})
+
+	t.Run("should keep submitting while report handler is busy", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+		smsc := smpptest.NewServer("gateway", "secret")
+		defer smsc.Close()
+
+		sender := newSender(t, smsc)
+		release := make(chan struct{})
+		defer close(release)
+		sender.OnDeliveryReport(func(report models.DeliveryReport) {
+			<-release
+		})
+
+		_, err := sender.Send(ctx, msg)
+		assert.NoError(t, err)
+
+		smsc.Deliver(receipt("id:smsc-1 sub:001 dlvrd:001 submit date:2510171200 done date:2510171201 stat:DELIVRD err:000 text:Test"))
+
+		actualRes, actualErr := sender.Send(ctx, msg)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, "smsc-2", actualRes.MessageId)
+	})
+}
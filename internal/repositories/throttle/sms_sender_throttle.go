@@ -0,0 +1,162 @@
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/repositories"
+
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+	pkgMetrics "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/metrics"
+)
+
+// inFlightPollInterval is how often a send waiting for an in-flight slot
+// checks again, since slots are freed when other sends finish.
+const inFlightPollInterval = 10 * time.Millisecond
+
+type Config struct {
+	// TPS is the contracted messages per second of the provider.
+	TPS float64 `mapstructure:"tps"`
+	// Burst is how many messages may be sent at once after the provider was
+	// idle. It defaults to one second of TPS.
+	Burst int `mapstructure:"burst"`
+	// MaxInFlight caps the sends waiting for the provider to answer.
+	MaxInFlight int           `mapstructure:"max_in_flight"`
+	Lease       time.Duration `mapstructure:"lease"`
+	// MaxWait bounds how long a send waits for the limits, after which the
+	// message goes back to the queue without using up an attempt.
+	MaxWait time.Duration `mapstructure:"max_wait"`
+}
+
+// SmsSender shapes the sends of a provider to its throughput limits, which
+// are shared with other gateway instances through the limiter.
+type SmsSender struct {
+	name    string
+	sender  repositories.ISmsSender
+	limiter repositories.IProviderLimiter
+	limit   models.ProviderLimit
+	maxWait time.Duration
+}
+
+func NewSmsSender(
+	name string,
+	cfg Config,
+	sender repositories.ISmsSender,
+	limiter repositories.IProviderLimiter,
+) *SmsSender {
+	if cfg.Burst <= 0 {
+		cfg.Burst = max(1, int(math.Ceil(cfg.TPS)))
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = 30 * time.Second
+	}
+	if cfg.MaxWait <= 0 {
+		cfg.MaxWait = 5 * time.Second
+	}
+
+	return &SmsSender{
+		name:    name,
+		sender:  sender,
+		limiter: limiter,
+		limit: models.ProviderLimit{
+			TPS:         cfg.TPS,
+			Burst:       cfg.Burst,
+			MaxInFlight: cfg.MaxInFlight,
+			Lease:       cfg.Lease,
+		},
+		maxWait: cfg.MaxWait,
+	}
+}
+
+// OnDeliveryReport forwards the handler to the provider when it reports
+// deliveries itself.
+func (s *SmsSender) OnDeliveryReport(handler func(models.DeliveryReport)) {
+	if reporter, ok := s.sender.(repositories.IDeliveryReporter); ok {
+		reporter.OnDeliveryReport(handler)
+	}
+}
+
+func (s *SmsSender) Close() error {
+	if c, ok := s.sender.(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
+
+func (s *SmsSender) limited() bool {
+	return s.limit.TPS > 0 || s.limit.MaxInFlight > 0
+}
+
+// Send waits until the provider has room for the message and holds an
+// in-flight slot while the provider handles it. Sends that wait longer than
+// MaxWait fail with ProviderThrottledError, and limiter failures are
+// temporary send failures.
+func (s *SmsSender) Send(ctx context.Context, msg models.Sms) (models.SendResult, error) {
+	if s.limited() {
+		token, err := s.acquire(ctx)
+		if err != nil {
+			return models.SendResult{}, err
+		}
+		defer s.release(token)
+	}
+
+	res, err := s.sender.Send(ctx, msg)
+	if err != nil {
+		return models.SendResult{}, err
+	}
+
+	pkgMetrics.ProviderSentMetric.WithLabelValues(s.name).Inc()
+	return res, nil
+}
+
+func (s *SmsSender) acquire(ctx context.Context) (string, error) {
+	started := time.Now()
+	defer func() {
+		pkgMetrics.ProviderThrottleWaitMetric.WithLabelValues(s.name).Observe(time.Since(started).Seconds())
+	}()
+
+	for {
+		token, throttle, err := s.limiter.AcquireSend(ctx, s.name, s.limit)
+		if err != nil {
+			pkgLog.Error(err, "failed to acquire send of provider %s", s.name)
+			return "", fmt.Errorf("%w: %s", models.TemporarySendError, err.Error())
+		}
+		if throttle.Reason == "" {
+			return token, nil
+		}
+
+		pkgMetrics.ProviderThrottledMetric.WithLabelValues(s.name, string(throttle.Reason)).Inc()
+		wait := throttle.Wait
+		if throttle.Reason == models.ThrottleInFlight {
+			wait = inFlightPollInterval
+		}
+		if time.Since(started)+wait > s.maxWait {
+			return "", fmt.Errorf("%w: %s waited for %s limit", models.ProviderThrottledError, s.name, throttle.Reason)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// release frees the in-flight slot even when the send context is canceled,
+// otherwise the slot is held until its lease expires.
+func (s *SmsSender) release(token string) {
+	if token == "" {
+		return
+	}
+
+	if err := s.limiter.ReleaseSend(context.Background(), s.name, token); err != nil {
+		pkgLog.Error(err, "failed to release send of provider %s", s.name)
+	}
+}
@@ -0,0 +1,198 @@
+package throttle_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/mocks"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/throttle"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSmsSender_Send(t *testing.T) {
+	metrics.RegisterMetrics()
+
+	cfg := throttle.Config{
+		TPS:         10,
+		MaxInFlight: 5,
+		Lease:       time.Minute,
+		MaxWait:     time.Second,
+	}
+	expectedLimit := models.ProviderLimit{
+		TPS:         10,
+		Burst:       10,
+		MaxInFlight: 5,
+		Lease:       time.Minute,
+	}
+
+	t.Run("should send without limiter when provider is not limited", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		mockSender := mocks.NewMockISmsSender(t)
+		mockLimiter := mocks.NewMockIProviderLimiter(t)
+
+		mockSender.EXPECT().
+			Send(ctx, msg).
+			Return(models.SendResult{MessageId: "1"}, nil).
+			Once()
+
+		sender := throttle.NewSmsSender("unlimited", throttle.Config{}, mockSender, mockLimiter)
+
+		actualRes, actualErr := sender.Send(ctx, msg)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.SendResult{MessageId: "1"}, actualRes)
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.ProviderSentMetric.WithLabelValues("unlimited")))
+	})
+
+	t.Run("should acquire and release send", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		mockSender := mocks.NewMockISmsSender(t)
+		mockLimiter := mocks.NewMockIProviderLimiter(t)
+
+		mockLimiter.EXPECT().
+			AcquireSend(ctx, "acquire", expectedLimit).
+			Return("token", models.Throttle{}, nil).
+			Once()
+
+		mockSender.EXPECT().
+			Send(ctx, msg).
+			Return(models.SendResult{MessageId: "1"}, nil).
+			Once()
+
+		mockLimiter.EXPECT().
+			ReleaseSend(mock.Anything, "acquire", "token").
+			Return(nil).
+			Once()
+
+		sender := throttle.NewSmsSender("acquire", cfg, mockSender, mockLimiter)
+
+		actualRes, actualErr := sender.Send(ctx, msg)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, models.SendResult{MessageId: "1"}, actualRes)
+	})
+
+	t.Run("should wait for rate limit and release on failed send", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		mockSender := mocks.NewMockISmsSender(t)
+		mockLimiter := mocks.NewMockIProviderLimiter(t)
+
+		mockLimiter.EXPECT().
+			AcquireSend(ctx, "rate", expectedLimit).
+			Return("", models.Throttle{Reason: models.ThrottleRate, Wait: time.Millisecond}, nil).
+			Once()
+
+		mockLimiter.EXPECT().
+			AcquireSend(ctx, "rate", expectedLimit).
+			Return("token", models.Throttle{}, nil).
+			Once()
+
+		mockSender.EXPECT().
+			Send(ctx, msg).
+			Return(models.SendResult{}, models.TemporarySendError).
+			Once()
+
+		mockLimiter.EXPECT().
+			ReleaseSend(mock.Anything, "rate", "token").
+			Return(nil).
+			Once()
+
+		sender := throttle.NewSmsSender("rate", cfg, mockSender, mockLimiter)
+
+		_, actualErr := sender.Send(ctx, msg)
+		assert.ErrorIs(t, actualErr, models.TemporarySendError)
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.ProviderThrottledMetric.WithLabelValues("rate", "rate")))
+	})
+
+	t.Run("should return ProviderThrottledError when wait exceeds max wait", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		mockSender := mocks.NewMockISmsSender(t)
+		mockLimiter := mocks.NewMockIProviderLimiter(t)
+
+		mockLimiter.EXPECT().
+			AcquireSend(ctx, "busy", expectedLimit).
+			Return("", models.Throttle{Reason: models.ThrottleRate, Wait: time.Minute}, nil).
+			Once()
+
+		sender := throttle.NewSmsSender("busy", cfg, mockSender, mockLimiter)
+
+		_, actualErr := sender.Send(ctx, msg)
+		assert.ErrorIs(t, actualErr, models.ProviderThrottledError)
+	})
+
+	t.Run("should return TemporarySendError when limiter fails", func(t *testing.T) {
+		ctx := context.Background()
+		msg := models.Sms{
+			Entity: &shared.Entity{
+				ID: "1",
+			},
+			UserId:   "2",
+			Content:  "Test Content",
+			Receiver: "09123456789",
+			Cost:     100,
+			Status:   models.StatusEnqueued,
+		}
+
+		mockSender := mocks.NewMockISmsSender(t)
+		mockLimiter := mocks.NewMockIProviderLimiter(t)
+
+		mockLimiter.EXPECT().
+			AcquireSend(ctx, "broken", expectedLimit).
+			Return("", models.Throttle{}, errors.New("connection refused")).
+			Once()
+
+		sender := throttle.NewSmsSender("broken", cfg, mockSender, mockLimiter)
+
+		_, actualErr := sender.Send(ctx, msg)
+		assert.ErrorIs(t, actualErr, models.TemporarySendError)
+	})
+}
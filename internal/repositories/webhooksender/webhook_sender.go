@@ -0,0 +1,75 @@
+package webhooksender
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/models"
+)
+
+const (
+	HeaderId        = "X-Webhook-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+
+	// signatureVersion prefixes signatures so the scheme can change without
+	// breaking receivers.
+	signatureVersion = "v1="
+	maxResponseSize  = 1 << 16
+)
+
+type Config struct {
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+type WebhookSender struct {
+	client *http.Client
+}
+
+func NewWebhookSender(cfg Config) *WebhookSender {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	return &WebhookSender{
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+			// A redirect would resend the signed body to a url the user did
+			// not register.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (s *WebhookSender) Send(ctx context.Context, req models.Request) (int, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.Url, bytes.NewReader(req.Body))
+	if err != nil {
+		return 0, err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(HeaderEvent, req.Event)
+	httpReq.Header.Set(HeaderTimestamp, strconv.FormatInt(req.Timestamp, 10))
+	httpReq.Header.Set(HeaderSignature, signatureVersion+req.Signature)
+	if req.DeliveryId != "" {
+		httpReq.Header.Set(HeaderId, req.DeliveryId)
+	}
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseSize))
+
+	return resp.StatusCode, nil
+}
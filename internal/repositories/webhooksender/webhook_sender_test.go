@@ -0,0 +1,77 @@
+package webhooksender_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/repositories/webhooksender"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSender_Send(t *testing.T) {
+	t.Run("should post signed body and return response status", func(t *testing.T) {
+		ctx := context.Background()
+
+		var actualHeader http.Header
+		var actualBody string
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actualHeader = r.Header.Clone()
+			body, _ := io.ReadAll(r.Body)
+			actualBody = string(body)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer receiver.Close()
+
+		sender := webhooksender.NewWebhookSender(webhooksender.Config{Timeout: time.Second})
+		actualStatus, actualErr := sender.Send(ctx, models.Request{
+			DeliveryId: "9",
+			Url:        receiver.URL,
+			Event:      models.EventMessageStatus,
+			Timestamp:  1700000000,
+			Signature:  "abc",
+			Body:       []byte(`{"event":"message.status"}`),
+		})
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, http.StatusAccepted, actualStatus)
+		assert.Equal(t, `{"event":"message.status"}`, actualBody)
+		assert.Equal(t, "application/json", actualHeader.Get("Content-Type"))
+		assert.Equal(t, "9", actualHeader.Get(webhooksender.HeaderId))
+		assert.Equal(t, models.EventMessageStatus, actualHeader.Get(webhooksender.HeaderEvent))
+		assert.Equal(t, "1700000000", actualHeader.Get(webhooksender.HeaderTimestamp))
+		assert.Equal(t, "v1=abc", actualHeader.Get(webhooksender.HeaderSignature))
+	})
+
+	t.Run("should not follow redirects", func(t *testing.T) {
+		ctx := context.Background()
+
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		}))
+		defer receiver.Close()
+
+		sender := webhooksender.NewWebhookSender(webhooksender.Config{Timeout: time.Second})
+		actualStatus, actualErr := sender.Send(ctx, models.Request{Url: receiver.URL})
+
+		assert.NoError(t, actualErr)
+		assert.Equal(t, http.StatusFound, actualStatus)
+	})
+
+	t.Run("should return error when receiver is unreachable", func(t *testing.T) {
+		ctx := context.Background()
+
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		receiver.Close()
+
+		sender := webhooksender.NewWebhookSender(webhooksender.Config{Timeout: time.Second})
+		actualStatus, actualErr := sender.Send(ctx, models.Request{Url: receiver.URL})
+
+		assert.Error(t, actualErr)
+		assert.Equal(t, 0, actualStatus)
+	})
+}
@@ -0,0 +1,10 @@
+package shared
+
+import "context"
+
+// IUnitOfWork runs fn in a single transaction spanning every repository
+// that is called with the context passed to fn. The transaction commits
+// when fn returns nil and rolls back otherwise.
+type IUnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,101 @@
+package smsgateway
+
+import (
+	"context"
+	"slices"
+
+	apikeymodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/models"
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+)
+
+// CreateApiKey issues a key for a user and returns it with the raw key, which
+// can not be shown again. Keys that require signing also get a signing secret.
+func (s *SmsGateway) CreateApiKey(
+	ctx context.Context,
+	userId string,
+	name string,
+	scopes []apikeymodels.Scope,
+	requireSignature bool,
+) (apikeymodels.ApiKey, string, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "create api key context canceled")
+		return apikeymodels.ApiKey{}, "", err
+	}
+
+	newCtx := context.Background()
+	if _, getUserErr := s.GetUser(newCtx, userId); getUserErr != nil {
+		return apikeymodels.ApiKey{}, "", getUserErr
+	}
+
+	res, rawKey, err := s.apiKey.CreateApiKey(newCtx, apikeymodels.ApiKey{
+		UserId:           userId,
+		Name:             name,
+		Scopes:           scopes,
+		RequireSignature: requireSignature,
+	})
+	if err != nil {
+		pkgLog.Error(err, "failed to create api key")
+		return apikeymodels.ApiKey{}, "", err
+	}
+
+	return res, rawKey, nil
+}
+
+func (s *SmsGateway) GetApiKeys(ctx context.Context, userId string) ([]apikeymodels.ApiKey, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "get api keys context canceled")
+		return nil, err
+	}
+
+	newCtx := context.Background()
+	if _, getUserErr := s.GetUser(newCtx, userId); getUserErr != nil {
+		return nil, getUserErr
+	}
+
+	res, err := s.apiKey.GetApiKeys(newCtx, userId)
+	if err != nil {
+		pkgLog.Error(err, "failed to get api keys")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// RevokeApiKey revokes a key of a user on behalf of caller, which can only
+// revoke keys with scopes it could grant.
+func (s *SmsGateway) RevokeApiKey(
+	ctx context.Context,
+	caller apikeymodels.ApiKey,
+	userId string,
+	id string,
+) (apikeymodels.ApiKey, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "revoke api key context canceled")
+		return apikeymodels.ApiKey{}, err
+	}
+
+	newCtx := context.Background()
+	keys, getErr := s.apiKey.GetApiKeys(newCtx, userId)
+	if getErr != nil {
+		pkgLog.Error(getErr, "failed to get api keys")
+		return apikeymodels.ApiKey{}, getErr
+	}
+	i := slices.IndexFunc(keys, func(key apikeymodels.ApiKey) bool {
+		return key.Entity != nil && key.ID == id
+	})
+	if i < 0 {
+		return apikeymodels.ApiKey{}, apikeymodels.ApiKeyNotExistError
+	}
+	if !caller.CanGrant(keys[i].Scopes) {
+		pkgLog.Error(apikeymodels.ScopeNotGrantedError, "api key %s can not revoke api key %s", caller.Prefix, id)
+		return apikeymodels.ApiKey{}, apikeymodels.ScopeNotGrantedError
+	}
+
+	res, err := s.apiKey.RevokeApiKey(newCtx, userId, id)
+	if err != nil {
+		pkgLog.Error(err, "failed to revoke api key")
+		return apikeymodels.ApiKey{}, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,48 @@
+package smsgateway
+
+import (
+	"context"
+	"time"
+
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+)
+
+func (s *SmsGateway) IdempotencyPurgeWorker(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "idempotency purge worker context canceled")
+		return 0, err
+	}
+
+	newCtx := context.Background()
+	purged, err := s.idempotency.PurgeExpired(newCtx)
+	if err != nil {
+		pkgLog.Error(err, "failed to purge expired idempotency keys")
+		return 0, nil
+	}
+
+	return purged, nil
+}
+
+func (s *SmsGateway) StartIdempotencyPurgeWorker(ctx context.Context) error {
+	pkgLog.Debug("starting idempotency purge worker...")
+	var stopErr error
+	for {
+		stopErr = ctx.Err()
+		if stopErr != nil {
+			pkgLog.Error(stopErr, "idempotency purge worker context canceled")
+			break
+		}
+
+		_, stopErr = s.IdempotencyPurgeWorker(ctx)
+		if stopErr != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(s.cfg.IdempotencyPurgeInterval):
+		}
+	}
+
+	pkgLog.Error(stopErr, "idempotency purge worker shutdown successfully")
+	return stopErr
+}
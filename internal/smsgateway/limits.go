@@ -0,0 +1,50 @@
+package smsgateway
+
+import (
+	"context"
+
+	ratelimitmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/models"
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+)
+
+// GetUserUsage returns the send limits of the user with its usage of the
+// daily and monthly quotas.
+func (s *SmsGateway) GetUserUsage(ctx context.Context, userId string) (ratelimitmodels.Usage, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "get user usage context canceled")
+		return ratelimitmodels.Usage{}, err
+	}
+
+	newCtx := context.Background()
+	res, err := s.rateLimit.GetUsage(newCtx, userId)
+	if err != nil {
+		pkgLog.Error(err, "failed to get user usage")
+		return ratelimitmodels.Usage{}, err
+	}
+
+	return res, nil
+}
+
+func (s *SmsGateway) SetUserLimits(
+	ctx context.Context,
+	userId string,
+	limits ratelimitmodels.Limits,
+) (ratelimitmodels.Limits, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "set user limits context canceled")
+		return ratelimitmodels.Limits{}, err
+	}
+
+	newCtx := context.Background()
+	if _, getUserErr := s.GetUser(newCtx, userId); getUserErr != nil {
+		return ratelimitmodels.Limits{}, getUserErr
+	}
+
+	res, err := s.rateLimit.SetLimits(newCtx, userId, limits)
+	if err != nil {
+		pkgLog.Error(err, "failed to set user limits")
+		return ratelimitmodels.Limits{}, err
+	}
+
+	return res, nil
+}
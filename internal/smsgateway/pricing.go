@@ -0,0 +1,286 @@
+package smsgateway
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pricingmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/pricing/models"
+	smsmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/pkg/gsm"
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+)
+
+// Quote is the cost preview of messages, priced as they would be when sent.
+type Quote struct {
+	Messages         []smsmodels.Sms
+	Prices           []pricingmodels.UnitPrice
+	TotalCost        int64
+	AvailableBalance int64
+}
+
+func (s *SmsGateway) QuoteMessages(ctx context.Context, userId string, sms []smsmodels.Sms) (Quote, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "quote messages context canceled")
+		return Quote{}, err
+	}
+
+	newCtx := context.Background()
+	user, getUserErr := s.GetUser(newCtx, userId)
+	if getUserErr != nil {
+		return Quote{}, getUserErr
+	}
+
+	normalized, normalizeErr := s.normalizeReceivers(sms)
+	if normalizeErr != nil {
+		return Quote{}, normalizeErr
+	}
+
+	prices, resolveErr := s.resolvePrices(newCtx, userId, normalized)
+	if resolveErr != nil {
+		return Quote{}, resolveErr
+	}
+
+	msgs, totalCost := s.applyPrices(normalized, prices)
+	return Quote{
+		Messages:         msgs,
+		Prices:           prices,
+		TotalCost:        totalCost,
+		AvailableBalance: user.AvailableBalance(),
+	}, nil
+}
+
+func (s *SmsGateway) CreatePriceList(ctx context.Context, list pricingmodels.PriceList) (pricingmodels.PriceList, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "create price list context canceled")
+		return pricingmodels.PriceList{}, err
+	}
+
+	newCtx := context.Background()
+	res, err := s.pricing.CreatePriceList(newCtx, list)
+	if err != nil {
+		pkgLog.Error(err, "failed to create price list")
+		return pricingmodels.PriceList{}, err
+	}
+
+	return res, nil
+}
+
+func (s *SmsGateway) GetPriceLists(ctx context.Context, skip int, limit int) ([]pricingmodels.PriceList, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "get price lists context canceled")
+		return nil, err
+	}
+
+	newCtx := context.Background()
+	res, err := s.pricing.GetPriceLists(newCtx, skip, limit)
+	if err != nil {
+		pkgLog.Error(err, "failed to get price lists")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *SmsGateway) AddPriceListPrices(
+	ctx context.Context,
+	priceListId string,
+	prices []pricingmodels.Price,
+) ([]pricingmodels.Price, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "add price list prices context canceled")
+		return nil, err
+	}
+
+	for i := range prices {
+		prices[i].PriceListId = priceListId
+		prices[i].UserId = ""
+	}
+
+	newCtx := context.Background()
+	res, err := s.pricing.AddPrices(newCtx, prices)
+	if err != nil {
+		pkgLog.Error(err, "failed to add price list prices")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *SmsGateway) GetPriceListPrices(ctx context.Context, priceListId string, skip int, limit int) ([]pricingmodels.Price, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "get price list prices context canceled")
+		return nil, err
+	}
+
+	newCtx := context.Background()
+	res, err := s.pricing.GetPrices(newCtx, pricingmodels.PriceFilter{PriceListId: priceListId}, skip, limit)
+	if err != nil {
+		pkgLog.Error(err, "failed to get price list prices")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// AddUserPrices adds price overrides of the user.
+func (s *SmsGateway) AddUserPrices(ctx context.Context, userId string, prices []pricingmodels.Price) ([]pricingmodels.Price, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "add user prices context canceled")
+		return nil, err
+	}
+
+	newCtx := context.Background()
+	if _, getUserErr := s.GetUser(newCtx, userId); getUserErr != nil {
+		return nil, getUserErr
+	}
+
+	for i := range prices {
+		prices[i].PriceListId = ""
+		prices[i].UserId = userId
+	}
+
+	res, err := s.pricing.AddPrices(newCtx, prices)
+	if err != nil {
+		pkgLog.Error(err, "failed to add user prices")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *SmsGateway) GetUserPrices(ctx context.Context, userId string, skip int, limit int) ([]pricingmodels.Price, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "get user prices context canceled")
+		return nil, err
+	}
+
+	newCtx := context.Background()
+	res, err := s.pricing.GetPrices(newCtx, pricingmodels.PriceFilter{UserId: userId}, skip, limit)
+	if err != nil {
+		pkgLog.Error(err, "failed to get user prices")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *SmsGateway) DeletePrice(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "delete price context canceled")
+		return err
+	}
+
+	newCtx := context.Background()
+	if err := s.pricing.DeletePrice(newCtx, id); err != nil {
+		pkgLog.Error(err, "failed to delete price")
+		return err
+	}
+
+	return nil
+}
+
+// AssignUserPriceList assigns a price list to the user, or unassigns the
+// current one when priceListId is empty.
+func (s *SmsGateway) AssignUserPriceList(ctx context.Context, userId string, priceListId string) error {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "assign user price list context canceled")
+		return err
+	}
+
+	newCtx := context.Background()
+	if _, getUserErr := s.GetUser(newCtx, userId); getUserErr != nil {
+		return getUserErr
+	}
+
+	if err := s.pricing.AssignUserPriceList(newCtx, userId, priceListId); err != nil {
+		pkgLog.Error(err, "failed to assign user price list")
+		return err
+	}
+
+	return nil
+}
+
+// priceMessages builds the messages to schedule with their normalized
+// receiver, encoding, segments and cost, and returns their total cost.
+func (s *SmsGateway) priceMessages(ctx context.Context, userId string, sms []smsmodels.Sms) ([]smsmodels.Sms, int64, error) {
+	normalized, err := s.normalizeReceivers(sms)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	prices, err := s.resolvePrices(ctx, userId, normalized)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	msgs, totalCost := s.applyPrices(normalized, prices)
+	return msgs, totalCost, nil
+}
+
+// normalizeReceivers returns the messages with their receivers in E.164, so
+// prices and routes match one form of each number.
+func (s *SmsGateway) normalizeReceivers(sms []smsmodels.Sms) ([]smsmodels.Sms, error) {
+	msgs := make([]smsmodels.Sms, len(sms))
+	for i := range sms {
+		number, err := s.phone.Normalize(sms[i].Receiver)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, sms[i].Receiver)
+		}
+
+		msgs[i] = sms[i]
+		msgs[i].Receiver = number.Number
+		msgs[i].Country = number.Country
+		msgs[i].Operator = number.Operator
+	}
+
+	return msgs, nil
+}
+
+func (s *SmsGateway) resolvePrices(ctx context.Context, userId string, sms []smsmodels.Sms) ([]pricingmodels.UnitPrice, error) {
+	receivers := make([]string, len(sms))
+	for i := range sms {
+		receivers[i] = sms[i].Receiver
+	}
+
+	prices, err := s.pricing.ResolvePrices(ctx, userId, receivers, time.Now())
+	if err != nil {
+		pkgLog.Error(err, "failed to resolve message prices")
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+// applyPrices bills each segment of a message at its resolved price, or at
+// the configured message cost when no price matches its receiver.
+func (s *SmsGateway) applyPrices(sms []smsmodels.Sms, prices []pricingmodels.UnitPrice) ([]smsmodels.Sms, int64) {
+	totalCost := int64(0)
+	msgs := make([]smsmodels.Sms, len(sms))
+	for i := range sms {
+		price := prices[i].Price
+		if prices[i].Source == pricingmodels.SourceNone {
+			price = s.cfg.MessageCost
+		}
+
+		encoding, segments := gsm.Segments(sms[i].Content)
+		msgs[i] = smsmodels.Sms{
+			Content:  sms[i].Content,
+			Receiver: sms[i].Receiver,
+			Country:  sms[i].Country,
+			Operator: sms[i].Operator,
+			Encoding: smsmodels.EncodingGSM7,
+			Segments: segments,
+			Cost:     segments * price,
+			Tags:     sms[i].Tags,
+			SendAt:   sms[i].SendAt,
+			Priority: sms[i].Priority,
+		}
+		if encoding == gsm.EncodingUCS2 {
+			msgs[i].Encoding = smsmodels.EncodingUCS2
+		}
+		totalCost += int64(msgs[i].Cost)
+	}
+
+	return msgs, totalCost
+}
@@ -3,12 +3,22 @@ package smsgateway
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/AshkanAbd/arvancloud_sms_gateway/common"
+	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
+
+	apikeysrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/services"
+	idempotencysrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/idempotency/services"
+	phonesrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/phone/services"
+	pricingsrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/pricing/services"
+	ratelimitsrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/services"
 	smsmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
 	smssrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/services"
 	usermodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
 	usersrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/services"
+	webhooksrv "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/services"
 	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
 )
 
@@ -16,24 +26,53 @@ type Config struct {
 	EnqueueCount              int           `mapstructure:"enqueue_count"`
 	FullCapacitySleepDuration time.Duration `mapstructure:"full_capacity_sleep_duration"`
 	EmptyEnqueueSleepDuration time.Duration `mapstructure:"empty_enqueue_sleep_duration"`
-	MessageCost               int           `mapstructure:"message_cost"`
+	MessageCost               int           `mapstructure:"message_cost"` // segment price when no price list matches
+	RecoveryInterval          time.Duration `mapstructure:"recovery_interval"`
+	ReconcileInterval         time.Duration `mapstructure:"reconcile_interval"`
+	WebhookInterval           time.Duration `mapstructure:"webhook_interval"`
+	IdempotencyPurgeInterval  time.Duration `mapstructure:"idempotency_purge_interval"`
+	// HoldSettleDelay is how long a hold may stay held after its message
+	// finished before the reconcile worker settles it.
+	HoldSettleDelay     time.Duration `mapstructure:"hold_settle_delay"`
+	HoldSettleBatchSize int           `mapstructure:"hold_settle_batch_size"`
 }
 
 type SmsGateway struct {
-	user usersrv.IUserService
-	sms  smssrv.ISmsService
-	cfg  Config
+	user        usersrv.IUserService
+	sms         smssrv.ISmsService
+	pricing     pricingsrv.IPricingService
+	webhook     webhooksrv.IWebhookService
+	apiKey      apikeysrv.IApiKeyService
+	rateLimit   ratelimitsrv.IRateLimitService
+	phone       phonesrv.IPhoneService
+	idempotency idempotencysrv.IIdempotencyService
+	uow         shared.IUnitOfWork
+	cfg         Config
 }
 
 func NewSmsGateway(
 	cfg Config,
 	user usersrv.IUserService,
 	sms smssrv.ISmsService,
+	pricing pricingsrv.IPricingService,
+	webhook webhooksrv.IWebhookService,
+	apiKey apikeysrv.IApiKeyService,
+	rateLimit ratelimitsrv.IRateLimitService,
+	phone phonesrv.IPhoneService,
+	idempotency idempotencysrv.IIdempotencyService,
+	uow shared.IUnitOfWork,
 ) *SmsGateway {
 	return &SmsGateway{
-		cfg:  cfg,
-		user: user,
-		sms:  sms,
+		cfg:         cfg,
+		user:        user,
+		sms:         sms,
+		pricing:     pricing,
+		webhook:     webhook,
+		apiKey:      apiKey,
+		rateLimit:   rateLimit,
+		phone:       phone,
+		idempotency: idempotency,
+		uow:         uow,
 	}
 }
 
@@ -103,6 +142,9 @@ func (s *SmsGateway) SendWorker(ctx context.Context) error {
 		if errors.Is(err, smsmodels.MessageNotExistError) || errors.Is(err, smsmodels.EmptyQueueError) {
 			return nil
 		}
+		if errors.Is(err, smsmodels.ProviderThrottledError) {
+			return nil
+		}
 		pkgLog.Error(err, "failed to enqueue sms")
 
 		if errors.Is(err, smsmodels.InvalidQueueError) {
@@ -112,11 +154,17 @@ func (s *SmsGateway) SendWorker(ctx context.Context) error {
 		return nil
 	}
 
-	if msg.Status == smsmodels.StatusFailed {
-		if _, err := s.user.IncreaseUserBalance(newCtx, msg.UserId, int64(msg.Cost)); err != nil {
-			pkgLog.Error(err, "failed to increase user balance")
+	switch msg.Status {
+	case smsmodels.StatusSent:
+		if _, err := s.user.CaptureHold(newCtx, msg.ID); err != nil {
+			pkgLog.Error(err, "failed to capture balance hold")
+		}
+	case smsmodels.StatusFailed:
+		if _, err := s.user.ReleaseHold(newCtx, msg.ID); err != nil {
+			pkgLog.Error(err, "failed to release balance hold")
 		}
 	}
+	s.publishMessageStatus(newCtx, msg)
 
 	return nil
 }
@@ -141,6 +189,147 @@ func (s *SmsGateway) StartSendWorkers(ctx context.Context) error {
 	return stopErr
 }
 
+func (s *SmsGateway) RecoveryWorker(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "recovery worker context canceled")
+		return 0, err
+	}
+
+	newCtx := context.Background()
+	recovered, err := s.sms.RecoverUnacked(newCtx)
+	if err != nil {
+		pkgLog.Error(err, "failed to recover unacknowledged sms")
+
+		if errors.Is(err, smsmodels.InvalidQueueError) {
+			return 0, err
+		}
+
+		return 0, nil
+	}
+	pkgLog.Debug("recovered sms: %d", recovered)
+
+	return recovered, nil
+}
+
+func (s *SmsGateway) StartRecoveryWorker(ctx context.Context) error {
+	pkgLog.Debug("starting recovery worker...")
+	var stopErr error
+	for {
+		stopErr = ctx.Err()
+		if stopErr != nil {
+			pkgLog.Error(stopErr, "recovery worker context canceled")
+			break
+		}
+
+		_, stopErr = s.RecoveryWorker(ctx)
+		if stopErr != nil {
+			break
+		}
+		time.Sleep(s.cfg.RecoveryInterval)
+	}
+
+	pkgLog.Error(stopErr, "recovery worker shutdown successfully")
+	return stopErr
+}
+
+func (s *SmsGateway) ReconcileWorker(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "reconcile worker context canceled")
+		return 0, err
+	}
+
+	newCtx := context.Background()
+	res, err := s.sms.ReconcileStuckMessages(newCtx)
+	for _, msg := range res.Failed {
+		if _, releaseErr := s.user.ReleaseHold(newCtx, msg.ID); releaseErr != nil {
+			pkgLog.Error(releaseErr, "failed to release balance hold")
+		}
+		s.publishMessageStatus(newCtx, msg)
+	}
+	for _, msg := range res.Sent {
+		if _, captureErr := s.user.CaptureHold(newCtx, msg.ID); captureErr != nil {
+			pkgLog.Error(captureErr, "failed to capture balance hold")
+		}
+		s.publishMessageStatus(newCtx, msg)
+	}
+	if err != nil {
+		pkgLog.Error(err, "failed to reconcile stuck sms")
+
+		if errors.Is(err, smsmodels.InvalidQueueError) {
+			return 0, err
+		}
+
+		return 0, nil
+	}
+	reconciled := res.Rescheduled + len(res.Failed) + len(res.Sent)
+	pkgLog.Debug("reconciled sms: %d", reconciled)
+
+	return reconciled, nil
+}
+
+// ReconcileHolds settles holds left held by finished messages, such as when
+// the send worker failed to capture or release them after the status change.
+func (s *SmsGateway) ReconcileHolds(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "reconcile holds context canceled")
+		return 0, err
+	}
+
+	newCtx := context.Background()
+	holds, err := s.user.GetUnsettledHolds(newCtx, time.Now().Add(-s.cfg.HoldSettleDelay), s.cfg.HoldSettleBatchSize)
+	if err != nil {
+		pkgLog.Error(err, "failed to get unsettled holds")
+		return 0, nil
+	}
+
+	settled := 0
+	for _, hold := range holds {
+		var settleErr error
+		if hold.Capture {
+			_, settleErr = s.user.CaptureHold(newCtx, hold.MessageId)
+		} else {
+			_, settleErr = s.user.ReleaseHold(newCtx, hold.MessageId)
+		}
+		if settleErr != nil {
+			if !errors.Is(settleErr, usermodels.HoldNotExistError) {
+				pkgLog.Error(settleErr, "failed to settle balance hold of message %s", hold.MessageId)
+			}
+			continue
+		}
+		settled++
+	}
+	if settled > 0 {
+		pkgLog.Info("%d unsettled balance holds settled", settled)
+	}
+
+	return settled, nil
+}
+
+func (s *SmsGateway) StartReconcileWorker(ctx context.Context) error {
+	pkgLog.Debug("starting reconcile worker...")
+	var stopErr error
+	for {
+		stopErr = ctx.Err()
+		if stopErr != nil {
+			pkgLog.Error(stopErr, "reconcile worker context canceled")
+			break
+		}
+
+		_, stopErr = s.ReconcileWorker(ctx)
+		if stopErr != nil {
+			break
+		}
+		_, stopErr = s.ReconcileHolds(ctx)
+		if stopErr != nil {
+			break
+		}
+		time.Sleep(s.cfg.ReconcileInterval)
+	}
+
+	pkgLog.Error(stopErr, "reconcile worker shutdown successfully")
+	return stopErr
+}
+
 func (s *SmsGateway) CreateUser(ctx context.Context, user usermodels.User) (usermodels.User, error) {
 	if err := ctx.Err(); err != nil {
 		pkgLog.Error(err, "create user context canceled")
@@ -201,30 +390,39 @@ func (s *SmsGateway) SendSingleMessage(ctx context.Context, userId string, sms s
 		return getUserErr
 	}
 
-	totalCost := int64(s.cfg.MessageCost)
+	msgs, totalCost, priceErr := s.priceMessages(newCtx, userId, []smsmodels.Sms{sms})
+	if priceErr != nil {
+		return priceErr
+	}
 
-	if user.Balance < totalCost {
+	if user.AvailableBalance() < totalCost {
 		return usermodels.InsufficientBalanceError
 	}
 
-	msg := smsmodels.Sms{
-		Content:  sms.Content,
-		Receiver: sms.Receiver,
-		Cost:     s.cfg.MessageCost,
-	}
-	if _, decreaseErr := s.user.DecreaseUserBalance(newCtx, userId, totalCost); decreaseErr != nil {
-		pkgLog.Error(decreaseErr, "failed to decrease user balance")
-		return decreaseErr
+	reservation, limitErr := s.rateLimit.Reserve(newCtx, userId, len(msgs))
+	if limitErr != nil {
+		return limitErr
 	}
 
-	if scheduleErr := s.sms.ScheduleSms(newCtx, userId, []smsmodels.Sms{msg}); scheduleErr != nil {
-		pkgLog.Error(scheduleErr, "failed to schedule sms")
+	err := s.uow.Do(newCtx, func(txCtx context.Context) error {
+		created, scheduleErr := s.sms.ScheduleSms(txCtx, userId, msgs)
+		if scheduleErr != nil {
+			pkgLog.Error(scheduleErr, "failed to schedule sms")
+			return scheduleErr
+		}
 
-		if _, increaseErr := s.user.IncreaseUserBalance(newCtx, userId, totalCost); increaseErr != nil {
-			pkgLog.Error(increaseErr, "failed to increase user balance")
+		if holdErr := s.user.HoldUserBalance(txCtx, userId, messageHolds(created)); holdErr != nil {
+			pkgLog.Error(holdErr, "failed to hold user balance")
+			return holdErr
 		}
 
-		return scheduleErr
+		return nil
+	})
+	if err != nil {
+		if releaseErr := s.rateLimit.Release(newCtx, reservation); releaseErr != nil {
+			pkgLog.Error(releaseErr, "failed to release send limits of user %s", userId)
+		}
+		return err
 	}
 
 	return nil
@@ -242,46 +440,184 @@ func (s *SmsGateway) SendBulkMessage(ctx context.Context, userId string, sms []s
 		return getUserErr
 	}
 
-	totalCost := int64(s.cfg.MessageCost * len(sms))
+	msgs, totalCost, priceErr := s.priceMessages(newCtx, userId, sms)
+	if priceErr != nil {
+		return priceErr
+	}
 
-	if user.Balance < totalCost {
+	if user.AvailableBalance() < totalCost {
 		return usermodels.InsufficientBalanceError
 	}
 
-	msgs := make([]smsmodels.Sms, len(sms))
-	for i := range sms {
-		msgs[i] = smsmodels.Sms{
-			Content:  sms[i].Content,
-			Receiver: sms[i].Receiver,
-			Cost:     s.cfg.MessageCost,
+	reservation, limitErr := s.rateLimit.Reserve(newCtx, userId, len(msgs))
+	if limitErr != nil {
+		return limitErr
+	}
+
+	err := s.uow.Do(newCtx, func(txCtx context.Context) error {
+		created, scheduleErr := s.sms.ScheduleSms(txCtx, userId, msgs)
+		if scheduleErr != nil {
+			pkgLog.Error(scheduleErr, "failed to schedule sms")
+			return scheduleErr
+		}
+
+		if holdErr := s.user.HoldUserBalance(txCtx, userId, messageHolds(created)); holdErr != nil {
+			pkgLog.Error(holdErr, "failed to hold user balance")
+			return holdErr
+		}
+
+		return nil
+	})
+	if err != nil {
+		if releaseErr := s.rateLimit.Release(newCtx, reservation); releaseErr != nil {
+			pkgLog.Error(releaseErr, "failed to release send limits of user %s", userId)
 		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *SmsGateway) SetUserEnqueueWeight(ctx context.Context, userId string, weight int) (usermodels.User, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "set user enqueue weight context canceled")
+		return usermodels.User{}, err
+	}
+
+	newCtx := context.Background()
+	res, err := s.user.SetUserEnqueueWeight(newCtx, userId, weight)
+	if err != nil {
+		pkgLog.Error(err, "failed to set user enqueue weight")
+		return usermodels.User{}, err
 	}
-	if _, decreaseErr := s.user.DecreaseUserBalance(newCtx, userId, totalCost); decreaseErr != nil {
-		pkgLog.Error(decreaseErr, "failed to decrease user balance")
-		return decreaseErr
+
+	return res, nil
+}
+
+func (s *SmsGateway) SetUserAccount(
+	ctx context.Context,
+	userId string,
+	accountType usermodels.AccountType,
+	creditLimit int64,
+) (usermodels.User, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "set user account context canceled")
+		return usermodels.User{}, err
 	}
 
-	if scheduleErr := s.sms.ScheduleSms(newCtx, userId, msgs); scheduleErr != nil {
-		pkgLog.Error(scheduleErr, "failed to schedule sms")
+	newCtx := context.Background()
+	res, err := s.user.SetUserAccount(newCtx, userId, accountType, creditLimit)
+	if err != nil {
+		pkgLog.Error(err, "failed to set user account")
+		return usermodels.User{}, err
+	}
 
-		if _, increaseErr := s.user.IncreaseUserBalance(newCtx, userId, totalCost); increaseErr != nil {
-			pkgLog.Error(increaseErr, "failed to increase user balance")
+	return res, nil
+}
+
+// GetUserStatement returns the usage statement of the user for the calendar
+// month that contains month.
+func (s *SmsGateway) GetUserStatement(ctx context.Context, userId string, month time.Time) (usermodels.BalanceStatement, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "get user statement context canceled")
+		return usermodels.BalanceStatement{}, err
+	}
+
+	newCtx := context.Background()
+	if _, getUserErr := s.GetUser(newCtx, userId); getUserErr != nil {
+		return usermodels.BalanceStatement{}, getUserErr
+	}
+
+	res, err := s.user.GetUserStatement(newCtx, userId, month)
+	if err != nil {
+		pkgLog.Error(err, "failed to get user statement")
+		return usermodels.BalanceStatement{}, err
+	}
+
+	return res, nil
+}
+
+// CancelMessage cancels a message that is not enqueued yet and releases its hold.
+func (s *SmsGateway) CancelMessage(ctx context.Context, userId string, smsId string) (smsmodels.Sms, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "cancel message context canceled")
+		return smsmodels.Sms{}, err
+	}
+
+	newCtx := context.Background()
+	var msg smsmodels.Sms
+	err := s.uow.Do(newCtx, func(txCtx context.Context) error {
+		var cancelErr error
+		msg, cancelErr = s.sms.CancelSms(txCtx, userId, smsId)
+		if cancelErr != nil {
+			pkgLog.Error(cancelErr, "failed to cancel message")
+			return cancelErr
+		}
+
+		if _, releaseErr := s.user.ReleaseHold(txCtx, msg.ID); releaseErr != nil {
+			pkgLog.Error(releaseErr, "failed to release balance hold")
+			return releaseErr
 		}
 
-		return scheduleErr
+		return nil
+	})
+	if err != nil {
+		return smsmodels.Sms{}, err
 	}
+	s.publishMessageStatus(newCtx, msg)
 
-	return nil
+	return msg, nil
+}
+
+// ProcessDeliveryReport records the final delivery state a provider reported
+// for one of its sent messages.
+func (s *SmsGateway) ProcessDeliveryReport(ctx context.Context, report smsmodels.DeliveryReport) (smsmodels.Sms, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "process delivery report context canceled")
+		return smsmodels.Sms{}, err
+	}
+
+	newCtx := context.Background()
+	msg, err := s.sms.ProcessDeliveryReport(newCtx, report)
+	if err != nil {
+		pkgLog.Error(err, "failed to process delivery report")
+		return smsmodels.Sms{}, err
+	}
+	s.publishMessageStatus(newCtx, msg)
+
+	return msg, nil
+}
+
+func (s *SmsGateway) RescheduleMessage(ctx context.Context, userId string, smsId string, sendAt time.Time) (smsmodels.Sms, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "reschedule message context canceled")
+		return smsmodels.Sms{}, err
+	}
+
+	newCtx := context.Background()
+	msg, err := s.sms.RescheduleSms(newCtx, userId, smsId, sendAt)
+	if err != nil {
+		pkgLog.Error(err, "failed to reschedule message")
+		return smsmodels.Sms{}, err
+	}
+
+	return msg, nil
 }
 
-func (s *SmsGateway) IncreaseUserBalance(ctx context.Context, userId string, amount int64) (int64, error) {
+func (s *SmsGateway) IncreaseUserBalance(ctx context.Context, userId string, amount int64, reference string) (int64, error) {
 	if err := ctx.Err(); err != nil {
 		pkgLog.Error(err, "increase user context canceled")
 		return 0, err
 	}
 
 	newCtx := context.Background()
-	newBalance, err := s.user.IncreaseUserBalance(newCtx, userId, amount)
+	newBalance, err := s.user.IncreaseUserBalance(newCtx, userId, []usermodels.BalanceChange{
+		{
+			Type:      usermodels.TransactionTopUp,
+			Amount:    amount,
+			Reference: reference,
+		},
+	})
 	if err != nil {
 		pkgLog.Error(err, "failed to increase user balance")
 		return 0, err
@@ -289,3 +625,166 @@ func (s *SmsGateway) IncreaseUserBalance(ctx context.Context, userId string, amo
 
 	return newBalance, nil
 }
+
+func (s *SmsGateway) GetUserTransactions(
+	ctx context.Context,
+	userId string,
+	filter usermodels.TransactionFilter,
+	skip int,
+	limit int,
+) ([]usermodels.BalanceTransaction, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "get user transactions context canceled")
+		return nil, err
+	}
+
+	newCtx := context.Background()
+	txs, err := s.user.GetUserTransactions(newCtx, userId, filter, skip, limit)
+	if err != nil {
+		pkgLog.Error(err, "failed to get user transactions")
+		return nil, err
+	}
+
+	return txs, nil
+}
+
+func (s *SmsGateway) GetDeadLetters(ctx context.Context, skip int, limit int) ([]smsmodels.DeadLetter, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "get dead letters context canceled")
+		return nil, err
+	}
+
+	newCtx := context.Background()
+	letters, err := s.sms.GetDeadLetters(newCtx, skip, limit)
+	if err != nil {
+		pkgLog.Error(err, "failed to get dead letters")
+		return nil, err
+	}
+
+	return letters, nil
+}
+
+func (s *SmsGateway) GetDeadLetter(ctx context.Context, id string) (smsmodels.DeadLetter, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "get dead letter context canceled")
+		return smsmodels.DeadLetter{}, err
+	}
+
+	newCtx := context.Background()
+	letter, err := s.sms.GetDeadLetter(newCtx, id)
+	if err != nil {
+		pkgLog.Error(err, "failed to get dead letter")
+		return smsmodels.DeadLetter{}, err
+	}
+
+	return letter, nil
+}
+
+// RequeueDeadLetter schedules the failed message of a dead letter again. The
+// hold of failed messages is released, so the cost is held once more.
+func (s *SmsGateway) RequeueDeadLetter(ctx context.Context, id string) (smsmodels.Sms, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "requeue dead letter context canceled")
+		return smsmodels.Sms{}, err
+	}
+
+	newCtx := context.Background()
+	letter, err := s.GetDeadLetter(newCtx, id)
+	if err != nil {
+		return smsmodels.Sms{}, err
+	}
+	if letter.MessageId == "" {
+		return smsmodels.Sms{}, smsmodels.NotRequeueableError
+	}
+
+	msg := smsmodels.Sms{}
+	if err := common.JSONToValue(letter.Payload, &msg); err != nil {
+		pkgLog.Error(err, "failed to decode dead letter %s payload", id)
+		return smsmodels.Sms{}, fmt.Errorf("%w: %s", smsmodels.NotRequeueableError, err)
+	}
+
+	user, getUserErr := s.GetUser(newCtx, msg.UserId)
+	if getUserErr != nil {
+		return smsmodels.Sms{}, getUserErr
+	}
+
+	totalCost := int64(msg.Cost)
+
+	if user.AvailableBalance() < totalCost {
+		return smsmodels.Sms{}, usermodels.InsufficientBalanceError
+	}
+
+	var requeued smsmodels.Sms
+	err = s.uow.Do(newCtx, func(txCtx context.Context) error {
+		hold := []usermodels.BalanceHold{
+			{
+				MessageId: letter.MessageId,
+				Amount:    totalCost,
+			},
+		}
+		if holdErr := s.user.HoldUserBalance(txCtx, msg.UserId, hold); holdErr != nil {
+			pkgLog.Error(holdErr, "failed to hold user balance")
+			return holdErr
+		}
+
+		var requeueErr error
+		requeued, requeueErr = s.sms.RequeueDeadLetter(txCtx, id)
+		if requeueErr != nil {
+			pkgLog.Error(requeueErr, "failed to requeue dead letter")
+			return requeueErr
+		}
+
+		return nil
+	})
+	if err != nil {
+		return smsmodels.Sms{}, err
+	}
+
+	return requeued, nil
+}
+
+func (s *SmsGateway) DeleteDeadLetter(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "delete dead letter context canceled")
+		return err
+	}
+
+	newCtx := context.Background()
+	if err := s.sms.DeleteDeadLetter(newCtx, id); err != nil {
+		pkgLog.Error(err, "failed to delete dead letter")
+		return err
+	}
+
+	return nil
+}
+
+func (s *SmsGateway) PurgeDeadLetters(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "purge dead letters context canceled")
+		return 0, err
+	}
+
+	newCtx := context.Background()
+	purged, err := s.sms.PurgeDeadLetters(newCtx)
+	if err != nil {
+		pkgLog.Error(err, "failed to purge dead letters")
+		return 0, err
+	}
+
+	return purged, nil
+}
+
+// messageHolds builds one balance hold per message for its cost.
+func messageHolds(msgs []smsmodels.Sms) []usermodels.BalanceHold {
+	holds := make([]usermodels.BalanceHold, len(msgs))
+	for i := range msgs {
+		holds[i] = usermodels.BalanceHold{
+			Amount: int64(msgs[i].Cost),
+		}
+		if msgs[i].Entity != nil {
+			holds[i].MessageId = msgs[i].ID
+		}
+	}
+
+	return holds
+}
@@ -3,17 +3,32 @@ package smsgateway_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/AshkanAbd/arvancloud_sms_gateway/common"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared"
 	"github.com/AshkanAbd/arvancloud_sms_gateway/internal/smsgateway"
 	"github.com/stretchr/testify/assert"
-
+	"github.com/stretchr/testify/mock"
+
+	apikeymocks "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/mocks"
+	apikeymodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/apikey/models"
+	idempotencymocks "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/idempotency/mocks"
+	phonemocks "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/phone/mocks"
+	phonemodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/phone/models"
+	pricingmocks "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/pricing/mocks"
+	pricingmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/pricing/models"
+	ratelimitmocks "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/mocks"
+	ratelimitmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/ratelimit/models"
 	smsmocks "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/mocks"
 	smsmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
 	usermocks "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/mocks"
 	usermodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/user/models"
+	webhookmocks "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/mocks"
+	webhookmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/models"
+	sharedmocks "github.com/AshkanAbd/arvancloud_sms_gateway/internal/shared/mocks"
 )
 
 func TestSmsGateway_CreateUser(t *testing.T) {
@@ -27,6 +42,13 @@ func TestSmsGateway_CreateUser(t *testing.T) {
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
 		expectedUser := usermodels.User{
 			Name: "AshkanAbd",
@@ -37,7 +59,7 @@ func TestSmsGateway_CreateUser(t *testing.T) {
 			Return(expectedUser, nil).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
 		actualUser, actualErr := smsGateway.CreateUser(ctx, expectedUser)
 		assert.NoError(t, actualErr)
@@ -49,6 +71,13 @@ func TestSmsGateway_CreateUser(t *testing.T) {
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
 		expectedUser := usermodels.User{
 			Name: "AshkanAbd",
@@ -61,7 +90,7 @@ func TestSmsGateway_CreateUser(t *testing.T) {
 			Return(usermodels.User{}, expectedErr).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
 		actualUser, actualErr := smsGateway.CreateUser(ctx, expectedUser)
 		assert.Error(t, actualErr)
@@ -81,6 +110,13 @@ func TestSmsGateway_GetUser(t *testing.T) {
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
 		userId := "1"
 
@@ -94,7 +130,7 @@ func TestSmsGateway_GetUser(t *testing.T) {
 			Return(expectedUser, nil).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
 		actualUser, actualErr := smsGateway.GetUser(ctx, userId)
 		assert.NoError(t, actualErr)
@@ -107,6 +143,13 @@ func TestSmsGateway_GetUser(t *testing.T) {
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
 		userId := "1"
 
@@ -117,7 +160,7 @@ func TestSmsGateway_GetUser(t *testing.T) {
 			Return(usermodels.User{}, expectedErr).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
 		actualUser, actualErr := smsGateway.GetUser(ctx, userId)
 		assert.Error(t, actualErr)
@@ -137,6 +180,13 @@ func TestSmsGateway_GetUserMessages(t *testing.T) {
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
 		userId := "1"
 
@@ -167,7 +217,7 @@ func TestSmsGateway_GetUserMessages(t *testing.T) {
 			Return(expectedMsgs, nil).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
 		actualMsgs, actualErr := smsGateway.GetUserMessages(ctx, userId, 0, 10, true)
 		assert.NoError(t, actualErr)
@@ -189,6 +239,13 @@ func TestSmsGateway_GetUserMessages(t *testing.T) {
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
 		userId := "1"
 
@@ -199,7 +256,7 @@ func TestSmsGateway_GetUserMessages(t *testing.T) {
 			Return(nil, expectedErr).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
 		actualMsgs, actualErr := smsGateway.GetUserMessages(ctx, userId, 0, 10, true)
 		assert.Error(t, actualErr)
@@ -219,6 +276,13 @@ func TestSmsGateway_SendSingleMessage(t *testing.T) {
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
 		userId := "1"
 
@@ -231,6 +295,8 @@ func TestSmsGateway_SendSingleMessage(t *testing.T) {
 		msg := smsmodels.Sms{
 			Content:  "Test Content 1",
 			Receiver: "09123456789",
+			Tags:     []string{"otp"},
+			Priority: smsmodels.PriorityHigh,
 		}
 
 		mockUser.EXPECT().
@@ -238,9 +304,36 @@ func TestSmsGateway_SendSingleMessage(t *testing.T) {
 			Return(user, nil).
 			Once()
 
+		mockPhone.EXPECT().
+			Normalize(msg.Receiver).
+			Return(phonemodels.PhoneNumber{Number: msg.Receiver}, nil).
+			Once()
+
+		mockPricing.EXPECT().
+			ResolvePrices(ctx, userId, []string{msg.Receiver}, mock.Anything).
+			Return([]pricingmodels.UnitPrice{
+				{Receiver: msg.Receiver, Source: pricingmodels.SourceNone},
+			}, nil).
+			Once()
+
+		reservation := ratelimitmodels.Reservation{UserId: userId, Count: 1}
+		mockRateLimit.EXPECT().
+			Reserve(ctx, userId, 1).
+			Return(reservation, nil).
+			Once()
+
+		mockUow.EXPECT().
+			Do(ctx, mock.Anything).
+			RunAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).
+			Once()
+
 		mockUser.EXPECT().
-			DecreaseUserBalance(ctx, userId, int64(cfg.MessageCost)).
-			Return(0, nil).
+			HoldUserBalance(ctx, userId, []usermodels.BalanceHold{
+				{MessageId: "10", Amount: int64(cfg.MessageCost)},
+			}).
+			Return(nil).
 			Once()
 
 		mockSms.EXPECT().
@@ -248,29 +341,41 @@ func TestSmsGateway_SendSingleMessage(t *testing.T) {
 				{
 					Content:  msg.Content,
 					Receiver: msg.Receiver,
+					Segments: 1,
 					Cost:     cfg.MessageCost,
+					Tags:     msg.Tags,
+					Priority: msg.Priority,
 				},
-			}).Return(nil).
+			}).Return([]smsmodels.Sms{
+			{Entity: &shared.Entity{ID: "10"}, Cost: cfg.MessageCost},
+		}, nil).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
 		actualErr := smsGateway.SendSingleMessage(ctx, userId, msg)
 		assert.NoError(t, actualErr)
 	})
 
-	t.Run("should return InsufficientBalanceError when user balance is not enough", func(t *testing.T) {
+	t.Run("should normalize receiver before pricing and scheduling", func(t *testing.T) {
 		ctx := context.Background()
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
 		userId := "1"
 
 		user := usermodels.User{
 			Entity:  &shared.Entity{ID: "1"},
 			Name:    "AshkanAbd",
-			Balance: 10,
+			Balance: 1000,
 		}
 
 		msg := smsmodels.Sms{
@@ -283,18 +388,71 @@ func TestSmsGateway_SendSingleMessage(t *testing.T) {
 			Return(user, nil).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		mockPhone.EXPECT().
+			Normalize(msg.Receiver).
+			Return(phonemodels.PhoneNumber{Number: "+989123456789", Country: "IR", Operator: "MCI"}, nil).
+			Once()
+
+		mockPricing.EXPECT().
+			ResolvePrices(ctx, userId, []string{"+989123456789"}, mock.Anything).
+			Return([]pricingmodels.UnitPrice{
+				{Receiver: "+989123456789", Prefix: "98912", Price: 50, Source: pricingmodels.SourcePlan},
+			}, nil).
+			Once()
+
+		reservation := ratelimitmodels.Reservation{UserId: userId, Count: 1}
+		mockRateLimit.EXPECT().
+			Reserve(ctx, userId, 1).
+			Return(reservation, nil).
+			Once()
+
+		mockUow.EXPECT().
+			Do(ctx, mock.Anything).
+			RunAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).
+			Once()
+
+		mockSms.EXPECT().
+			ScheduleSms(ctx, userId, []smsmodels.Sms{
+				{
+					Content:  msg.Content,
+					Receiver: "+989123456789",
+					Country:  "IR",
+					Operator: "MCI",
+					Segments: 1,
+					Cost:     50,
+				},
+			}).Return([]smsmodels.Sms{
+			{Entity: &shared.Entity{ID: "10"}, Cost: 50},
+		}, nil).
+			Once()
+
+		mockUser.EXPECT().
+			HoldUserBalance(ctx, userId, []usermodels.BalanceHold{
+				{MessageId: "10", Amount: 50},
+			}).
+			Return(nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
 		actualErr := smsGateway.SendSingleMessage(ctx, userId, msg)
-		assert.Error(t, actualErr)
-		assert.Equal(t, usermodels.InsufficientBalanceError, actualErr)
+		assert.NoError(t, actualErr)
 	})
 
-	t.Run("should return InsufficientBalanceError when user balance is not enough because of race", func(t *testing.T) {
+	t.Run("should return InvalidPhoneNumberError when receiver is not valid", func(t *testing.T) {
 		ctx := context.Background()
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
 		userId := "1"
 
@@ -306,7 +464,7 @@ func TestSmsGateway_SendSingleMessage(t *testing.T) {
 
 		msg := smsmodels.Sms{
 			Content:  "Test Content 1",
-			Receiver: "09123456789",
+			Receiver: "09801234567",
 		}
 
 		mockUser.EXPECT().
@@ -314,23 +472,29 @@ func TestSmsGateway_SendSingleMessage(t *testing.T) {
 			Return(user, nil).
 			Once()
 
-		mockUser.EXPECT().
-			DecreaseUserBalance(ctx, userId, int64(cfg.MessageCost)).
-			Return(0, usermodels.InsufficientBalanceError).
+		mockPhone.EXPECT().
+			Normalize(msg.Receiver).
+			Return(phonemodels.PhoneNumber{}, phonemodels.UnallocatedRangeError).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
 		actualErr := smsGateway.SendSingleMessage(ctx, userId, msg)
-		assert.Error(t, actualErr)
-		assert.Equal(t, usermodels.InsufficientBalanceError, actualErr)
+		assert.ErrorIs(t, actualErr, phonemodels.InvalidPhoneNumberError)
 	})
 
-	t.Run("should return error when can not schedule message", func(t *testing.T) {
+	t.Run("should price a long UCS-2 message per segment at its resolved price", func(t *testing.T) {
 		ctx := context.Background()
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
 		userId := "1"
 
@@ -341,20 +505,38 @@ func TestSmsGateway_SendSingleMessage(t *testing.T) {
 		}
 
 		msg := smsmodels.Sms{
-			Content:  "Test Content 1",
+			Content:  strings.Repeat("س", 100),
 			Receiver: "09123456789",
 		}
 
-		expectedErr := fmt.Errorf("some error")
-
 		mockUser.EXPECT().
 			GetUser(ctx, userId).
 			Return(user, nil).
 			Once()
 
-		mockUser.EXPECT().
-			DecreaseUserBalance(ctx, userId, int64(cfg.MessageCost)).
-			Return(0, nil).
+		mockPhone.EXPECT().
+			Normalize(msg.Receiver).
+			Return(phonemodels.PhoneNumber{Number: msg.Receiver}, nil).
+			Once()
+
+		mockPricing.EXPECT().
+			ResolvePrices(ctx, userId, []string{msg.Receiver}, mock.Anything).
+			Return([]pricingmodels.UnitPrice{
+				{Receiver: msg.Receiver, Prefix: "0912", Price: 90, Source: pricingmodels.SourcePlan},
+			}, nil).
+			Once()
+
+		reservation := ratelimitmodels.Reservation{UserId: userId, Count: 1}
+		mockRateLimit.EXPECT().
+			Reserve(ctx, userId, 1).
+			Return(reservation, nil).
+			Once()
+
+		mockUow.EXPECT().
+			Do(ctx, mock.Anything).
+			RunAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).
 			Once()
 
 		mockSms.EXPECT().
@@ -362,52 +544,52 @@ func TestSmsGateway_SendSingleMessage(t *testing.T) {
 				{
 					Content:  msg.Content,
 					Receiver: msg.Receiver,
-					Cost:     cfg.MessageCost,
+					Encoding: smsmodels.EncodingUCS2,
+					Segments: 2,
+					Cost:     180,
 				},
-			}).Return(expectedErr).
+			}).Return([]smsmodels.Sms{
+			{Entity: &shared.Entity{ID: "10"}, Cost: 180},
+		}, nil).
 			Once()
 
 		mockUser.EXPECT().
-			IncreaseUserBalance(ctx, userId, int64(cfg.MessageCost)).
-			Return(0, nil).
+			HoldUserBalance(ctx, userId, []usermodels.BalanceHold{
+				{MessageId: "10", Amount: int64(180)},
+			}).
+			Return(nil).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
 		actualErr := smsGateway.SendSingleMessage(ctx, userId, msg)
-		assert.Error(t, actualErr)
-		assert.Equal(t, expectedErr, actualErr)
+		assert.NoError(t, actualErr)
 	})
-}
-
-func TestSmsGateway_SendBulkMessage(t *testing.T) {
-	cfg := smsgateway.Config{
-		EnqueueCount: 0,
-		MessageCost:  100,
-	}
 
-	t.Run("should schedule message", func(t *testing.T) {
+	t.Run("should return InsufficientBalanceError when user balance does not cover all segments", func(t *testing.T) {
 		ctx := context.Background()
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
 		userId := "1"
 
 		user := usermodels.User{
 			Entity:  &shared.Entity{ID: "1"},
 			Name:    "AshkanAbd",
-			Balance: 1000,
+			Balance: 200,
 		}
 
-		msgs := []smsmodels.Sms{
-			{
-				Content:  "Test Content 1",
-				Receiver: "09123456789",
-			}, {
-				Content:  "Test Content 2",
-				Receiver: "09123456788",
-			},
+		msg := smsmodels.Sms{
+			Content:  strings.Repeat("a", 320),
+			Receiver: "09123456789",
 		}
 
 		mockUser.EXPECT().
@@ -415,29 +597,23 @@ func TestSmsGateway_SendBulkMessage(t *testing.T) {
 			Return(user, nil).
 			Once()
 
-		mockUser.EXPECT().
-			DecreaseUserBalance(ctx, userId, int64(cfg.MessageCost*len(msgs))).
-			Return(0, nil).
+		mockPhone.EXPECT().
+			Normalize(msg.Receiver).
+			Return(phonemodels.PhoneNumber{Number: msg.Receiver}, nil).
 			Once()
 
-		mockSms.EXPECT().
-			ScheduleSms(ctx, userId, []smsmodels.Sms{
-				{
-					Content:  msgs[0].Content,
-					Receiver: msgs[0].Receiver,
-					Cost:     cfg.MessageCost,
-				}, {
-					Content:  msgs[1].Content,
-					Receiver: msgs[1].Receiver,
-					Cost:     cfg.MessageCost,
-				},
-			}).Return(nil).
+		mockPricing.EXPECT().
+			ResolvePrices(ctx, userId, []string{msg.Receiver}, mock.Anything).
+			Return([]pricingmodels.UnitPrice{
+				{Receiver: msg.Receiver, Source: pricingmodels.SourceNone},
+			}, nil).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
-		actualErr := smsGateway.SendBulkMessage(ctx, userId, msgs)
-		assert.NoError(t, actualErr)
+		actualErr := smsGateway.SendSingleMessage(ctx, userId, msg)
+		assert.Error(t, actualErr)
+		assert.Equal(t, usermodels.InsufficientBalanceError, actualErr)
 	})
 
 	t.Run("should return InsufficientBalanceError when user balance is not enough", func(t *testing.T) {
@@ -445,6 +621,13 @@ func TestSmsGateway_SendBulkMessage(t *testing.T) {
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
 		userId := "1"
 
@@ -454,14 +637,9 @@ func TestSmsGateway_SendBulkMessage(t *testing.T) {
 			Balance: 10,
 		}
 
-		msgs := []smsmodels.Sms{
-			{
-				Content:  "Test Content 1",
-				Receiver: "09123456789",
-			}, {
-				Content:  "Test Content 2",
-				Receiver: "09123456788",
-			},
+		msg := smsmodels.Sms{
+			Content:  "Test Content 1",
+			Receiver: "09123456789",
 		}
 
 		mockUser.EXPECT().
@@ -469,35 +647,50 @@ func TestSmsGateway_SendBulkMessage(t *testing.T) {
 			Return(user, nil).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		mockPhone.EXPECT().
+			Normalize(msg.Receiver).
+			Return(phonemodels.PhoneNumber{Number: msg.Receiver}, nil).
+			Once()
 
-		actualErr := smsGateway.SendBulkMessage(ctx, userId, msgs)
+		mockPricing.EXPECT().
+			ResolvePrices(ctx, userId, []string{msg.Receiver}, mock.Anything).
+			Return([]pricingmodels.UnitPrice{
+				{Receiver: msg.Receiver, Source: pricingmodels.SourceNone},
+			}, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.SendSingleMessage(ctx, userId, msg)
 		assert.Error(t, actualErr)
 		assert.Equal(t, usermodels.InsufficientBalanceError, actualErr)
 	})
 
-	t.Run("should return InsufficientBalanceError when user balance is not enough because of race", func(t *testing.T) {
+	t.Run("should return InsufficientBalanceError when user balance is held", func(t *testing.T) {
 		ctx := context.Background()
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
 		userId := "1"
 
 		user := usermodels.User{
-			Entity:  &shared.Entity{ID: "1"},
-			Name:    "AshkanAbd",
-			Balance: 1000,
+			Entity:      &shared.Entity{ID: "1"},
+			Name:        "AshkanAbd",
+			Balance:     1000,
+			HeldBalance: 950,
 		}
 
-		msgs := []smsmodels.Sms{
-			{
-				Content:  "Test Content 1",
-				Receiver: "09123456789",
-			}, {
-				Content:  "Test Content 2",
-				Receiver: "09123456788",
-			},
+		msg := smsmodels.Sms{
+			Content:  "Test Content 1",
+			Receiver: "09123456789",
 		}
 
 		mockUser.EXPECT().
@@ -505,23 +698,37 @@ func TestSmsGateway_SendBulkMessage(t *testing.T) {
 			Return(user, nil).
 			Once()
 
-		mockUser.EXPECT().
-			DecreaseUserBalance(ctx, userId, int64(cfg.MessageCost*len(msgs))).
-			Return(0, usermodels.InsufficientBalanceError).
+		mockPhone.EXPECT().
+			Normalize(msg.Receiver).
+			Return(phonemodels.PhoneNumber{Number: msg.Receiver}, nil).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		mockPricing.EXPECT().
+			ResolvePrices(ctx, userId, []string{msg.Receiver}, mock.Anything).
+			Return([]pricingmodels.UnitPrice{
+				{Receiver: msg.Receiver, Source: pricingmodels.SourceNone},
+			}, nil).
+			Once()
 
-		actualErr := smsGateway.SendBulkMessage(ctx, userId, msgs)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.SendSingleMessage(ctx, userId, msg)
 		assert.Error(t, actualErr)
 		assert.Equal(t, usermodels.InsufficientBalanceError, actualErr)
 	})
 
-	t.Run("should return error when can not schedule message", func(t *testing.T) {
+	t.Run("should return InsufficientBalanceError when user balance is not enough because of race", func(t *testing.T) {
 		ctx := context.Background()
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
 		userId := "1"
 
@@ -531,334 +738,2995 @@ func TestSmsGateway_SendBulkMessage(t *testing.T) {
 			Balance: 1000,
 		}
 
-		msgs := []smsmodels.Sms{
-			{
-				Content:  "Test Content 1",
-				Receiver: "09123456789",
-			}, {
-				Content:  "Test Content 2",
-				Receiver: "09123456788",
-			},
+		msg := smsmodels.Sms{
+			Content:  "Test Content 1",
+			Receiver: "09123456789",
 		}
 
-		expectedErr := fmt.Errorf("some error")
-
 		mockUser.EXPECT().
 			GetUser(ctx, userId).
 			Return(user, nil).
 			Once()
 
-		mockUser.EXPECT().
-			DecreaseUserBalance(ctx, userId, int64(cfg.MessageCost*len(msgs))).
-			Return(0, nil).
+		mockPhone.EXPECT().
+			Normalize(msg.Receiver).
+			Return(phonemodels.PhoneNumber{Number: msg.Receiver}, nil).
+			Once()
+
+		mockPricing.EXPECT().
+			ResolvePrices(ctx, userId, []string{msg.Receiver}, mock.Anything).
+			Return([]pricingmodels.UnitPrice{
+				{Receiver: msg.Receiver, Source: pricingmodels.SourceNone},
+			}, nil).
+			Once()
+
+		reservation := ratelimitmodels.Reservation{UserId: userId, Count: 1}
+		mockRateLimit.EXPECT().
+			Reserve(ctx, userId, 1).
+			Return(reservation, nil).
+			Once()
+
+		mockUow.EXPECT().
+			Do(ctx, mock.Anything).
+			RunAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).
 			Once()
 
 		mockSms.EXPECT().
 			ScheduleSms(ctx, userId, []smsmodels.Sms{
 				{
-					Content:  msgs[0].Content,
-					Receiver: msgs[0].Receiver,
-					Cost:     cfg.MessageCost,
-				}, {
-					Content:  msgs[1].Content,
-					Receiver: msgs[1].Receiver,
+					Content:  msg.Content,
+					Receiver: msg.Receiver,
+					Segments: 1,
 					Cost:     cfg.MessageCost,
 				},
-			}).Return(expectedErr).
+			}).Return([]smsmodels.Sms{
+			{Entity: &shared.Entity{ID: "10"}, Cost: cfg.MessageCost},
+		}, nil).
 			Once()
 
 		mockUser.EXPECT().
-			IncreaseUserBalance(ctx, userId, int64(cfg.MessageCost*len(msgs))).
-			Return(0, nil).
+			HoldUserBalance(ctx, userId, []usermodels.BalanceHold{
+				{MessageId: "10", Amount: int64(cfg.MessageCost)},
+			}).
+			Return(usermodels.InsufficientBalanceError).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		mockRateLimit.EXPECT().
+			Release(ctx, reservation).
+			Return(nil).
+			Once()
 
-		actualErr := smsGateway.SendBulkMessage(ctx, userId, msgs)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.SendSingleMessage(ctx, userId, msg)
 		assert.Error(t, actualErr)
-		assert.Equal(t, expectedErr, actualErr)
+		assert.Equal(t, usermodels.InsufficientBalanceError, actualErr)
 	})
-}
-
-func TestSmsGateway_EnqueueWorker(t *testing.T) {
-	cfg := smsgateway.Config{
-		EnqueueCount: 10,
-		MessageCost:  100,
-	}
 
-	t.Run("should enqueue scheduled messages", func(t *testing.T) {
+	t.Run("should schedule message on the credit of a postpaid user", func(t *testing.T) {
 		ctx := context.Background()
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
-		expectedEnqueue := 10
+		userId := "1"
 
-		mockSms.EXPECT().
-			EnqueueEarliest(ctx, cfg.EnqueueCount).
-			Return(10, nil).
+		user := usermodels.User{
+			Entity:      &shared.Entity{ID: "1"},
+			Name:        "AshkanAbd",
+			Balance:     -500,
+			AccountType: usermodels.AccountPostpaid,
+			CreditLimit: 1000,
+		}
+
+		msg := smsmodels.Sms{
+			Content:  "Test Content 1",
+			Receiver: "09123456789",
+		}
+
+		mockUser.EXPECT().
+			GetUser(ctx, userId).
+			Return(user, nil).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		mockPhone.EXPECT().
+			Normalize(msg.Receiver).
+			Return(phonemodels.PhoneNumber{Number: msg.Receiver}, nil).
+			Once()
 
-		actualEnqueue, actualErr := smsGateway.EnqueueWorker(ctx)
-		assert.NoError(t, actualErr)
-		assert.Equal(t, expectedEnqueue, actualEnqueue)
-	})
+		mockPricing.EXPECT().
+			ResolvePrices(ctx, userId, []string{msg.Receiver}, mock.Anything).
+			Return([]pricingmodels.UnitPrice{
+				{Receiver: msg.Receiver, Source: pricingmodels.SourceNone},
+			}, nil).
+			Once()
 
-	t.Run("should return InvalidQueueError when queue is not valid", func(t *testing.T) {
-		ctx := context.Background()
+		reservation := ratelimitmodels.Reservation{UserId: userId, Count: 1}
+		mockRateLimit.EXPECT().
+			Reserve(ctx, userId, 1).
+			Return(reservation, nil).
+			Once()
 
-		mockUser := usermocks.NewMockIUserService(t)
-		mockSms := smsmocks.NewMockISmsService(t)
+		mockUow.EXPECT().
+			Do(ctx, mock.Anything).
+			RunAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).
+			Once()
 
 		mockSms.EXPECT().
-			EnqueueEarliest(ctx, cfg.EnqueueCount).
-			Return(0, smsmodels.InvalidQueueError).
+			ScheduleSms(ctx, userId, []smsmodels.Sms{
+				{
+					Content:  msg.Content,
+					Receiver: msg.Receiver,
+					Segments: 1,
+					Cost:     cfg.MessageCost,
+				},
+			}).Return([]smsmodels.Sms{
+			{Entity: &shared.Entity{ID: "10"}, Cost: cfg.MessageCost},
+		}, nil).
+			Once()
+
+		mockUser.EXPECT().
+			HoldUserBalance(ctx, userId, []usermodels.BalanceHold{
+				{MessageId: "10", Amount: int64(cfg.MessageCost)},
+			}).
+			Return(nil).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
-		actualEnqueue, actualErr := smsGateway.EnqueueWorker(ctx)
-		assert.Error(t, actualErr)
-		assert.Equal(t, smsmodels.InvalidQueueError, actualErr)
-		assert.Equal(t, 0, actualEnqueue)
+		actualErr := smsGateway.SendSingleMessage(ctx, userId, msg)
+		assert.NoError(t, actualErr)
 	})
 
-	t.Run("should return NoCapacityInQueueError when queue is full", func(t *testing.T) {
+	t.Run("should return InsufficientBalanceError when postpaid credit is used up", func(t *testing.T) {
 		ctx := context.Background()
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
-		mockSms.EXPECT().
-			EnqueueEarliest(ctx, cfg.EnqueueCount).
-			Return(0, smsmodels.NoCapacityInQueueError).
+		userId := "1"
+
+		user := usermodels.User{
+			Entity:      &shared.Entity{ID: "1"},
+			Name:        "AshkanAbd",
+			Balance:     -950,
+			AccountType: usermodels.AccountPostpaid,
+			CreditLimit: 1000,
+		}
+
+		msg := smsmodels.Sms{
+			Content:  "Test Content 1",
+			Receiver: "09123456789",
+		}
+
+		mockUser.EXPECT().
+			GetUser(ctx, userId).
+			Return(user, nil).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		mockPhone.EXPECT().
+			Normalize(msg.Receiver).
+			Return(phonemodels.PhoneNumber{Number: msg.Receiver}, nil).
+			Once()
 
-		actualEnqueue, actualErr := smsGateway.EnqueueWorker(ctx)
+		mockPricing.EXPECT().
+			ResolvePrices(ctx, userId, []string{msg.Receiver}, mock.Anything).
+			Return([]pricingmodels.UnitPrice{
+				{Receiver: msg.Receiver, Source: pricingmodels.SourceNone},
+			}, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.SendSingleMessage(ctx, userId, msg)
 		assert.Error(t, actualErr)
-		assert.Equal(t, smsmodels.NoCapacityInQueueError, actualErr)
-		assert.Equal(t, 0, actualEnqueue)
+		assert.Equal(t, usermodels.InsufficientBalanceError, actualErr)
 	})
 
-	t.Run("should return nil when other errors occurred", func(t *testing.T) {
+	t.Run("should return error when can not resolve prices", func(t *testing.T) {
 		ctx := context.Background()
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
-		mockSms.EXPECT().
-			EnqueueEarliest(ctx, cfg.EnqueueCount).
-			Return(0, fmt.Errorf("some error")).
-			Once()
+		userId := "1"
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		user := usermodels.User{
+			Entity:  &shared.Entity{ID: "1"},
+			Name:    "AshkanAbd",
+			Balance: 1000,
+		}
 
-		actualEnqueue, actualErr := smsGateway.EnqueueWorker(ctx)
-		assert.NoError(t, actualErr)
-		assert.Equal(t, 0, actualEnqueue)
-	})
+		msg := smsmodels.Sms{
+			Content:  "Test Content 1",
+			Receiver: "09123456789",
+		}
+
+		expectedErr := fmt.Errorf("some error")
+
+		mockUser.EXPECT().
+			GetUser(ctx, userId).
+			Return(user, nil).
+			Once()
+
+		mockPhone.EXPECT().
+			Normalize(msg.Receiver).
+			Return(phonemodels.PhoneNumber{Number: msg.Receiver}, nil).
+			Once()
+
+		mockPricing.EXPECT().
+			ResolvePrices(ctx, userId, []string{msg.Receiver}, mock.Anything).
+			Return(nil, expectedErr).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.SendSingleMessage(ctx, userId, msg)
+		assert.Error(t, actualErr)
+		assert.Equal(t, expectedErr, actualErr)
+	})
+
+	t.Run("should return error when can not schedule message", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		userId := "1"
+
+		user := usermodels.User{
+			Entity:  &shared.Entity{ID: "1"},
+			Name:    "AshkanAbd",
+			Balance: 1000,
+		}
+
+		msg := smsmodels.Sms{
+			Content:  "Test Content 1",
+			Receiver: "09123456789",
+		}
+
+		expectedErr := fmt.Errorf("some error")
+
+		mockUser.EXPECT().
+			GetUser(ctx, userId).
+			Return(user, nil).
+			Once()
+
+		mockPhone.EXPECT().
+			Normalize(msg.Receiver).
+			Return(phonemodels.PhoneNumber{Number: msg.Receiver}, nil).
+			Once()
+
+		mockPricing.EXPECT().
+			ResolvePrices(ctx, userId, []string{msg.Receiver}, mock.Anything).
+			Return([]pricingmodels.UnitPrice{
+				{Receiver: msg.Receiver, Source: pricingmodels.SourceNone},
+			}, nil).
+			Once()
+
+		reservation := ratelimitmodels.Reservation{UserId: userId, Count: 1}
+		mockRateLimit.EXPECT().
+			Reserve(ctx, userId, 1).
+			Return(reservation, nil).
+			Once()
+
+		mockUow.EXPECT().
+			Do(ctx, mock.Anything).
+			RunAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).
+			Once()
+
+		mockSms.EXPECT().
+			ScheduleSms(ctx, userId, []smsmodels.Sms{
+				{
+					Content:  msg.Content,
+					Receiver: msg.Receiver,
+					Segments: 1,
+					Cost:     cfg.MessageCost,
+				},
+			}).Return(nil, expectedErr).
+			Once()
+
+		mockRateLimit.EXPECT().
+			Release(ctx, reservation).
+			Return(nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.SendSingleMessage(ctx, userId, msg)
+		assert.Error(t, actualErr)
+		assert.Equal(t, expectedErr, actualErr)
+	})
+}
+
+func TestSmsGateway_SendBulkMessage(t *testing.T) {
+	cfg := smsgateway.Config{
+		EnqueueCount: 0,
+		MessageCost:  100,
+	}
+
+	t.Run("should schedule message", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		userId := "1"
+
+		user := usermodels.User{
+			Entity:  &shared.Entity{ID: "1"},
+			Name:    "AshkanAbd",
+			Balance: 1000,
+		}
+
+		msgs := []smsmodels.Sms{
+			{
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+			}, {
+				Content:  "Test Content 2",
+				Receiver: "09123456788",
+			},
+		}
+
+		mockUser.EXPECT().
+			GetUser(ctx, userId).
+			Return(user, nil).
+			Once()
+
+		mockPhone.EXPECT().
+			Normalize(msgs[0].Receiver).
+			Return(phonemodels.PhoneNumber{Number: msgs[0].Receiver}, nil).
+			Once()
+
+		mockPhone.EXPECT().
+			Normalize(msgs[1].Receiver).
+			Return(phonemodels.PhoneNumber{Number: msgs[1].Receiver}, nil).
+			Once()
+
+		mockPricing.EXPECT().
+			ResolvePrices(ctx, userId, []string{msgs[0].Receiver, msgs[1].Receiver}, mock.Anything).
+			Return([]pricingmodels.UnitPrice{
+				{Receiver: msgs[0].Receiver, Source: pricingmodels.SourceNone},
+				{Receiver: msgs[1].Receiver, Source: pricingmodels.SourceNone},
+			}, nil).
+			Once()
+
+		reservation := ratelimitmodels.Reservation{UserId: userId, Count: len(msgs)}
+		mockRateLimit.EXPECT().
+			Reserve(ctx, userId, len(msgs)).
+			Return(reservation, nil).
+			Once()
+
+		mockUow.EXPECT().
+			Do(ctx, mock.Anything).
+			RunAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).
+			Once()
+
+		mockUser.EXPECT().
+			HoldUserBalance(ctx, userId, []usermodels.BalanceHold{
+				{MessageId: "10", Amount: int64(cfg.MessageCost)},
+				{MessageId: "11", Amount: int64(cfg.MessageCost)},
+			}).
+			Return(nil).
+			Once()
+
+		mockSms.EXPECT().
+			ScheduleSms(ctx, userId, []smsmodels.Sms{
+				{
+					Content:  msgs[0].Content,
+					Receiver: msgs[0].Receiver,
+					Segments: 1,
+					Cost:     cfg.MessageCost,
+				}, {
+					Content:  msgs[1].Content,
+					Receiver: msgs[1].Receiver,
+					Segments: 1,
+					Cost:     cfg.MessageCost,
+				},
+			}).Return([]smsmodels.Sms{
+			{Entity: &shared.Entity{ID: "10"}, Cost: cfg.MessageCost},
+			{Entity: &shared.Entity{ID: "11"}, Cost: cfg.MessageCost},
+		}, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.SendBulkMessage(ctx, userId, msgs)
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("should return limit error without scheduling when user is over its limits", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		userId := "1"
+
+		user := usermodels.User{
+			Entity:  &shared.Entity{ID: "1"},
+			Name:    "AshkanAbd",
+			Balance: 1000,
+		}
+
+		msgs := []smsmodels.Sms{
+			{
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+			}, {
+				Content:  "Test Content 2",
+				Receiver: "09123456788",
+			},
+		}
+
+		mockUser.EXPECT().
+			GetUser(ctx, userId).
+			Return(user, nil).
+			Once()
+
+		mockPhone.EXPECT().
+			Normalize(msgs[0].Receiver).
+			Return(phonemodels.PhoneNumber{Number: msgs[0].Receiver}, nil).
+			Once()
+
+		mockPhone.EXPECT().
+			Normalize(msgs[1].Receiver).
+			Return(phonemodels.PhoneNumber{Number: msgs[1].Receiver}, nil).
+			Once()
+
+		mockPricing.EXPECT().
+			ResolvePrices(ctx, userId, []string{msgs[0].Receiver, msgs[1].Receiver}, mock.Anything).
+			Return([]pricingmodels.UnitPrice{
+				{Receiver: msgs[0].Receiver, Source: pricingmodels.SourceNone},
+				{Receiver: msgs[1].Receiver, Source: pricingmodels.SourceNone},
+			}, nil).
+			Once()
+
+		expectedErr := &ratelimitmodels.LimitError{
+			Err:        ratelimitmodels.RateLimitExceededError,
+			RetryAfter: time.Second,
+		}
+		mockRateLimit.EXPECT().
+			Reserve(ctx, userId, len(msgs)).
+			Return(ratelimitmodels.Reservation{}, expectedErr).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.SendBulkMessage(ctx, userId, msgs)
+		assert.ErrorIs(t, actualErr, ratelimitmodels.RateLimitExceededError)
+	})
+
+	t.Run("should return InsufficientBalanceError when user balance is not enough", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		userId := "1"
+
+		user := usermodels.User{
+			Entity:  &shared.Entity{ID: "1"},
+			Name:    "AshkanAbd",
+			Balance: 10,
+		}
+
+		msgs := []smsmodels.Sms{
+			{
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+			}, {
+				Content:  "Test Content 2",
+				Receiver: "09123456788",
+			},
+		}
+
+		mockUser.EXPECT().
+			GetUser(ctx, userId).
+			Return(user, nil).
+			Once()
+
+		mockPhone.EXPECT().
+			Normalize(msgs[0].Receiver).
+			Return(phonemodels.PhoneNumber{Number: msgs[0].Receiver}, nil).
+			Once()
+
+		mockPhone.EXPECT().
+			Normalize(msgs[1].Receiver).
+			Return(phonemodels.PhoneNumber{Number: msgs[1].Receiver}, nil).
+			Once()
+
+		mockPricing.EXPECT().
+			ResolvePrices(ctx, userId, []string{msgs[0].Receiver, msgs[1].Receiver}, mock.Anything).
+			Return([]pricingmodels.UnitPrice{
+				{Receiver: msgs[0].Receiver, Source: pricingmodels.SourceNone},
+				{Receiver: msgs[1].Receiver, Source: pricingmodels.SourceNone},
+			}, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.SendBulkMessage(ctx, userId, msgs)
+		assert.Error(t, actualErr)
+		assert.Equal(t, usermodels.InsufficientBalanceError, actualErr)
+	})
+
+	t.Run("should return InsufficientBalanceError when user balance is not enough because of race", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		userId := "1"
+
+		user := usermodels.User{
+			Entity:  &shared.Entity{ID: "1"},
+			Name:    "AshkanAbd",
+			Balance: 1000,
+		}
+
+		msgs := []smsmodels.Sms{
+			{
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+			}, {
+				Content:  "Test Content 2",
+				Receiver: "09123456788",
+			},
+		}
+
+		mockUser.EXPECT().
+			GetUser(ctx, userId).
+			Return(user, nil).
+			Once()
+
+		mockPhone.EXPECT().
+			Normalize(msgs[0].Receiver).
+			Return(phonemodels.PhoneNumber{Number: msgs[0].Receiver}, nil).
+			Once()
+
+		mockPhone.EXPECT().
+			Normalize(msgs[1].Receiver).
+			Return(phonemodels.PhoneNumber{Number: msgs[1].Receiver}, nil).
+			Once()
+
+		mockPricing.EXPECT().
+			ResolvePrices(ctx, userId, []string{msgs[0].Receiver, msgs[1].Receiver}, mock.Anything).
+			Return([]pricingmodels.UnitPrice{
+				{Receiver: msgs[0].Receiver, Source: pricingmodels.SourceNone},
+				{Receiver: msgs[1].Receiver, Source: pricingmodels.SourceNone},
+			}, nil).
+			Once()
+
+		reservation := ratelimitmodels.Reservation{UserId: userId, Count: len(msgs)}
+		mockRateLimit.EXPECT().
+			Reserve(ctx, userId, len(msgs)).
+			Return(reservation, nil).
+			Once()
+
+		mockUow.EXPECT().
+			Do(ctx, mock.Anything).
+			RunAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).
+			Once()
+
+		mockSms.EXPECT().
+			ScheduleSms(ctx, userId, []smsmodels.Sms{
+				{
+					Content:  msgs[0].Content,
+					Receiver: msgs[0].Receiver,
+					Segments: 1,
+					Cost:     cfg.MessageCost,
+				}, {
+					Content:  msgs[1].Content,
+					Receiver: msgs[1].Receiver,
+					Segments: 1,
+					Cost:     cfg.MessageCost,
+				},
+			}).Return([]smsmodels.Sms{
+			{Entity: &shared.Entity{ID: "10"}, Cost: cfg.MessageCost},
+			{Entity: &shared.Entity{ID: "11"}, Cost: cfg.MessageCost},
+		}, nil).
+			Once()
+
+		mockUser.EXPECT().
+			HoldUserBalance(ctx, userId, []usermodels.BalanceHold{
+				{MessageId: "10", Amount: int64(cfg.MessageCost)},
+				{MessageId: "11", Amount: int64(cfg.MessageCost)},
+			}).
+			Return(usermodels.InsufficientBalanceError).
+			Once()
+
+		mockRateLimit.EXPECT().
+			Release(ctx, reservation).
+			Return(nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.SendBulkMessage(ctx, userId, msgs)
+		assert.Error(t, actualErr)
+		assert.Equal(t, usermodels.InsufficientBalanceError, actualErr)
+	})
+
+	t.Run("should return error when can not schedule message", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		userId := "1"
+
+		user := usermodels.User{
+			Entity:  &shared.Entity{ID: "1"},
+			Name:    "AshkanAbd",
+			Balance: 1000,
+		}
+
+		msgs := []smsmodels.Sms{
+			{
+				Content:  "Test Content 1",
+				Receiver: "09123456789",
+			}, {
+				Content:  "Test Content 2",
+				Receiver: "09123456788",
+			},
+		}
+
+		expectedErr := fmt.Errorf("some error")
+
+		mockUser.EXPECT().
+			GetUser(ctx, userId).
+			Return(user, nil).
+			Once()
+
+		mockPhone.EXPECT().
+			Normalize(msgs[0].Receiver).
+			Return(phonemodels.PhoneNumber{Number: msgs[0].Receiver}, nil).
+			Once()
+
+		mockPhone.EXPECT().
+			Normalize(msgs[1].Receiver).
+			Return(phonemodels.PhoneNumber{Number: msgs[1].Receiver}, nil).
+			Once()
+
+		mockPricing.EXPECT().
+			ResolvePrices(ctx, userId, []string{msgs[0].Receiver, msgs[1].Receiver}, mock.Anything).
+			Return([]pricingmodels.UnitPrice{
+				{Receiver: msgs[0].Receiver, Source: pricingmodels.SourceNone},
+				{Receiver: msgs[1].Receiver, Source: pricingmodels.SourceNone},
+			}, nil).
+			Once()
+
+		reservation := ratelimitmodels.Reservation{UserId: userId, Count: len(msgs)}
+		mockRateLimit.EXPECT().
+			Reserve(ctx, userId, len(msgs)).
+			Return(reservation, nil).
+			Once()
+
+		mockUow.EXPECT().
+			Do(ctx, mock.Anything).
+			RunAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).
+			Once()
+
+		mockSms.EXPECT().
+			ScheduleSms(ctx, userId, []smsmodels.Sms{
+				{
+					Content:  msgs[0].Content,
+					Receiver: msgs[0].Receiver,
+					Segments: 1,
+					Cost:     cfg.MessageCost,
+				}, {
+					Content:  msgs[1].Content,
+					Receiver: msgs[1].Receiver,
+					Segments: 1,
+					Cost:     cfg.MessageCost,
+				},
+			}).Return(nil, expectedErr).
+			Once()
+
+		mockRateLimit.EXPECT().
+			Release(ctx, reservation).
+			Return(nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.SendBulkMessage(ctx, userId, msgs)
+		assert.Error(t, actualErr)
+		assert.Equal(t, expectedErr, actualErr)
+	})
+}
+
+func TestSmsGateway_CancelMessage(t *testing.T) {
+	cfg := smsgateway.Config{
+		EnqueueCount: 0,
+		MessageCost:  100,
+	}
+
+	t.Run("should cancel message and release its hold", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		canceled := smsmodels.Sms{
+			Entity:   &shared.Entity{ID: "2"},
+			UserId:   "1",
+			Content:  "Test Content 1",
+			Receiver: "09123456789",
+			Cost:     200,
+			Status:   smsmodels.StatusCanceled,
+		}
+
+		mockUow.EXPECT().
+			Do(ctx, mock.Anything).
+			RunAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).
+			Once()
+
+		mockSms.EXPECT().
+			CancelSms(ctx, canceled.UserId, canceled.ID).
+			Return(canceled, nil).
+			Once()
+
+		mockUser.EXPECT().
+			ReleaseHold(ctx, canceled.ID).
+			Return(usermodels.BalanceHold{MessageId: canceled.ID, Amount: int64(canceled.Cost)}, nil).
+			Once()
+
+		mockWebhook.EXPECT().
+			Publish(ctx, canceled.UserId, webhookmodels.EventMessageStatus, mock.MatchedBy(func(data any) bool {
+				return strings.Contains(common.ValueToJSON(data), `"status":"Canceled"`)
+			})).
+			Return(1, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualMsg, actualErr := smsGateway.CancelMessage(ctx, canceled.UserId, canceled.ID)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, canceled, actualMsg)
+	})
+
+	t.Run("should not release hold when message can not be canceled", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockUow.EXPECT().
+			Do(ctx, mock.Anything).
+			RunAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).
+			Once()
+
+		mockSms.EXPECT().
+			CancelSms(ctx, "1", "2").
+			Return(smsmodels.Sms{}, smsmodels.MessageNotExistError).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualMsg, actualErr := smsGateway.CancelMessage(ctx, "1", "2")
+		assert.Error(t, actualErr)
+		assert.Equal(t, smsmodels.MessageNotExistError, actualErr)
+		assert.Equal(t, smsmodels.Sms{}, actualMsg)
+	})
+
+	t.Run("should roll back cancel when can not release its hold", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		canceled := smsmodels.Sms{
+			Entity: &shared.Entity{ID: "2"},
+			UserId: "1",
+			Cost:   200,
+			Status: smsmodels.StatusCanceled,
+		}
+
+		expectedErr := fmt.Errorf("some error")
+
+		mockUow.EXPECT().
+			Do(ctx, mock.Anything).
+			RunAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).
+			Once()
+
+		mockSms.EXPECT().
+			CancelSms(ctx, canceled.UserId, canceled.ID).
+			Return(canceled, nil).
+			Once()
+
+		mockUser.EXPECT().
+			ReleaseHold(ctx, canceled.ID).
+			Return(usermodels.BalanceHold{}, expectedErr).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualMsg, actualErr := smsGateway.CancelMessage(ctx, canceled.UserId, canceled.ID)
+		assert.Error(t, actualErr)
+		assert.Equal(t, expectedErr, actualErr)
+		assert.Equal(t, smsmodels.Sms{}, actualMsg)
+	})
+}
+
+func TestSmsGateway_ProcessDeliveryReport(t *testing.T) {
+	cfg := smsgateway.Config{}
+
+	t.Run("should process delivery report", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		report := smsmodels.DeliveryReport{
+			Provider:  "primary",
+			MessageId: "p-1",
+			Status:    smsmodels.StatusDelivered,
+		}
+		expectedMsg := smsmodels.Sms{
+			Entity:            &shared.Entity{ID: "1"},
+			UserId:            "2",
+			Status:            smsmodels.StatusDelivered,
+			Provider:          "primary",
+			ProviderMessageId: "p-1",
+		}
+
+		mockSms.EXPECT().
+			ProcessDeliveryReport(mock.Anything, report).
+			Return(expectedMsg, nil).
+			Once()
+
+		mockWebhook.EXPECT().
+			Publish(ctx, expectedMsg.UserId, webhookmodels.EventMessageStatus, mock.MatchedBy(func(data any) bool {
+				return strings.Contains(common.ValueToJSON(data), `"status":"Delivered"`)
+			})).
+			Return(1, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualMsg, actualErr := smsGateway.ProcessDeliveryReport(ctx, report)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedMsg, actualMsg)
+	})
+
+	t.Run("should return error when message does not exist", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		report := smsmodels.DeliveryReport{
+			Provider:  "primary",
+			MessageId: "p-1",
+			Status:    smsmodels.StatusDelivered,
+		}
+
+		mockSms.EXPECT().
+			ProcessDeliveryReport(mock.Anything, report).
+			Return(smsmodels.Sms{}, smsmodels.MessageNotExistError).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		_, actualErr := smsGateway.ProcessDeliveryReport(ctx, report)
+		assert.ErrorIs(t, actualErr, smsmodels.MessageNotExistError)
+	})
+}
+
+func TestSmsGateway_EnqueueWorker(t *testing.T) {
+	cfg := smsgateway.Config{
+		EnqueueCount: 10,
+		MessageCost:  100,
+	}
+
+	t.Run("should enqueue scheduled messages", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		expectedEnqueue := 10
+
+		mockSms.EXPECT().
+			EnqueueEarliest(ctx, cfg.EnqueueCount).
+			Return(10, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualEnqueue, actualErr := smsGateway.EnqueueWorker(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedEnqueue, actualEnqueue)
+	})
+
+	t.Run("should return InvalidQueueError when queue is not valid", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockSms.EXPECT().
+			EnqueueEarliest(ctx, cfg.EnqueueCount).
+			Return(0, smsmodels.InvalidQueueError).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualEnqueue, actualErr := smsGateway.EnqueueWorker(ctx)
+		assert.Error(t, actualErr)
+		assert.Equal(t, smsmodels.InvalidQueueError, actualErr)
+		assert.Equal(t, 0, actualEnqueue)
+	})
+
+	t.Run("should return NoCapacityInQueueError when queue is full", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockSms.EXPECT().
+			EnqueueEarliest(ctx, cfg.EnqueueCount).
+			Return(0, smsmodels.NoCapacityInQueueError).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualEnqueue, actualErr := smsGateway.EnqueueWorker(ctx)
+		assert.Error(t, actualErr)
+		assert.Equal(t, smsmodels.NoCapacityInQueueError, actualErr)
+		assert.Equal(t, 0, actualEnqueue)
+	})
+
+	t.Run("should return nil when other errors occurred", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockSms.EXPECT().
+			EnqueueEarliest(ctx, cfg.EnqueueCount).
+			Return(0, fmt.Errorf("some error")).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualEnqueue, actualErr := smsGateway.EnqueueWorker(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 0, actualEnqueue)
+	})
+}
+
+func TestSmsGateway_SendWorker(t *testing.T) {
+	cfg := smsgateway.Config{
+		EnqueueCount: 10,
+		MessageCost:  100,
+	}
+
+	t.Run("should send a queue message and capture its hold", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		msg := smsmodels.Sms{
+			Entity:   &shared.Entity{ID: "2"},
+			Content:  "Test Content 1",
+			Receiver: "09123456789",
+			UserId:   "1",
+			Cost:     200,
+			Status:   smsmodels.StatusSent,
+		}
+
+		mockSms.EXPECT().
+			SendFromQueue(ctx).
+			Return(msg, nil).
+			Once()
+
+		mockUser.EXPECT().
+			CaptureHold(ctx, msg.ID).
+			Return(usermodels.BalanceHold{MessageId: msg.ID, Amount: int64(msg.Cost)}, nil).
+			Once()
+
+		mockWebhook.EXPECT().
+			Publish(ctx, msg.UserId, webhookmodels.EventMessageStatus, mock.MatchedBy(func(data any) bool {
+				return strings.Contains(common.ValueToJSON(data), `"status":"Sent"`)
+			})).
+			Return(1, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.SendWorker(ctx)
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("should release balance hold when can not send", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		msg := smsmodels.Sms{
+			Entity:   &shared.Entity{ID: "2"},
+			Content:  "Test Content 1",
+			Receiver: "09123456789",
+			UserId:   "1",
+			Cost:     200,
+			Status:   smsmodels.StatusFailed,
+		}
+
+		mockSms.EXPECT().
+			SendFromQueue(ctx).
+			Return(msg, nil).
+			Once()
+
+		mockUser.EXPECT().
+			ReleaseHold(ctx, msg.ID).
+			Return(usermodels.BalanceHold{MessageId: msg.ID, Amount: int64(msg.Cost)}, nil).
+			Once()
+
+		mockWebhook.EXPECT().
+			Publish(ctx, msg.UserId, webhookmodels.EventMessageStatus, mock.MatchedBy(func(data any) bool {
+				return strings.Contains(common.ValueToJSON(data), `"status":"Failed"`)
+			})).
+			Return(1, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.SendWorker(ctx)
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("should not settle balance hold when message will be retried", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		msg := smsmodels.Sms{
+			Entity:   &shared.Entity{ID: "2"},
+			Content:  "Test Content 1",
+			Receiver: "09123456789",
+			UserId:   "1",
+			Cost:     200,
+			Status:   smsmodels.StatusRetrying,
+			Attempts: 1,
+		}
+
+		mockSms.EXPECT().
+			SendFromQueue(ctx).
+			Return(msg, nil).
+			Once()
+
+		mockWebhook.EXPECT().
+			Publish(ctx, msg.UserId, webhookmodels.EventMessageStatus, mock.MatchedBy(func(data any) bool {
+				return strings.Contains(common.ValueToJSON(data), `"status":"Retrying"`)
+			})).
+			Return(1, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.SendWorker(ctx)
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("should return nil when can not release balance hold", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		msg := smsmodels.Sms{
+			Entity:   &shared.Entity{ID: "2"},
+			Content:  "Test Content 1",
+			Receiver: "09123456789",
+			UserId:   "1",
+			Cost:     200,
+			Status:   smsmodels.StatusFailed,
+		}
+
+		mockSms.EXPECT().
+			SendFromQueue(ctx).
+			Return(msg, nil).
+			Once()
+
+		mockUser.EXPECT().
+			ReleaseHold(ctx, msg.ID).
+			Return(usermodels.BalanceHold{}, fmt.Errorf("some error")).
+			Once()
+
+		mockWebhook.EXPECT().
+			Publish(ctx, msg.UserId, webhookmodels.EventMessageStatus, mock.MatchedBy(func(data any) bool {
+				return strings.Contains(common.ValueToJSON(data), `"status":"Failed"`)
+			})).
+			Return(0, fmt.Errorf("some error")).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.SendWorker(ctx)
+		assert.NoError(t, actualErr)
+	})
+
+	t.Run("should return InvalidQueueError when queue is not valid", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockSms.EXPECT().
+			SendFromQueue(ctx).
+			Return(smsmodels.Sms{}, smsmodels.InvalidQueueError).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.SendWorker(ctx)
+		assert.Error(t, actualErr)
+		assert.Equal(t, smsmodels.InvalidQueueError, actualErr)
+	})
+
+	t.Run("should return nil when message not exists", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockSms.EXPECT().
+			SendFromQueue(ctx).
+			Return(smsmodels.Sms{}, smsmodels.MessageNotExistError).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.SendWorker(ctx)
+		assert.NoError(t, actualErr)
+	})
+}
+
+func TestSmsGateway_RecoveryWorker(t *testing.T) {
+	cfg := smsgateway.Config{
+		EnqueueCount: 10,
+		MessageCost:  100,
+	}
+
+	t.Run("should recover unacknowledged messages", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockSms.EXPECT().
+			RecoverUnacked(ctx).
+			Return(2, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualRecovered, actualErr := smsGateway.RecoveryWorker(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 2, actualRecovered)
+	})
+
+	t.Run("should return InvalidQueueError when queue is not valid", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockSms.EXPECT().
+			RecoverUnacked(ctx).
+			Return(0, smsmodels.InvalidQueueError).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualRecovered, actualErr := smsGateway.RecoveryWorker(ctx)
+		assert.Error(t, actualErr)
+		assert.Equal(t, smsmodels.InvalidQueueError, actualErr)
+		assert.Equal(t, 0, actualRecovered)
+	})
+
+	t.Run("should return nil when other errors occurred", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockSms.EXPECT().
+			RecoverUnacked(ctx).
+			Return(0, fmt.Errorf("connection refused")).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualRecovered, actualErr := smsGateway.RecoveryWorker(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 0, actualRecovered)
+	})
+}
+
+func TestSmsGateway_ReconcileWorker(t *testing.T) {
+	cfg := smsgateway.Config{
+		EnqueueCount: 10,
+		MessageCost:  100,
+	}
+
+	t.Run("should release holds of failed stranded messages", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		failedMsg := smsmodels.Sms{
+			Entity:   &shared.Entity{ID: "3"},
+			UserId:   "1",
+			Content:  "Test Content 1",
+			Receiver: "09123456789",
+			Cost:     200,
+			Status:   smsmodels.StatusFailed,
+		}
+
+		mockSms.EXPECT().
+			ReconcileStuckMessages(ctx).
+			Return(smsmodels.ReconcileResult{
+				Rescheduled: 2,
+				Failed:      []smsmodels.Sms{failedMsg},
+			}, nil).
+			Once()
+
+		mockUser.EXPECT().
+			ReleaseHold(ctx, failedMsg.ID).
+			Return(usermodels.BalanceHold{MessageId: failedMsg.ID, Amount: int64(failedMsg.Cost)}, nil).
+			Once()
+
+		mockWebhook.EXPECT().
+			Publish(ctx, failedMsg.UserId, webhookmodels.EventMessageStatus, mock.MatchedBy(func(data any) bool {
+				return strings.Contains(common.ValueToJSON(data), `"status":"Failed"`)
+			})).
+			Return(1, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualReconciled, actualErr := smsGateway.ReconcileWorker(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 3, actualReconciled)
+	})
+
+	t.Run("should capture holds of accepted messages recorded as sent", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		sentMsg := smsmodels.Sms{
+			Entity:   &shared.Entity{ID: "3"},
+			UserId:   "1",
+			Content:  "Test Content 1",
+			Receiver: "09123456789",
+			Cost:     200,
+			Status:   smsmodels.StatusSent,
+		}
+
+		mockSms.EXPECT().
+			ReconcileStuckMessages(ctx).
+			Return(smsmodels.ReconcileResult{
+				Sent: []smsmodels.Sms{sentMsg},
+			}, nil).
+			Once()
+
+		mockUser.EXPECT().
+			CaptureHold(ctx, sentMsg.ID).
+			Return(usermodels.BalanceHold{MessageId: sentMsg.ID, Amount: int64(sentMsg.Cost)}, nil).
+			Once()
+
+		mockWebhook.EXPECT().
+			Publish(ctx, sentMsg.UserId, webhookmodels.EventMessageStatus, mock.MatchedBy(func(data any) bool {
+				return strings.Contains(common.ValueToJSON(data), `"status":"Sent"`)
+			})).
+			Return(1, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualReconciled, actualErr := smsGateway.ReconcileWorker(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 1, actualReconciled)
+	})
+
+	t.Run("should release holds of failed stranded messages when reconcile is partially done", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		failedMsg := smsmodels.Sms{
+			Entity:   &shared.Entity{ID: "3"},
+			UserId:   "1",
+			Content:  "Test Content 1",
+			Receiver: "09123456789",
+			Cost:     200,
+			Status:   smsmodels.StatusFailed,
+		}
+
+		mockSms.EXPECT().
+			ReconcileStuckMessages(ctx).
+			Return(smsmodels.ReconcileResult{
+				Failed: []smsmodels.Sms{failedMsg},
+			}, fmt.Errorf("db error")).
+			Once()
+
+		mockUser.EXPECT().
+			ReleaseHold(ctx, failedMsg.ID).
+			Return(usermodels.BalanceHold{MessageId: failedMsg.ID, Amount: int64(failedMsg.Cost)}, nil).
+			Once()
+
+		mockWebhook.EXPECT().
+			Publish(ctx, failedMsg.UserId, webhookmodels.EventMessageStatus, mock.MatchedBy(func(data any) bool {
+				return strings.Contains(common.ValueToJSON(data), `"status":"Failed"`)
+			})).
+			Return(1, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualReconciled, actualErr := smsGateway.ReconcileWorker(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 0, actualReconciled)
+	})
+
+	t.Run("should return InvalidQueueError when queue is not valid", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockSms.EXPECT().
+			ReconcileStuckMessages(ctx).
+			Return(smsmodels.ReconcileResult{}, smsmodels.InvalidQueueError).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualReconciled, actualErr := smsGateway.ReconcileWorker(ctx)
+		assert.Error(t, actualErr)
+		assert.Equal(t, smsmodels.InvalidQueueError, actualErr)
+		assert.Equal(t, 0, actualReconciled)
+	})
+}
+
+func TestSmsGateway_ReconcileHolds(t *testing.T) {
+	cfg := smsgateway.Config{
+		HoldSettleDelay:     5 * time.Minute,
+		HoldSettleBatchSize: 100,
+	}
+
+	t.Run("should capture holds of sent messages and release holds of failed ones", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockUser.EXPECT().
+			GetUnsettledHolds(ctx, mock.MatchedBy(func(finishedBefore time.Time) bool {
+				return time.Since(finishedBefore) >= cfg.HoldSettleDelay
+			}), cfg.HoldSettleBatchSize).
+			Return([]usermodels.UnsettledHold{
+				{MessageId: "1", Capture: true},
+				{MessageId: "2", Capture: false},
+			}, nil).
+			Once()
+
+		mockUser.EXPECT().
+			CaptureHold(ctx, "1").
+			Return(usermodels.BalanceHold{MessageId: "1", Amount: 100, Status: usermodels.HoldStatusCaptured}, nil).
+			Once()
+
+		mockUser.EXPECT().
+			ReleaseHold(ctx, "2").
+			Return(usermodels.BalanceHold{MessageId: "2", Amount: 100, Status: usermodels.HoldStatusReleased}, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualSettled, actualErr := smsGateway.ReconcileHolds(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 2, actualSettled)
+	})
+
+	t.Run("should skip holds settled meanwhile and keep settling the rest", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockUser.EXPECT().
+			GetUnsettledHolds(ctx, mock.Anything, cfg.HoldSettleBatchSize).
+			Return([]usermodels.UnsettledHold{
+				{MessageId: "1", Capture: true},
+				{MessageId: "2", Capture: true},
+				{MessageId: "3", Capture: false},
+			}, nil).
+			Once()
+
+		mockUser.EXPECT().
+			CaptureHold(ctx, "1").
+			Return(usermodels.BalanceHold{}, usermodels.HoldNotExistError).
+			Once()
+
+		mockUser.EXPECT().
+			CaptureHold(ctx, "2").
+			Return(usermodels.BalanceHold{}, fmt.Errorf("db error")).
+			Once()
+
+		mockUser.EXPECT().
+			ReleaseHold(ctx, "3").
+			Return(usermodels.BalanceHold{MessageId: "3", Amount: 100, Status: usermodels.HoldStatusReleased}, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualSettled, actualErr := smsGateway.ReconcileHolds(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 1, actualSettled)
+	})
+
+	t.Run("should return nothing when unsettled holds can not be fetched", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockUser.EXPECT().
+			GetUnsettledHolds(ctx, mock.Anything, cfg.HoldSettleBatchSize).
+			Return(nil, fmt.Errorf("db error")).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualSettled, actualErr := smsGateway.ReconcileHolds(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 0, actualSettled)
+	})
+}
+
+func TestSmsGateway_IncreaseUserBalance(t *testing.T) {
+	cfg := smsgateway.Config{
+		EnqueueCount: 10,
+		MessageCost:  100,
+	}
+
+	t.Run("should increase user balance", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		inputUserId := "1"
+		inputAmount := int64(100)
+		inputReference := "payment-1"
+
+		mockUser.EXPECT().
+			IncreaseUserBalance(ctx, inputUserId, []usermodels.BalanceChange{
+				{Type: usermodels.TransactionTopUp, Amount: inputAmount, Reference: inputReference},
+			}).
+			Return(inputAmount, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualBalance, actualErr := smsGateway.IncreaseUserBalance(ctx, inputUserId, inputAmount, inputReference)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, inputAmount, actualBalance)
+	})
+
+	t.Run("should return UserNotExistError when can not increase balance", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		inputUserId := "1"
+		inputAmount := int64(100)
+		inputReference := "payment-1"
+
+		mockUser.EXPECT().
+			IncreaseUserBalance(ctx, inputUserId, []usermodels.BalanceChange{
+				{Type: usermodels.TransactionTopUp, Amount: inputAmount, Reference: inputReference},
+			}).
+			Return(0, usermodels.UserNotExistError).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualBalance, actualErr := smsGateway.IncreaseUserBalance(ctx, inputUserId, inputAmount, inputReference)
+		assert.Error(t, actualErr)
+		assert.Equal(t, usermodels.UserNotExistError, actualErr)
+		assert.Equal(t, int64(0), actualBalance)
+	})
+
+	t.Run("should return error when can not increase balance", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		inputUserId := "1"
+		inputAmount := int64(100)
+		inputReference := "payment-1"
+
+		expectedErr := fmt.Errorf("some error")
+
+		mockUser.EXPECT().
+			IncreaseUserBalance(ctx, inputUserId, []usermodels.BalanceChange{
+				{Type: usermodels.TransactionTopUp, Amount: inputAmount, Reference: inputReference},
+			}).
+			Return(0, expectedErr).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualBalance, actualErr := smsGateway.IncreaseUserBalance(ctx, inputUserId, inputAmount, inputReference)
+		assert.Error(t, actualErr)
+		assert.Equal(t, expectedErr, actualErr)
+		assert.Equal(t, int64(0), actualBalance)
+	})
+}
+
+func TestSmsGateway_GetUserTransactions(t *testing.T) {
+	cfg := smsgateway.Config{
+		EnqueueCount: 10,
+		MessageCost:  100,
+	}
+
+	t.Run("should return user transactions", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		inputUserId := "1"
+		inputFilter := usermodels.TransactionFilter{Type: usermodels.TransactionMessageCharge}
+		expectedTxs := []usermodels.BalanceTransaction{
+			{UserId: inputUserId, Type: usermodels.TransactionMessageCharge, Amount: -100, BalanceAfter: 900, MessageId: "10"},
+		}
+
+		mockUser.EXPECT().
+			GetUserTransactions(ctx, inputUserId, inputFilter, 0, 10).
+			Return(expectedTxs, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualTxs, actualErr := smsGateway.GetUserTransactions(ctx, inputUserId, inputFilter, 0, 10)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedTxs, actualTxs)
+	})
+
+	t.Run("should return error when can not get user transactions", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		inputUserId := "1"
+		expectedErr := fmt.Errorf("some error")
+
+		mockUser.EXPECT().
+			GetUserTransactions(ctx, inputUserId, usermodels.TransactionFilter{}, 0, 10).
+			Return(nil, expectedErr).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualTxs, actualErr := smsGateway.GetUserTransactions(ctx, inputUserId, usermodels.TransactionFilter{}, 0, 10)
+		assert.Error(t, actualErr)
+		assert.Equal(t, expectedErr, actualErr)
+		assert.Nil(t, actualTxs)
+	})
+}
+
+func TestSmsGateway_SetUserEnqueueWeight(t *testing.T) {
+	cfg := smsgateway.Config{
+		EnqueueCount: 10,
+		MessageCost:  100,
+	}
+
+	t.Run("should set user enqueue weight", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		inputUserId := "1"
+		inputWeight := 5
+		expectedUser := usermodels.User{Name: "test", EnqueueWeight: inputWeight}
+
+		mockUser.EXPECT().
+			SetUserEnqueueWeight(ctx, inputUserId, inputWeight).
+			Return(expectedUser, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualUser, actualErr := smsGateway.SetUserEnqueueWeight(ctx, inputUserId, inputWeight)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedUser, actualUser)
+	})
+
+	t.Run("should return UserNotExistError when user not exists", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		inputUserId := "1"
+		inputWeight := 5
+
+		mockUser.EXPECT().
+			SetUserEnqueueWeight(ctx, inputUserId, inputWeight).
+			Return(usermodels.User{}, usermodels.UserNotExistError).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualUser, actualErr := smsGateway.SetUserEnqueueWeight(ctx, inputUserId, inputWeight)
+		assert.Error(t, actualErr)
+		assert.Equal(t, usermodels.UserNotExistError, actualErr)
+		assert.Equal(t, usermodels.User{}, actualUser)
+	})
+}
+
+func TestSmsGateway_SetUserAccount(t *testing.T) {
+	cfg := smsgateway.Config{
+		EnqueueCount: 10,
+		MessageCost:  100,
+	}
+
+	t.Run("should set user account", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		inputUserId := "1"
+		expectedUser := usermodels.User{Name: "test", AccountType: usermodels.AccountPostpaid, CreditLimit: 5000}
+
+		mockUser.EXPECT().
+			SetUserAccount(ctx, inputUserId, usermodels.AccountPostpaid, int64(5000)).
+			Return(expectedUser, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualUser, actualErr := smsGateway.SetUserAccount(ctx, inputUserId, usermodels.AccountPostpaid, 5000)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedUser, actualUser)
+	})
+
+	t.Run("should return InsufficientBalanceError when balance does not fit in the new limit", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		inputUserId := "1"
+
+		mockUser.EXPECT().
+			SetUserAccount(ctx, inputUserId, usermodels.AccountPrepaid, int64(0)).
+			Return(usermodels.User{}, usermodels.InsufficientBalanceError).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		_, actualErr := smsGateway.SetUserAccount(ctx, inputUserId, usermodels.AccountPrepaid, 0)
+		assert.Error(t, actualErr)
+		assert.Equal(t, usermodels.InsufficientBalanceError, actualErr)
+	})
+}
+
+func TestSmsGateway_GetUserStatement(t *testing.T) {
+	cfg := smsgateway.Config{
+		EnqueueCount: 10,
+		MessageCost:  100,
+	}
+
+	month := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should return user statement", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		inputUserId := "1"
+		expectedStatement := usermodels.BalanceStatement{
+			UserId:          inputUserId,
+			OpeningBalance:  1000,
+			ClosingBalance:  800,
+			Charges:         -200,
+			ChargedMessages: 2,
+		}
+
+		mockUser.EXPECT().
+			GetUser(ctx, inputUserId).
+			Return(usermodels.User{Entity: &shared.Entity{ID: inputUserId}}, nil).
+			Once()
+
+		mockUser.EXPECT().
+			GetUserStatement(ctx, inputUserId, month).
+			Return(expectedStatement, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualStatement, actualErr := smsGateway.GetUserStatement(ctx, inputUserId, month)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedStatement, actualStatement)
+	})
+
+	t.Run("should return UserNotExistError when user not exists", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		inputUserId := "1"
+
+		mockUser.EXPECT().
+			GetUser(ctx, inputUserId).
+			Return(usermodels.User{}, usermodels.UserNotExistError).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		_, actualErr := smsGateway.GetUserStatement(ctx, inputUserId, month)
+		assert.Error(t, actualErr)
+		assert.Equal(t, usermodels.UserNotExistError, actualErr)
+	})
+}
+
+func TestSmsGateway_RequeueDeadLetter(t *testing.T) {
+	cfg := smsgateway.Config{
+		EnqueueCount: 0,
+		MessageCost:  100,
+	}
+
+	failedMsg := smsmodels.Sms{
+		Entity:   &shared.Entity{ID: "7"},
+		UserId:   "1",
+		Content:  "Test Content 1",
+		Receiver: "09123456789",
+		Cost:     200,
+		Status:   smsmodels.StatusEnqueued,
+		Attempts: 3,
+	}
+	letter := smsmodels.DeadLetter{
+		Entity:    &shared.Entity{ID: "2"},
+		MessageId: failedMsg.ID,
+		Payload:   common.ValueToJSON(failedMsg),
+		Reason:    smsmodels.ReasonRetriesExhausted,
+		Error:     smsmodels.SendError.Error(),
+	}
+
+	t.Run("should hold user balance and requeue message", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		user := usermodels.User{
+			Entity:  &shared.Entity{ID: "1"},
+			Name:    "AshkanAbd",
+			Balance: 1000,
+		}
+		requeued := failedMsg
+		requeued.Status = smsmodels.StatusScheduled
+		requeued.Attempts = 0
+
+		mockSms.EXPECT().
+			GetDeadLetter(ctx, letter.ID).
+			Return(letter, nil).
+			Once()
+
+		mockUser.EXPECT().
+			GetUser(ctx, failedMsg.UserId).
+			Return(user, nil).
+			Once()
+
+		mockUow.EXPECT().
+			Do(ctx, mock.Anything).
+			RunAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).
+			Once()
+
+		mockUser.EXPECT().
+			HoldUserBalance(ctx, failedMsg.UserId, []usermodels.BalanceHold{
+				{MessageId: letter.MessageId, Amount: int64(failedMsg.Cost)},
+			}).
+			Return(nil).
+			Once()
+
+		mockSms.EXPECT().
+			RequeueDeadLetter(ctx, letter.ID).
+			Return(requeued, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualMsg, actualErr := smsGateway.RequeueDeadLetter(ctx, letter.ID)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, requeued, actualMsg)
+	})
+
+	t.Run("should return NotRequeueableError when dead letter has no message", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockSms.EXPECT().
+			GetDeadLetter(ctx, "1").
+			Return(smsmodels.DeadLetter{
+				Entity:  &shared.Entity{ID: "1"},
+				Payload: "xyz",
+				Reason:  smsmodels.ReasonUndecodable,
+			}, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualMsg, actualErr := smsGateway.RequeueDeadLetter(ctx, "1")
+		assert.Error(t, actualErr)
+		assert.Equal(t, smsmodels.NotRequeueableError, actualErr)
+		assert.Equal(t, smsmodels.Sms{}, actualMsg)
+	})
+
+	t.Run("should return InsufficientBalanceError when user balance is not enough", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		user := usermodels.User{
+			Entity:  &shared.Entity{ID: "1"},
+			Name:    "AshkanAbd",
+			Balance: 100,
+		}
+
+		mockSms.EXPECT().
+			GetDeadLetter(ctx, letter.ID).
+			Return(letter, nil).
+			Once()
+
+		mockUser.EXPECT().
+			GetUser(ctx, failedMsg.UserId).
+			Return(user, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualMsg, actualErr := smsGateway.RequeueDeadLetter(ctx, letter.ID)
+		assert.Error(t, actualErr)
+		assert.Equal(t, usermodels.InsufficientBalanceError, actualErr)
+		assert.Equal(t, smsmodels.Sms{}, actualMsg)
+	})
+
+	t.Run("should not hold user balance when can not requeue message", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		user := usermodels.User{
+			Entity:  &shared.Entity{ID: "1"},
+			Name:    "AshkanAbd",
+			Balance: 1000,
+		}
+
+		mockSms.EXPECT().
+			GetDeadLetter(ctx, letter.ID).
+			Return(letter, nil).
+			Once()
+
+		mockUser.EXPECT().
+			GetUser(ctx, failedMsg.UserId).
+			Return(user, nil).
+			Once()
+
+		mockUow.EXPECT().
+			Do(ctx, mock.Anything).
+			RunAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).
+			Once()
+
+		mockUser.EXPECT().
+			HoldUserBalance(ctx, failedMsg.UserId, []usermodels.BalanceHold{
+				{MessageId: letter.MessageId, Amount: int64(failedMsg.Cost)},
+			}).
+			Return(nil).
+			Once()
+
+		mockSms.EXPECT().
+			RequeueDeadLetter(ctx, letter.ID).
+			Return(smsmodels.Sms{}, smsmodels.MessageNotExistError).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualMsg, actualErr := smsGateway.RequeueDeadLetter(ctx, letter.ID)
+		assert.Error(t, actualErr)
+		assert.Equal(t, smsmodels.MessageNotExistError, actualErr)
+		assert.Equal(t, smsmodels.Sms{}, actualMsg)
+	})
+}
+
+func TestSmsGateway_QuoteMessages(t *testing.T) {
+	cfg := smsgateway.Config{
+		EnqueueCount: 0,
+		MessageCost:  100,
+	}
+
+	t.Run("should quote messages by receiver price and segments", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		userId := "1"
+
+		user := usermodels.User{
+			Entity:      &shared.Entity{ID: "1"},
+			Name:        "AshkanAbd",
+			Balance:     1000,
+			HeldBalance: 300,
+		}
+
+		msgs := []smsmodels.Sms{
+			{
+				Content:  "Test Content 1",
+				Receiver: "989123456789",
+			}, {
+				Content:  strings.Repeat("س", 100),
+				Receiver: "12025550100",
+			},
+		}
+
+		mockUser.EXPECT().
+			GetUser(ctx, userId).
+			Return(user, nil).
+			Once()
+
+		prices := []pricingmodels.UnitPrice{
+			{Receiver: msgs[0].Receiver, Prefix: "98", Price: 80, Source: pricingmodels.SourceOverride},
+			{Receiver: msgs[1].Receiver, Source: pricingmodels.SourceNone},
+		}
+
+		mockPhone.EXPECT().
+			Normalize(msgs[0].Receiver).
+			Return(phonemodels.PhoneNumber{Number: msgs[0].Receiver}, nil).
+			Once()
+
+		mockPhone.EXPECT().
+			Normalize(msgs[1].Receiver).
+			Return(phonemodels.PhoneNumber{Number: msgs[1].Receiver}, nil).
+			Once()
+
+		mockPricing.EXPECT().
+			ResolvePrices(ctx, userId, []string{msgs[0].Receiver, msgs[1].Receiver}, mock.Anything).
+			Return(prices, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualQuote, actualErr := smsGateway.QuoteMessages(ctx, userId, msgs)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, smsgateway.Quote{
+			Messages: []smsmodels.Sms{
+				{
+					Content:  msgs[0].Content,
+					Receiver: msgs[0].Receiver,
+					Encoding: smsmodels.EncodingGSM7,
+					Segments: 1,
+					Cost:     80,
+				}, {
+					Content:  msgs[1].Content,
+					Receiver: msgs[1].Receiver,
+					Encoding: smsmodels.EncodingUCS2,
+					Segments: 2,
+					Cost:     2 * cfg.MessageCost,
+				},
+			},
+			Prices:           prices,
+			TotalCost:        280,
+			AvailableBalance: 700,
+		}, actualQuote)
+	})
+
+	t.Run("should return UserNotExistError when user does not exist", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		userId := "1"
+
+		mockUser.EXPECT().
+			GetUser(ctx, userId).
+			Return(usermodels.User{}, usermodels.UserNotExistError).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		_, actualErr := smsGateway.QuoteMessages(ctx, userId, []smsmodels.Sms{{Content: "Test Content 1", Receiver: "09123456789"}})
+		assert.Error(t, actualErr)
+		assert.Equal(t, usermodels.UserNotExistError, actualErr)
+	})
 }
 
-func TestSmsGateway_SendWorker(t *testing.T) {
+func TestSmsGateway_AddUserPrices(t *testing.T) {
 	cfg := smsgateway.Config{
-		EnqueueCount: 10,
+		EnqueueCount: 0,
 		MessageCost:  100,
 	}
 
-	t.Run("should send a queue message", func(t *testing.T) {
+	t.Run("should add prices as overrides of the user", func(t *testing.T) {
 		ctx := context.Background()
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
-		msg := smsmodels.Sms{
-			Content:  "Test Content 1",
-			Receiver: "09123456789",
-			UserId:   "1",
-			Status:   smsmodels.StatusSent,
+		userId := "1"
+
+		expectedPrices := []pricingmodels.Price{
+			{Entity: &shared.Entity{ID: "5"}, UserId: userId, Prefix: "98", Price: 80},
 		}
 
-		mockSms.EXPECT().
-			SendFromQueue(ctx).
-			Return(msg, nil).
+		mockUser.EXPECT().
+			GetUser(ctx, userId).
+			Return(usermodels.User{Entity: &shared.Entity{ID: userId}}, nil).
+			Once()
+
+		mockPricing.EXPECT().
+			AddPrices(ctx, []pricingmodels.Price{
+				{UserId: userId, Prefix: "98", Price: 80},
+			}).
+			Return(expectedPrices, nil).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
-		actualErr := smsGateway.SendWorker(ctx)
+		actualPrices, actualErr := smsGateway.AddUserPrices(ctx, userId, []pricingmodels.Price{
+			{PriceListId: "3", Prefix: "98", Price: 80},
+		})
 		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedPrices, actualPrices)
 	})
 
-	t.Run("should increase user balance when can not send", func(t *testing.T) {
+	t.Run("should return UserNotExistError when user does not exist", func(t *testing.T) {
 		ctx := context.Background()
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
-		msg := smsmodels.Sms{
-			Content:  "Test Content 1",
-			Receiver: "09123456789",
-			UserId:   "1",
-			Cost:     200,
-			Status:   smsmodels.StatusFailed,
-		}
+		userId := "1"
 
-		mockSms.EXPECT().
-			SendFromQueue(ctx).
-			Return(msg, nil).
+		mockUser.EXPECT().
+			GetUser(ctx, userId).
+			Return(usermodels.User{}, usermodels.UserNotExistError).
 			Once()
 
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		_, actualErr := smsGateway.AddUserPrices(ctx, userId, []pricingmodels.Price{{Prefix: "98", Price: 80}})
+		assert.Error(t, actualErr)
+		assert.Equal(t, usermodels.UserNotExistError, actualErr)
+	})
+}
+
+func TestSmsGateway_AssignUserPriceList(t *testing.T) {
+	cfg := smsgateway.Config{
+		EnqueueCount: 0,
+		MessageCost:  100,
+	}
+
+	t.Run("should assign price list to user", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		userId := "1"
+
 		mockUser.EXPECT().
-			IncreaseUserBalance(ctx, msg.UserId, int64(msg.Cost)).
-			Return(0, nil).
+			GetUser(ctx, userId).
+			Return(usermodels.User{Entity: &shared.Entity{ID: userId}}, nil).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		mockPricing.EXPECT().
+			AssignUserPriceList(ctx, userId, "2").
+			Return(nil).
+			Once()
 
-		actualErr := smsGateway.SendWorker(ctx)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.AssignUserPriceList(ctx, userId, "2")
 		assert.NoError(t, actualErr)
 	})
 
-	t.Run("should return nil when can not increase user balance", func(t *testing.T) {
+	t.Run("should return PriceListNotExistError when price list does not exist", func(t *testing.T) {
 		ctx := context.Background()
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
-		msg := smsmodels.Sms{
-			Content:  "Test Content 1",
-			Receiver: "09123456789",
-			UserId:   "1",
-			Cost:     200,
-			Status:   smsmodels.StatusFailed,
-		}
+		userId := "1"
 
-		mockSms.EXPECT().
-			SendFromQueue(ctx).
-			Return(msg, nil).
+		mockUser.EXPECT().
+			GetUser(ctx, userId).
+			Return(usermodels.User{Entity: &shared.Entity{ID: userId}}, nil).
+			Once()
+
+		mockPricing.EXPECT().
+			AssignUserPriceList(ctx, userId, "2").
+			Return(pricingmodels.PriceListNotExistError).
 			Once()
 
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualErr := smsGateway.AssignUserPriceList(ctx, userId, "2")
+		assert.Error(t, actualErr)
+		assert.Equal(t, pricingmodels.PriceListNotExistError, actualErr)
+	})
+}
+
+func TestSmsGateway_CreateWebhook(t *testing.T) {
+	cfg := smsgateway.Config{}
+
+	t.Run("should create webhook for user", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		expectedWebhook := webhookmodels.Webhook{
+			Entity: &shared.Entity{ID: "5"},
+			UserId: "1",
+			Url:    "https://example.com/hooks",
+			Secret: "whsec_1",
+		}
+
 		mockUser.EXPECT().
-			IncreaseUserBalance(ctx, msg.UserId, int64(msg.Cost)).
-			Return(0, fmt.Errorf("some error")).
+			GetUser(ctx, "1").
+			Return(usermodels.User{Entity: &shared.Entity{ID: "1"}}, nil).
+			Once()
+
+		mockWebhook.EXPECT().
+			CreateWebhook(ctx, "1", expectedWebhook.Url).
+			Return(expectedWebhook, nil).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
-		actualErr := smsGateway.SendWorker(ctx)
+		actualWebhook, actualErr := smsGateway.CreateWebhook(ctx, "1", expectedWebhook.Url)
 		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedWebhook, actualWebhook)
 	})
 
-	t.Run("should return InvalidQueueError when queue is not valid", func(t *testing.T) {
+	t.Run("should return UserNotExistError when user does not exist", func(t *testing.T) {
 		ctx := context.Background()
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
-		mockSms.EXPECT().
-			SendFromQueue(ctx).
-			Return(smsmodels.Sms{}, smsmodels.InvalidQueueError).
+		mockUser.EXPECT().
+			GetUser(ctx, "1").
+			Return(usermodels.User{}, usermodels.UserNotExistError).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
-		actualErr := smsGateway.SendWorker(ctx)
+		_, actualErr := smsGateway.CreateWebhook(ctx, "1", "https://example.com/hooks")
 		assert.Error(t, actualErr)
-		assert.Equal(t, smsmodels.InvalidQueueError, actualErr)
+		assert.Equal(t, usermodels.UserNotExistError, actualErr)
 	})
+}
 
-	t.Run("should return nil when message not exists", func(t *testing.T) {
+func TestSmsGateway_WebhookWorker(t *testing.T) {
+	cfg := smsgateway.Config{}
+
+	t.Run("should dispatch due webhook deliveries", func(t *testing.T) {
 		ctx := context.Background()
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockWebhook.EXPECT().
+			DispatchDue(ctx).
+			Return(3, nil).
+			Once()
 
-		mockSms.EXPECT().
-			SendFromQueue(ctx).
-			Return(smsmodels.Sms{}, smsmodels.MessageNotExistError).
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualDispatched, actualErr := smsGateway.WebhookWorker(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 3, actualDispatched)
+	})
+
+	t.Run("should return nil when dispatch fails", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockWebhook.EXPECT().
+			DispatchDue(ctx).
+			Return(0, fmt.Errorf("db error")).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
-		actualErr := smsGateway.SendWorker(ctx)
+		actualDispatched, actualErr := smsGateway.WebhookWorker(ctx)
 		assert.NoError(t, actualErr)
+		assert.Equal(t, 0, actualDispatched)
 	})
 }
 
-func TestSmsGateway_IncreaseUserBalance(t *testing.T) {
-	cfg := smsgateway.Config{
-		EnqueueCount: 10,
-		MessageCost:  100,
-	}
+func TestSmsGateway_IdempotencyPurgeWorker(t *testing.T) {
+	cfg := smsgateway.Config{}
 
-	t.Run("should increase user balance", func(t *testing.T) {
+	t.Run("should purge expired idempotency keys", func(t *testing.T) {
 		ctx := context.Background()
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockIdempotency.EXPECT().
+			PurgeExpired(ctx).
+			Return(3, nil).
+			Once()
 
-		inputUserId := "1"
-		inputAmount := int64(100)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
-		mockUser.EXPECT().
-			IncreaseUserBalance(ctx, inputUserId, inputAmount).
-			Return(inputAmount, nil).
+		actualPurged, actualErr := smsGateway.IdempotencyPurgeWorker(ctx)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, 3, actualPurged)
+	})
+
+	t.Run("should return nil when purge fails", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockIdempotency.EXPECT().
+			PurgeExpired(ctx).
+			Return(0, fmt.Errorf("db error")).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
-		actualBalance, actualErr := smsGateway.IncreaseUserBalance(ctx, inputUserId, inputAmount)
+		actualPurged, actualErr := smsGateway.IdempotencyPurgeWorker(ctx)
 		assert.NoError(t, actualErr)
-		assert.Equal(t, inputAmount, actualBalance)
+		assert.Equal(t, 0, actualPurged)
 	})
+}
 
-	t.Run("should return UserNotExistError when can not increase balance", func(t *testing.T) {
+func TestSmsGateway_CreateApiKey(t *testing.T) {
+	cfg := smsgateway.Config{}
+
+	t.Run("should create api key for user", func(t *testing.T) {
 		ctx := context.Background()
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		scopes := []apikeymodels.Scope{apikeymodels.ScopeSend}
+		expectedKey := apikeymodels.ApiKey{
+			Entity: &shared.Entity{ID: "3"},
+			UserId: "1",
+			Name:   "backend",
+			Scopes: scopes,
+		}
 
-		inputUserId := "1"
-		inputAmount := int64(100)
+		mockUser.EXPECT().
+			GetUser(ctx, "1").
+			Return(usermodels.User{Entity: &shared.Entity{ID: "1"}}, nil).
+			Once()
+
+		mockApiKey.EXPECT().
+			CreateApiKey(ctx, apikeymodels.ApiKey{UserId: "1", Name: "backend", Scopes: scopes, RequireSignature: true}).
+			Return(expectedKey, "sgw_test", nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualKey, actualRawKey, actualErr := smsGateway.CreateApiKey(ctx, "1", "backend", scopes, true)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedKey, actualKey)
+		assert.Equal(t, "sgw_test", actualRawKey)
+	})
+
+	t.Run("should return UserNotExistError when user does not exist", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
 
 		mockUser.EXPECT().
-			IncreaseUserBalance(ctx, inputUserId, inputAmount).
-			Return(0, usermodels.UserNotExistError).
+			GetUser(ctx, "1").
+			Return(usermodels.User{}, usermodels.UserNotExistError).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
 
-		actualBalance, actualErr := smsGateway.IncreaseUserBalance(ctx, inputUserId, inputAmount)
+		_, _, actualErr := smsGateway.CreateApiKey(ctx, "1", "backend", []apikeymodels.Scope{apikeymodels.ScopeRead}, false)
 		assert.Error(t, actualErr)
 		assert.Equal(t, usermodels.UserNotExistError, actualErr)
-		assert.Equal(t, int64(0), actualBalance)
 	})
+}
 
-	t.Run("should return error when can not increase balance", func(t *testing.T) {
+func TestSmsGateway_RevokeApiKey(t *testing.T) {
+	cfg := smsgateway.Config{}
+
+	t.Run("should revoke api key the caller could grant", func(t *testing.T) {
 		ctx := context.Background()
 
 		mockUser := usermocks.NewMockIUserService(t)
 		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		caller := apikeymodels.ApiKey{
+			UserId: "1",
+			Scopes: []apikeymodels.Scope{apikeymodels.ScopeKeys, apikeymodels.ScopeSend},
+		}
+		key := apikeymodels.ApiKey{
+			Entity: &shared.Entity{ID: "3"},
+			UserId: "1",
+			Scopes: []apikeymodels.Scope{apikeymodels.ScopeSend},
+		}
+		expectedKey := key
+		expectedKey.RevokedAt = time.Now()
 
-		inputUserId := "1"
-		inputAmount := int64(100)
+		mockApiKey.EXPECT().
+			GetApiKeys(ctx, "1").
+			Return([]apikeymodels.ApiKey{key}, nil).
+			Once()
 
-		expectedErr := fmt.Errorf("some error")
+		mockApiKey.EXPECT().
+			RevokeApiKey(ctx, "1", "3").
+			Return(expectedKey, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualKey, actualErr := smsGateway.RevokeApiKey(ctx, caller, "1", "3")
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedKey, actualKey)
+	})
+
+	t.Run("should return ScopeNotGrantedError when caller could not grant key scopes", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		caller := apikeymodels.ApiKey{
+			UserId: "1",
+			Scopes: []apikeymodels.Scope{apikeymodels.ScopeKeys},
+		}
+
+		mockApiKey.EXPECT().
+			GetApiKeys(ctx, "1").
+			Return([]apikeymodels.ApiKey{
+				{
+					Entity: &shared.Entity{ID: "3"},
+					UserId: "1",
+					Scopes: []apikeymodels.Scope{apikeymodels.ScopeBilling},
+				},
+			}, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		_, actualErr := smsGateway.RevokeApiKey(ctx, caller, "1", "3")
+		assert.ErrorIs(t, actualErr, apikeymodels.ScopeNotGrantedError)
+	})
+
+	t.Run("should return ApiKeyNotExistError when key is not of user", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockApiKey.EXPECT().
+			GetApiKeys(ctx, "1").
+			Return(nil, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		_, actualErr := smsGateway.RevokeApiKey(ctx, apikeymodels.ApiKey{Scopes: []apikeymodels.Scope{apikeymodels.ScopeAdmin}}, "1", "3")
+		assert.ErrorIs(t, actualErr, apikeymodels.ApiKeyNotExistError)
+	})
+}
+
+func TestSmsGateway_GetUserUsage(t *testing.T) {
+	cfg := smsgateway.Config{}
+
+	t.Run("should return usage of user", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		expectedUsage := ratelimitmodels.Usage{
+			Limits: ratelimitmodels.Limits{Daily: 100},
+			Daily:  12,
+		}
+		mockRateLimit.EXPECT().
+			GetUsage(ctx, "1").
+			Return(expectedUsage, nil).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualUsage, actualErr := smsGateway.GetUserUsage(ctx, "1")
+		assert.NoError(t, actualErr)
+		assert.Equal(t, expectedUsage, actualUsage)
+	})
+}
+
+func TestSmsGateway_SetUserLimits(t *testing.T) {
+	cfg := smsgateway.Config{}
+
+	t.Run("should set limits of user", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		limits := ratelimitmodels.Limits{PerSecond: 10, MaxBulkSize: 500}
 
 		mockUser.EXPECT().
-			IncreaseUserBalance(ctx, inputUserId, inputAmount).
-			Return(0, expectedErr).
+			GetUser(ctx, "1").
+			Return(usermodels.User{Entity: &shared.Entity{ID: "1"}}, nil).
 			Once()
 
-		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms)
+		mockRateLimit.EXPECT().
+			SetLimits(ctx, "1", limits).
+			Return(limits, nil).
+			Once()
 
-		actualBalance, actualErr := smsGateway.IncreaseUserBalance(ctx, inputUserId, inputAmount)
-		assert.Error(t, actualErr)
-		assert.Equal(t, expectedErr, actualErr)
-		assert.Equal(t, int64(0), actualBalance)
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		actualLimits, actualErr := smsGateway.SetUserLimits(ctx, "1", limits)
+		assert.NoError(t, actualErr)
+		assert.Equal(t, limits, actualLimits)
+	})
+
+	t.Run("should return UserNotExistError when user does not exist", func(t *testing.T) {
+		ctx := context.Background()
+
+		mockUser := usermocks.NewMockIUserService(t)
+		mockSms := smsmocks.NewMockISmsService(t)
+		mockPricing := pricingmocks.NewMockIPricingService(t)
+		mockWebhook := webhookmocks.NewMockIWebhookService(t)
+		mockApiKey := apikeymocks.NewMockIApiKeyService(t)
+		mockRateLimit := ratelimitmocks.NewMockIRateLimitService(t)
+		mockPhone := phonemocks.NewMockIPhoneService(t)
+		mockIdempotency := idempotencymocks.NewMockIIdempotencyService(t)
+		mockUow := sharedmocks.NewMockIUnitOfWork(t)
+
+		mockUser.EXPECT().
+			GetUser(ctx, "1").
+			Return(usermodels.User{}, usermodels.UserNotExistError).
+			Once()
+
+		smsGateway := smsgateway.NewSmsGateway(cfg, mockUser, mockSms, mockPricing, mockWebhook, mockApiKey, mockRateLimit, mockPhone, mockIdempotency, mockUow)
+
+		_, actualErr := smsGateway.SetUserLimits(ctx, "1", ratelimitmodels.Limits{})
+		assert.Equal(t, usermodels.UserNotExistError, actualErr)
 	})
 }
@@ -0,0 +1,208 @@
+package smsgateway
+
+import (
+	"context"
+	"time"
+
+	smsmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/sms/models"
+	webhookmodels "github.com/AshkanAbd/arvancloud_sms_gateway/internal/modules/webhook/models"
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+)
+
+// messageStatusEvent is the data of the message.status webhook event.
+type messageStatusEvent struct {
+	Id                string     `json:"id"`
+	UserId            string     `json:"userId"`
+	Receiver          string     `json:"receiver"`
+	Status            string     `json:"status"`
+	Tags              []string   `json:"tags,omitempty"`
+	Provider          string     `json:"provider,omitempty"`
+	ProviderMessageId string     `json:"providerMessageId,omitempty"`
+	Attempts          int        `json:"attempts"`
+	Error             string     `json:"error,omitempty"`
+	SentAt            *time.Time `json:"sentAt,omitempty"`
+	DoneAt            *time.Time `json:"doneAt,omitempty"`
+}
+
+func newMessageStatusEvent(msg smsmodels.Sms) messageStatusEvent {
+	event := messageStatusEvent{
+		UserId:            msg.UserId,
+		Receiver:          msg.Receiver,
+		Status:            msg.Status.String(),
+		Tags:              msg.Tags,
+		Provider:          msg.Provider,
+		ProviderMessageId: msg.ProviderMessageId,
+		Attempts:          msg.Attempts,
+		Error:             msg.DeliveryError,
+	}
+
+	if msg.Entity != nil {
+		event.Id = msg.ID
+	}
+	if !msg.SentAt.IsZero() {
+		event.SentAt = &msg.SentAt
+	}
+	if !msg.DoneAt.IsZero() {
+		event.DoneAt = &msg.DoneAt
+	}
+
+	return event
+}
+
+// publishMessageStatus queues a message.status event for the webhooks of the
+// message owner. The state change is already stored, so a failure is only
+// logged.
+func (s *SmsGateway) publishMessageStatus(ctx context.Context, msg smsmodels.Sms) {
+	if _, err := s.webhook.Publish(ctx, msg.UserId, webhookmodels.EventMessageStatus, newMessageStatusEvent(msg)); err != nil {
+		pkgLog.Error(err, "failed to publish status of message %s", msg.ID)
+	}
+}
+
+func (s *SmsGateway) WebhookWorker(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "webhook worker context canceled")
+		return 0, err
+	}
+
+	newCtx := context.Background()
+	dispatched, err := s.webhook.DispatchDue(newCtx)
+	if err != nil {
+		pkgLog.Error(err, "failed to dispatch webhook deliveries")
+		return 0, nil
+	}
+
+	return dispatched, nil
+}
+
+func (s *SmsGateway) StartWebhookWorker(ctx context.Context) error {
+	pkgLog.Debug("starting webhook worker...")
+	var stopErr error
+	for {
+		stopErr = ctx.Err()
+		if stopErr != nil {
+			pkgLog.Error(stopErr, "webhook worker context canceled")
+			break
+		}
+
+		var dispatched int
+		dispatched, stopErr = s.WebhookWorker(ctx)
+		if stopErr != nil {
+			break
+		}
+		if dispatched == 0 {
+			time.Sleep(s.cfg.WebhookInterval)
+		}
+	}
+
+	pkgLog.Error(stopErr, "webhook worker shutdown successfully")
+	return stopErr
+}
+
+func (s *SmsGateway) CreateWebhook(ctx context.Context, userId string, url string) (webhookmodels.Webhook, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "create webhook context canceled")
+		return webhookmodels.Webhook{}, err
+	}
+
+	newCtx := context.Background()
+	if _, getUserErr := s.GetUser(newCtx, userId); getUserErr != nil {
+		return webhookmodels.Webhook{}, getUserErr
+	}
+
+	res, err := s.webhook.CreateWebhook(newCtx, userId, url)
+	if err != nil {
+		pkgLog.Error(err, "failed to create webhook")
+		return webhookmodels.Webhook{}, err
+	}
+
+	return res, nil
+}
+
+func (s *SmsGateway) GetWebhooks(ctx context.Context, userId string) ([]webhookmodels.Webhook, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "get webhooks context canceled")
+		return nil, err
+	}
+
+	newCtx := context.Background()
+	if _, getUserErr := s.GetUser(newCtx, userId); getUserErr != nil {
+		return nil, getUserErr
+	}
+
+	res, err := s.webhook.GetWebhooks(newCtx, userId)
+	if err != nil {
+		pkgLog.Error(err, "failed to get webhooks")
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *SmsGateway) DeleteWebhook(ctx context.Context, userId string, id string) error {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "delete webhook context canceled")
+		return err
+	}
+
+	newCtx := context.Background()
+	if err := s.webhook.DeleteWebhook(newCtx, userId, id); err != nil {
+		pkgLog.Error(err, "failed to delete webhook")
+		return err
+	}
+
+	return nil
+}
+
+func (s *SmsGateway) RotateWebhookSecret(ctx context.Context, userId string, id string) (webhookmodels.Webhook, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "rotate webhook secret context canceled")
+		return webhookmodels.Webhook{}, err
+	}
+
+	newCtx := context.Background()
+	res, err := s.webhook.RotateSecret(newCtx, userId, id)
+	if err != nil {
+		pkgLog.Error(err, "failed to rotate webhook secret")
+		return webhookmodels.Webhook{}, err
+	}
+
+	return res, nil
+}
+
+func (s *SmsGateway) TestWebhook(ctx context.Context, userId string, id string) (webhookmodels.Delivery, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "test webhook context canceled")
+		return webhookmodels.Delivery{}, err
+	}
+
+	newCtx := context.Background()
+	res, err := s.webhook.TestWebhook(newCtx, userId, id)
+	if err != nil {
+		pkgLog.Error(err, "failed to test webhook")
+		return webhookmodels.Delivery{}, err
+	}
+
+	return res, nil
+}
+
+func (s *SmsGateway) GetWebhookDeliveries(
+	ctx context.Context,
+	userId string,
+	id string,
+	skip int,
+	limit int,
+) ([]webhookmodels.Delivery, error) {
+	if err := ctx.Err(); err != nil {
+		pkgLog.Error(err, "get webhook deliveries context canceled")
+		return nil, err
+	}
+
+	newCtx := context.Background()
+	res, err := s.webhook.GetDeliveries(newCtx, userId, id, skip, limit)
+	if err != nil {
+		pkgLog.Error(err, "failed to get webhook deliveries")
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,2 @@
+ALTER TABLE messages ADD COLUMN tags TEXT NOT NULL DEFAULT '';
+ALTER TABLE messages ADD COLUMN provider TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS messages_due_idx;
+ALTER TABLE messages DROP COLUMN IF EXISTS next_attempt_at;
+ALTER TABLE messages DROP COLUMN IF EXISTS attempts;
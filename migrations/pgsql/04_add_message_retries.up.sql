@@ -0,0 +1,4 @@
+ALTER TABLE messages ADD COLUMN attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE messages ADD COLUMN next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+CREATE INDEX messages_due_idx ON messages USING btree (next_attempt_at, id) WHERE status IN (0, 4);
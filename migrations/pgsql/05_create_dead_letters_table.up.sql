@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS dead_letters
+(
+    id         SERIAL PRIMARY KEY,
+    message_id BIGINT    NULL,
+    payload    TEXT      NOT NULL,
+    reason     TEXT      NOT NULL,
+    error      TEXT      NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE dead_letters ADD CONSTRAINT fk_messages_dead_letters FOREIGN KEY (message_id) REFERENCES messages (id) ON DELETE CASCADE ON UPDATE CASCADE;
+CREATE INDEX dead_letters_message_id_idx ON dead_letters USING btree (message_id);
+CREATE INDEX dead_letters_created_at_idx ON dead_letters USING brin (created_at);
@@ -0,0 +1,5 @@
+ALTER TABLE messages ADD COLUMN send_at TIMESTAMP NOT NULL DEFAULT NOW();
+UPDATE messages SET send_at = created_at;
+
+-- next_attempt_at starts at send_at, so messages_due_idx already keeps
+-- future rows out of the range EnqueueMessages scans.
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS messages_due_idx;
+CREATE INDEX messages_due_idx ON messages USING btree (next_attempt_at, id) WHERE status IN (0, 4);
+
+ALTER TABLE messages DROP COLUMN IF EXISTS priority;
@@ -0,0 +1,4 @@
+ALTER TABLE messages ADD COLUMN priority SMALLINT NOT NULL DEFAULT 0;
+
+DROP INDEX IF EXISTS messages_due_idx;
+CREATE INDEX messages_due_idx ON messages USING btree (priority, next_attempt_at, id) WHERE status IN (0, 4);
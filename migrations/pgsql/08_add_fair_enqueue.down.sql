@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS messages_user_due_idx;
+CREATE INDEX messages_due_idx ON messages USING btree (priority, next_attempt_at, id) WHERE status IN (0, 4);
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS user_enqueue_weight_positive;
+ALTER TABLE users DROP COLUMN IF EXISTS enqueue_weight;
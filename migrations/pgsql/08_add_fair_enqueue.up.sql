@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN enqueue_weight INT NOT NULL DEFAULT 1;
+ALTER TABLE users ADD CONSTRAINT user_enqueue_weight_positive CHECK (enqueue_weight > 0);
+
+DROP INDEX IF EXISTS messages_due_idx;
+CREATE INDEX messages_user_due_idx ON messages USING btree (user_id, priority, next_attempt_at, id) WHERE status IN (0, 4);
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS balance_transactions
+(
+    id            SERIAL PRIMARY KEY,
+    user_id       BIGINT    NOT NULL,
+    type          TEXT      NOT NULL,
+    amount        BIGINT    NOT NULL,
+    balance_after BIGINT    NOT NULL,
+    message_id    BIGINT    NULL,
+    reference     TEXT      NOT NULL DEFAULT '',
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE balance_transactions ADD CONSTRAINT fk_users_balance_transactions FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE;
+ALTER TABLE balance_transactions ADD CONSTRAINT balance_transaction_amount_not_zero CHECK (amount <> 0);
+CREATE INDEX balance_transactions_user_id_idx ON balance_transactions USING btree (user_id, id);
+CREATE INDEX balance_transactions_message_id_idx ON balance_transactions USING btree (message_id);
+
+-- Opening entries, so every user balance equals the sum of its ledger.
+INSERT INTO balance_transactions (user_id, type, amount, balance_after, reference)
+SELECT id, 'adjustment', balance, balance, 'opening balance'
+FROM users
+WHERE balance <> 0;
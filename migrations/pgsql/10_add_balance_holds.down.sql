@@ -0,0 +1,6 @@
+UPDATE users SET balance = balance - held_balance;
+
+DROP TABLE IF EXISTS balance_holds;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS user_insufficient_balance_for_holds;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS user_held_balance_positive;
+ALTER TABLE users DROP COLUMN IF EXISTS held_balance;
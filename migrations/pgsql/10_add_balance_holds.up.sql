@@ -0,0 +1,45 @@
+ALTER TABLE users ADD COLUMN held_balance BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE users ADD CONSTRAINT user_held_balance_positive CHECK (held_balance >= 0);
+ALTER TABLE users ADD CONSTRAINT user_insufficient_balance_for_holds CHECK (balance >= held_balance);
+
+CREATE TABLE IF NOT EXISTS balance_holds
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    BIGINT    NOT NULL,
+    message_id BIGINT    NOT NULL,
+    amount     BIGINT    NOT NULL,
+    status     TEXT      NOT NULL DEFAULT 'held',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE balance_holds ADD CONSTRAINT fk_users_balance_holds FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE;
+ALTER TABLE balance_holds ADD CONSTRAINT fk_messages_balance_holds FOREIGN KEY (message_id) REFERENCES messages (id) ON DELETE CASCADE ON UPDATE CASCADE;
+ALTER TABLE balance_holds ADD CONSTRAINT balance_hold_amount_positive CHECK (amount > 0);
+CREATE UNIQUE INDEX balance_holds_message_id_held_idx ON balance_holds USING btree (message_id) WHERE status = 'held';
+
+-- Messages still in flight were charged when scheduled. Refund them and
+-- hold their cost instead, so they are captured or released like new ones.
+INSERT INTO balance_transactions (user_id, type, amount, balance_after, message_id, reference)
+SELECT m.user_id,
+       'refund',
+       m.cost,
+       u.balance + SUM(m.cost) OVER (PARTITION BY m.user_id ORDER BY m.id),
+       m.id,
+       'converted to hold'
+FROM messages m
+         JOIN users u ON u.id = m.user_id
+WHERE m.status IN (0, 1, 4)
+  AND m.cost > 0;
+
+INSERT INTO balance_holds (user_id, message_id, amount)
+SELECT user_id, id, cost
+FROM messages
+WHERE status IN (0, 1, 4)
+  AND cost > 0;
+
+UPDATE users u
+SET balance      = u.balance + h.total,
+    held_balance = u.held_balance + h.total
+FROM (SELECT user_id, SUM(amount) AS total FROM balance_holds GROUP BY user_id) h
+WHERE u.id = h.user_id;
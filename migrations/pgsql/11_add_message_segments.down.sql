@@ -0,0 +1,2 @@
+ALTER TABLE messages DROP COLUMN IF EXISTS segments;
+ALTER TABLE messages DROP COLUMN IF EXISTS encoding;
@@ -0,0 +1,2 @@
+ALTER TABLE messages ADD COLUMN encoding SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE messages ADD COLUMN segments INT NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_price_lists;
+DROP TABLE IF EXISTS prices;
+DROP TABLE IF EXISTS price_lists;
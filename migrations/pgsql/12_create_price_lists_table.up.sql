@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS price_lists
+(
+    id         SERIAL PRIMARY KEY,
+    name       TEXT      NOT NULL,
+    is_default BOOLEAN   NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE price_lists ADD CONSTRAINT price_list_name_empty CHECK (name <> '');
+CREATE UNIQUE INDEX price_lists_default_idx ON price_lists USING btree (is_default) WHERE is_default;
+
+CREATE TABLE IF NOT EXISTS prices
+(
+    id             SERIAL PRIMARY KEY,
+    price_list_id  BIGINT    NULL,
+    user_id        BIGINT    NULL,
+    prefix         TEXT      NOT NULL,
+    price          INT       NOT NULL,
+    effective_from TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE prices ADD CONSTRAINT fk_price_lists_prices FOREIGN KEY (price_list_id) REFERENCES price_lists (id) ON DELETE CASCADE ON UPDATE CASCADE;
+ALTER TABLE prices ADD CONSTRAINT fk_users_prices FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE;
+ALTER TABLE prices ADD CONSTRAINT price_scope_invalid CHECK ((price_list_id IS NULL) <> (user_id IS NULL));
+ALTER TABLE prices ADD CONSTRAINT price_negative CHECK (price >= 0);
+ALTER TABLE prices ADD CONSTRAINT price_prefix_invalid CHECK (prefix ~ '^[0-9]{1,15}$');
+CREATE INDEX prices_price_list_id_idx ON prices USING btree (price_list_id, prefix, effective_from);
+CREATE INDEX prices_user_id_idx ON prices USING btree (user_id, prefix, effective_from);
+
+CREATE TABLE IF NOT EXISTS user_price_lists
+(
+    user_id       BIGINT PRIMARY KEY,
+    price_list_id BIGINT    NOT NULL,
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE user_price_lists ADD CONSTRAINT fk_users_user_price_lists FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE;
+ALTER TABLE user_price_lists ADD CONSTRAINT fk_price_lists_user_price_lists FOREIGN KEY (price_list_id) REFERENCES price_lists (id) ON DELETE CASCADE ON UPDATE CASCADE;
+CREATE INDEX user_price_lists_price_list_id_idx ON user_price_lists USING btree (price_list_id);
@@ -0,0 +1,9 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS user_insufficient_balance_for_holds;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS user_insufficient_balance;
+ALTER TABLE users ADD CONSTRAINT user_insufficient_balance CHECK (balance >= 0);
+ALTER TABLE users ADD CONSTRAINT user_insufficient_balance_for_holds CHECK (balance >= held_balance);
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS user_credit_limit_invalid;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS user_account_type_invalid;
+ALTER TABLE users DROP COLUMN IF EXISTS credit_limit;
+ALTER TABLE users DROP COLUMN IF EXISTS account_type;
@@ -0,0 +1,10 @@
+ALTER TABLE users ADD COLUMN account_type TEXT NOT NULL DEFAULT 'prepaid';
+ALTER TABLE users ADD COLUMN credit_limit BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE users ADD CONSTRAINT user_account_type_invalid CHECK (account_type IN ('prepaid', 'postpaid'));
+ALTER TABLE users ADD CONSTRAINT user_credit_limit_invalid CHECK (credit_limit >= 0 AND (account_type = 'postpaid' OR credit_limit = 0));
+
+-- Postpaid balances may go below zero down to their credit limit.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS user_insufficient_balance_for_holds;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS user_insufficient_balance;
+ALTER TABLE users ADD CONSTRAINT user_insufficient_balance CHECK (balance + credit_limit >= 0);
+ALTER TABLE users ADD CONSTRAINT user_insufficient_balance_for_holds CHECK (balance + credit_limit >= held_balance);
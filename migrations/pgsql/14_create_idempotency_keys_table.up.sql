@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys
+(
+    scope        TEXT      NOT NULL,
+    key          TEXT      NOT NULL,
+    request_hash TEXT      NOT NULL,
+    status       INT       NOT NULL DEFAULT 0,
+    status_code  INT       NOT NULL DEFAULT 0,
+    response     BYTEA     NULL,
+    expires_at   TIMESTAMP NOT NULL,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys USING btree (expires_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS messages_provider_message_id_idx;
+ALTER TABLE messages DROP COLUMN IF EXISTS delivery_error;
+ALTER TABLE messages DROP COLUMN IF EXISTS done_at;
+ALTER TABLE messages DROP COLUMN IF EXISTS sent_at;
+ALTER TABLE messages DROP COLUMN IF EXISTS provider_message_id;
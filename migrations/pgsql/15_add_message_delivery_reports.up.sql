@@ -0,0 +1,6 @@
+ALTER TABLE messages ADD COLUMN provider_message_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE messages ADD COLUMN sent_at TIMESTAMP NULL;
+ALTER TABLE messages ADD COLUMN done_at TIMESTAMP NULL;
+ALTER TABLE messages ADD COLUMN delivery_error TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX messages_provider_message_id_idx ON messages USING btree (provider, provider_message_id) WHERE provider_message_id <> '';
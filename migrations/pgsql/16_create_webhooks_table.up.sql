@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS webhooks
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    BIGINT    NOT NULL,
+    url        TEXT      NOT NULL,
+    secret     TEXT      NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE webhooks ADD CONSTRAINT fk_users_webhooks FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE;
+ALTER TABLE webhooks ADD CONSTRAINT webhook_url_empty CHECK (url <> '');
+CREATE INDEX webhooks_user_id_idx ON webhooks USING btree (user_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id              SERIAL PRIMARY KEY,
+    webhook_id      BIGINT    NOT NULL,
+    event           TEXT      NOT NULL,
+    payload         TEXT      NOT NULL,
+    status          INT       NOT NULL DEFAULT 0,
+    attempts        INT       NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    response_status INT       NOT NULL DEFAULT 0,
+    error           TEXT      NOT NULL DEFAULT '',
+    created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE webhook_deliveries ADD CONSTRAINT fk_webhooks_webhook_deliveries FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE ON UPDATE CASCADE;
+CREATE INDEX webhook_deliveries_webhook_id_idx ON webhook_deliveries USING btree (webhook_id, created_at);
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries USING btree (next_attempt_at) WHERE status = 0;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id         SERIAL PRIMARY KEY,
+    user_id    BIGINT    NOT NULL,
+    name       TEXT      NOT NULL DEFAULT '',
+    prefix     TEXT      NOT NULL,
+    key_hash   TEXT      NOT NULL,
+    scopes     TEXT      NOT NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE api_keys ADD CONSTRAINT fk_users_api_keys FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE;
+ALTER TABLE api_keys ADD CONSTRAINT api_key_scopes_empty CHECK (scopes <> '');
+CREATE UNIQUE INDEX api_keys_key_hash_idx ON api_keys USING btree (key_hash);
+CREATE INDEX api_keys_user_id_idx ON api_keys USING btree (user_id);
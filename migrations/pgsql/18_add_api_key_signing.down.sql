@@ -0,0 +1,3 @@
+ALTER TABLE api_keys DROP CONSTRAINT IF EXISTS api_key_signing_secret_empty;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS signing_secret;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS require_signature;
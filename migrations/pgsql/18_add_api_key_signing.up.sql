@@ -0,0 +1,3 @@
+ALTER TABLE api_keys ADD COLUMN require_signature BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE api_keys ADD COLUMN signing_secret TEXT NOT NULL DEFAULT '';
+ALTER TABLE api_keys ADD CONSTRAINT api_key_signing_secret_empty CHECK (NOT require_signature OR signing_secret <> '');
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS send_limits
+(
+    user_id       BIGINT PRIMARY KEY,
+    per_second    INT       NOT NULL DEFAULT 0,
+    per_minute    INT       NOT NULL DEFAULT 0,
+    daily         INT       NOT NULL DEFAULT 0,
+    monthly       INT       NOT NULL DEFAULT 0,
+    max_bulk_size INT       NOT NULL DEFAULT 0,
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE send_limits ADD CONSTRAINT fk_users_send_limits FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE ON UPDATE CASCADE;
+ALTER TABLE send_limits ADD CONSTRAINT send_limits_positive CHECK (per_second >= 0 AND per_minute >= 0 AND daily >= 0 AND monthly >= 0 AND max_bulk_size >= 0);
@@ -0,0 +1,2 @@
+ALTER TABLE messages DROP COLUMN IF EXISTS operator;
+ALTER TABLE messages DROP COLUMN IF EXISTS country;
@@ -0,0 +1,2 @@
+ALTER TABLE messages ADD COLUMN country TEXT NOT NULL DEFAULT '';
+ALTER TABLE messages ADD COLUMN operator TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- EnqueueMessages finds users with due messages of a lane from this index,
+-- then takes their messages from messages_user_due_idx.
+CREATE INDEX messages_due_idx ON messages USING btree (priority, next_attempt_at, user_id) WHERE status IN (0, 4);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS messages_due_users_idx;
+CREATE INDEX messages_due_idx ON messages USING btree (priority, next_attempt_at, user_id) WHERE status IN (0, 4);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS messages_due_idx;
+-- EnqueueMessages skips through the users with due messages of a lane on this
+-- index, then takes their messages from messages_user_due_idx.
+CREATE INDEX messages_due_users_idx ON messages USING btree (priority, user_id, next_attempt_at) WHERE status IN (0, 4);
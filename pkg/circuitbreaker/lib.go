@@ -0,0 +1,224 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	OpenStateError = errors.New("circuit breaker is open")
+)
+
+type Config struct {
+	// ConsecutiveFailures opens the breaker after this many failures in a row.
+	ConsecutiveFailures int `mapstructure:"consecutive_failures"`
+	// FailureRate opens the breaker when the ratio of failures in the current
+	// window reaches it, once at least MinRequests were recorded.
+	FailureRate float64       `mapstructure:"failure_rate"`
+	MinRequests int           `mapstructure:"min_requests"`
+	Window      time.Duration `mapstructure:"window"`
+	// OpenTimeout is how long the breaker stays open before probing.
+	OpenTimeout time.Duration `mapstructure:"open_timeout"`
+	// HalfOpenRequests is the number of probes allowed while half-open. All of
+	// them must succeed to close the breaker again.
+	HalfOpenRequests int `mapstructure:"half_open_requests"`
+}
+
+// Breaker tracks the health of a single dependency.
+type Breaker struct {
+	name string
+	cfg  Config
+
+	m        sync.Mutex
+	state    State
+	openedAt time.Time
+
+	windowStart time.Time
+	requests    int
+	failures    int
+	consecutive int
+
+	probes         int
+	probeSuccesses int
+
+	onStateChange func(name string, from State, to State)
+}
+
+func NewBreaker(name string, cfg Config) *Breaker {
+	if cfg.ConsecutiveFailures <= 0 {
+		cfg.ConsecutiveFailures = 5
+	}
+	if cfg.FailureRate <= 0 || cfg.FailureRate > 1 {
+		cfg.FailureRate = 0.5
+	}
+	if cfg.MinRequests <= 0 {
+		cfg.MinRequests = 20
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = time.Minute
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	if cfg.HalfOpenRequests <= 0 {
+		cfg.HalfOpenRequests = 1
+	}
+
+	return &Breaker{
+		name:        name,
+		cfg:         cfg,
+		state:       StateClosed,
+		windowStart: time.Now(),
+	}
+}
+
+// OnStateChange registers a callback invoked on every state transition. It
+// must be called before the breaker is used and must not call back into the
+// breaker.
+func (b *Breaker) OnStateChange(handler func(name string, from State, to State)) {
+	b.onStateChange = handler
+}
+
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+func (b *Breaker) State() State {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	b.refresh(time.Now())
+	return b.state
+}
+
+// Allow reports whether a request may be sent. Every allowed request must be
+// followed by exactly one call to Success, Failure or Discard.
+func (b *Breaker) Allow() error {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	b.refresh(time.Now())
+
+	switch b.state {
+	case StateOpen:
+		return OpenStateError
+	case StateHalfOpen:
+		if b.probes >= b.cfg.HalfOpenRequests {
+			return OpenStateError
+		}
+		b.probes++
+	}
+
+	return nil
+}
+
+func (b *Breaker) Success() {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	now := time.Now()
+	b.refresh(now)
+
+	switch b.state {
+	case StateHalfOpen:
+		b.probeSuccesses++
+		if b.probeSuccesses >= b.cfg.HalfOpenRequests {
+			b.setState(StateClosed, now)
+		}
+	case StateClosed:
+		b.requests++
+		b.consecutive = 0
+	}
+}
+
+func (b *Breaker) Failure() {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	now := time.Now()
+	b.refresh(now)
+
+	switch b.state {
+	case StateHalfOpen:
+		b.setState(StateOpen, now)
+	case StateClosed:
+		b.requests++
+		b.failures++
+		b.consecutive++
+
+		if b.consecutive >= b.cfg.ConsecutiveFailures {
+			b.setState(StateOpen, now)
+			return
+		}
+		if b.requests >= b.cfg.MinRequests && float64(b.failures)/float64(b.requests) >= b.cfg.FailureRate {
+			b.setState(StateOpen, now)
+		}
+	}
+}
+
+// Discard releases a request allowed by Allow without recording its outcome,
+// e.g. when the caller gave up before the dependency answered.
+func (b *Breaker) Discard() {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if b.state == StateHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
+
+func (b *Breaker) refresh(now time.Time) {
+	switch b.state {
+	case StateOpen:
+		if now.Sub(b.openedAt) >= b.cfg.OpenTimeout {
+			b.setState(StateHalfOpen, now)
+		}
+	case StateClosed:
+		if now.Sub(b.windowStart) >= b.cfg.Window {
+			b.windowStart = now
+			b.requests = 0
+			b.failures = 0
+		}
+	}
+}
+
+func (b *Breaker) setState(state State, now time.Time) {
+	from := b.state
+	b.state = state
+
+	b.windowStart = now
+	b.requests = 0
+	b.failures = 0
+	b.consecutive = 0
+	b.probes = 0
+	b.probeSuccesses = 0
+	if state == StateOpen {
+		b.openedAt = now
+	}
+
+	if b.onStateChange != nil && from != state {
+		b.onStateChange(b.name, from, state)
+	}
+}
@@ -0,0 +1,90 @@
+package circuitbreaker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/pkg/circuitbreaker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker(t *testing.T) {
+	t.Run("should open after consecutive failures", func(t *testing.T) {
+		breaker := circuitbreaker.NewBreaker("test", circuitbreaker.Config{
+			ConsecutiveFailures: 3,
+		})
+
+		for range 3 {
+			assert.NoError(t, breaker.Allow())
+			breaker.Failure()
+		}
+
+		assert.Equal(t, circuitbreaker.StateOpen, breaker.State())
+		assert.ErrorIs(t, breaker.Allow(), circuitbreaker.OpenStateError)
+	})
+
+	t.Run("should open when failure rate reaches threshold", func(t *testing.T) {
+		breaker := circuitbreaker.NewBreaker("test", circuitbreaker.Config{
+			ConsecutiveFailures: 100,
+			FailureRate:         0.5,
+			MinRequests:         4,
+		})
+
+		for _, ok := range []bool{true, false, true, false} {
+			assert.NoError(t, breaker.Allow())
+			if ok {
+				breaker.Success()
+			} else {
+				breaker.Failure()
+			}
+		}
+
+		assert.Equal(t, circuitbreaker.StateOpen, breaker.State())
+	})
+
+	t.Run("should close after successful half-open probes", func(t *testing.T) {
+		var transitions []circuitbreaker.State
+		breaker := circuitbreaker.NewBreaker("test", circuitbreaker.Config{
+			ConsecutiveFailures: 1,
+			OpenTimeout:         10 * time.Millisecond,
+			HalfOpenRequests:    2,
+		})
+		breaker.OnStateChange(func(name string, from circuitbreaker.State, to circuitbreaker.State) {
+			transitions = append(transitions, to)
+		})
+
+		assert.NoError(t, breaker.Allow())
+		breaker.Failure()
+		time.Sleep(20 * time.Millisecond)
+
+		assert.NoError(t, breaker.Allow())
+		assert.NoError(t, breaker.Allow())
+		assert.ErrorIs(t, breaker.Allow(), circuitbreaker.OpenStateError)
+		breaker.Success()
+		assert.Equal(t, circuitbreaker.StateHalfOpen, breaker.State())
+		breaker.Success()
+
+		assert.Equal(t, circuitbreaker.StateClosed, breaker.State())
+		assert.Equal(t, []circuitbreaker.State{
+			circuitbreaker.StateOpen,
+			circuitbreaker.StateHalfOpen,
+			circuitbreaker.StateClosed,
+		}, transitions)
+	})
+
+	t.Run("should reopen when half-open probe fails", func(t *testing.T) {
+		breaker := circuitbreaker.NewBreaker("test", circuitbreaker.Config{
+			ConsecutiveFailures: 1,
+			OpenTimeout:         10 * time.Millisecond,
+		})
+
+		assert.NoError(t, breaker.Allow())
+		breaker.Failure()
+		time.Sleep(20 * time.Millisecond)
+
+		assert.NoError(t, breaker.Allow())
+		breaker.Failure()
+
+		assert.Equal(t, circuitbreaker.StateOpen, breaker.State())
+	})
+}
@@ -0,0 +1,138 @@
+package gsm
+
+import "unicode/utf16"
+
+type Encoding int
+
+const (
+	EncodingGSM7 Encoding = iota
+	EncodingUCS2
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case EncodingGSM7:
+		return "gsm7"
+	case EncodingUCS2:
+		return "ucs2"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	gsm7SingleSeptets = 160
+	gsm7MultiSeptets  = 153
+	ucs2SingleUnits   = 70
+	ucs2MultiUnits    = 67
+)
+
+// escape is the GSM 03.38 code that switches to the extension table.
+const escape = 0x1B
+
+// basicCharset is the GSM 03.38 default alphabet in code order without the
+// escape code.
+const basicCharset = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+var (
+	basic = basicCodes()
+	// extension is the GSM 03.38 extension table. Each of its characters is
+	// sent as the escape code followed by its code, so it takes two septets.
+	extension = map[rune]byte{
+		'\f': 0x0A, '^': 0x14, '{': 0x28, '}': 0x29, '\\': 0x2F,
+		'[': 0x3C, '~': 0x3D, ']': 0x3E, '|': 0x40, '€': 0x65,
+	}
+)
+
+func basicCodes() map[rune]byte {
+	codes := make(map[rune]byte, 127)
+	code := byte(0)
+	for _, r := range basicCharset {
+		if code == escape {
+			code++
+		}
+		codes[r] = code
+		code++
+	}
+
+	return codes
+}
+
+// Detect returns EncodingGSM7 when every character of content is in the
+// GSM 03.38 alphabet or its extension table, and EncodingUCS2 otherwise.
+func Detect(content string) Encoding {
+	for _, r := range content {
+		if _, ok := basic[r]; ok {
+			continue
+		}
+		if _, ok := extension[r]; ok {
+			continue
+		}
+		return EncodingUCS2
+	}
+
+	return EncodingGSM7
+}
+
+// Encode maps content to GSM 03.38 codes, one septet per byte as SMPP sends
+// the default alphabet. It returns false when content is not GSM-7.
+func Encode(content string) ([]byte, bool) {
+	payload := make([]byte, 0, len(content))
+	for _, r := range content {
+		if code, ok := basic[r]; ok {
+			payload = append(payload, code)
+			continue
+		}
+		if code, ok := extension[r]; ok {
+			payload = append(payload, escape, code)
+			continue
+		}
+		return nil, false
+	}
+
+	return payload, true
+}
+
+// Segments returns the encoding of content and the number of SMS segments
+// needed to send it. Content that does not fit a single message is split
+// into concatenated segments, which lose room to the UDH, and a character
+// is never split between two segments.
+func Segments(content string) (Encoding, int) {
+	encoding := Detect(content)
+	if encoding == EncodingGSM7 {
+		return encoding, count(content, gsm7SingleSeptets, gsm7MultiSeptets, septets)
+	}
+
+	return encoding, count(content, ucs2SingleUnits, ucs2MultiUnits, utf16.RuneLen)
+}
+
+func septets(r rune) int {
+	if _, ok := extension[r]; ok {
+		return 2
+	}
+
+	return 1
+}
+
+func count(content string, single int, multi int, size func(r rune) int) int {
+	total := 0
+	for _, r := range content {
+		total += size(r)
+	}
+	if total <= single {
+		return 1
+	}
+
+	segments, used := 1, 0
+	for _, r := range content {
+		n := size(r)
+		if used+n > multi {
+			segments++
+			used = 0
+		}
+		used += n
+	}
+
+	return segments
+}
@@ -0,0 +1,68 @@
+package gsm_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/pkg/gsm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	t.Run("should detect GSM-7 for basic and extension characters", func(t *testing.T) {
+		assert.Equal(t, gsm.EncodingGSM7, gsm.Detect("Hello @ £5, {ok} [1] ~ €"))
+	})
+
+	t.Run("should detect UCS-2 for characters out of the GSM alphabet", func(t *testing.T) {
+		assert.Equal(t, gsm.EncodingUCS2, gsm.Detect("سلام"))
+		assert.Equal(t, gsm.EncodingUCS2, gsm.Detect("price `5`"))
+	})
+}
+
+func TestEncode(t *testing.T) {
+	t.Run("should map characters to GSM 03.38 codes", func(t *testing.T) {
+		actualPayload, actualOk := gsm.Encode("Hi @ £_$")
+		assert.True(t, actualOk)
+		assert.Equal(t, []byte{0x48, 0x69, 0x20, 0x00, 0x20, 0x01, 0x11, 0x02}, actualPayload)
+	})
+
+	t.Run("should map characters after the escape code and extension characters", func(t *testing.T) {
+		actualPayload, actualOk := gsm.Encode("Æ€[")
+		assert.True(t, actualOk)
+		assert.Equal(t, []byte{0x1C, 0x1B, 0x65, 0x1B, 0x3C}, actualPayload)
+	})
+
+	t.Run("should return false for characters out of the GSM alphabet", func(t *testing.T) {
+		_, actualOk := gsm.Encode("price `5`")
+		assert.False(t, actualOk)
+	})
+}
+
+func TestSegments(t *testing.T) {
+	tests := []struct {
+		name             string
+		content          string
+		expectedEncoding gsm.Encoding
+		expectedSegments int
+	}{
+		{"should fit 160 GSM-7 characters in one segment", strings.Repeat("a", 160), gsm.EncodingGSM7, 1},
+		{"should split 161 GSM-7 characters in two segments", strings.Repeat("a", 161), gsm.EncodingGSM7, 2},
+		{"should fit 306 GSM-7 characters in two segments", strings.Repeat("a", 306), gsm.EncodingGSM7, 2},
+		{"should count extension characters as two septets", strings.Repeat("€", 80), gsm.EncodingGSM7, 1},
+		{"should split when extension characters exceed one segment", strings.Repeat("€", 81), gsm.EncodingGSM7, 2},
+		{"should not split an extension character between segments", strings.Repeat("a", 152) + "€" + strings.Repeat("a", 8), gsm.EncodingGSM7, 2},
+		{"should fit 70 UCS-2 characters in one segment", strings.Repeat("س", 70), gsm.EncodingUCS2, 1},
+		{"should split 71 UCS-2 characters in two segments", strings.Repeat("س", 71), gsm.EncodingUCS2, 2},
+		{"should split 1000 UCS-2 characters in 15 segments", strings.Repeat("س", 1000), gsm.EncodingUCS2, 15},
+		{"should not split a surrogate pair between segments", strings.Repeat("س", 66) + "😀" + strings.Repeat("س", 5), gsm.EncodingUCS2, 2},
+		{"should count an empty content as one segment", "", gsm.EncodingGSM7, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualEncoding, actualSegments := gsm.Segments(tt.content)
+			assert.Equal(t, tt.expectedEncoding, actualEncoding)
+			assert.Equal(t, tt.expectedSegments, actualSegments)
+		})
+	}
+}
@@ -6,6 +6,12 @@ import (
 )
 
 var SmsStatusMetric *prometheus.CounterVec
+var ProviderCircuitStateMetric *prometheus.GaugeVec
+var MessageReconciledMetric *prometheus.CounterVec
+var DeadLetterMetric *prometheus.CounterVec
+var ProviderSentMetric *prometheus.CounterVec
+var ProviderThrottledMetric *prometheus.CounterVec
+var ProviderThrottleWaitMetric *prometheus.HistogramVec
 
 var registry = prometheus.NewRegistry()
 
@@ -15,7 +21,44 @@ func RegisterMetrics() {
 		Help: "The total number of processed messages",
 	}, []string{"status"})
 
+	ProviderCircuitStateMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "provider_circuit_state",
+		Help: "The circuit breaker state of sms providers (0: closed, 1: open, 2: half-open)",
+	}, []string{"chain", "provider"})
+
+	MessageReconciledMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "message_reconciled_count",
+		Help: "The total number of stranded enqueued messages reconciled",
+	}, []string{"action"})
+
+	DeadLetterMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "message_dead_letter_count",
+		Help: "The total number of dead-lettered messages",
+	}, []string{"reason"})
+
+	ProviderSentMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "provider_sent_count",
+		Help: "The total number of messages accepted by sms providers, its rate is the provider throughput",
+	}, []string{"provider"})
+
+	ProviderThrottledMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "provider_throttled_count",
+		Help: "The total number of times a send waited for the throughput limit of its provider",
+	}, []string{"provider", "reason"})
+
+	ProviderThrottleWaitMetric = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "provider_throttle_wait_seconds",
+		Help:    "The time sends waited for the throughput limit of their provider",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 14),
+	}, []string{"provider"})
+
 	registry.MustRegister(SmsStatusMetric)
+	registry.MustRegister(ProviderCircuitStateMetric)
+	registry.MustRegister(MessageReconciledMetric)
+	registry.MustRegister(DeadLetterMetric)
+	registry.MustRegister(ProviderSentMetric)
+	registry.MustRegister(ProviderThrottledMetric)
+	registry.MustRegister(ProviderThrottleWaitMetric)
 }
 
 func GetRegistry() *prometheus.Registry {
@@ -0,0 +1,438 @@
+package smpp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pkgLog "github.com/AshkanAbd/arvancloud_sms_gateway/pkg/logger"
+)
+
+const (
+	BindModeTransmitter = "transmitter"
+	BindModeTransceiver = "transceiver"
+
+	maxSequence = 0x7FFFFFFF
+)
+
+var (
+	NotBoundError        = errors.New("smpp session is not bound")
+	ClosedError          = errors.New("smpp client is closed")
+	ResponseTimeoutError = errors.New("smpp response timeout")
+	ConnectionLostError  = errors.New("smpp connection lost")
+)
+
+type Config struct {
+	Address             string        `mapstructure:"address"`
+	SystemId            string        `mapstructure:"system_id"`
+	Password            string        `mapstructure:"password"`
+	SystemType          string        `mapstructure:"system_type"`
+	BindMode            string        `mapstructure:"bind_mode"`
+	WindowSize          int           `mapstructure:"window_size"`
+	EnquireLinkInterval time.Duration `mapstructure:"enquire_link_interval"`
+	ResponseTimeout     time.Duration `mapstructure:"response_timeout"`
+	MinReconnectBackoff time.Duration `mapstructure:"min_reconnect_backoff"`
+	MaxReconnectBackoff time.Duration `mapstructure:"max_reconnect_backoff"`
+	// DeliverQueueSize is how many deliver_sm may wait for the handler.
+	// Beyond it the SMSC is answered with throttled and retries later.
+	DeliverQueueSize int `mapstructure:"deliver_queue_size"`
+}
+
+// Client keeps a single persistent bind to an SMSC, re-binding with
+// exponential backoff whenever the connection is lost.
+type Client struct {
+	cfg    Config
+	window chan struct{}
+	seq    atomic.Uint32
+
+	m       sync.Mutex
+	sess    *session
+	boundCh chan struct{}
+
+	onDeliver func(ShortMessage)
+	deliverCh chan ShortMessage
+
+	closeCh   chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+type session struct {
+	conn    net.Conn
+	writeM  sync.Mutex
+	pendM   sync.Mutex
+	pending map[uint32]chan Pdu
+
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+func NewClient(cfg Config) *Client {
+	if cfg.BindMode == "" {
+		cfg.BindMode = BindModeTransmitter
+	}
+	if cfg.WindowSize <= 0 {
+		cfg.WindowSize = 10
+	}
+	if cfg.EnquireLinkInterval <= 0 {
+		cfg.EnquireLinkInterval = 30 * time.Second
+	}
+	if cfg.ResponseTimeout <= 0 {
+		cfg.ResponseTimeout = 5 * time.Second
+	}
+	if cfg.MinReconnectBackoff <= 0 {
+		cfg.MinReconnectBackoff = 500 * time.Millisecond
+	}
+	if cfg.MaxReconnectBackoff < cfg.MinReconnectBackoff {
+		cfg.MaxReconnectBackoff = 30 * time.Second
+	}
+	if cfg.DeliverQueueSize <= 0 {
+		cfg.DeliverQueueSize = 100
+	}
+
+	return &Client{
+		cfg:       cfg,
+		window:    make(chan struct{}, cfg.WindowSize),
+		boundCh:   make(chan struct{}),
+		deliverCh: make(chan ShortMessage, cfg.DeliverQueueSize),
+		closeCh:   make(chan struct{}),
+	}
+}
+
+// OnDeliver registers a handler for deliver_sm requests received over a
+// transceiver bind. It must be called before Start. The handler runs on its
+// own goroutine, so a slow handler never stalls the session.
+func (c *Client) OnDeliver(handler func(ShortMessage)) {
+	c.onDeliver = handler
+}
+
+func (c *Client) Start() {
+	if c.onDeliver != nil {
+		c.wg.Add(1)
+		go c.dispatchDeliveries()
+	}
+
+	c.wg.Add(1)
+	go c.run()
+}
+
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		pkgLog.Info("closing smpp client for %s", c.cfg.Address)
+		close(c.closeCh)
+	})
+	c.wg.Wait()
+
+	return nil
+}
+
+func (c *Client) Submit(ctx context.Context, sm ShortMessage) (string, error) {
+	select {
+	case c.window <- struct{}{}:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-c.closeCh:
+		return "", ClosedError
+	}
+	defer func() { <-c.window }()
+
+	sess, err := c.waitSession(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.request(ctx, sess, SubmitSm, sm.Marshal())
+	if err != nil {
+		return "", err
+	}
+	if resp.Status != StatusOk {
+		return "", StatusError{Status: resp.Status}
+	}
+
+	return UnmarshalMessageId(resp.Body)
+}
+
+func (c *Client) run() {
+	defer c.wg.Done()
+
+	backoff := c.cfg.MinReconnectBackoff
+	for {
+		sess, err := c.connect()
+		if err != nil {
+			pkgLog.Error(err, "failed to bind to smsc %s, retrying in %s", c.cfg.Address, backoff)
+			select {
+			case <-time.After(backoff):
+			case <-c.closeCh:
+				return
+			}
+			backoff = min(backoff*2, c.cfg.MaxReconnectBackoff)
+			continue
+		}
+
+		pkgLog.Info("bound to smsc %s as %s", c.cfg.Address, c.cfg.BindMode)
+		backoff = c.cfg.MinReconnectBackoff
+		c.setSession(sess)
+
+		c.wg.Add(1)
+		go c.keepAlive(sess)
+
+		select {
+		case <-sess.done:
+			pkgLog.Warn("connection to smsc %s lost", c.cfg.Address)
+			c.setSession(nil)
+		case <-c.closeCh:
+			c.setSession(nil)
+			c.unbind(sess)
+			return
+		}
+	}
+}
+
+func (c *Client) connect() (*session, error) {
+	conn, err := net.DialTimeout("tcp", c.cfg.Address, c.cfg.ResponseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	sess := &session{
+		conn:    conn,
+		pending: make(map[uint32]chan Pdu),
+		done:    make(chan struct{}),
+	}
+	c.wg.Add(1)
+	go c.read(sess)
+
+	bindCmd := BindTransmitter
+	if c.cfg.BindMode == BindModeTransceiver {
+		bindCmd = BindTransceiver
+	}
+	resp, err := c.request(context.Background(), sess, bindCmd, Bind{
+		SystemId:   c.cfg.SystemId,
+		Password:   c.cfg.Password,
+		SystemType: c.cfg.SystemType,
+	}.Marshal())
+	if err != nil {
+		sess.close()
+		return nil, err
+	}
+	if resp.Status != StatusOk {
+		sess.close()
+		return nil, StatusError{Status: resp.Status}
+	}
+
+	return sess, nil
+}
+
+func (c *Client) unbind(sess *session) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.ResponseTimeout)
+	defer cancel()
+
+	if _, err := c.request(ctx, sess, Unbind, nil); err != nil {
+		pkgLog.Error(err, "failed to unbind from smsc %s", c.cfg.Address)
+	}
+	sess.close()
+}
+
+func (c *Client) keepAlive(sess *session) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.cfg.EnquireLinkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := c.request(context.Background(), sess, EnquireLink, nil); err != nil {
+				pkgLog.Error(err, "enquire_link to smsc %s failed", c.cfg.Address)
+				sess.close()
+				return
+			}
+		case <-sess.done:
+			return
+		}
+	}
+}
+
+func (c *Client) read(sess *session) {
+	defer c.wg.Done()
+	defer sess.close()
+
+	for {
+		p, err := ReadPdu(sess.conn)
+		if err != nil {
+			return
+		}
+
+		if p.IsResponse() {
+			sess.resolve(p)
+			continue
+		}
+
+		switch p.CommandId {
+		case EnquireLink:
+			_ = sess.write(Pdu{CommandId: EnquireLinkResp, Sequence: p.Sequence})
+		case DeliverSm:
+			_ = sess.write(Pdu{CommandId: DeliverSmResp, Status: c.queueDelivery(p), Sequence: p.Sequence, Body: MarshalMessageId("")})
+		case Unbind:
+			_ = sess.write(Pdu{CommandId: UnbindResp, Sequence: p.Sequence})
+			return
+		default:
+			_ = sess.write(Pdu{CommandId: GenericNack, Status: StatusInvalidCmdId, Sequence: p.Sequence})
+		}
+	}
+}
+
+// queueDelivery hands the deliver_sm to the dispatcher without blocking the
+// read loop, and returns the command status to answer the SMSC with.
+func (c *Client) queueDelivery(p Pdu) uint32 {
+	if c.onDeliver == nil {
+		return StatusOk
+	}
+
+	sm, err := UnmarshalShortMessage(p.Body)
+	if err != nil {
+		pkgLog.Error(err, "failed to decode deliver_sm from smsc %s", c.cfg.Address)
+		return StatusOk
+	}
+
+	select {
+	case c.deliverCh <- sm:
+		return StatusOk
+	default:
+		pkgLog.Warn("deliver_sm queue of smsc %s is full, throttling", c.cfg.Address)
+		return StatusThrottled
+	}
+}
+
+func (c *Client) dispatchDeliveries() {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case sm := <-c.deliverCh:
+			c.onDeliver(sm)
+		case <-c.closeCh:
+			return
+		}
+	}
+}
+
+func (c *Client) request(ctx context.Context, sess *session, commandId uint32, body []byte) (Pdu, error) {
+	seq := c.nextSequence()
+	ch := sess.register(seq)
+	defer sess.unregister(seq)
+
+	if err := sess.write(Pdu{CommandId: commandId, Sequence: seq, Body: body}); err != nil {
+		sess.close()
+		return Pdu{}, ConnectionLostError
+	}
+
+	timer := time.NewTimer(c.cfg.ResponseTimeout)
+	defer timer.Stop()
+
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-timer.C:
+		return Pdu{}, ResponseTimeoutError
+	case <-sess.done:
+		return Pdu{}, ConnectionLostError
+	case <-ctx.Done():
+		return Pdu{}, ctx.Err()
+	}
+}
+
+func (c *Client) waitSession(ctx context.Context) (*session, error) {
+	timer := time.NewTimer(c.cfg.ResponseTimeout)
+	defer timer.Stop()
+
+	for {
+		c.m.Lock()
+		sess, boundCh := c.sess, c.boundCh
+		c.m.Unlock()
+
+		if sess != nil {
+			return sess, nil
+		}
+
+		select {
+		case <-boundCh:
+		case <-timer.C:
+			return nil, NotBoundError
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.closeCh:
+			return nil, ClosedError
+		}
+	}
+}
+
+func (c *Client) setSession(sess *session) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.sess = sess
+	if sess != nil {
+		close(c.boundCh)
+	} else {
+		c.boundCh = make(chan struct{})
+	}
+}
+
+func (c *Client) nextSequence() uint32 {
+	for {
+		seq := c.seq.Add(1)
+		if seq <= maxSequence {
+			return seq
+		}
+		c.seq.CompareAndSwap(seq, 0)
+	}
+}
+
+func (s *session) write(p Pdu) error {
+	s.writeM.Lock()
+	defer s.writeM.Unlock()
+
+	_, err := s.conn.Write(p.Marshal())
+	return err
+}
+
+func (s *session) register(seq uint32) chan Pdu {
+	ch := make(chan Pdu, 1)
+
+	s.pendM.Lock()
+	s.pending[seq] = ch
+	s.pendM.Unlock()
+
+	return ch
+}
+
+func (s *session) unregister(seq uint32) {
+	s.pendM.Lock()
+	delete(s.pending, seq)
+	s.pendM.Unlock()
+}
+
+func (s *session) resolve(p Pdu) {
+	s.pendM.Lock()
+	ch, ok := s.pending[p.Sequence]
+	s.pendM.Unlock()
+
+	if !ok {
+		return
+	}
+	select {
+	case ch <- p:
+	default:
+	}
+}
+
+func (s *session) close() {
+	s.doneOnce.Do(func() {
+		close(s.done)
+		_ = s.conn.Close()
+	})
+}
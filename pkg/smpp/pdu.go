@@ -0,0 +1,321 @@
+package smpp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	GenericNack         uint32 = 0x80000000
+	BindReceiver        uint32 = 0x00000001
+	BindReceiverResp    uint32 = 0x80000001
+	BindTransmitter     uint32 = 0x00000002
+	BindTransmitterResp uint32 = 0x80000002
+	SubmitSm            uint32 = 0x00000004
+	SubmitSmResp        uint32 = 0x80000004
+	DeliverSm           uint32 = 0x00000005
+	DeliverSmResp       uint32 = 0x80000005
+	Unbind              uint32 = 0x00000006
+	UnbindResp          uint32 = 0x80000006
+	BindTransceiver     uint32 = 0x00000009
+	BindTransceiverResp uint32 = 0x80000009
+	EnquireLink         uint32 = 0x00000015
+	EnquireLinkResp     uint32 = 0x80000015
+)
+
+const (
+	StatusOk              uint32 = 0x00000000
+	StatusInvalidMsgLen   uint32 = 0x00000001
+	StatusInvalidCmdId    uint32 = 0x00000003
+	StatusAlreadyBound    uint32 = 0x00000005
+	StatusSystemError     uint32 = 0x00000008
+	StatusInvalidDestAddr uint32 = 0x0000000B
+	StatusBindFailed      uint32 = 0x0000000D
+	StatusInvalidPassword uint32 = 0x0000000E
+	StatusInvalidSystemId uint32 = 0x0000000F
+	StatusMsgQueueFull    uint32 = 0x00000014
+	StatusThrottled       uint32 = 0x00000058
+	StatusSubmitFailed    uint32 = 0x00000045
+)
+
+const (
+	InterfaceVersion = 0x34
+
+	DataCodingDefault uint8 = 0x00
+	DataCodingUCS2    uint8 = 0x08
+
+	TonInternational uint8 = 0x01
+	NpiIsdn          uint8 = 0x01
+
+	TagMessagePayload uint16 = 0x0424
+
+	headerLen      = 16
+	maxPduLen      = 64 * 1024
+	maxShortMsgLen = 254
+)
+
+var (
+	InvalidPduError = errors.New("invalid smpp pdu")
+)
+
+// StatusError is returned when the SMSC responds with a non-zero command status.
+type StatusError struct {
+	Status uint32
+}
+
+func (e StatusError) Error() string {
+	return fmt.Sprintf("smpp command status 0x%08X", e.Status)
+}
+
+// Temporary reports whether the SMSC may accept the same request later.
+func (e StatusError) Temporary() bool {
+	switch e.Status {
+	case StatusSystemError, StatusMsgQueueFull, StatusThrottled:
+		return true
+	default:
+		return false
+	}
+}
+
+type Pdu struct {
+	CommandId uint32
+	Status    uint32
+	Sequence  uint32
+	Body      []byte
+}
+
+func (p Pdu) IsResponse() bool {
+	return p.CommandId&GenericNack != 0
+}
+
+func (p Pdu) Marshal() []byte {
+	buf := make([]byte, headerLen+len(p.Body))
+	binary.BigEndian.PutUint32(buf[0:4], uint32(len(buf)))
+	binary.BigEndian.PutUint32(buf[4:8], p.CommandId)
+	binary.BigEndian.PutUint32(buf[8:12], p.Status)
+	binary.BigEndian.PutUint32(buf[12:16], p.Sequence)
+	copy(buf[headerLen:], p.Body)
+
+	return buf
+}
+
+func ReadPdu(r io.Reader) (Pdu, error) {
+	header := make([]byte, headerLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return Pdu{}, err
+	}
+
+	length := binary.BigEndian.Uint32(header[0:4])
+	if length < headerLen || length > maxPduLen {
+		return Pdu{}, InvalidPduError
+	}
+
+	p := Pdu{
+		CommandId: binary.BigEndian.Uint32(header[4:8]),
+		Status:    binary.BigEndian.Uint32(header[8:12]),
+		Sequence:  binary.BigEndian.Uint32(header[12:16]),
+		Body:      make([]byte, length-headerLen),
+	}
+	if _, err := io.ReadFull(r, p.Body); err != nil {
+		return Pdu{}, err
+	}
+
+	return p, nil
+}
+
+type Bind struct {
+	SystemId   string
+	Password   string
+	SystemType string
+}
+
+func (b Bind) Marshal() []byte {
+	w := &bytes.Buffer{}
+	writeCString(w, b.SystemId)
+	writeCString(w, b.Password)
+	writeCString(w, b.SystemType)
+	w.WriteByte(InterfaceVersion)
+	w.WriteByte(0)
+	w.WriteByte(0)
+	writeCString(w, "")
+
+	return w.Bytes()
+}
+
+func UnmarshalBind(body []byte) (Bind, error) {
+	r := bytes.NewReader(body)
+	b := Bind{}
+	var err error
+	if b.SystemId, err = readCString(r); err != nil {
+		return Bind{}, err
+	}
+	if b.Password, err = readCString(r); err != nil {
+		return Bind{}, err
+	}
+	if b.SystemType, err = readCString(r); err != nil {
+		return Bind{}, err
+	}
+
+	return b, nil
+}
+
+// ShortMessage is the common body of submit_sm and deliver_sm.
+type ShortMessage struct {
+	ServiceType        string
+	SourceAddrTon      uint8
+	SourceAddrNpi      uint8
+	SourceAddr         string
+	DestAddrTon        uint8
+	DestAddrNpi        uint8
+	DestinationAddr    string
+	EsmClass           uint8
+	RegisteredDelivery uint8
+	DataCoding         uint8
+	Message            []byte
+}
+
+func (s ShortMessage) Marshal() []byte {
+	w := &bytes.Buffer{}
+	writeCString(w, s.ServiceType)
+	w.WriteByte(s.SourceAddrTon)
+	w.WriteByte(s.SourceAddrNpi)
+	writeCString(w, s.SourceAddr)
+	w.WriteByte(s.DestAddrTon)
+	w.WriteByte(s.DestAddrNpi)
+	writeCString(w, s.DestinationAddr)
+	w.WriteByte(s.EsmClass)
+	w.WriteByte(0) // protocol_id
+	w.WriteByte(0) // priority_flag
+	writeCString(w, "")
+	writeCString(w, "")
+	w.WriteByte(s.RegisteredDelivery)
+	w.WriteByte(0) // replace_if_present_flag
+	w.WriteByte(s.DataCoding)
+	w.WriteByte(0) // sm_default_msg_id
+
+	if len(s.Message) <= maxShortMsgLen {
+		w.WriteByte(byte(len(s.Message)))
+		w.Write(s.Message)
+		return w.Bytes()
+	}
+
+	w.WriteByte(0)
+	tlv := make([]byte, 4)
+	binary.BigEndian.PutUint16(tlv[0:2], TagMessagePayload)
+	binary.BigEndian.PutUint16(tlv[2:4], uint16(len(s.Message)))
+	w.Write(tlv)
+	w.Write(s.Message)
+
+	return w.Bytes()
+}
+
+func UnmarshalShortMessage(body []byte) (ShortMessage, error) {
+	r := bytes.NewReader(body)
+	s := ShortMessage{}
+	var err error
+
+	if s.ServiceType, err = readCString(r); err != nil {
+		return ShortMessage{}, err
+	}
+	if s.SourceAddrTon, s.SourceAddrNpi, err = readTwoBytes(r); err != nil {
+		return ShortMessage{}, err
+	}
+	if s.SourceAddr, err = readCString(r); err != nil {
+		return ShortMessage{}, err
+	}
+	if s.DestAddrTon, s.DestAddrNpi, err = readTwoBytes(r); err != nil {
+		return ShortMessage{}, err
+	}
+	if s.DestinationAddr, err = readCString(r); err != nil {
+		return ShortMessage{}, err
+	}
+
+	fixed := make([]byte, 3)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		return ShortMessage{}, InvalidPduError
+	}
+	s.EsmClass = fixed[0]
+	if _, err = readCString(r); err != nil {
+		return ShortMessage{}, err
+	}
+	if _, err = readCString(r); err != nil {
+		return ShortMessage{}, err
+	}
+
+	fixed = make([]byte, 5)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		return ShortMessage{}, InvalidPduError
+	}
+	s.RegisteredDelivery = fixed[0]
+	s.DataCoding = fixed[2]
+	smLength := int(fixed[4])
+
+	s.Message = make([]byte, smLength)
+	if _, err := io.ReadFull(r, s.Message); err != nil {
+		return ShortMessage{}, InvalidPduError
+	}
+
+	for r.Len() >= 4 {
+		tlv := make([]byte, 4)
+		_, _ = io.ReadFull(r, tlv)
+		tag := binary.BigEndian.Uint16(tlv[0:2])
+		value := make([]byte, binary.BigEndian.Uint16(tlv[2:4]))
+		if _, err := io.ReadFull(r, value); err != nil {
+			return ShortMessage{}, InvalidPduError
+		}
+		if tag == TagMessagePayload {
+			s.Message = value
+		}
+	}
+
+	return s, nil
+}
+
+func MarshalMessageId(id string) []byte {
+	w := &bytes.Buffer{}
+	writeCString(w, id)
+	return w.Bytes()
+}
+
+func UnmarshalMessageId(body []byte) (string, error) {
+	if len(body) == 0 {
+		return "", nil
+	}
+
+	return readCString(bytes.NewReader(body))
+}
+
+func writeCString(w *bytes.Buffer, s string) {
+	w.WriteString(s)
+	w.WriteByte(0)
+}
+
+func readCString(r *bytes.Reader) (string, error) {
+	var b bytes.Buffer
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			return "", InvalidPduError
+		}
+		if c == 0 {
+			return b.String(), nil
+		}
+		b.WriteByte(c)
+	}
+}
+
+func readTwoBytes(r *bytes.Reader) (uint8, uint8, error) {
+	first, err := r.ReadByte()
+	if err != nil {
+		return 0, 0, InvalidPduError
+	}
+	second, err := r.ReadByte()
+	if err != nil {
+		return 0, 0, InvalidPduError
+	}
+
+	return first, second, nil
+}
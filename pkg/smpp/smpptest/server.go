@@ -0,0 +1,190 @@
+// Package smpptest provides an in-process fake SMSC for exercising SMPP
+// clients in tests.
+package smpptest
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/AshkanAbd/arvancloud_sms_gateway/pkg/smpp"
+)
+
+// Server is a minimal SMSC accepting binds and submit_sm requests.
+type Server struct {
+	SystemId string
+	Password string
+
+	// SubmitStatus decides the command status for each submit_sm. Nil means
+	// every message is accepted.
+	SubmitStatus func(sm smpp.ShortMessage) uint32
+
+	listener net.Listener
+
+	m         sync.Mutex
+	conns     map[net.Conn]*sync.Mutex
+	submitted []smpp.ShortMessage
+	binds     int
+	nextId    int
+
+	wg sync.WaitGroup
+}
+
+func NewServer(systemId string, password string) *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("smpptest: failed to listen: %v", err))
+	}
+
+	s := &Server{
+		SystemId: systemId,
+		Password: password,
+		listener: listener,
+		conns:    make(map[net.Conn]*sync.Mutex),
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s
+}
+
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Submitted returns the messages accepted so far.
+func (s *Server) Submitted() []smpp.ShortMessage {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return append([]smpp.ShortMessage(nil), s.submitted...)
+}
+
+// Binds returns the number of successful binds, including re-binds.
+func (s *Server) Binds() int {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	return s.binds
+}
+
+// DropConnections closes every open client connection without unbinding.
+func (s *Server) DropConnections() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+}
+
+// Deliver sends a deliver_sm to every bound client.
+func (s *Server) Deliver(sm smpp.ShortMessage) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for conn, writeM := range s.conns {
+		writeM.Lock()
+		_, _ = conn.Write(smpp.Pdu{CommandId: smpp.DeliverSm, Sequence: 1, Body: sm.Marshal()}.Marshal())
+		writeM.Unlock()
+	}
+}
+
+func (s *Server) Close() {
+	_ = s.listener.Close()
+	s.DropConnections()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		writeM := &sync.Mutex{}
+		s.m.Lock()
+		s.conns[conn] = writeM
+		s.m.Unlock()
+
+		s.wg.Add(1)
+		go s.handle(conn, writeM)
+	}
+}
+
+func (s *Server) handle(conn net.Conn, writeM *sync.Mutex) {
+	defer s.wg.Done()
+	defer func() {
+		s.m.Lock()
+		delete(s.conns, conn)
+		s.m.Unlock()
+		_ = conn.Close()
+	}()
+
+	reply := func(p smpp.Pdu) {
+		writeM.Lock()
+		defer writeM.Unlock()
+		_, _ = conn.Write(p.Marshal())
+	}
+
+	bound := false
+	for {
+		p, err := smpp.ReadPdu(conn)
+		if err != nil {
+			return
+		}
+
+		switch p.CommandId {
+		case smpp.BindTransmitter, smpp.BindTransceiver, smpp.BindReceiver:
+			status := smpp.StatusOk
+			bind, err := smpp.UnmarshalBind(p.Body)
+			if err != nil || bind.SystemId != s.SystemId || bind.Password != s.Password {
+				status = smpp.StatusBindFailed
+			} else {
+				bound = true
+				s.m.Lock()
+				s.binds++
+				s.m.Unlock()
+			}
+			reply(smpp.Pdu{CommandId: p.CommandId | smpp.GenericNack, Status: status, Sequence: p.Sequence, Body: smpp.MarshalMessageId("smpptest")})
+		case smpp.SubmitSm:
+			if !bound {
+				reply(smpp.Pdu{CommandId: smpp.SubmitSmResp, Status: smpp.StatusBindFailed, Sequence: p.Sequence})
+				continue
+			}
+			sm, err := smpp.UnmarshalShortMessage(p.Body)
+			if err != nil {
+				reply(smpp.Pdu{CommandId: smpp.SubmitSmResp, Status: smpp.StatusInvalidMsgLen, Sequence: p.Sequence})
+				continue
+			}
+			status := smpp.StatusOk
+			if s.SubmitStatus != nil {
+				status = s.SubmitStatus(sm)
+			}
+			if status != smpp.StatusOk {
+				reply(smpp.Pdu{CommandId: smpp.SubmitSmResp, Status: status, Sequence: p.Sequence})
+				continue
+			}
+
+			s.m.Lock()
+			s.nextId++
+			id := fmt.Sprintf("smsc-%d", s.nextId)
+			s.submitted = append(s.submitted, sm)
+			s.m.Unlock()
+			reply(smpp.Pdu{CommandId: smpp.SubmitSmResp, Sequence: p.Sequence, Body: smpp.MarshalMessageId(id)})
+		case smpp.EnquireLink:
+			reply(smpp.Pdu{CommandId: smpp.EnquireLinkResp, Sequence: p.Sequence})
+		case smpp.Unbind:
+			reply(smpp.Pdu{CommandId: smpp.UnbindResp, Sequence: p.Sequence})
+			return
+		default:
+			if !p.IsResponse() {
+				reply(smpp.Pdu{CommandId: smpp.GenericNack, Status: smpp.StatusInvalidCmdId, Sequence: p.Sequence})
+			}
+		}
+	}
+}